@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -9,19 +10,53 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App     App
-		HTTP    HTTP
-		Log     Log
-		PG      PG
-		RMQ     RMQ
-		Metrics Metrics
-		Swagger Swagger
+		App          App
+		HTTP         HTTP
+		Log          Log
+		PG           PG
+		RMQ          RMQ
+		Metrics      Metrics
+		Swagger      Swagger
+		Admin        Admin
+		OIDC         OIDC
+		Integrations Integrations
+		Chaos        Chaos
+		Capture      Capture
+		IDs          IDs
+		Jobs         Jobs
+		Tasks        Tasks
+		Policy       Policy
+		Health       Health
+		Reminders    Reminders
+		Absences     Absences
+		Usage        Usage
+		ClientQuota  ClientQuota
+		Quota        Quota
+		Assignment   Assignment
+		Reconcile    Reconcile
+		Merge        Merge
+		RBAC         RBAC
+		Telegram     Telegram
+		Notify       Notify
+		Limiter      Limiter
+		GitHub       GitHub
+		GitLab       GitLab
+		Bitbucket    Bitbucket
+		Slack        Slack
+		ClickHouse   ClickHouse
+		Workload     Workload
+		StatsHistory StatsHistory
+		WeeklyReport WeeklyReport
+		Stats        Stats
+		SMTP         SMTP
+		Hooks        Hooks
 	}
 
 	// App -.
 	App struct {
 		Name    string `env:"APP_NAME,required"`
 		Version string `env:"APP_VERSION,required"`
+		Env     string `env:"APP_ENV"`
 	}
 
 	// HTTP -.
@@ -39,6 +74,10 @@ type (
 	PG struct {
 		PoolMax int    `env:"PG_POOL_MAX,required"`
 		URL     string `env:"PG_URL,required"`
+		// AcquireTimeout bounds the wait for a free connection; 0 waits for the request context.
+		AcquireTimeout time.Duration `env:"PG_ACQUIRE_TIMEOUT" envDefault:"2s"`
+		// QueryBudget is how many queries a request may run before it is logged; 0 disables the log.
+		QueryBudget int `env:"PG_QUERY_BUDGET" envDefault:"25"`
 	}
 
 	// RMQ -,
@@ -57,12 +96,320 @@ type (
 	Swagger struct {
 		Enabled bool `env:"SWAGGER_ENABLED" envDefault:"false"`
 	}
+
+	// Admin -.
+	Admin struct {
+		Token string `env:"ADMIN_TOKEN"`
+	}
+
+	// OIDC - identity provider whose bearer tokens authenticate /v1/me; the
+	// routes are only mounted when OIDC_ISSUER is set. Signing keys come from
+	// OIDC_JWKS_URL, or the issuer's discovery document when it is empty.
+	OIDC struct {
+		Issuer      string        `env:"OIDC_ISSUER"`
+		Audience    string        `env:"OIDC_AUDIENCE"`
+		JWKSURL     string        `env:"OIDC_JWKS_URL"`
+		JWKSRefresh time.Duration `env:"OIDC_JWKS_REFRESH" envDefault:"1h"`
+	}
+
+	// Integrations - Secret authenticates VCS integrations reporting PR state
+	// to /pullRequest/externalState; the endpoint rejects every call without it.
+	Integrations struct {
+		Sandbox         bool   `env:"INTEGRATIONS_SANDBOX" envDefault:"false"`
+		SandboxCapacity int    `env:"INTEGRATIONS_SANDBOX_CAPACITY" envDefault:"500"`
+		Secret          string `env:"INTEGRATIONS_SECRET"`
+	}
+
+	// Capture - per-team/PR debug capture started via /admin/capture.
+	Capture struct {
+		Capacity    int           `env:"CAPTURE_CAPACITY" envDefault:"1000"`
+		MaxDuration time.Duration `env:"CAPTURE_MAX_DURATION" envDefault:"24h"`
+		// Retention is how long captured entries and expired sessions are kept.
+		Retention time.Duration `env:"CAPTURE_RETENTION" envDefault:"72h"`
+	}
+
+	// IDs -.
+	IDs struct {
+		Generator string `env:"ID_GENERATOR" envDefault:"uuidv7"`
+	}
+
+	// Jobs - scheduling shared by all background jobs.
+	Jobs struct {
+		// Jitter delays each run by a random amount up to this, so instances
+		// started together spread their runs out; 0 runs jobs on the dot.
+		Jitter time.Duration `env:"JOBS_JITTER" envDefault:"5s"`
+	}
+
+	// Tasks - durable Postgres task queue. When enabled, notification sends
+	// and VCS mirroring go through it instead of in-memory queues.
+	Tasks struct {
+		Enabled           bool          `env:"TASKS_ENABLED" envDefault:"false"`
+		Workers           int           `env:"TASKS_WORKERS" envDefault:"4"`
+		PollInterval      time.Duration `env:"TASKS_POLL_INTERVAL" envDefault:"1s"`
+		VisibilityTimeout time.Duration `env:"TASKS_VISIBILITY_TIMEOUT" envDefault:"1m"`
+		MaxAttempts       int           `env:"TASKS_MAX_ATTEMPTS" envDefault:"8"`
+		InitialBackoff    time.Duration `env:"TASKS_INITIAL_BACKOFF" envDefault:"5s"`
+		MaxBackoff        time.Duration `env:"TASKS_MAX_BACKOFF" envDefault:"10m"`
+	}
+
+	// Policy - background housekeeping of users and PRs.
+	Policy struct {
+		Interval       time.Duration `env:"POLICY_INTERVAL" envDefault:"1h"`
+		InactiveAfter  time.Duration `env:"POLICY_INACTIVE_AFTER" envDefault:"0s"`
+		AutoDeactivate bool          `env:"POLICY_AUTO_DEACTIVATE" envDefault:"false"`
+		CloseAfter     time.Duration `env:"POLICY_CLOSE_AFTER" envDefault:"0s"`
+		CloseGrace     time.Duration `env:"POLICY_CLOSE_GRACE" envDefault:"72h"`
+	}
+
+	// Health - thresholds for GET /team/health. ReviewSLA applies to normal
+	// priority PRs; the others have their own.
+	Health struct {
+		ReviewSLA       time.Duration `env:"HEALTH_REVIEW_SLA" envDefault:"48h"`
+		ReviewSLALow    time.Duration `env:"HEALTH_REVIEW_SLA_LOW" envDefault:"96h"`
+		ReviewSLAHigh   time.Duration `env:"HEALTH_REVIEW_SLA_HIGH" envDefault:"24h"`
+		ReviewSLAUrgent time.Duration `env:"HEALTH_REVIEW_SLA_URGENT" envDefault:"4h"`
+		MinCoverage     float64       `env:"HEALTH_MIN_COVERAGE" envDefault:"0.8"`
+		MaxBreachRate   float64       `env:"HEALTH_MAX_BREACH_RATE" envDefault:"0.2"`
+		MaxImbalance    int           `env:"HEALTH_MAX_IMBALANCE" envDefault:"3"`
+	}
+
+	// Reminders - nudges to reviewers who haven't approved, at a cadence set by
+	// PR priority; Interval 0 disables the job, a cadence of 0 skips that priority.
+	Reminders struct {
+		Interval      time.Duration `env:"REMINDER_INTERVAL" envDefault:"0s"`
+		CadenceLow    time.Duration `env:"REMINDER_CADENCE_LOW" envDefault:"72h"`
+		CadenceNormal time.Duration `env:"REMINDER_CADENCE_NORMAL" envDefault:"24h"`
+		CadenceHigh   time.Duration `env:"REMINDER_CADENCE_HIGH" envDefault:"8h"`
+		CadenceUrgent time.Duration `env:"REMINDER_CADENCE_URGENT" envDefault:"2h"`
+	}
+
+	// Absences - how often absences that have started hand their owner's open
+	// reviews over to teammates; 0 leaves it to create/update only.
+	Absences struct {
+		Interval time.Duration `env:"ABSENCE_INTERVAL" envDefault:"5m"`
+	}
+
+	// Usage - per-client API request counters; each process flushes its tally to
+	// Postgres every FlushInterval, and 0 turns tracking off.
+	Usage struct {
+		FlushInterval time.Duration `env:"USAGE_FLUSH_INTERVAL" envDefault:"30s"`
+	}
+
+	// ClientQuota - default request caps per UTC day and calendar month (0
+	// uncaps), applied per remote address to callers without a quota of their
+	// own; clients get their own via /admin/clients/quotas.
+	ClientQuota struct {
+		Enabled bool  `env:"CLIENT_QUOTA_ENABLED" envDefault:"false"`
+		Daily   int64 `env:"CLIENT_QUOTA_DAILY" envDefault:"0"`
+		Monthly int64 `env:"CLIENT_QUOTA_MONTHLY" envDefault:"0"`
+	}
+
+	// Quota - default open-PR limit per author; teams may override it via /team/settings.
+	Quota struct {
+		MaxOpenPRsPerAuthor int    `env:"QUOTA_MAX_OPEN_PRS_PER_AUTHOR" envDefault:"0"`
+		Mode                string `env:"QUOTA_MODE" envDefault:"warn"`
+	}
+
+	// Assignment - default reviewer strategy; teams may override it via /team/settings.
+	// Candidates assigned within Cooldown, or to one of the team's last
+	// CooldownPRs PRs, rank after the others whatever the strategy; 0 turns
+	// either off.
+	Assignment struct {
+		Strategy    string        `env:"ASSIGNMENT_STRATEGY" envDefault:"least_loaded"`
+		Cooldown    time.Duration `env:"ASSIGNMENT_COOLDOWN" envDefault:"0"`
+		CooldownPRs int           `env:"ASSIGNMENT_COOLDOWN_PRS" envDefault:"0"`
+	}
+
+	// Reconcile - how reviewer changes made directly in a VCS are resolved
+	// when they disagree with the assignment.
+	Reconcile struct {
+		Policy string `env:"RECONCILE_POLICY" envDefault:"merge"`
+	}
+
+	// Merge - default approvals a PR needs before /pullRequest/merge accepts it;
+	// teams may override it via /team/settings.
+	Merge struct {
+		RequiredApprovals int `env:"MERGE_REQUIRED_APPROVALS" envDefault:"0"`
+	}
+
+	// RBAC - team management permissions. Reassignments, capacity changes and
+	// settings updates that name an actor_id always require the actor to be the
+	// subject, a team lead or a delegate; Enforce also rejects those without one
+	// and only trusts actors from an OIDC bearer token, not the body's actor_id.
+	RBAC struct {
+		Enforce bool `env:"RBAC_ENFORCE" envDefault:"false"`
+	}
+
+	// Notify - delivery settings shared by all notification providers.
+	Notify struct {
+		// DedupWindow suppresses repeated messages about the same PR to the same
+		// recipient; 0 disables it.
+		DedupWindow time.Duration `env:"NOTIFY_DEDUP_WINDOW" envDefault:"30s"`
+		// TrackDeliveries records what each provider did with each message,
+		// for /admin/notifications.
+		TrackDeliveries bool `env:"NOTIFY_TRACK_DELIVERIES" envDefault:"true"`
+		// FlushInterval is how often tracked deliveries are written to Postgres.
+		FlushInterval time.Duration `env:"NOTIFY_FLUSH_INTERVAL" envDefault:"5s"`
+	}
+
+	// Limiter - bounded concurrency for expensive write paths (PR creation, VCS webhooks).
+	Limiter struct {
+		// MaxInFlight caps concurrent writes; 0 disables the limiter.
+		MaxInFlight  int           `env:"LIMITER_MAX_IN_FLIGHT" envDefault:"0"`
+		QueueTimeout time.Duration `env:"LIMITER_QUEUE_TIMEOUT" envDefault:"100ms"`
+		RetryAfter   time.Duration `env:"LIMITER_RETRY_AFTER" envDefault:"1s"`
+	}
+
+	// Workload - refresh of the reviewer_workload view that assignment reads
+	// open review counts from; 0 leaves refreshing to POST /admin/workload/refresh.
+	Workload struct {
+		RefreshInterval time.Duration `env:"WORKLOAD_REFRESH_INTERVAL" envDefault:"1m"`
+	}
+
+	// StatsHistory - nightly per-team stats snapshot for GET /stats/history,
+	// taken at Hour (UTC).
+	StatsHistory struct {
+		Enabled bool `env:"STATS_SNAPSHOT_ENABLED" envDefault:"true"`
+		Hour    int  `env:"STATS_SNAPSHOT_HOUR" envDefault:"1"`
+	}
+
+	// WeeklyReport - each team's digest of the past week, posted to the team
+	// (its Slack channel, where it has one) on Day (0 is Sunday) at Hour (UTC).
+	WeeklyReport struct {
+		Enabled bool `env:"WEEKLY_REPORT_ENABLED" envDefault:"true"`
+		Day     int  `env:"WEEKLY_REPORT_DAY" envDefault:"1"`
+		Hour    int  `env:"WEEKLY_REPORT_HOUR" envDefault:"9"`
+	}
+
+	// Stats - load shedding: while the average Postgres query latency is over
+	// ShedLatency, and when their query fails, stats endpoints serve their
+	// last result, up to StaleMaxAge old, marked "stale". A zero ShedLatency
+	// turns it off.
+	Stats struct {
+		ShedLatency time.Duration `env:"STATS_SHED_LATENCY" envDefault:"500ms"`
+		StaleMaxAge time.Duration `env:"STATS_STALE_MAX_AGE" envDefault:"1h"`
+	}
+
+	// GitHub - pull_request webhook; disabled when WebhookSecret is empty.
+	// With Token set, reviewer assignments are mirrored as review requests on
+	// the GitHub PRs of MirrorRepos (every linked PR when empty). MirrorLogin,
+	// the account behind Token, keeps the webhook from reconciling its changes.
+	GitHub struct {
+		WebhookSecret   string   `env:"GITHUB_WEBHOOK_SECRET"`
+		Token           string   `env:"GITHUB_TOKEN"`
+		APIURL          string   `env:"GITHUB_API_URL" envDefault:"https://api.github.com"`
+		MirrorRepos     []string `env:"GITHUB_MIRROR_REPOS" envSeparator:","`
+		MirrorQueueSize int      `env:"GITHUB_MIRROR_QUEUE_SIZE" envDefault:"1000"`
+		MirrorWorkers   int      `env:"GITHUB_MIRROR_WORKERS" envDefault:"2"`
+		MirrorLogin     string   `env:"GITHUB_MIRROR_LOGIN"`
+	}
+
+	// GitLab - reviewer assignments mirrored into the reviewer lists of merge
+	// requests, for the projects (paths, "group/name") in MirrorProjects only;
+	// disabled when Token is empty. The merge request webhook, disabled when
+	// WebhookSecret is empty, reconciles reviewer changes made in GitLab except
+	// those of MirrorUsername, the account behind Token.
+	GitLab struct {
+		WebhookSecret   string   `env:"GITLAB_WEBHOOK_SECRET"`
+		MirrorUsername  string   `env:"GITLAB_MIRROR_USERNAME"`
+		Token           string   `env:"GITLAB_TOKEN"`
+		APIURL          string   `env:"GITLAB_API_URL" envDefault:"https://gitlab.com/api/v4"`
+		MirrorProjects  []string `env:"GITLAB_MIRROR_PROJECTS" envSeparator:","`
+		MirrorQueueSize int      `env:"GITLAB_MIRROR_QUEUE_SIZE" envDefault:"1000"`
+		MirrorWorkers   int      `env:"GITLAB_MIRROR_WORKERS" envDefault:"2"`
+	}
+
+	// Bitbucket - Bitbucket Cloud pullrequest webhook; disabled when WebhookSecret is empty.
+	Bitbucket struct {
+		WebhookSecret string `env:"BITBUCKET_WEBHOOK_SECRET"`
+	}
+
+	// ClickHouse - analytics store fed with PR lifecycle events; disabled when URL is empty.
+	ClickHouse struct {
+		URL           string        `env:"CLICKHOUSE_URL"`
+		Database      string        `env:"CLICKHOUSE_DATABASE" envDefault:"default"`
+		User          string        `env:"CLICKHOUSE_USER" envDefault:"default"`
+		Password      string        `env:"CLICKHOUSE_PASSWORD"`
+		BufferSize    int           `env:"CLICKHOUSE_BUFFER_SIZE" envDefault:"10000"`
+		BatchSize     int           `env:"CLICKHOUSE_BATCH_SIZE" envDefault:"500"`
+		FlushInterval time.Duration `env:"CLICKHOUSE_FLUSH_INTERVAL" envDefault:"5s"`
+	}
+
+	// Slack - bot that DMs assigned reviewers; disabled when Token is empty.
+	// Teams may opt out (or in, when DefaultEnabled is false) via /team/settings.
+	Slack struct {
+		Token          string `env:"SLACK_BOT_TOKEN"`
+		APIURL         string `env:"SLACK_API_URL" envDefault:"https://slack.com/api"`
+		DefaultEnabled bool   `env:"SLACK_DEFAULT_ENABLED" envDefault:"true"`
+		// DefaultChannel receives team-addressed messages of teams without
+		// their own slack_channel; empty posts them nowhere.
+		DefaultChannel string `env:"SLACK_DEFAULT_CHANNEL"`
+	}
+
+	// Hooks - delivery of PR events to the webhook URLs teams register.
+	Hooks struct {
+		QueueSize      int           `env:"HOOKS_QUEUE_SIZE" envDefault:"1000"`
+		Workers        int           `env:"HOOKS_WORKERS" envDefault:"4"`
+		MaxAttempts    int           `env:"HOOKS_MAX_ATTEMPTS" envDefault:"5"`
+		InitialBackoff time.Duration `env:"HOOKS_INITIAL_BACKOFF" envDefault:"1s"`
+		MaxBackoff     time.Duration `env:"HOOKS_MAX_BACKOFF" envDefault:"1m"`
+		// PublicURL is this service's externally reachable base URL; receivers
+		// of webhooks with require_ack confirm reviewer.assigned deliveries
+		// under it, so require_ack is refused while it is empty. AckTimeout is how long to wait before sending again, up to
+		// AckMaxAttempts sends, after which the assignment is flagged.
+		PublicURL      string        `env:"HOOKS_PUBLIC_URL"`
+		AckTimeout     time.Duration `env:"HOOKS_ACK_TIMEOUT" envDefault:"5m"`
+		AckMaxAttempts int           `env:"HOOKS_ACK_MAX_ATTEMPTS" envDefault:"3"`
+		AckInterval    time.Duration `env:"HOOKS_ACK_INTERVAL" envDefault:"1m"`
+	}
+
+	// SMTP - email notifications on review assignment and merge; disabled when
+	// Host is empty. Messages go through an async queue of QueueSize.
+	SMTP struct {
+		Host      string `env:"SMTP_HOST"`
+		Port      int    `env:"SMTP_PORT" envDefault:"587"`
+		Username  string `env:"SMTP_USERNAME"`
+		Password  string `env:"SMTP_PASSWORD"`
+		From      string `env:"SMTP_FROM"`
+		QueueSize int    `env:"SMTP_QUEUE_SIZE" envDefault:"1000"`
+		Workers   int    `env:"SMTP_WORKERS" envDefault:"2"`
+	}
+
+	// Telegram - bot used for review notifications; disabled when Token is empty.
+	// WebhookSecret must match the secret_token passed to setWebhook.
+	Telegram struct {
+		Token         string        `env:"TELEGRAM_BOT_TOKEN"`
+		WebhookSecret string        `env:"TELEGRAM_WEBHOOK_SECRET"`
+		APIURL        string        `env:"TELEGRAM_API_URL" envDefault:"https://api.telegram.org"`
+		SnoozeFor     time.Duration `env:"TELEGRAM_SNOOZE_FOR" envDefault:"4h"`
+	}
+
+	// Chaos - fault injection for resilience tests, only allowed with APP_ENV=test.
+	Chaos struct {
+		Enabled bool `env:"CHAOS_ENABLED" envDefault:"false"`
+	}
 )
 
-// NewConfig returns app config.
+// NewConfig returns app config. Defaults come from the APP_ENV profile
+// (dev when unset); variables set in the environment override them.
+// All missing or invalid settings are reported in a single *ValidationError.
 func NewConfig() (*Config, error) {
+	envs, err := environment()
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+
+	// Parse failures don't stop validation, so every problem is reported at once.
+	v := &validator{}
+
 	cfg := &Config{}
-	if err := env.Parse(cfg); err != nil {
+	if err := env.ParseWithOptions(cfg, env.Options{Environment: envs}); err != nil {
+		v.parseProblems(err)
+	}
+
+	cfg.validate(v)
+
+	if err := v.err(); err != nil {
 		return nil, fmt.Errorf("config error: %w", err)
 	}
 
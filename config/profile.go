@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Profiles selected by APP_ENV.
+const (
+	ProfileDev     = "dev"
+	ProfileStaging = "staging"
+	ProfileProd    = "prod"
+	ProfileTest    = "test"
+)
+
+const _defaultProfile = ProfileDev
+
+// profiles hold per-environment defaults. They only fill variables that are
+// not set in the process environment, so explicit values always win.
+var profiles = map[string]map[string]string{
+	ProfileDev: {
+		"LOG_LEVEL":             "debug",
+		"HTTP_USE_PREFORK_MODE": "false",
+		"INTEGRATIONS_SANDBOX":  "true",
+		"SWAGGER_ENABLED":       "true",
+	},
+	ProfileStaging: {
+		"LOG_LEVEL":             "info",
+		"HTTP_USE_PREFORK_MODE": "false",
+		"INTEGRATIONS_SANDBOX":  "true",
+		"SWAGGER_ENABLED":       "true",
+	},
+	ProfileProd: {
+		"LOG_LEVEL":             "warn",
+		"HTTP_USE_PREFORK_MODE": "false",
+		"INTEGRATIONS_SANDBOX":  "false",
+		"SWAGGER_ENABLED":       "false",
+	},
+	ProfileTest: {
+		"LOG_LEVEL":             "debug",
+		"HTTP_USE_PREFORK_MODE": "false",
+		"INTEGRATIONS_SANDBOX":  "true",
+		"SWAGGER_ENABLED":       "false",
+	},
+}
+
+// environment returns the process environment layered over the defaults of
+// the APP_ENV profile.
+func environment() (map[string]string, error) {
+	name := os.Getenv("APP_ENV")
+	if name == "" {
+		name = _defaultProfile
+	}
+
+	defaults, ok := profiles[name]
+	if !ok {
+		known := make([]string, 0, len(profiles))
+		for k := range profiles {
+			known = append(known, k)
+		}
+		sort.Strings(known)
+
+		return nil, fmt.Errorf("unknown APP_ENV %q (expected one of %s)", name, strings.Join(known, ", "))
+	}
+
+	envs := make(map[string]string, len(defaults))
+	for k, v := range defaults {
+		envs[k] = v
+	}
+
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			envs[k] = v
+		}
+	}
+
+	envs["APP_ENV"] = name
+
+	return envs, nil
+}
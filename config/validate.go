@@ -0,0 +1,289 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// Problem is a single invalid or missing setting.
+type Problem struct {
+	Var    string
+	Reason string
+}
+
+func (p Problem) Error() string {
+	return p.Var + ": " + p.Reason
+}
+
+// ValidationError lists every problem found, so operators can fix them in one go.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("%d invalid setting(s):", len(e.Problems)))
+
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap exposes the individual problems to errors.As.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		errs = append(errs, p)
+	}
+
+	return errs
+}
+
+type validator struct {
+	problems []Problem
+}
+
+// add records a problem; only the first problem per variable is kept, so a
+// value that failed to parse isn't reported again by the range checks.
+func (v *validator) add(name, format string, args ...any) {
+	for _, p := range v.problems {
+		if p.Var == name {
+			return
+		}
+	}
+
+	v.problems = append(v.problems, Problem{Var: name, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) check(ok bool, name, format string, args ...any) {
+	if !ok {
+		v.add(name, format, args...)
+	}
+}
+
+func (v *validator) oneOf(name, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	v.add(name, "%q is not one of %s", value, strings.Join(allowed, ", "))
+}
+
+func (v *validator) url(name, value string, schemes ...string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		v.add(name, "%q is not a valid URL", value)
+		return
+	}
+
+	v.oneOf(name+" scheme", u.Scheme, schemes...)
+}
+
+// parseProblems turns env parsing failures into problems.
+func (v *validator) parseProblems(err error) {
+	var agg env.AggregateError
+	if !errors.As(err, &agg) {
+		v.add("env", "%v", err)
+		return
+	}
+
+	for _, e := range agg.Errors {
+		var notSet env.VarIsNotSetError
+		var empty env.EmptyVarError
+		var parse env.ParseError
+
+		switch {
+		case errors.As(e, &notSet):
+			v.add(notSet.Key, "required but not set")
+		case errors.As(e, &empty):
+			v.add(empty.Key, "must not be empty")
+		case errors.As(e, &parse):
+			v.add(envKey(parse.Name), "cannot parse as %s: %v", parse.Type, parse.Err)
+		default:
+			v.add("env", "%v", e)
+		}
+	}
+}
+
+// validate checks value ranges, formats and combinations of settings.
+func (c *Config) validate(v *validator) {
+	if port, err := strconv.Atoi(c.HTTP.Port); err != nil || port < 1 || port > 65535 {
+		v.add("HTTP_PORT", "%q is not a port in 1..65535", c.HTTP.Port)
+	}
+
+	v.oneOf("LOG_LEVEL", c.Log.Level, "debug", "info", "warn", "error")
+
+	v.check(c.PG.PoolMax > 0, "PG_POOL_MAX", "must be positive, got %d", c.PG.PoolMax)
+	v.url("PG_URL", c.PG.URL, "postgres", "postgresql")
+	v.check(c.PG.AcquireTimeout >= 0, "PG_ACQUIRE_TIMEOUT", "must not be negative")
+	v.check(c.PG.QueryBudget >= 0, "PG_QUERY_BUDGET", "must not be negative")
+	v.url("RMQ_URL", c.RMQ.URL, "amqp", "amqps")
+
+	v.check(c.Integrations.SandboxCapacity > 0, "INTEGRATIONS_SANDBOX_CAPACITY", "must be positive, got %d", c.Integrations.SandboxCapacity)
+	v.check(c.Capture.Capacity > 0, "CAPTURE_CAPACITY", "must be positive, got %d", c.Capture.Capacity)
+	v.check(c.Capture.MaxDuration > 0, "CAPTURE_MAX_DURATION", "must be positive, got %s", c.Capture.MaxDuration)
+	v.check(c.Capture.Retention > 0, "CAPTURE_RETENTION", "must be positive, got %s", c.Capture.Retention)
+	v.oneOf("ID_GENERATOR", c.IDs.Generator, "uuidv7", "ulid")
+
+	v.check(c.Jobs.Jitter >= 0, "JOBS_JITTER", "must not be negative")
+	if c.Tasks.Enabled {
+		v.check(c.Tasks.Workers > 0, "TASKS_WORKERS", "must be positive, got %d", c.Tasks.Workers)
+		v.check(c.Tasks.PollInterval > 0, "TASKS_POLL_INTERVAL", "must be positive")
+		v.check(c.Tasks.VisibilityTimeout > 0, "TASKS_VISIBILITY_TIMEOUT", "must be positive")
+		v.check(c.Tasks.MaxAttempts > 0, "TASKS_MAX_ATTEMPTS", "must be positive, got %d", c.Tasks.MaxAttempts)
+		v.check(c.Tasks.InitialBackoff > 0 && c.Tasks.InitialBackoff <= c.Tasks.MaxBackoff,
+			"TASKS_INITIAL_BACKOFF", "must be positive and not exceed TASKS_MAX_BACKOFF")
+	}
+	v.check(c.Policy.InactiveAfter >= 0, "POLICY_INACTIVE_AFTER", "must not be negative")
+	v.check(c.Policy.CloseAfter >= 0, "POLICY_CLOSE_AFTER", "must not be negative")
+	v.check(c.Policy.CloseGrace >= 0, "POLICY_CLOSE_GRACE", "must not be negative")
+
+	jobs := c.Policy.InactiveAfter > 0 || c.Policy.CloseAfter > 0
+	if jobs {
+		v.check(c.Policy.Interval > 0, "POLICY_INTERVAL", "must be positive when policy jobs are enabled")
+	}
+
+	v.check(c.Health.ReviewSLA > 0, "HEALTH_REVIEW_SLA", "must be positive")
+	v.check(c.Health.ReviewSLALow > 0, "HEALTH_REVIEW_SLA_LOW", "must be positive")
+	v.check(c.Health.ReviewSLAHigh > 0, "HEALTH_REVIEW_SLA_HIGH", "must be positive")
+	v.check(c.Health.ReviewSLAUrgent > 0, "HEALTH_REVIEW_SLA_URGENT", "must be positive")
+	v.check(c.Health.MinCoverage >= 0 && c.Health.MinCoverage <= 1, "HEALTH_MIN_COVERAGE", "must be within 0..1, got %v", c.Health.MinCoverage)
+	v.check(c.Health.MaxBreachRate >= 0 && c.Health.MaxBreachRate <= 1, "HEALTH_MAX_BREACH_RATE", "must be within 0..1, got %v", c.Health.MaxBreachRate)
+	v.check(c.Health.MaxImbalance >= 0, "HEALTH_MAX_IMBALANCE", "must not be negative")
+
+	v.check(c.Reminders.Interval >= 0, "REMINDER_INTERVAL", "must not be negative")
+	v.check(c.Reminders.CadenceLow >= 0, "REMINDER_CADENCE_LOW", "must not be negative")
+	v.check(c.Reminders.CadenceNormal >= 0, "REMINDER_CADENCE_NORMAL", "must not be negative")
+	v.check(c.Reminders.CadenceHigh >= 0, "REMINDER_CADENCE_HIGH", "must not be negative")
+	v.check(c.Reminders.CadenceUrgent >= 0, "REMINDER_CADENCE_URGENT", "must not be negative")
+	v.check(c.Absences.Interval >= 0, "ABSENCE_INTERVAL", "must not be negative")
+	v.check(c.Usage.FlushInterval >= 0, "USAGE_FLUSH_INTERVAL", "must not be negative")
+	v.check(c.ClientQuota.Daily >= 0, "CLIENT_QUOTA_DAILY", "must not be negative")
+	v.check(c.ClientQuota.Monthly >= 0, "CLIENT_QUOTA_MONTHLY", "must not be negative")
+
+	v.check(c.Quota.MaxOpenPRsPerAuthor >= 0, "QUOTA_MAX_OPEN_PRS_PER_AUTHOR", "must not be negative")
+	v.oneOf("QUOTA_MODE", c.Quota.Mode, "warn", "enforce")
+	v.oneOf("ASSIGNMENT_STRATEGY", c.Assignment.Strategy, "least_loaded", "round_robin", "random")
+	v.check(c.Assignment.Cooldown >= 0, "ASSIGNMENT_COOLDOWN", "must not be negative")
+	v.check(c.Assignment.CooldownPRs >= 0, "ASSIGNMENT_COOLDOWN_PRS", "must not be negative, got %d", c.Assignment.CooldownPRs)
+	v.oneOf("RECONCILE_POLICY", c.Reconcile.Policy, "prefer_external", "prefer_internal", "merge")
+	v.check(c.Merge.RequiredApprovals >= 0, "MERGE_REQUIRED_APPROVALS", "must not be negative")
+
+	v.check(c.Notify.DedupWindow >= 0, "NOTIFY_DEDUP_WINDOW", "must not be negative")
+	v.check(c.Notify.FlushInterval > 0, "NOTIFY_FLUSH_INTERVAL", "must be positive, got %s", c.Notify.FlushInterval)
+
+	v.check(c.Limiter.MaxInFlight >= 0, "LIMITER_MAX_IN_FLIGHT", "must not be negative")
+	v.check(c.Limiter.QueueTimeout >= 0, "LIMITER_QUEUE_TIMEOUT", "must not be negative")
+	v.check(c.Limiter.RetryAfter >= 0, "LIMITER_RETRY_AFTER", "must not be negative")
+
+	v.check(c.Workload.RefreshInterval >= 0, "WORKLOAD_REFRESH_INTERVAL", "must not be negative")
+	v.check(c.StatsHistory.Hour >= 0 && c.StatsHistory.Hour < 24, "STATS_SNAPSHOT_HOUR", "must be between 0 and 23, got %d", c.StatsHistory.Hour)
+	v.check(c.WeeklyReport.Day >= 0 && c.WeeklyReport.Day < 7, "WEEKLY_REPORT_DAY", "must be between 0 and 6, got %d", c.WeeklyReport.Day)
+	v.check(c.WeeklyReport.Hour >= 0 && c.WeeklyReport.Hour < 24, "WEEKLY_REPORT_HOUR", "must be between 0 and 23, got %d", c.WeeklyReport.Hour)
+	v.check(c.Stats.ShedLatency >= 0, "STATS_SHED_LATENCY", "must not be negative")
+	v.check(c.Stats.StaleMaxAge > 0, "STATS_STALE_MAX_AGE", "must be positive")
+
+	if c.ClickHouse.URL != "" {
+		v.url("CLICKHOUSE_URL", c.ClickHouse.URL, "http", "https")
+		v.check(c.ClickHouse.BufferSize > 0, "CLICKHOUSE_BUFFER_SIZE", "must be positive, got %d", c.ClickHouse.BufferSize)
+		v.check(c.ClickHouse.BatchSize > 0, "CLICKHOUSE_BATCH_SIZE", "must be positive, got %d", c.ClickHouse.BatchSize)
+		v.check(c.ClickHouse.FlushInterval > 0, "CLICKHOUSE_FLUSH_INTERVAL", "must be positive")
+	}
+
+	v.check(c.Hooks.QueueSize > 0, "HOOKS_QUEUE_SIZE", "must be positive, got %d", c.Hooks.QueueSize)
+	v.check(c.Hooks.Workers > 0, "HOOKS_WORKERS", "must be positive, got %d", c.Hooks.Workers)
+	v.check(c.Hooks.MaxAttempts > 0, "HOOKS_MAX_ATTEMPTS", "must be positive, got %d", c.Hooks.MaxAttempts)
+	v.check(c.Hooks.InitialBackoff > 0 && c.Hooks.InitialBackoff <= c.Hooks.MaxBackoff,
+		"HOOKS_INITIAL_BACKOFF", "must be positive and not exceed HOOKS_MAX_BACKOFF")
+	if c.Hooks.PublicURL != "" {
+		v.url("HOOKS_PUBLIC_URL", c.Hooks.PublicURL, "http", "https")
+	}
+	v.check(c.Hooks.AckTimeout > 0, "HOOKS_ACK_TIMEOUT", "must be positive")
+	v.check(c.Hooks.AckMaxAttempts > 0, "HOOKS_ACK_MAX_ATTEMPTS", "must be positive, got %d", c.Hooks.AckMaxAttempts)
+	v.check(c.Hooks.AckInterval >= 0, "HOOKS_ACK_INTERVAL", "must not be negative")
+
+	if c.SMTP.Host != "" {
+		v.check(c.SMTP.Port > 0 && c.SMTP.Port <= 65535, "SMTP_PORT", "must be a valid port, got %d", c.SMTP.Port)
+		v.check(c.SMTP.From != "", "SMTP_FROM", "required when SMTP_HOST is set")
+		v.check(c.SMTP.QueueSize > 0, "SMTP_QUEUE_SIZE", "must be positive, got %d", c.SMTP.QueueSize)
+		v.check(c.SMTP.Workers > 0, "SMTP_WORKERS", "must be positive, got %d", c.SMTP.Workers)
+	}
+
+	if c.OIDC.Issuer != "" {
+		v.url("OIDC_ISSUER", c.OIDC.Issuer, "http", "https")
+		if c.OIDC.JWKSURL != "" {
+			v.url("OIDC_JWKS_URL", c.OIDC.JWKSURL, "http", "https")
+		}
+		v.check(c.OIDC.JWKSRefresh > 0, "OIDC_JWKS_REFRESH", "must be positive")
+		v.check(c.OIDC.Audience != "", "OIDC_AUDIENCE", "required when OIDC_ISSUER is set")
+	}
+
+	if c.Slack.Token != "" {
+		v.url("SLACK_API_URL", c.Slack.APIURL, "http", "https")
+	}
+
+	if c.GitHub.Token != "" {
+		v.url("GITHUB_API_URL", c.GitHub.APIURL, "http", "https")
+		v.check(c.GitHub.MirrorQueueSize > 0, "GITHUB_MIRROR_QUEUE_SIZE", "must be positive, got %d", c.GitHub.MirrorQueueSize)
+		v.check(c.GitHub.MirrorWorkers > 0, "GITHUB_MIRROR_WORKERS", "must be positive, got %d", c.GitHub.MirrorWorkers)
+	}
+
+	if c.GitLab.Token != "" {
+		v.url("GITLAB_API_URL", c.GitLab.APIURL, "http", "https")
+		v.check(len(c.GitLab.MirrorProjects) > 0, "GITLAB_MIRROR_PROJECTS", "required when GITLAB_TOKEN is set")
+		v.check(c.GitLab.MirrorQueueSize > 0, "GITLAB_MIRROR_QUEUE_SIZE", "must be positive, got %d", c.GitLab.MirrorQueueSize)
+		v.check(c.GitLab.MirrorWorkers > 0, "GITLAB_MIRROR_WORKERS", "must be positive, got %d", c.GitLab.MirrorWorkers)
+	}
+
+	if c.Telegram.Token != "" {
+		v.check(c.Telegram.WebhookSecret != "", "TELEGRAM_WEBHOOK_SECRET", "required when TELEGRAM_BOT_TOKEN is set")
+		v.url("TELEGRAM_API_URL", c.Telegram.APIURL, "http", "https")
+		v.check(c.Telegram.SnoozeFor > 0, "TELEGRAM_SNOOZE_FOR", "must be positive")
+	}
+
+	if c.Chaos.Enabled && c.App.Env != ProfileTest {
+		v.add("CHAOS_ENABLED", "may only be enabled with APP_ENV=test, got %s", c.App.Env)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Problems: v.problems}
+}
+
+// envKey maps a Config field name to its env variable; ambiguous names
+// (e.g. Enabled) are returned as is.
+func envKey(field string) string {
+	var keys []string
+
+	sections := reflect.TypeOf(Config{})
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i).Type
+		for j := 0; j < section.NumField(); j++ {
+			f := section.Field(j)
+			if f.Name == field {
+				key, _, _ := strings.Cut(f.Tag.Get("env"), ",")
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	if len(keys) != 1 {
+		return field
+	}
+
+	return keys[0]
+}
@@ -3,11 +3,14 @@ package integration_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -93,15 +96,23 @@ func TestE2EFlow(t *testing.T) {
 
 	t.Log("Step 2: Getting team 'backend4' info...")
 	doRequest(t, "GET", basePathV1+"/team/get?team_name=backend4", "", 200)
+	doRequest(t, "GET", basePathV1+"/team/list?limit=1", "", 200)
+	doRequest(t, "GET", basePathV1+"/team/list?include_members=false", "", 200)
+	doRequest(t, "GET", basePathV1+"/team/list?include_members=maybe", "", 400)
 	t.Log("Team info retrieved successfully")
 
 	t.Log("Step 3: Creating first PR...")
 	prBody := `{"pull_request_id":"pr-1024","pull_request_name":"Test PR","author_id":"u1"}`
 	doRequest(t, "POST", basePathV1+"/pullRequest/create", prBody, 201)
 	t.Log("First PR created successfully")
+	doRequest(t, "GET", basePathV1+"/pullRequest/get?pull_request_id=pr-1024", "", 200)
+	doRequest(t, "GET", basePathV1+"/pullRequest/get?pull_request_id=pr-1024&fields=status,assigned_reviewers", "", 200)
+	doRequest(t, "GET", basePathV1+"/pullRequest/get?pull_request_id=not-exist", "", 404)
+	doRequest(t, "GET", basePathV1+"/pullRequest/get", "", 400)
 
 	t.Log("👥 Step 4: Checking assigned reviews...")
-	doRequest(t, "GET", basePathV1+"/users/getReview?user_id=u2", "", 200)
+	resp := doRequest(t, "GET", basePathV1+"/users/getReview?user_id=u2", "", 200)
+	assertList(t, resp, "user_id")
 	t.Log("Reviews for u2 checked successfully")
 	doRequest(t, "GET", basePathV1+"/users/getReview?user_id=u3", "", 200)
 	t.Log("Reviews for u3 checked successfully")
@@ -135,8 +146,41 @@ func TestE2EFlow(t *testing.T) {
 	doRequest(t, "POST", basePathV1+"/pullRequest/create", badPR, 404)
 	t.Log("Properly handled non-existent author case")
 
+	t.Log("Step 10a: Listing PRs with filters...")
+	resp = doRequest(t, "GET", basePathV1+"/pullRequest/list?status=OPEN&author_id=u1&sort=oldest", "", 200)
+	assertList(t, resp)
+	doRequest(t, "GET", basePathV1+"/pullRequest/list?created_after=2024-01-01T00:00:00Z&sort=updated", "", 200)
+	doRequest(t, "GET", basePathV1+"/pullRequest/list?status=open", "", 400)
+	doRequest(t, "GET", basePathV1+"/pullRequest/list?sort=random", "", 400)
+	doRequest(t, "GET", basePathV1+"/pullRequest/search?q=Test&limit=5", "", 200)
+	doRequest(t, "GET", basePathV1+"/pullRequest/search?q=%20", "", 400)
+
+	t.Log("Step 10b: Creating a stacked PR...")
+	doRequest(t, "POST", basePathV1+"/team/settings", `{"team_name":"backend4","stack_reviewers":"sometimes"}`, 400)
+	doRequest(t, "POST", basePathV1+"/team/settings", `{"team_name":"backend4","clear":["team_name"]}`, 400)
+	doRequest(t, "POST", basePathV1+"/pullRequest/create", `{"pull_request_id":"pr-1027","pull_request_name":"Stacked PR","author_id":"u1","stack_id":"search-v2"}`, 201)
+
 	t.Log("Step 11: Getting system stats...")
 	doRequest(t, "GET", basePathV1+"/stats", "", 200)
+	doRequest(t, "GET", basePathV1+"/stats?bucket=week", "", 200)
+	doRequest(t, "GET", basePathV1+"/stats?bucket=month", "", 400)
+	doRequest(t, "GET", basePathV1+"/stats?format=csv", "", 200)
+	doRequest(t, "GET", basePathV1+"/stats?format=xlsx&dataset=users", "", 200)
+	doRequest(t, "GET", basePathV1+"/stats?format=pdf", "", 400)
+	doRequest(t, "GET", basePathV1+"/stats/user?user_id=u2", "", 200)
+	doRequest(t, "GET", basePathV1+"/stats/user?user_id=not-exist", "", 404)
+	resp = doRequest(t, "GET", basePathV1+"/stats/sla", "", 200)
+	assertList(t, resp, "from", "to", "stale")
+	doRequest(t, "GET", basePathV1+"/stats/sla?from=2024-02-01&to=2024-01-01", "", 400)
+	resp = doRequest(t, "GET", basePathV1+"/stats/leaderboard?sort=open", "", 200)
+	assertList(t, resp, "from", "to", "stale")
+	resp = doRequest(t, "GET", basePathV1+"/stats/history?team_name=backend4", "", 200)
+	assertList(t, resp, "from", "to")
+	resp = doRequest(t, "GET", basePathV1+"/teams?limit=1", "", 200)
+	assertList(t, resp)
+	resp = doRequest(t, "GET", basePathV1+"/users?limit=2", "", 200)
+	assertList(t, resp)
+	doRequest(t, "GET", basePathV1+"/stats/leaderboard?sort=karma", "", 400)
 	t.Log("Stats retrieved successfully")
 
 	t.Log("Step 12: Health check...")
@@ -159,6 +203,14 @@ func TestAdditionalScenarios(t *testing.T) {
 	doRequest(t, "POST", basePathV1+"/team/add", teamBody, 400)
 	t.Log("Duplicate team creation properly rejected")
 
+	t.Log("Testing invalid team entities...")
+	doRequest(t, "POST", basePathV1+"/team/add", `{"team_name": "twice-team", "members": [
+		{"user_id": "twice-user", "username": "Twice", "is_active": true},
+		{"user_id": "twice-user", "username": "Twice", "is_active": true}
+	]}`, 400)
+	doRequest(t, "POST", basePathV1+"/team/add", `{"team_name": " ", "members": []}`, 400)
+	t.Log("Invalid teams properly rejected")
+
 	t.Log("🔍 Testing non-existent team...")
 	doRequest(t, "GET", basePathV1+"/team/get?team_name=nonexistent", "", 404)
 	t.Log("Non-existent team properly handled")
@@ -171,7 +223,7 @@ func TestAdditionalScenarios(t *testing.T) {
 	t.Log("Additional scenarios completed successfully!")
 }
 
-func doRequest(t *testing.T, method, url, body string, wantStatus int) *http.Response {
+func doRequest(t testing.TB, method, url, body string, wantStatus int) *http.Response {
 	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
 	if err != nil {
 		t.Fatalf("Request creation error: %v", err)
@@ -201,6 +253,35 @@ func doRequest(t *testing.T, method, url, body string, wantStatus int) *http.Res
 	return resp
 }
 
+// assertList checks that resp is a list envelope: items, next_cursor and
+// total_estimate, plus the context fields named in keys.
+func assertList(t testing.TB, resp *http.Response, keys ...string) {
+	t.Helper()
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Decoding list response: %v", err)
+	}
+
+	if _, ok := body["items"].([]any); !ok {
+		t.Fatalf("items is not an array: %v", body)
+	}
+
+	if _, ok := body["next_cursor"].(string); !ok {
+		t.Fatalf("next_cursor is not a string: %v", body)
+	}
+
+	if _, ok := body["total_estimate"].(float64); !ok {
+		t.Fatalf("total_estimate is not a number: %v", body)
+	}
+
+	for _, k := range keys {
+		if _, ok := body[k]; !ok {
+			t.Fatalf("%s is missing: %v", k, body)
+		}
+	}
+}
+
 func TestEdgeCases(t *testing.T) {
 	t.Log("Starting edge cases test...")
 
@@ -222,5 +303,91 @@ func TestEdgeCases(t *testing.T) {
 	doRequest(t, "POST", basePathV1+"/users/setIsActive", badUserBody, 404)
 	t.Log("Non-existent user properly handled")
 
+	t.Log("Testing close and reopen with a stale expected_version...")
+	doRequest(t, "POST", basePathV1+"/pullRequest/create", `{"pull_request_id":"edge-pr-2","pull_request_name":"Edge PR 2","author_id":"u1"}`, 201)
+	stale := `{"pull_request_id":"edge-pr-2","expected_version":"2000-01-01T00:00:00Z"}`
+	doRequest(t, "POST", basePathV1+"/pullRequest/close", stale, 412)
+	doRequest(t, "POST", basePathV1+"/pullRequest/close", `{"pull_request_id":"edge-pr-2"}`, 200)
+	doRequest(t, "POST", basePathV1+"/pullRequest/reopen", stale, 412)
+	doRequest(t, "POST", basePathV1+"/pullRequest/reopen", `{"pull_request_id":"edge-pr-2"}`, 200)
+	t.Log("Stale close and reopen properly rejected")
+
 	t.Log("Edge cases completed successfully!")
 }
+
+func TestOutgoingWebhookNonPublic(t *testing.T) {
+	t.Log("Starting non-public webhook test...")
+
+	hits := make(chan struct{}, 1)
+	ln, err := net.Listen("tcp", ":8090")
+	if err != nil {
+		t.Fatalf("Listening for deliveries: %v", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		select {
+		case hits <- struct{}{}:
+		default:
+		}
+		w.WriteHeader(http.StatusOK)
+	}), ReadHeaderTimeout: requestTimeout}
+	go srv.Serve(ln) //nolint:errcheck // closed below
+	defer srv.Close()
+
+	doRequest(t, "POST", basePathV1+"/team/add", `{"team_name": "hooks-team", "members": [
+		{"user_id": "hooks-lead", "username": "Lead", "is_active": true, "role": "lead"},
+		{"user_id": "hooks-u1", "username": "Dev", "is_active": true},
+		{"user_id": "hooks-u2", "username": "Reviewer", "is_active": true}
+	]}`, 201)
+
+	t.Log("Testing webhooks at non-public IP addresses are rejected...")
+	for _, url := range []string{"http://127.0.0.1:8090/hook", "http://169.254.169.254/latest", "http://10.0.0.1/hook", "http://[::1]:8090/hook"} {
+		doRequest(t, "POST", basePathV1+"/team/webhooks/add",
+			fmt.Sprintf(`{"team_name":"hooks-team","url":%q,"actor_id":"hooks-lead"}`, url), 400)
+	}
+
+	t.Log("Testing a hostname resolving to a non-public address gets no deliveries...")
+	doRequest(t, "POST", basePathV1+"/team/webhooks/add",
+		`{"team_name":"hooks-team","url":"http://test.lvh.me:8090/hook","actor_id":"hooks-lead"}`, 201)
+	doRequest(t, "POST", basePathV1+"/pullRequest/create",
+		`{"pull_request_id":"hooks-pr-1","pull_request_name":"Hooks PR","author_id":"hooks-u1"}`, 201)
+
+	select {
+	case <-hits:
+		t.Fatal("Event was delivered to a non-public address")
+	case <-time.After(3 * time.Second):
+	}
+
+	t.Log("Non-public webhook test completed successfully!")
+}
+
+// BenchmarkCreatePRLargeTeam measures PR creation (candidate query included)
+// for an author whose team has thousands of members.
+func BenchmarkCreatePRLargeTeam(b *testing.B) {
+	const members = 3000
+
+	prefix := fmt.Sprintf("bench-%d", time.Now().UnixNano())
+
+	var team strings.Builder
+
+	fmt.Fprintf(&team, `{"team_name": "%s", "members": [`, prefix)
+
+	for i := range members {
+		if i > 0 {
+			team.WriteString(",")
+		}
+
+		fmt.Fprintf(&team, `{"user_id": "%s-u%d", "username": "Bench %d", "is_active": %t}`, prefix, i, i, i%10 != 0)
+	}
+
+	team.WriteString(`]}`)
+
+	resp := doRequest(b, "POST", basePathV1+"/team/add", team.String(), 201)
+	resp.Body.Close()
+
+	for i := 0; b.Loop(); i++ {
+		body := fmt.Sprintf(`{"pull_request_id":"%s-pr%d","pull_request_name":"Bench PR","author_id":"%s-u%d"}`, prefix, i, prefix, 1+i%(members-1))
+
+		resp := doRequest(b, "POST", basePathV1+"/pullRequest/create", body, 201)
+		resp.Body.Close()
+	}
+}
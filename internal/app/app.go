@@ -2,49 +2,432 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	nethttp "net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/evrone/go-clean-template/config"
+	bbproc "github.com/evrone/go-clean-template/internal/controller/bitbucket"
+	ghproc "github.com/evrone/go-clean-template/internal/controller/github"
+	glproc "github.com/evrone/go-clean-template/internal/controller/gitlab"
 	http "github.com/evrone/go-clean-template/internal/controller/http"
+	tgbot "github.com/evrone/go-clean-template/internal/controller/telegram"
+	"github.com/evrone/go-clean-template/internal/entity"
+	chaosrepo "github.com/evrone/go-clean-template/internal/repo/chaos"
+	chrepo "github.com/evrone/go-clean-template/internal/repo/clickhouse"
 	pgrepo "github.com/evrone/go-clean-template/internal/repo/postgres"
 	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/capture"
+	"github.com/evrone/go-clean-template/pkg/chaos"
+	"github.com/evrone/go-clean-template/pkg/clickhouse"
+	"github.com/evrone/go-clean-template/pkg/github"
+	"github.com/evrone/go-clean-template/pkg/gitlab"
 	"github.com/evrone/go-clean-template/pkg/httpserver"
+	"github.com/evrone/go-clean-template/pkg/idgen"
 	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/evrone/go-clean-template/pkg/mail"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+	"github.com/evrone/go-clean-template/pkg/oidc"
 	"github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/evrone/go-clean-template/pkg/sandbox"
+	"github.com/evrone/go-clean-template/pkg/scheduler"
+	"github.com/evrone/go-clean-template/pkg/slack"
+	"github.com/evrone/go-clean-template/pkg/taskqueue"
+	"github.com/evrone/go-clean-template/pkg/telegram"
+	"github.com/evrone/go-clean-template/pkg/webhook"
 )
 
 func Run(cfg *config.Config) {
 	l := logger.New(cfg.Log.Level)
 
-	pg, err := postgres.New(cfg.PG.URL, postgres.MaxPoolSize(cfg.PG.PoolMax))
+	// Module loggers; their levels can be changed at runtime via /admin/loglevel.
+	repoLog, httpLog, notifyLog, jobsLog := l.Module("repo"), l.Module("http"), l.Module("notify"), l.Module("jobs")
+
+	pg, err := postgres.New(cfg.PG.URL,
+		postgres.MaxPoolSize(cfg.PG.PoolMax),
+		postgres.AcquireTimeout(cfg.PG.AcquireTimeout),
+	)
 	if err != nil {
 		l.Fatal(fmt.Errorf("app - Run - postgres.New: %w", err))
 	}
 	defer pg.Close()
 
-	pgRepo, err := pgrepo.NewWithPool(pg.Pool)
+	pgRepo, err := pgrepo.NewWithPool(pg.DB())
 	if err != nil {
 		l.Fatal(fmt.Errorf("app - Run - postgres.NewWithPool: %w", err))
 	}
 
-	userRepo := pgRepo.UserRepo()
-	teamRepo := pgRepo.TeamRepo()
-	prRepo := pgRepo.PRRepo()
+	var (
+		userRepo usecase.UserRepo = pgRepo.UserRepo()
+		teamRepo usecase.TeamRepo = pgRepo.TeamRepo()
+		prRepo   usecase.PRRepo   = pgRepo.PRRepo()
+	)
+
+	// Chaos: fault injection around repositories and the notifier for resilience tests
+	var injector *chaos.Injector
+	if cfg.Chaos.Enabled {
+		injector = chaos.New(chaosrepo.Targets...)
+		userRepo = chaosrepo.NewUserRepo(userRepo, injector)
+		teamRepo = chaosrepo.NewTeamRepo(teamRepo, injector)
+		prRepo = chaosrepo.NewPRRepo(prRepo, injector)
+		l.Warn("app - Run - chaos fault injection enabled")
+	}
+
+	webhookRepo := pgRepo.WebhookDeliveryRepo()
+
+	idGen, err := idgen.New(cfg.IDs.Generator)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - idgen.New: %w", err))
+	}
+
+	identityRepo := pgRepo.IdentityRepo()
+	auditRepo := pgRepo.AuditRepo()
+
+	// Debug capture is idle until a session is started via /admin/capture.
+	captureRecorder := capture.NewRecorder(cfg.Capture.Capacity, cfg.Capture.MaxDuration)
+
+	// Sandbox: outbound integrations are captured instead of delivered
+	var sandboxRecorder *sandbox.Recorder
+	integrationsClient := &nethttp.Client{Timeout: 10 * time.Second}
+	if cfg.Integrations.Sandbox {
+		sandboxRecorder = sandbox.NewRecorder(cfg.Integrations.SandboxCapacity)
+		integrationsClient = sandbox.NewClient(sandboxRecorder)
+		l.Info("app - Run - integrations sandbox mode enabled")
+	}
+
+	// Durable task queue; its handlers are registered below, before it starts
+	var tasks *taskqueue.Queue
+	if cfg.Tasks.Enabled {
+		tasks = taskqueue.New(pgRepo.TaskRepo(), jobsLog,
+			taskqueue.Workers(cfg.Tasks.Workers),
+			taskqueue.PollInterval(cfg.Tasks.PollInterval),
+			taskqueue.VisibilityTimeout(cfg.Tasks.VisibilityTimeout),
+			taskqueue.MaxAttempts(cfg.Tasks.MaxAttempts),
+			taskqueue.Backoff(cfg.Tasks.InitialBackoff, cfg.Tasks.MaxBackoff),
+		)
+	}
+
+	// Notifications
+	notificationLog := usecase.NewNotificationLogUseCase(pgRepo.NotificationLogRepo())
+	track := func(provider string, n notifier.Notifier) notifier.Notifier {
+		if !cfg.Notify.TrackDeliveries {
+			return n
+		}
+		return notifier.NewTracked(provider, n, func(d notifier.Delivery) {
+			notificationLog.Record(entity.NotificationDelivery{
+				Provider:      d.Provider,
+				Kind:          d.Message.Kind,
+				UserID:        d.Message.UserID,
+				TeamName:      d.Message.TeamName,
+				PullRequestID: d.Message.PullRequestID,
+				Status:        d.Status,
+				Detail:        d.Detail,
+				DurationMs:    float64(d.Duration) / float64(time.Millisecond),
+				CreatedAt:     d.At,
+			})
+		})
+	}
+
+	// queued sends a provider's messages through the task queue, if enabled.
+	queued := func(provider string, n notifier.Notifier) notifier.Notifier {
+		if tasks == nil {
+			return n
+		}
+		return notifier.NewQueued(tasks, "notify."+provider, n)
+	}
+
+	notifiers := notifier.Multi{notifier.NewLog(notifyLog)}
+
+	var tgClient *telegram.Client
+	if cfg.Telegram.Token != "" {
+		tgClient = telegram.New(cfg.Telegram.Token, cfg.Telegram.APIURL, integrationsClient)
+		notifiers = append(notifiers, queued("telegram", track("telegram", notifier.NewTelegram(tgClient, func(ctx context.Context, userID string) (string, error) {
+			return identityRepo.ExternalIDFor(ctx, userID, entity.IdentityProviderTelegram)
+		}))))
+	}
+
+	if cfg.Slack.Token != "" {
+		slackClient := slack.New(cfg.Slack.Token, cfg.Slack.APIURL, integrationsClient)
+		teamSettingsRepo := pgRepo.TeamSettingsRepo()
+		notifiers = append(notifiers, queued("slack", track("slack", notifier.NewSlack(slackClient,
+			func(ctx context.Context, userID string) (string, error) {
+				return identityRepo.ExternalIDFor(ctx, userID, entity.IdentityProviderSlack)
+			},
+			func(ctx context.Context, teamName string) bool {
+				s, _ := teamSettingsRepo.Get(ctx, teamName)
+				return s.SlackEnabledOr(cfg.Slack.DefaultEnabled)
+			},
+			func(ctx context.Context, teamName string) string {
+				s, _ := teamSettingsRepo.Get(ctx, teamName)
+				return s.SlackChannelOr(cfg.Slack.DefaultChannel)
+			},
+		))))
+	}
+
+	if cfg.SMTP.Host != "" {
+		mailClient := mail.New(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+		if sandboxRecorder != nil {
+			mailClient = mail.NewSandboxed(cfg.SMTP.From, sandboxRecorder)
+		}
+
+		email := track("email", notifier.NewEmail(mailClient, func(ctx context.Context, userID string) (string, error) {
+			return identityRepo.ExternalIDFor(ctx, userID, entity.IdentityProviderEmail)
+		}))
+		if tasks != nil {
+			email = queued("email", email)
+		} else {
+			async := notifier.NewAsync(email, cfg.SMTP.QueueSize, cfg.SMTP.Workers, notifyLog)
+			async.Start()
+			defer async.Stop()
+
+			email = async
+		}
+
+		notifiers = append(notifiers, email)
+	}
+
+	var notify notifier.Notifier = notifiers
+	if cfg.Notify.DedupWindow > 0 {
+		notify = notifier.NewDedup(notifiers, cfg.Notify.DedupWindow)
+	}
+	if injector != nil {
+		notify = chaosrepo.NewNotifier(notify, injector)
+	}
+
+	// Outgoing webhooks
+	hookSender := webhook.NewSender(integrationsClient, webhook.Options{
+		QueueSize:      cfg.Hooks.QueueSize,
+		Workers:        cfg.Hooks.Workers,
+		MaxAttempts:    cfg.Hooks.MaxAttempts,
+		InitialBackoff: cfg.Hooks.InitialBackoff,
+		MaxBackoff:     cfg.Hooks.MaxBackoff,
+	}, notifyLog)
+	hookSender.Start()
+	defer hookSender.Stop()
+
+	// Webhooks can require acks only when receivers have somewhere to send them
+	var ackURL string
+	if cfg.Hooks.PublicURL != "" {
+		ackURL = strings.TrimSuffix(cfg.Hooks.PublicURL, "/") + "/v1/webhooks/assignments/ack"
+	}
 
 	// Usecase
-	prUC := usecase.NewPRUseCase(prRepo, userRepo, teamRepo)
+	prOpts := []usecase.Option{
+		usecase.WithIDGenerator(idGen),
+		usecase.WithNotifier(notify),
+		usecase.WithIdentityRepo(identityRepo),
+		usecase.WithAuditRepo(auditRepo),
+		usecase.WithWorkloadRepo(pgRepo.WorkloadRepo()),
+		usecase.WithTxManager(pgRepo.TxManager()),
+		usecase.WithStatsRepo(pgRepo.StatsRepo()),
+		usecase.WithStatsHistoryRepo(pgRepo.StatsHistoryRepo()),
+		usecase.WithOutgoingWebhooks(pgRepo.OutgoingWebhookRepo(), hookSender),
+		usecase.WithAssignmentAcks(pgRepo.AssignmentAckRepo(), usecase.AckPolicy{
+			URL:         ackURL,
+			Timeout:     cfg.Hooks.AckTimeout,
+			MaxAttempts: cfg.Hooks.AckMaxAttempts,
+		}),
+		usecase.WithHealthThresholds(usecase.HealthThresholds{
+			ReviewSLA: cfg.Health.ReviewSLA,
+			PrioritySLA: map[string]time.Duration{
+				entity.PriorityLow:    cfg.Health.ReviewSLALow,
+				entity.PriorityHigh:   cfg.Health.ReviewSLAHigh,
+				entity.PriorityUrgent: cfg.Health.ReviewSLAUrgent,
+			},
+			MinCoverage:   cfg.Health.MinCoverage,
+			MaxBreachRate: cfg.Health.MaxBreachRate,
+			MaxImbalance:  cfg.Health.MaxImbalance,
+		}),
+		usecase.WithTeamSettingsRepo(pgRepo.TeamSettingsRepo()),
+		usecase.WithRotationRepo(pgRepo.RotationRepo()),
+		usecase.WithOnCallRepo(pgRepo.OnCallRepo()),
+		usecase.WithReviewRuleRepo(pgRepo.ReviewRuleRepo()),
+		usecase.WithReviewExclusionRepo(pgRepo.ReviewExclusionRepo()),
+		usecase.WithReviewRepo(pgRepo.ReviewRepo()),
+		usecase.WithAbsenceRepo(pgRepo.AbsenceRepo()),
+		usecase.WithDecisionCapture(captureRecorder),
+		usecase.WithAssignmentStrategy(cfg.Assignment.Strategy),
+		usecase.WithReviewCooldown(usecase.ReviewCooldown{Window: cfg.Assignment.Cooldown, PRs: cfg.Assignment.CooldownPRs}),
+		usecase.WithReviewerReconciliation(pgRepo.ReviewerConflictRepo(), cfg.Reconcile.Policy),
+		usecase.WithRequiredApprovals(cfg.Merge.RequiredApprovals),
+		usecase.WithDelegationRepo(pgRepo.DelegationRepo()),
+		usecase.WithRBAC(cfg.RBAC.Enforce),
+		usecase.WithQuota(usecase.QuotaPolicy{
+			MaxOpenPRsPerAuthor: cfg.Quota.MaxOpenPRsPerAuthor,
+			Mode:                cfg.Quota.Mode,
+		}),
+	}
+
+	// Stats served from cache while Postgres is slow
+	if cfg.Stats.ShedLatency > 0 {
+		prOpts = append(prOpts, usecase.WithStatsShedding(func() bool {
+			return pg.Latency().Average() > cfg.Stats.ShedLatency
+		}, cfg.Stats.StaleMaxAge))
+	}
+
+	// Reviewer assignments mirrored into the VCSs
+	var mirrors usecase.ReviewerMirrors
+	if cfg.GitHub.Token != "" {
+		mirror := ghproc.NewMirror(github.NewClient(cfg.GitHub.Token, cfg.GitHub.APIURL, integrationsClient),
+			func(ctx context.Context, userID string) (string, error) {
+				return identityRepo.ExternalIDFor(ctx, userID, entity.IdentityProviderGitHub)
+			}, cfg.GitHub.MirrorRepos, cfg.GitHub.MirrorQueueSize, cfg.GitHub.MirrorWorkers, notifyLog)
+		if tasks != nil {
+			mirror.Durable(tasks)
+		}
+		mirror.Start()
+		defer mirror.Stop()
+
+		mirrors = append(mirrors, mirror)
+	}
+	if cfg.GitLab.Token != "" {
+		mirror := glproc.NewMirror(gitlab.New(cfg.GitLab.Token, cfg.GitLab.APIURL, integrationsClient),
+			func(ctx context.Context, userID string) (string, error) {
+				return identityRepo.ExternalIDFor(ctx, userID, entity.IdentityProviderGitLab)
+			}, cfg.GitLab.MirrorProjects, cfg.GitLab.MirrorQueueSize, cfg.GitLab.MirrorWorkers, notifyLog)
+		if tasks != nil {
+			mirror.Durable(tasks)
+		}
+		mirror.Start()
+		defer mirror.Stop()
+
+		mirrors = append(mirrors, mirror)
+	}
+	if len(mirrors) > 0 {
+		prOpts = append(prOpts, usecase.WithReviewerMirror(mirrors))
+	}
+
+	// Every process works the task queue; claims never hand a task to two.
+	if tasks != nil {
+		tasks.Start()
+		defer tasks.Stop()
+	}
+
+	// In-process bus publishing PR and user events to subscribers
+	bus := usecase.NewBus()
+	defer bus.Close()
+
+	prOpts = append(prOpts, usecase.WithEventSink(bus), usecase.WithUserEventSink(bus))
+
+	// Analytics: PR lifecycle events mirrored to ClickHouse
+	var analyticsRepo usecase.AnalyticsRepo
+	if cfg.ClickHouse.URL != "" {
+		chClient := clickhouse.New(cfg.ClickHouse.URL, cfg.ClickHouse.Database, cfg.ClickHouse.User, cfg.ClickHouse.Password,
+			&nethttp.Client{Timeout: 30 * time.Second})
+		if err := chrepo.EnsureSchema(context.Background(), chClient); err != nil {
+			l.Fatal(fmt.Errorf("app - Run - clickhouse.EnsureSchema: %w", err))
+		}
+
+		sink := chrepo.NewSink(chClient, cfg.ClickHouse.BufferSize, cfg.ClickHouse.BatchSize, cfg.ClickHouse.FlushInterval, repoLog)
+		sink.Start()
+		defer sink.Stop()
+
+		events := bus.PRs.Subscribe("analytics", cfg.ClickHouse.BufferSize)
+		defer events.Cancel()
+
+		go func() {
+			for e := range events.C {
+				sink.Publish(context.Background(), e)
+			}
+		}()
+
+		analyticsRepo = chrepo.NewAnalyticsRepo(chClient)
+	}
+
+	prUC := usecase.NewPRUseCase(prRepo, userRepo, teamRepo, prOpts...)
+	webhookUC := usecase.NewWebhookUseCase(webhookRepo)
+
+	// Stats remembered for users who are since deleted are dropped
+	userEvents := bus.Users.Subscribe("stats-cache", 256)
+	defer userEvents.Cancel()
+
+	go func() {
+		for e := range userEvents.C {
+			if e.Type == entity.UserEventDeleted {
+				prUC.ForgetUserStats(e.UserID)
+			}
+		}
+	}()
+
+	var usageUC *usecase.UsageUseCase
+	if cfg.Usage.FlushInterval > 0 {
+		usageUC = usecase.NewUsageUseCase(pgRepo.UsageRepo())
+	}
+
+	var quotaUC *usecase.ClientQuotaUseCase
+	if cfg.ClientQuota.Enabled {
+		quotaUC = usecase.NewClientQuotaUseCase(pgRepo.ClientQuotaRepo(), pgRepo.TxManager(), entity.ClientQuota{
+			Daily:   cfg.ClientQuota.Daily,
+			Monthly: cfg.ClientQuota.Monthly,
+		})
+	}
+
+	if tgClient != nil {
+		webhookUC.RegisterProcessor("telegram", tgbot.NewBot(prUC, tgClient, cfg.Telegram.SnoozeFor, l.Module("telegram")))
+	}
+
+	if cfg.GitHub.WebhookSecret != "" {
+		webhookUC.RegisterProcessor("github", ghproc.NewProcessor(prUC, cfg.GitHub.MirrorLogin))
+	}
+
+	if cfg.Bitbucket.WebhookSecret != "" {
+		webhookUC.RegisterProcessor("bitbucket", bbproc.NewProcessor(prUC))
+	}
+
+	if cfg.GitLab.WebhookSecret != "" {
+		webhookUC.RegisterProcessor("gitlab", glproc.NewProcessor(prUC, cfg.GitLab.MirrorUsername))
+	}
+
+	// Self-service routes authenticate callers with OpenID Connect tokens
+	var verifier *oidc.Verifier
+	if cfg.OIDC.Issuer != "" {
+		verifier = oidc.NewVerifier(cfg.OIDC.Issuer, cfg.OIDC.Audience, cfg.OIDC.JWKSURL, cfg.OIDC.JWKSRefresh,
+			&nethttp.Client{Timeout: 10 * time.Second})
+	}
+
+	// Background jobs, registered below once the HTTP server knows whether
+	// this process is a prefork child
+	jobs := scheduler.New(jobsLog, scheduler.Jitter(cfg.Jobs.Jitter))
 
 	// HTTP Server
-	httpServer := httpserver.New(l, httpserver.Port(cfg.HTTP.Port), httpserver.Prefork(cfg.HTTP.UsePreforkMode))
+	httpServer := httpserver.New(httpLog, httpserver.Port(cfg.HTTP.Port), httpserver.Prefork(cfg.HTTP.UsePreforkMode))
 
 	// Register routes
-	http.NewRouter(httpServer.App, cfg, prUC, userRepo, teamRepo, prRepo, l)
+	http.NewRouter(httpServer.App, cfg, prUC, teamRepo, prRepo, pgRepo.SearchRepo(), bus, analyticsRepo, webhookUC, auditRepo, notificationLog, pgRepo.SchemaRepo(), usageUC, quotaUC, sandboxRecorder, captureRecorder, injector, verifier, l.Levels(), jobs, tasks, httpLog)
 
 	httpServer.Start()
 
+	// Every process buffers its own request tally, deliveries and captures,
+	// so each one flushes or prunes them.
+	if usageUC != nil {
+		jobs.Add("usage-flush", scheduler.Every(cfg.Usage.FlushInterval), usageUC.Flush)
+	}
+	jobs.Add("capture-prune", scheduler.Every(time.Hour), func(context.Context) error {
+		captureRecorder.Prune(cfg.Capture.Retention)
+		return nil
+	})
+	if cfg.Notify.TrackDeliveries {
+		jobs.Add("notification-log-flush", scheduler.Every(cfg.Notify.FlushInterval), notificationLog.Flush)
+	}
+
+	switch {
+	case !httpServer.Primary():
+		l.Info("app - Run - prefork child pid %d: background jobs are left to the parent process", os.Getpid())
+	case cfg.HTTP.UsePreforkMode:
+		l.Info("app - Run - prefork parent pid %d: running background jobs", os.Getpid())
+		registerJobs(jobs, cfg, prUC, jobsLog)
+	default:
+		registerJobs(jobs, cfg, prUC, jobsLog)
+	}
+
+	jobs.Start()
+	defer jobs.Stop()
+
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
@@ -58,4 +441,25 @@ func Run(cfg *config.Config) {
 	if err := httpServer.Shutdown(); err != nil {
 		l.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
+
+	// Let running jobs finish before the final flush below.
+	jobs.Stop()
+
+	if usageUC != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := usageUC.Flush(ctx); err != nil {
+			l.Error(fmt.Errorf("app - Run - usageUC.Flush: %w", err))
+		}
+	}
+
+	if cfg.Notify.TrackDeliveries {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := notificationLog.Flush(ctx); err != nil {
+			l.Error(fmt.Errorf("app - Run - notificationLog.Flush: %w", err))
+		}
+	}
 }
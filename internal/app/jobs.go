@@ -0,0 +1,141 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrone/go-clean-template/config"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/evrone/go-clean-template/pkg/scheduler"
+)
+
+// registerJobs adds the background policy jobs enabled in cfg to s.
+func registerJobs(s *scheduler.Scheduler, cfg *config.Config, prUC *usecase.PRUseCase, l logger.Interface) {
+	if cfg.Policy.InactiveAfter > 0 {
+		policy := usecase.AgeOutPolicy{
+			InactiveAfter:  cfg.Policy.InactiveAfter,
+			AutoDeactivate: cfg.Policy.AutoDeactivate,
+		}
+
+		s.Add("age-out", scheduler.Every(cfg.Policy.Interval), func(ctx context.Context) error {
+			report, err := prUC.AgeOutInactiveUsers(ctx, time.Now(), policy)
+			if err != nil {
+				return err
+			}
+
+			if len(report.Flagged) > 0 || report.Cleared > 0 {
+				l.Info("app - job age-out - flagged %d, deactivated %d, cleared %d",
+					len(report.Flagged), len(report.Deactivated), report.Cleared)
+			}
+
+			return nil
+		})
+	}
+
+	if cfg.Policy.CloseAfter > 0 {
+		policy := usecase.AutoClosePolicy{
+			AbandonedAfter: cfg.Policy.CloseAfter,
+			GracePeriod:    cfg.Policy.CloseGrace,
+		}
+
+		s.Add("auto-close", scheduler.Every(cfg.Policy.Interval), func(ctx context.Context) error {
+			report, err := prUC.AutoCloseAbandonedPRs(ctx, time.Now(), policy)
+			if err != nil {
+				return err
+			}
+
+			if len(report.Warned) > 0 || len(report.Closed) > 0 || len(report.Reprieved) > 0 {
+				l.Info("app - job auto-close - warned %d, closed %d, reprieved %d",
+					len(report.Warned), len(report.Closed), len(report.Reprieved))
+			}
+
+			return nil
+		})
+	}
+
+	if cfg.Reminders.Interval > 0 {
+		policy := usecase.ReminderPolicy{Cadence: map[string]time.Duration{
+			entity.PriorityLow:    cfg.Reminders.CadenceLow,
+			entity.PriorityNormal: cfg.Reminders.CadenceNormal,
+			entity.PriorityHigh:   cfg.Reminders.CadenceHigh,
+			entity.PriorityUrgent: cfg.Reminders.CadenceUrgent,
+		}}
+
+		s.Add("reminders", scheduler.Every(cfg.Reminders.Interval), func(ctx context.Context) error {
+			report, err := prUC.SendReminders(ctx, time.Now(), policy)
+			if err != nil {
+				return err
+			}
+
+			if report.Reminded > 0 {
+				l.Info("app - job reminders - reminded %d", report.Reminded)
+			}
+
+			return nil
+		})
+	}
+
+	if cfg.Absences.Interval > 0 {
+		s.Add("absences", scheduler.Every(cfg.Absences.Interval), func(ctx context.Context) error {
+			report, err := prUC.StartAbsences(ctx, time.Now())
+
+			if len(report.Released) > 0 {
+				l.Info("app - job absences - released %d", len(report.Released))
+			}
+
+			return err
+		})
+	}
+
+	if cfg.Workload.RefreshInterval > 0 {
+		s.Add("workload-refresh", scheduler.Every(cfg.Workload.RefreshInterval), func(ctx context.Context) error {
+			_, err := prUC.RefreshWorkload(ctx)
+			return err
+		})
+	}
+
+	if cfg.Hooks.AckInterval > 0 {
+		s.Add("assignment-acks", scheduler.Every(cfg.Hooks.AckInterval), func(ctx context.Context) error {
+			report, err := prUC.RetryAssignmentAcks(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if report.Resent > 0 || report.Flagged > 0 {
+				l.Info("app - job assignment-acks - resent %d, flagged %d", report.Resent, report.Flagged)
+			}
+
+			return nil
+		})
+	}
+
+	if cfg.StatsHistory.Enabled {
+		s.Add("stats-snapshot", scheduler.DailyAt(cfg.StatsHistory.Hour), func(ctx context.Context) error {
+			teams, err := prUC.SnapshotStats(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+
+			l.Info("app - job stats-snapshot - recorded %d teams", teams)
+
+			return nil
+		})
+	}
+
+	if cfg.WeeklyReport.Enabled {
+		schedule := scheduler.WeeklyAt{Day: time.Weekday(cfg.WeeklyReport.Day), Hour: cfg.WeeklyReport.Hour}
+
+		s.Add("weekly-report", schedule, func(ctx context.Context) error {
+			teams, err := prUC.SendWeeklyReports(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+
+			l.Info("app - job weekly-report - reported to %d teams", teams)
+
+			return nil
+		})
+	}
+}
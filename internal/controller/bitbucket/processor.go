@@ -0,0 +1,86 @@
+// Package bitbucket maps Bitbucket Cloud webhook deliveries onto PR use case calls.
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/bitbucket"
+)
+
+// Processor is registered as the "bitbucket" webhook processor so failed
+// deliveries can be replayed from the admin API.
+type Processor struct {
+	uc *usecase.PRUseCase
+}
+
+// NewProcessor -.
+func NewProcessor(uc *usecase.PRUseCase) *Processor {
+	return &Processor{uc: uc}
+}
+
+// Process -. Events other than pullrequest:created and pullrequest:fulfilled are ignored.
+func (p *Processor) Process(ctx context.Context, event string, payload []byte) error {
+	if event != bitbucket.EventPullRequestCreated && event != bitbucket.EventPullRequestFulfilled {
+		return nil
+	}
+
+	var e bitbucket.PullRequestEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return fmt.Errorf("bitbucket - decode %s: %w", event, err)
+	}
+
+	if event == bitbucket.EventPullRequestCreated {
+		return p.created(ctx, e)
+	}
+
+	return p.fulfilled(ctx, e)
+}
+
+// created creates the PR; a redelivered PR that already exists is a no-op.
+// Authors are matched by nickname, the same value users link under provider "bitbucket".
+func (p *Processor) created(ctx context.Context, e bitbucket.PullRequestEvent) error {
+	authorID, err := p.uc.ResolveUserID(ctx, entity.IdentityProviderBitbucket, e.PullRequest.Author.Nickname)
+	if err != nil {
+		return fmt.Errorf("bitbucket - resolve author %s: %w", e.PullRequest.Author.Nickname, err)
+	}
+
+	author := e.PullRequest.Author
+	if err := p.uc.SyncDisplay(ctx, authorID, author.DisplayName, author.Links.Avatar.Href); err != nil {
+		return fmt.Errorf("bitbucket - sync author %s: %w", authorID, err)
+	}
+
+	_, _, err = p.uc.CreatePR(ctx, usecase.CreatePRInput{
+		PullRequestName: e.PullRequest.Title,
+		AuthorID:        authorID,
+		Repository:      e.Repository.FullName,
+		ExternalNumber:  e.PullRequest.ID,
+		Draft:           e.PullRequest.Draft,
+	})
+	if err != nil && !errors.Is(err, usecase.ErrPRExists) {
+		return fmt.Errorf("bitbucket - create %s#%d: %w", e.Repository.FullName, e.PullRequest.ID, err)
+	}
+
+	return nil
+}
+
+// fulfilled marks the PR merged. PRs created before the webhook was installed are skipped.
+func (p *Processor) fulfilled(ctx context.Context, e bitbucket.PullRequestEvent) error {
+	prID, err := p.uc.ResolvePRID(ctx, e.Repository.FullName, e.PullRequest.ID)
+	if errors.Is(err, usecase.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("bitbucket - resolve %s#%d: %w", e.Repository.FullName, e.PullRequest.ID, err)
+	}
+
+	if _, err := p.uc.MarkMerged(ctx, prID); err != nil && !errors.Is(err, usecase.ErrPRClosed) {
+		return fmt.Errorf("bitbucket - merge %s: %w", prID, err)
+	}
+
+	return nil
+}
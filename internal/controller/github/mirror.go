@@ -0,0 +1,192 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/github"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/evrone/go-clean-template/pkg/taskqueue"
+)
+
+// _mirrorCallTimeout bounds a single GitHub API call.
+const _mirrorCallTimeout = 30 * time.Second
+
+// TaskKind is the task queue kind of mirror calls made through Durable.
+const TaskKind = "github.mirror"
+
+// LoginResolver returns the GitHub login linked to a user.
+type LoginResolver func(ctx context.Context, userID string) (string, error)
+
+type mirrorOp struct {
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+	UserID string `json:"user_id"`
+	Remove bool   `json:"remove"`
+}
+
+// Mirror requests a GitHub review from each reviewer assigned in the service
+// and withdraws the request when they are unassigned, so the PR on GitHub
+// shows the same reviewers. Only PRs linked to a GitHub repository and number
+// are mirrored, limited to repos when it is not empty; reviewers without a
+// linked GitHub login are skipped. Calls are made by background workers, each
+// PR always by the same one so its calls land in order, and dropped when the
+// worker's queue is full. With Durable, calls go through the task queue
+// instead, which keeps them in order per PR across retries.
+type Mirror struct {
+	client *github.Client
+	login  LoginResolver
+	repos  []string
+	queues []chan mirrorOp
+	tasks  *taskqueue.Queue
+	l      logger.Interface
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+var _ usecase.ReviewerMirror = (*Mirror)(nil)
+
+// NewMirror -.
+func NewMirror(client *github.Client, login LoginResolver, repos []string, queueSize, workers int, l logger.Interface) *Mirror {
+	queues := make([]chan mirrorOp, max(1, workers))
+	for i := range queues {
+		queues[i] = make(chan mirrorOp, max(1, queueSize/len(queues)))
+	}
+
+	return &Mirror{
+		client: client,
+		login:  login,
+		repos:  repos,
+		queues: queues,
+		l:      l,
+	}
+}
+
+// Durable sends calls through q instead of the in-memory queues, so they
+// survive restarts and failed ones are retried. Call it before q starts.
+func (m *Mirror) Durable(q *taskqueue.Queue) {
+	q.Register(TaskKind, func(ctx context.Context, payload json.RawMessage) error {
+		var op mirrorOp
+		if err := json.Unmarshal(payload, &op); err != nil {
+			return fmt.Errorf("decode op: %w", err)
+		}
+		return m.apply(ctx, op)
+	})
+	m.tasks = q
+}
+
+// Start launches the workers.
+func (m *Mirror) Start() {
+	for _, queue := range m.queues {
+		m.wg.Add(1)
+
+		go func() {
+			defer m.wg.Done()
+
+			for op := range queue {
+				ctx, cancel := context.WithTimeout(context.Background(), _mirrorCallTimeout)
+				if err := m.apply(ctx, op); err != nil {
+					m.l.Error(err)
+				}
+				cancel()
+			}
+		}()
+	}
+}
+
+// Stop makes the calls already queued and waits for the workers to exit.
+func (m *Mirror) Stop() {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		for _, queue := range m.queues {
+			close(queue)
+		}
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// ReviewerAssigned -.
+func (m *Mirror) ReviewerAssigned(ctx context.Context, pr entity.PullRequest, userID string) {
+	m.enqueue(ctx, pr, userID, false)
+}
+
+// ReviewerUnassigned -.
+func (m *Mirror) ReviewerUnassigned(ctx context.Context, pr entity.PullRequest, userID string) {
+	m.enqueue(ctx, pr, userID, true)
+}
+
+// Mirrors -.
+func (m *Mirror) Mirrors(pr entity.PullRequest) bool {
+	if pr.Repository == "" || pr.ExternalNumber <= 0 {
+		return false
+	}
+	return len(m.repos) == 0 || slices.Contains(m.repos, pr.Repository)
+}
+
+// enqueue is called once the caller's change has committed; ctx only bounds
+// writing the task, the call itself outlives the request.
+func (m *Mirror) enqueue(ctx context.Context, pr entity.PullRequest, userID string, remove bool) {
+	if !m.Mirrors(pr) {
+		return
+	}
+
+	op := mirrorOp{Repo: pr.Repository, Number: pr.ExternalNumber, UserID: userID, Remove: remove}
+	if m.tasks != nil {
+		key := fmt.Sprintf("%s#%d", pr.Repository, pr.ExternalNumber)
+		if err := m.tasks.EnqueueOrdered(ctx, TaskKind, key, op); err != nil {
+			m.l.Error(fmt.Errorf("github - mirror - enqueue %s#%d: %w", pr.Repository, pr.ExternalNumber, err))
+		}
+		return
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(pr.Repository))
+	queue := m.queues[(h.Sum32()+uint32(pr.ExternalNumber))%uint32(len(m.queues))]
+
+	select {
+	case queue <- op:
+	default:
+		m.l.Warn("github - mirror - queue full, dropped %s#%d for %s", pr.Repository, pr.ExternalNumber, userID)
+	}
+}
+
+func (m *Mirror) apply(ctx context.Context, op mirrorOp) error {
+	login, err := m.login(ctx, op.UserID)
+	if errors.Is(err, usecase.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("github - mirror - resolve %s: %w", op.UserID, err)
+	}
+
+	if op.Remove {
+		err = m.client.RemoveReviewers(ctx, op.Repo, op.Number, []string{login})
+	} else {
+		err = m.client.RequestReviewers(ctx, op.Repo, op.Number, []string{login})
+	}
+	if err != nil {
+		return fmt.Errorf("github - mirror %s#%d: %w", op.Repo, op.Number, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package github maps GitHub webhook deliveries onto PR use case calls.
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/github"
+)
+
+// Processor is registered as the "github" webhook processor so failed
+// deliveries can be replayed from the admin API.
+type Processor struct {
+	uc          *usecase.PRUseCase
+	mirrorLogin string
+}
+
+// NewProcessor -. Reviewer changes made by mirrorLogin, the account mirroring
+// assignments, are the service's own and not reconciled.
+func NewProcessor(uc *usecase.PRUseCase, mirrorLogin string) *Processor {
+	return &Processor{uc: uc, mirrorLogin: mirrorLogin}
+}
+
+// Process -. Events other than pull_request are ignored, as are actions the
+// service has no counterpart for (edited, labeled, ...).
+func (p *Processor) Process(ctx context.Context, event string, payload []byte) error {
+	if event != github.EventPullRequest {
+		return nil
+	}
+
+	var e github.PullRequestEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return fmt.Errorf("github - decode pull_request: %w", err)
+	}
+
+	switch e.Action {
+	case github.ActionOpened:
+		return p.opened(ctx, e)
+	case github.ActionReopened:
+		return p.reopened(ctx, e)
+	case github.ActionClosed:
+		return p.closed(ctx, e)
+	case github.ActionReadyForReview:
+		return p.readyForReview(ctx, e)
+	case github.ActionReviewRequested:
+		return p.reviewersChanged(ctx, e, entity.ReviewerChangeAdded)
+	case github.ActionReviewRequestRemoved:
+		return p.reviewersChanged(ctx, e, entity.ReviewerChangeRemoved)
+	default:
+		return nil
+	}
+}
+
+// opened creates the PR; a redelivered PR that already exists is a no-op.
+func (p *Processor) opened(ctx context.Context, e github.PullRequestEvent) error {
+	authorID, err := p.uc.ResolveUserID(ctx, entity.IdentityProviderGitHub, e.PullRequest.User.Login)
+	if err != nil {
+		return fmt.Errorf("github - resolve author %s: %w", e.PullRequest.User.Login, err)
+	}
+
+	// Pull request payloads carry no display name, only the avatar.
+	if err := p.uc.SyncDisplay(ctx, authorID, "", e.PullRequest.User.AvatarURL); err != nil {
+		return fmt.Errorf("github - sync author %s: %w", authorID, err)
+	}
+
+	labels := make([]string, len(e.PullRequest.Labels))
+	for i, l := range e.PullRequest.Labels {
+		labels[i] = l.Name
+	}
+
+	_, _, err = p.uc.CreatePR(ctx, usecase.CreatePRInput{
+		PullRequestName: e.PullRequest.Title,
+		AuthorID:        authorID,
+		Repository:      e.Repository.FullName,
+		ExternalNumber:  e.Number,
+		Labels:          labels,
+		Draft:           e.PullRequest.Draft,
+	})
+	if err != nil && !errors.Is(err, usecase.ErrPRExists) {
+		return fmt.Errorf("github - create %s#%d: %w", e.Repository.FullName, e.Number, err)
+	}
+
+	return nil
+}
+
+// reopened moves a closed PR back to OPEN. PRs we have never seen are created
+// as if just opened.
+func (p *Processor) reopened(ctx context.Context, e github.PullRequestEvent) error {
+	prID, err := p.uc.ResolvePRID(ctx, e.Repository.FullName, e.Number)
+	if errors.Is(err, usecase.ErrNotFound) {
+		return p.opened(ctx, e)
+	}
+	if err != nil {
+		return fmt.Errorf("github - resolve %s#%d: %w", e.Repository.FullName, e.Number, err)
+	}
+
+	if _, err := p.uc.ReopenPR(ctx, prID); err != nil && !errors.Is(err, usecase.ErrPRMerged) {
+		return fmt.Errorf("github - reopen %s: %w", prID, err)
+	}
+
+	return nil
+}
+
+// readyForReview assigns reviewers to a draft PR. PRs we have never seen are
+// created as if just opened.
+func (p *Processor) readyForReview(ctx context.Context, e github.PullRequestEvent) error {
+	prID, err := p.uc.ResolvePRID(ctx, e.Repository.FullName, e.Number)
+	if errors.Is(err, usecase.ErrNotFound) {
+		return p.opened(ctx, e)
+	}
+	if err != nil {
+		return fmt.Errorf("github - resolve %s#%d: %w", e.Repository.FullName, e.Number, err)
+	}
+
+	_, _, err = p.uc.MarkReady(ctx, prID, nil)
+	if err != nil && !errors.Is(err, usecase.ErrPRMerged) && !errors.Is(err, usecase.ErrPRClosed) {
+		return fmt.Errorf("github - ready %s: %w", prID, err)
+	}
+
+	return nil
+}
+
+// closed merges or closes the PR depending on whether GitHub merged it.
+// PRs opened before the webhook was installed are unknown and skipped.
+func (p *Processor) closed(ctx context.Context, e github.PullRequestEvent) error {
+	prID, err := p.uc.ResolvePRID(ctx, e.Repository.FullName, e.Number)
+	if errors.Is(err, usecase.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("github - resolve %s#%d: %w", e.Repository.FullName, e.Number, err)
+	}
+
+	if e.PullRequest.Merged {
+		_, err = p.uc.MarkMerged(ctx, prID)
+	} else {
+		_, err = p.uc.ClosePR(ctx, prID)
+	}
+	if err != nil && !errors.Is(err, usecase.ErrPRMerged) && !errors.Is(err, usecase.ErrPRClosed) {
+		return fmt.Errorf("github - %s %s: %w", e.Action, prID, err)
+	}
+
+	return nil
+}
+
+// reviewersChanged reconciles a reviewer requested or unrequested on GitHub
+// with the assignment. Team requests, changes on unknown PRs and changes on
+// PRs whose reviewers are settled (merged, closed, draft) are skipped.
+func (p *Processor) reviewersChanged(ctx context.Context, e github.PullRequestEvent, change string) error {
+	if e.RequestedReviewer == nil || (p.mirrorLogin != "" && e.Sender.Login == p.mirrorLogin) {
+		return nil
+	}
+
+	prID, err := p.uc.ResolvePRID(ctx, e.Repository.FullName, e.Number)
+	if errors.Is(err, usecase.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("github - resolve %s#%d: %w", e.Repository.FullName, e.Number, err)
+	}
+
+	userID, err := p.uc.ResolveUserID(ctx, entity.IdentityProviderGitHub, e.RequestedReviewer.Login)
+	if err != nil {
+		return fmt.Errorf("github - resolve reviewer %s: %w", e.RequestedReviewer.Login, err)
+	}
+
+	_, err = p.uc.ReconcileReviewer(ctx, entity.IdentityProviderGitHub, prID, userID, change)
+	if err != nil && !errors.Is(err, usecase.ErrPRMerged) && !errors.Is(err, usecase.ErrPRClosed) &&
+		!errors.Is(err, usecase.ErrPRDraft) {
+		return fmt.Errorf("github - reconcile %s %s: %w", prID, userID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+// Package gitlab mirrors reviewer assignments into GitLab merge requests and
+// reconciles the reviewer changes made there.
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/gitlab"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/evrone/go-clean-template/pkg/taskqueue"
+)
+
+// _mirrorCallTimeout bounds syncing one merge request.
+const _mirrorCallTimeout = 30 * time.Second
+
+// TaskKind is the task queue kind of mirror calls made through Durable.
+const TaskKind = "gitlab.mirror"
+
+// UserIDResolver returns the GitLab user ID linked to a user.
+type UserIDResolver func(ctx context.Context, userID string) (string, error)
+
+type mirrorOp struct {
+	Project   string   `json:"project"`
+	IID       int      `json:"iid"`
+	Reviewers []string `json:"reviewers"`
+}
+
+// Mirror sets the reviewers of a merge request to the PR's reviewers in the
+// service whenever one is assigned or unassigned. Only PRs whose repository is
+// one of projects are mirrored, each of them opting in on its own; reviewers
+// without a linked GitLab user are left out. GitLab keeps one reviewer list,
+// so reviewers added there by hand are replaced. Calls are made by background
+// workers, each merge request always by the same one so its updates land in
+// order, and dropped when the worker's queue is full. With Durable, calls go
+// through the task queue instead, which keeps them in order per merge request
+// across retries.
+type Mirror struct {
+	client   *gitlab.Client
+	gitlabID UserIDResolver
+	projects []string
+	queues   []chan mirrorOp
+	tasks    *taskqueue.Queue
+	l        logger.Interface
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+var _ usecase.ReviewerMirror = (*Mirror)(nil)
+
+// NewMirror -.
+func NewMirror(client *gitlab.Client, gitlabID UserIDResolver, projects []string, queueSize, workers int, l logger.Interface) *Mirror {
+	queues := make([]chan mirrorOp, max(1, workers))
+	for i := range queues {
+		queues[i] = make(chan mirrorOp, max(1, queueSize/len(queues)))
+	}
+
+	return &Mirror{
+		client:   client,
+		gitlabID: gitlabID,
+		projects: projects,
+		queues:   queues,
+		l:        l,
+	}
+}
+
+// Durable sends calls through q instead of the in-memory queues, so they
+// survive restarts and failed ones are retried. Call it before q starts.
+func (m *Mirror) Durable(q *taskqueue.Queue) {
+	q.Register(TaskKind, func(ctx context.Context, payload json.RawMessage) error {
+		var op mirrorOp
+		if err := json.Unmarshal(payload, &op); err != nil {
+			return fmt.Errorf("decode op: %w", err)
+		}
+		return m.apply(ctx, op)
+	})
+	m.tasks = q
+}
+
+// Start launches the workers.
+func (m *Mirror) Start() {
+	for _, queue := range m.queues {
+		m.wg.Add(1)
+
+		go func() {
+			defer m.wg.Done()
+
+			for op := range queue {
+				ctx, cancel := context.WithTimeout(context.Background(), _mirrorCallTimeout)
+				if err := m.apply(ctx, op); err != nil {
+					m.l.Error(err)
+				}
+				cancel()
+			}
+		}()
+	}
+}
+
+// Stop makes the calls already queued and waits for the workers to exit.
+func (m *Mirror) Stop() {
+	m.mu.Lock()
+	if !m.closed {
+		m.closed = true
+		for _, queue := range m.queues {
+			close(queue)
+		}
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// ReviewerAssigned -.
+func (m *Mirror) ReviewerAssigned(ctx context.Context, pr entity.PullRequest, _ string) {
+	m.enqueue(ctx, pr)
+}
+
+// ReviewerUnassigned -.
+func (m *Mirror) ReviewerUnassigned(ctx context.Context, pr entity.PullRequest, _ string) {
+	m.enqueue(ctx, pr)
+}
+
+// Mirrors -.
+func (m *Mirror) Mirrors(pr entity.PullRequest) bool {
+	return pr.ExternalNumber > 0 && slices.Contains(m.projects, pr.Repository)
+}
+
+// enqueue is called once the caller's change has committed; ctx only bounds
+// writing the task, the call itself outlives the request.
+func (m *Mirror) enqueue(ctx context.Context, pr entity.PullRequest) {
+	if !m.Mirrors(pr) {
+		return
+	}
+
+	op := mirrorOp{Project: pr.Repository, IID: pr.ExternalNumber, Reviewers: slices.Clone(pr.AssignedReviewers)}
+	if m.tasks != nil {
+		key := fmt.Sprintf("%s!%d", pr.Repository, pr.ExternalNumber)
+		if err := m.tasks.EnqueueOrdered(ctx, TaskKind, key, op); err != nil {
+			m.l.Error(fmt.Errorf("gitlab - mirror - enqueue %s!%d: %w", pr.Repository, pr.ExternalNumber, err))
+		}
+		return
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		return
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(pr.Repository))
+	queue := m.queues[(h.Sum32()+uint32(pr.ExternalNumber))%uint32(len(m.queues))]
+
+	select {
+	case queue <- op:
+	default:
+		m.l.Warn("gitlab - mirror - queue full, dropped %s!%d", pr.Repository, pr.ExternalNumber)
+	}
+}
+
+func (m *Mirror) apply(ctx context.Context, op mirrorOp) error {
+	ids := make([]int64, 0, len(op.Reviewers))
+	for _, userID := range op.Reviewers {
+		raw, err := m.gitlabID(ctx, userID)
+		if errors.Is(err, usecase.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("gitlab - mirror - resolve %s: %w", userID, err)
+		}
+
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			m.l.Warn("gitlab - mirror - %s is linked to non-numeric GitLab user %q", userID, raw)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	if err := m.client.SetReviewers(ctx, op.Project, op.IID, ids); err != nil {
+		return fmt.Errorf("gitlab - mirror %s!%d: %w", op.Project, op.IID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/gitlab"
+)
+
+// Processor is registered as the "gitlab" webhook processor so failed
+// deliveries can be replayed from the admin API. Only reviewer changes are
+// processed; merge requests are linked to PRs through the API.
+type Processor struct {
+	uc             *usecase.PRUseCase
+	mirrorUsername string
+}
+
+// NewProcessor -. Reviewer changes made by mirrorUsername, the account
+// mirroring assignments, are the service's own and not reconciled.
+func NewProcessor(uc *usecase.PRUseCase, mirrorUsername string) *Processor {
+	return &Processor{uc: uc, mirrorUsername: mirrorUsername}
+}
+
+// Process -. Events other than merge request updates of the reviewer list are
+// ignored.
+func (p *Processor) Process(ctx context.Context, event string, payload []byte) error {
+	if event != gitlab.EventMergeRequest {
+		return nil
+	}
+
+	var e gitlab.MergeRequestEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return fmt.Errorf("gitlab - decode merge request: %w", err)
+	}
+
+	if e.Changes.Reviewers == nil || (p.mirrorUsername != "" && e.User.Username == p.mirrorUsername) {
+		return nil
+	}
+
+	return p.reviewersChanged(ctx, e)
+}
+
+// reviewersChanged reconciles each reviewer added to or removed from the
+// merge request with the assignment. Changes on unknown PRs and on PRs whose
+// reviewers are settled (merged, closed, draft) are skipped.
+func (p *Processor) reviewersChanged(ctx context.Context, e gitlab.MergeRequestEvent) error {
+	project, iid := e.Project.PathWithNamespace, e.ObjectAttributes.IID
+
+	prID, err := p.uc.ResolvePRID(ctx, project, iid)
+	if errors.Is(err, usecase.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("gitlab - resolve %s!%d: %w", project, iid, err)
+	}
+
+	previous, current := e.Changes.Reviewers.Previous, e.Changes.Reviewers.Current
+
+	for _, u := range current {
+		if !hasUser(previous, u.ID) {
+			if err := p.reconcile(ctx, prID, strconv.FormatInt(u.ID, 10), entity.ReviewerChangeAdded); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, u := range previous {
+		if !hasUser(current, u.ID) {
+			if err := p.reconcile(ctx, prID, strconv.FormatInt(u.ID, 10), entity.ReviewerChangeRemoved); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// reconcile applies one reviewer change; gitlabID is the numeric GitLab user
+// ID, which is what users link under provider "gitlab".
+func (p *Processor) reconcile(ctx context.Context, prID, gitlabID, change string) error {
+	userID, err := p.uc.ResolveUserID(ctx, entity.IdentityProviderGitLab, gitlabID)
+	if err != nil {
+		return fmt.Errorf("gitlab - resolve reviewer %s: %w", gitlabID, err)
+	}
+
+	_, err = p.uc.ReconcileReviewer(ctx, entity.IdentityProviderGitLab, prID, userID, change)
+	if err != nil && !errors.Is(err, usecase.ErrPRMerged) && !errors.Is(err, usecase.ErrPRClosed) &&
+		!errors.Is(err, usecase.ErrPRDraft) {
+		return fmt.Errorf("gitlab - reconcile %s %s: %w", prID, userID, err)
+	}
+
+	return nil
+}
+
+func hasUser(users []gitlab.User, id int64) bool {
+	return slices.ContainsFunc(users, func(u gitlab.User) bool { return u.ID == id })
+}
@@ -0,0 +1,620 @@
+// Package admin implements operator-only routes mounted under /admin.
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/capture"
+	"github.com/evrone/go-clean-template/pkg/chaos"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/evrone/go-clean-template/pkg/sandbox"
+	"github.com/evrone/go-clean-template/pkg/scheduler"
+	"github.com/evrone/go-clean-template/pkg/taskqueue"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	pr       *usecase.PRUseCase
+	sandbox  *sandbox.Recorder
+	capture  *capture.Recorder
+	webhooks *usecase.WebhookUseCase
+	audit    usecase.AuditRepo
+	notifs   *usecase.NotificationLogUseCase
+	usage    *usecase.UsageUseCase
+	quotas   *usecase.ClientQuotaUseCase
+	chaos    *chaos.Injector
+	levels   *logger.Levels
+	jobs     *scheduler.Scheduler
+	tasks    *taskqueue.Queue
+	l        logger.Interface
+}
+
+// NewHandler -. sandboxRecorder, usage, quotas, injector and tasks are nil when their modes are off,
+// jobs in prefork mode.
+func NewHandler(pr *usecase.PRUseCase, sandboxRecorder *sandbox.Recorder, captureRecorder *capture.Recorder, webhooks *usecase.WebhookUseCase, audit usecase.AuditRepo, notifs *usecase.NotificationLogUseCase, usage *usecase.UsageUseCase, quotas *usecase.ClientQuotaUseCase, injector *chaos.Injector, levels *logger.Levels, jobs *scheduler.Scheduler, tasks *taskqueue.Queue, l logger.Interface) *Handler {
+	return &Handler{
+		pr:       pr,
+		sandbox:  sandboxRecorder,
+		capture:  captureRecorder,
+		webhooks: webhooks,
+		audit:    audit,
+		notifs:   notifs,
+		usage:    usage,
+		quotas:   quotas,
+		chaos:    injector,
+		levels:   levels,
+		jobs:     jobs,
+		tasks:    tasks,
+		l:        l,
+	}
+}
+
+func (h *Handler) RegisterRoutes(router fiber.Router) {
+	// Sandbox
+	sandboxGroup := router.Group("/sandbox")
+	sandboxGroup.Get("/messages", h.sandboxMessages)
+	sandboxGroup.Delete("/messages", h.sandboxReset)
+
+	// Debug capture
+	captureGroup := router.Group("/capture")
+	captureGroup.Get("", h.captureSessions)
+	captureGroup.Post("", h.captureStart)
+	captureGroup.Delete("", h.captureStop)
+	captureGroup.Get("/entries", h.captureEntries)
+
+	// Inbound webhooks
+	webhookGroup := router.Group("/webhooks")
+	webhookGroup.Get("/deliveries", h.webhookDeliveries)
+	webhookGroup.Post("/replay", h.webhookReplay)
+
+	// Acknowledged reviewer.assigned deliveries
+	ackGroup := router.Group("/assignmentAcks")
+	ackGroup.Get("", h.assignmentAcksList)
+	ackGroup.Post("/retry", h.assignmentAcksRetry)
+
+	// Reviewer changes made directly in a VCS
+	router.Get("/reviewerConflicts", h.reviewerConflictsList)
+
+	// Audit log
+	router.Get("/audit", h.auditList)
+
+	// Notification deliveries
+	router.Get("/notifications", h.notificationsList)
+
+	// Reviewer workload view
+	router.Post("/workload/refresh", h.workloadRefresh)
+
+	// Merge override
+	router.Post("/pullRequest/merge", h.pullRequestForceMerge)
+
+	// Directory sync
+	router.Post("/directory/offboard", h.directoryOffboard)
+
+	// API usage per client
+	router.Get("/clients/usage", h.clientsUsage)
+
+	// API client quotas
+	quotaGroup := router.Group("/clients/quotas")
+	quotaGroup.Get("", h.clientQuotasList)
+	quotaGroup.Put("", h.clientQuotasSet)
+	quotaGroup.Delete("", h.clientQuotasDelete)
+	quotaGroup.Get("/status", h.clientQuotasStatus)
+
+	// Fault injection
+	chaosGroup := router.Group("/chaos")
+	chaosGroup.Get("", h.chaosRules)
+	chaosGroup.Put("", h.chaosSet)
+	chaosGroup.Delete("", h.chaosReset)
+
+	// Background jobs
+	jobsGroup := router.Group("/jobs")
+	jobsGroup.Get("", h.jobsList)
+	jobsGroup.Post("/:name/run", h.jobsRun)
+
+	// Durable task queue
+	tasksGroup := router.Group("/tasks")
+	tasksGroup.Get("", h.tasksList)
+	tasksGroup.Post("/:id/retry", h.tasksRetry)
+
+	// Log levels
+	logGroup := router.Group("/loglevel")
+	logGroup.Get("", h.logLevels)
+	logGroup.Post("", h.logLevelSet)
+	logGroup.Delete("", h.logLevelReset)
+}
+
+// sandboxMessages implements GET /admin/sandbox/messages?provider=...
+func (h *Handler) sandboxMessages(c *fiber.Ctx) error {
+	if h.sandbox == nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "sandbox mode is disabled"}})
+	}
+	return c.JSON(fiber.Map{"messages": h.sandbox.List(c.Query("provider"))})
+}
+
+// sandboxReset implements DELETE /admin/sandbox/messages
+func (h *Handler) sandboxReset(c *fiber.Ctx) error {
+	if h.sandbox == nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "sandbox mode is disabled"}})
+	}
+	h.sandbox.Reset()
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// auditList implements GET /admin/audit?actor_id=...&source=...&pull_request_id=...&limit=...
+func (h *Handler) auditList(c *fiber.Ctx) error {
+	f := entity.AuditFilter{
+		ActorID:       c.Query("actor_id"),
+		Source:        c.Query("source"),
+		PullRequestID: c.Query("pull_request_id"),
+		Limit:         c.QueryInt("limit"),
+	}
+	entries, err := h.audit.List(c.Context(), f)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"entries": entries})
+}
+
+// notificationsList implements GET /admin/notifications?user_id=...&provider=...&status=...&kind=...&pull_request_id=...&since=...&limit=...
+// since is a duration back from now or an RFC 3339 time; it is unbounded by default.
+func (h *Handler) notificationsList(c *fiber.Ctx) error {
+	f := entity.NotificationFilter{
+		UserID:        c.Query("user_id"),
+		Provider:      c.Query("provider"),
+		Status:        c.Query("status"),
+		Kind:          c.Query("kind"),
+		PullRequestID: c.Query("pull_request_id"),
+		Limit:         c.QueryInt("limit"),
+	}
+	if s := c.Query("since"); s != "" {
+		since, ok := parseSince(s)
+		if !ok {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "since must be a duration or RFC 3339 time"}})
+		}
+		f.Since = since
+	}
+	deliveries, err := h.notifs.List(c.Context(), f)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"deliveries": deliveries})
+}
+
+// workloadRefresh implements POST /admin/workload/refresh
+func (h *Handler) workloadRefresh(c *fiber.Ctx) error {
+	refreshedAt, err := h.pr.RefreshWorkload(c.Context())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"refreshed_at": refreshedAt})
+}
+
+// pullRequestForceMerge implements POST /admin/pullRequest/merge
+// It merges regardless of the approvals the team requires; reason is audited.
+func (h *Handler) pullRequestForceMerge(c *fiber.Ctx) error {
+	var body struct {
+		PullRequestID string `json:"pull_request_id"`
+		ActorID       string `json:"actor_id"`
+		Reason        string `json:"reason"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.PullRequestID == "" || body.Reason == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "pull_request_id and reason required"}})
+	}
+	pr, err := h.pr.ForceMerge(c.Context(), body.PullRequestID, body.ActorID, body.Reason)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		case usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot merge a closed PR"}})
+		case usecase.ErrPRDraft:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_DRAFT", "message": "cannot merge a draft PR"}})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// directoryOffboard implements POST /admin/directory/offboard
+// The directory sync calls it with the users removed upstream.
+func (h *Handler) directoryOffboard(c *fiber.Ctx) error {
+	var body struct {
+		UserIDs []string `json:"user_ids"`
+		Source  string   `json:"source"`
+	}
+	if err := c.BodyParser(&body); err != nil || len(body.UserIDs) == 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_ids required"}})
+	}
+	if body.Source == "" {
+		body.Source = "directory"
+	}
+	report, err := h.pr.OffboardUsers(c.Context(), body.UserIDs, body.Source)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(report)
+}
+
+// clientsUsage implements GET /admin/clients/usage?since=...&client_id=...
+// since is a duration back from now or an RFC 3339 time; it defaults to 24h.
+func (h *Handler) clientsUsage(c *fiber.Ctx) error {
+	if h.usage == nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "usage tracking is disabled"}})
+	}
+	since := time.Now().Add(-24 * time.Hour)
+	if s := c.Query("since"); s != "" {
+		var ok bool
+		if since, ok = parseSince(s); !ok {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "since must be a duration or RFC 3339 time"}})
+		}
+	}
+	clients, err := h.usage.Report(c.Context(), since, c.Query("client_id"))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"since": since.UTC().Truncate(time.Hour), "clients": clients})
+}
+
+// clientQuotasList implements GET /admin/clients/quotas
+func (h *Handler) clientQuotasList(c *fiber.Ctx) error {
+	if h.quotas == nil {
+		return quotasDisabled(c)
+	}
+	quotas, err := h.quotas.List(c.Context())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"defaults": h.quotas.Defaults(), "quotas": quotas})
+}
+
+// clientQuotasSet implements PUT /admin/clients/quotas
+// The client's quota replaces the defaults; 0 leaves a period uncapped.
+func (h *Handler) clientQuotasSet(c *fiber.Ctx) error {
+	if h.quotas == nil {
+		return quotasDisabled(c)
+	}
+	var body entity.ClientQuota
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	quota, err := h.quotas.Set(c.Context(), entity.ClientQuota{ClientID: body.ClientID, Daily: body.Daily, Monthly: body.Monthly})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidClientQuota) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"quota": quota})
+}
+
+// clientQuotasDelete implements DELETE /admin/clients/quotas?client_id=...
+func (h *Handler) clientQuotasDelete(c *fiber.Ctx) error {
+	if h.quotas == nil {
+		return quotasDisabled(c)
+	}
+	if err := h.quotas.Delete(c.Context(), c.Query("client_id")); err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "client has no quota of its own"}})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// clientQuotasStatus implements GET /admin/clients/quotas/status?client_id=...
+func (h *Handler) clientQuotasStatus(c *fiber.Ctx) error {
+	if h.quotas == nil {
+		return quotasDisabled(c)
+	}
+	clientID := c.Query("client_id")
+	if clientID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "client_id required"}})
+	}
+	quota, status, err := h.quotas.Status(c.Context(), clientID, time.Now())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"quota": quota, "windows": status.Windows})
+}
+
+func quotasDisabled(c *fiber.Ctx) error {
+	return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "client quotas are disabled"}})
+}
+
+// captureSessions implements GET /admin/capture
+func (h *Handler) captureSessions(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"sessions": h.capture.Sessions()})
+}
+
+// captureStart implements POST /admin/capture
+// duration defaults to 15m and is capped by CAPTURE_MAX_DURATION.
+func (h *Handler) captureStart(c *fiber.Ctx) error {
+	var body struct {
+		TeamName      string `json:"team_name"`
+		PullRequestID string `json:"pull_request_id"`
+		Duration      string `json:"duration"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.TeamName == "" && body.PullRequestID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name or pull_request_id required"}})
+	}
+	var d time.Duration
+	if body.Duration != "" {
+		var err error
+		if d, err = time.ParseDuration(body.Duration); err != nil || d <= 0 {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "duration must be a positive duration, e.g. 30m"}})
+		}
+	}
+	session := h.capture.Start(body.TeamName, body.PullRequestID, d)
+	h.l.Warn("admin - capture started for team %q pr %q until %s", body.TeamName, body.PullRequestID, session.ExpiresAt.Format(time.RFC3339))
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"session": session})
+}
+
+// captureStop implements DELETE /admin/capture?id=...
+func (h *Handler) captureStop(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Query("id"), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "id required"}})
+	}
+	if !h.capture.Stop(id) {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "capture session not found"}})
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// captureEntries implements GET /admin/capture/entries?session_id=...
+func (h *Handler) captureEntries(c *fiber.Ctx) error {
+	var sessionID int64
+	if s := c.Query("session_id"); s != "" {
+		var err error
+		if sessionID, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid session_id"}})
+		}
+	}
+	return c.JSON(fiber.Map{"entries": h.capture.List(sessionID)})
+}
+
+// webhookDeliveries implements GET /admin/webhooks/deliveries?provider=...&failed=true&limit=...
+func (h *Handler) webhookDeliveries(c *fiber.Ctx) error {
+	f := entity.WebhookDeliveryFilter{
+		Provider:   c.Query("provider"),
+		FailedOnly: c.QueryBool("failed"),
+		Limit:      c.QueryInt("limit"),
+	}
+	deliveries, err := h.webhooks.ListDeliveries(c.Context(), f)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"deliveries": deliveries})
+}
+
+// webhookReplay implements POST /admin/webhooks/replay
+func (h *Handler) webhookReplay(c *fiber.Ctx) error {
+	var body struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if len(body.IDs) == 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "ids required"}})
+	}
+	results, err := h.webhooks.Replay(c.Context(), body.IDs)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"results": results})
+}
+
+// assignmentAcksList implements GET /admin/assignmentAcks?status=...&team_name=...
+// status=unacknowledged lists the assignments receivers never confirmed.
+func (h *Handler) assignmentAcksList(c *fiber.Ctx) error {
+	acks, err := h.pr.ListAssignmentAcks(c.Context(), entity.AssignmentAckFilter{
+		Status:   c.Query("status"),
+		TeamName: c.Query("team_name"),
+		Limit:    c.QueryInt("limit"),
+	})
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"acks": acks})
+}
+
+// assignmentAcksRetry implements POST /admin/assignmentAcks/retry
+func (h *Handler) assignmentAcksRetry(c *fiber.Ctx) error {
+	var body struct {
+		DeliveryID string `json:"delivery_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	ack, err := h.pr.RetryAssignmentAck(c.Context(), body.DeliveryID)
+	if err == usecase.ErrNotFound {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "delivery not found"}})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"ack": ack})
+}
+
+// reviewerConflictsList implements GET /admin/reviewerConflicts?pull_request_id=...&provider=...
+func (h *Handler) reviewerConflictsList(c *fiber.Ctx) error {
+	conflicts, err := h.pr.ListReviewerConflicts(c.Context(), entity.ReviewerConflictFilter{
+		PullRequestID: c.Query("pull_request_id"),
+		Provider:      c.Query("provider"),
+		Limit:         c.QueryInt("limit"),
+	})
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"conflicts": conflicts})
+}
+
+// chaosRules implements GET /admin/chaos
+func (h *Handler) chaosRules(c *fiber.Ctx) error {
+	if h.chaos == nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "chaos mode is disabled"}})
+	}
+	return c.JSON(fiber.Map{"rules": h.chaos.Rules()})
+}
+
+// chaosSet implements PUT /admin/chaos
+func (h *Handler) chaosSet(c *fiber.Ctx) error {
+	if h.chaos == nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "chaos mode is disabled"}})
+	}
+	var body struct {
+		Target string `json:"target"`
+		chaos.Rule
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if !h.chaos.Known(body.Target) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": fmt.Sprintf("target must be one of %s", strings.Join(h.chaos.Targets(), ", "))}})
+	}
+	if body.LatencyMS < 0 || body.ErrorRate < 0 || body.ErrorRate > 1 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "latency_ms must be >= 0 and error_rate in [0,1]"}})
+	}
+	h.chaos.Set(body.Target, body.Rule)
+	h.l.Warn("admin - chaos rule set for %s: %+v", body.Target, body.Rule)
+	return c.JSON(fiber.Map{"rules": h.chaos.Rules()})
+}
+
+// chaosReset implements DELETE /admin/chaos
+func (h *Handler) chaosReset(c *fiber.Ctx) error {
+	if h.chaos == nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "chaos mode is disabled"}})
+	}
+	h.chaos.Reset()
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// logLevels implements GET /admin/loglevel
+func (h *Handler) logLevels(c *fiber.Ctx) error {
+	global, modules := h.levels.Snapshot()
+	return c.JSON(fiber.Map{"level": global, "modules": modules})
+}
+
+// logLevelSet implements POST /admin/loglevel
+// An empty module changes the global level.
+func (h *Handler) logLevelSet(c *fiber.Ctx) error {
+	var body struct {
+		Level  string `json:"level"`
+		Module string `json:"module"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if err := h.levels.Set(body.Module, body.Level); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "level must be one of debug, info, warn, error"}})
+	}
+	h.l.Warn("admin - log level set to %s for module %q", body.Level, body.Module)
+	return h.logLevels(c)
+}
+
+// logLevelReset implements DELETE /admin/loglevel?module=...
+// The module follows the global level again.
+func (h *Handler) logLevelReset(c *fiber.Ctx) error {
+	module := c.Query("module")
+	if module == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "module required"}})
+	}
+	h.levels.Reset(module)
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// jobsList implements GET /admin/jobs
+func (h *Handler) jobsList(c *fiber.Ctx) error {
+	if h.jobs == nil {
+		return jobsUnreachable(c)
+	}
+	return c.JSON(fiber.Map{"jobs": h.jobs.Jobs()})
+}
+
+// jobsRun implements POST /admin/jobs/:name/run
+// The run starts in the background once any run in progress has finished.
+func (h *Handler) jobsRun(c *fiber.Ctx) error {
+	if h.jobs == nil {
+		return jobsUnreachable(c)
+	}
+	name := c.Params("name")
+	if err := h.jobs.Trigger(name); err != nil {
+		if errors.Is(err, scheduler.ErrUnknownJob) {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "job not found"}})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	h.l.Warn("admin - job %s triggered", name)
+	return c.Status(http.StatusAccepted).JSON(fiber.Map{"job": name})
+}
+
+// jobsUnreachable answers job requests under prefork: requests are served by
+// child processes, while the jobs run in the parent.
+func jobsUnreachable(c *fiber.Ctx) error {
+	return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "background jobs are not reachable in prefork mode"}})
+}
+
+// tasksList implements GET /admin/tasks?status=...&kind=...&limit=...
+func (h *Handler) tasksList(c *fiber.Ctx) error {
+	if h.tasks == nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "task queue is disabled"}})
+	}
+	status := c.Query("status")
+	switch status {
+	case "", taskqueue.StatusPending, taskqueue.StatusRunning, taskqueue.StatusDead:
+	default:
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "status must be pending, running or dead"}})
+	}
+	tasks, err := h.tasks.List(c.Context(), status, c.Query("kind"), c.QueryInt("limit"))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	return c.JSON(fiber.Map{"tasks": tasks})
+}
+
+// tasksRetry implements POST /admin/tasks/:id/retry
+// Only dead tasks can be retried; they run again with their attempts reset.
+func (h *Handler) tasksRetry(c *fiber.Ctx) error {
+	if h.tasks == nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "task queue is disabled"}})
+	}
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid task id"}})
+	}
+	if err := h.tasks.Retry(c.Context(), id); err != nil {
+		if errors.Is(err, taskqueue.ErrNotFound) {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "dead task not found"}})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+	h.l.Warn("admin - task %d requeued", id)
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// parseSince reads a since query value: a positive duration back from now or
+// an RFC 3339 time.
+func parseSince(s string) (time.Time, bool) {
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return time.Now().Add(-d), true
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminHeader carries the static admin token.
+const AdminHeader = "X-Admin-Token"
+
+// AdminAuth rejects requests that don't present the configured admin token.
+func AdminAuth(token string) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		got := ctx.Get(AdminHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return ctx.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "invalid admin token"}})
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/evrone/go-clean-template/pkg/capture"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CaptureRecorder keeps requests covered by a debug capture session.
+type CaptureRecorder interface {
+	Active(teams bool) bool
+	Match(teamName, prID string) (int64, bool)
+	Record(e capture.Entry) capture.Entry
+}
+
+// Capture records sanitized request and response bodies of requests that
+// concern a team or PR under an active capture session. teamOf resolves the
+// team of a PR when only its ID appears in the request; it is only consulted
+// while a team is being captured.
+func Capture(rec CaptureRecorder, teamOf func(ctx context.Context, prID string) (string, error)) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		if !rec.Active(false) {
+			return ctx.Next()
+		}
+
+		start := time.Now()
+
+		err := ctx.Next()
+
+		teamName, prID := ctx.Query("team_name"), ctx.Query("pull_request_id")
+		for _, body := range [][]byte{ctx.Body(), ctx.Response().Body()} {
+			t, p := captureIDs(body)
+			if teamName == "" {
+				teamName = t
+			}
+			if prID == "" {
+				prID = p
+			}
+		}
+
+		if teamName == "" && prID != "" && rec.Active(true) {
+			teamName, _ = teamOf(ctx.Context(), prID)
+		}
+
+		id, ok := rec.Match(teamName, prID)
+		if !ok {
+			return err
+		}
+
+		rec.Record(capture.Entry{
+			SessionID:     id,
+			Kind:          capture.KindHTTP,
+			TeamName:      teamName,
+			PullRequestID: prID,
+			Method:        ctx.Method(),
+			Path:          ctx.OriginalURL(),
+			Status:        ctx.Response().StatusCode(),
+			LatencyMs:     float64(time.Since(start)) / float64(time.Millisecond),
+			Request:       capture.Sanitize(ctx.Body()),
+			Response:      capture.Sanitize(ctx.Response().Body()),
+		})
+
+		return err
+	}
+}
+
+// captureIDs finds team_name and pull_request_id at the top level of a JSON
+// body or in one of its nested objects, e.g. {"pr": {...}}.
+func captureIDs(body []byte) (string, string) {
+	var top map[string]json.RawMessage
+	if json.Unmarshal(body, &top) != nil {
+		return "", ""
+	}
+
+	teamName, prID := idFields(top)
+	for _, raw := range top {
+		if teamName != "" && prID != "" {
+			break
+		}
+
+		var nested map[string]json.RawMessage
+		if json.Unmarshal(raw, &nested) != nil {
+			continue
+		}
+
+		t, p := idFields(nested)
+		if teamName == "" {
+			teamName = t
+		}
+		if prID == "" {
+			prID = p
+		}
+	}
+
+	return teamName, prID
+}
+
+func idFields(m map[string]json.RawMessage) (string, string) {
+	var teamName, prID string
+	_ = json.Unmarshal(m["team_name"], &teamName)
+	_ = json.Unmarshal(m["pull_request_id"], &prID)
+
+	return teamName, prID
+}
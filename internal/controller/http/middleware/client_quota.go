@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Error codes of the quota middleware: the client used up its request quota,
+// or the quota store could not be reached.
+const (
+	ErrorCodeQuotaExceeded    = "QUOTA_EXCEEDED"
+	ErrorCodeQuotaUnavailable = "QUOTA_UNAVAILABLE"
+)
+
+var (
+	quotaRejected = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_client_quota_rejected_total",
+		Help: "Requests rejected with 429 because the client's quota was used up.",
+	})
+	quotaErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_client_quota_errors_total",
+		Help: "Requests rejected with 503 because their quota could not be checked.",
+	})
+)
+
+// QuotaConsumer counts a request against the client's quotas.
+type QuotaConsumer interface {
+	Consume(ctx context.Context, clientID, fallbackID string, now time.Time) (entity.QuotaStatus, bool, error)
+}
+
+// ClientQuota rejects requests from clients that used up their quota with 429
+// QUOTA_EXCEEDED. Keys without a quota of their own, and requests without a
+// key, share the default quota of their remote address, so rotating keys
+// doesn't reset it. Responses of capped clients carry X-RateLimit-* headers
+// for the period closest to running out. Requests fail closed with 503
+// QUOTA_UNAVAILABLE when the quota store fails.
+func ClientQuota(q QuotaConsumer) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		clientID := ClientID(ctx.Get(APIKeyHeader))
+
+		status, allowed, err := q.Consume(ctx.Context(), clientID, AddressClientID(ctx.IP()), time.Now())
+		if err != nil {
+			quotaErrors.Inc()
+			ctx.Set(fiber.HeaderRetryAfter, "1")
+			return ctx.Status(http.StatusServiceUnavailable).JSON(fiber.Map{"error": fiber.Map{"code": ErrorCodeQuotaUnavailable, "message": "request quota could not be checked"}})
+		}
+
+		w, capped := status.Tightest()
+		if capped {
+			ctx.Set("X-RateLimit-Limit", strconv.FormatInt(w.Limit, 10))
+			ctx.Set("X-RateLimit-Remaining", strconv.FormatInt(w.Remaining, 10))
+			ctx.Set("X-RateLimit-Reset", strconv.FormatInt(w.ResetAt.Unix(), 10))
+		}
+
+		if !allowed {
+			quotaRejected.Inc()
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(1, int(time.Until(w.ResetAt).Seconds()))))
+
+			return ctx.Status(http.StatusTooManyRequests).JSON(fiber.Map{"error": fiber.Map{"code": ErrorCodeQuotaExceeded, "message": "request quota for the " + w.Period + " used up"}})
+		}
+
+		return ctx.Next()
+	}
+}
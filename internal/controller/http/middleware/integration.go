@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IntegrationHeader carries the secret shared with VCS integrations.
+const IntegrationHeader = "X-Integration-Secret"
+
+// IntegrationAuth rejects requests that don't present secret. With no secret
+// configured every request is rejected.
+func IntegrationAuth(secret string) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		got := ctx.Get(IntegrationHeader)
+		if secret == "" || subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+			return ctx.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "invalid integration secret"}})
+		}
+
+		return ctx.Next()
+	}
+}
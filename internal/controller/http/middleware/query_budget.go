@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/evrone/go-clean-template/pkg/logger"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestQueries = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pr_service_http_request_db_queries",
+		Help:    "Database queries run while serving a request.",
+		Buckets: []float64{0, 1, 2, 5, 10, 20, 50, 100, 250},
+	}, []string{"route"})
+	requestsOverBudget = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_http_requests_over_query_budget_total",
+		Help: "Requests that ran more database queries than the budget.",
+	}, []string{"route"})
+)
+
+// QueryBudget counts the database queries each request runs, by route, and
+// logs requests running more than budget of them. budget <= 0 only counts.
+func QueryBudget(budget int, l logger.Interface) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		counter := &pgdb.QueryCounter{}
+		ctx.Locals(pgdb.CounterKey{}, counter)
+
+		err := ctx.Next()
+
+		route, queries := ctx.Route().Path, counter.Queries()
+		requestQueries.WithLabelValues(route).Observe(float64(queries))
+
+		if budget > 0 && queries > int64(budget) {
+			requestsOverBudget.WithLabelValues(route).Inc()
+			l.Warn("http - %s %s ran %d queries in %s, over the budget of %d",
+				ctx.Method(), route, queries, counter.Duration(), budget)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// APIKeyHeader carries the key an integration identifies itself with.
+const APIKeyHeader = "X-API-Key"
+
+var (
+	clientRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_client_requests_total",
+		Help: "Requests served per route and status code.",
+	}, []string{"method", "route", "status"})
+	clientLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pr_service_client_request_duration_seconds",
+		Help:    "Request latency per route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// UsageRecorder tallies served requests.
+type UsageRecorder interface {
+	Record(r entity.APIRequest)
+}
+
+// Usage attributes every request to the client presenting it and reports it
+// to rec. Keys are never stored: a client is identified by a short
+// fingerprint of its key, or entity.AnonymousClient without one. Prometheus
+// only gets the route and status, since any caller can mint new client IDs.
+func Usage(rec UsageRecorder) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		err := ctx.Next()
+
+		status := ctx.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+		}
+
+		r := entity.APIRequest{
+			ClientID: ClientID(ctx.Get(APIKeyHeader)),
+			Method:   ctx.Method(),
+			Route:    ctx.Route().Path,
+			Status:   status,
+			Latency:  time.Since(start),
+			At:       start,
+		}
+		rec.Record(r)
+
+		clientRequests.WithLabelValues(r.Method, r.Route, strconv.Itoa(r.Status)).Inc()
+		clientLatency.WithLabelValues(r.Method, r.Route).Observe(r.Latency.Seconds())
+
+		return err
+	}
+}
+
+// ClientID returns the fingerprint usage is recorded under for key.
+func ClientID(key string) string {
+	if key == "" {
+		return entity.AnonymousClient
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return "key_" + hex.EncodeToString(sum[:6])
+}
+
+// AddressClientID returns the identity quotas fall back to for callers from
+// ip that have no quota of their own.
+func AddressClientID(ip string) string {
+	sum := sha256.Sum256([]byte(ip))
+	return "addr_" + hex.EncodeToString(sum[:6])
+}
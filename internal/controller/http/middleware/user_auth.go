@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/oidc"
+	"github.com/gofiber/fiber/v2"
+)
+
+// UserIDKey is the Locals key UserAuth stores the caller's user ID under.
+const UserIDKey = "user_id"
+
+// AdminKey is the Locals key Actor sets to true for requests made with the
+// admin token.
+const AdminKey = "admin"
+
+// TokenVerifier checks bearer tokens.
+type TokenVerifier interface {
+	Verify(ctx context.Context, raw string) (oidc.Claims, error)
+}
+
+// CallerResolver maps a verified token subject to a service user ID.
+type CallerResolver func(ctx context.Context, subject string) (string, error)
+
+// UserAuth authenticates the caller by their OpenID Connect bearer token and
+// stores their user ID under UserIDKey.
+func UserAuth(v TokenVerifier, resolve CallerResolver) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		raw, ok := strings.CutPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || raw == "" {
+			return ctx.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "bearer token required"}})
+		}
+
+		if ok, err := authenticate(ctx, v, resolve, raw); !ok {
+			return err
+		}
+
+		return ctx.Next()
+	}
+}
+
+// Actor identifies who asks for a team management operation without
+// requiring it: the admin token sets AdminKey, and a bearer token, when v is
+// set, is resolved to the caller's user ID under UserIDKey. An invalid
+// credential is rejected rather than ignored. Requests with neither fall back
+// to the actor_id the handler reads from the body.
+func Actor(adminToken string, v TokenVerifier, resolve CallerResolver) func(c *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		if got := ctx.Get(AdminHeader); got != "" {
+			if adminToken == "" || subtle.ConstantTimeCompare([]byte(got), []byte(adminToken)) != 1 {
+				return ctx.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "invalid admin token"}})
+			}
+			ctx.Locals(AdminKey, true)
+			return ctx.Next()
+		}
+
+		raw, ok := strings.CutPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+		if ok && raw != "" && v != nil {
+			if ok, err := authenticate(ctx, v, resolve, raw); !ok {
+				return err
+			}
+		}
+
+		return ctx.Next()
+	}
+}
+
+// authenticate verifies raw and stores the caller's user ID under UserIDKey.
+// When ok is false the request has been answered and err is what writing the
+// answer returned.
+func authenticate(ctx *fiber.Ctx, v TokenVerifier, resolve CallerResolver, raw string) (ok bool, err error) {
+	claims, err := v.Verify(ctx.Context(), raw)
+	if errors.Is(err, oidc.ErrInvalidToken) {
+		return false, ctx.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": err.Error()}})
+	}
+	if err != nil {
+		return false, ctx.Status(http.StatusServiceUnavailable).JSON(fiber.Map{"error": fiber.Map{"code": "AUTH_UNAVAILABLE", "message": "identity provider unavailable"}})
+	}
+
+	userID, err := resolve(ctx.Context(), claims.Subject)
+	if errors.Is(err, usecase.ErrNotFound) {
+		return false, ctx.Status(http.StatusForbidden).JSON(fiber.Map{"error": fiber.Map{"code": "UNKNOWN_USER", "message": "token does not belong to a known user"}})
+	}
+	if err != nil {
+		return false, ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	}
+
+	ctx.Locals(UserIDKey, userID)
+
+	return true, nil
+}
@@ -8,10 +8,18 @@ import (
 	"github.com/evrone/go-clean-template/config"
 	"github.com/evrone/go-clean-template/docs"
 	_ "github.com/evrone/go-clean-template/docs" // Swagger docs.
+	"github.com/evrone/go-clean-template/internal/controller/http/admin"
 	"github.com/evrone/go-clean-template/internal/controller/http/middleware"
+	"github.com/evrone/go-clean-template/internal/controller/http/status"
 	v1 "github.com/evrone/go-clean-template/internal/controller/http/v1"
 	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/capture"
+	"github.com/evrone/go-clean-template/pkg/chaos"
 	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/evrone/go-clean-template/pkg/oidc"
+	"github.com/evrone/go-clean-template/pkg/sandbox"
+	"github.com/evrone/go-clean-template/pkg/scheduler"
+	"github.com/evrone/go-clean-template/pkg/taskqueue"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/swagger"
 )
@@ -23,10 +31,11 @@ import (
 // @version     1.0
 // @host        localhost:8080
 // @BasePath    /v1
-func NewRouter(app *fiber.App, cfg *config.Config, pr *usecase.PRUseCase, users usecase.UserRepo, teams usecase.TeamRepo, prs usecase.PRRepo, l logger.Interface) {
+func NewRouter(app *fiber.App, cfg *config.Config, pr *usecase.PRUseCase, teams usecase.TeamRepo, prs usecase.PRRepo, search usecase.SearchRepo, bus *usecase.Bus, analytics usecase.AnalyticsRepo, webhooks *usecase.WebhookUseCase, audit usecase.AuditRepo, notifications *usecase.NotificationLogUseCase, schema usecase.SchemaRepo, usage *usecase.UsageUseCase, quotas *usecase.ClientQuotaUseCase, sandboxRecorder *sandbox.Recorder, captureRecorder *capture.Recorder, injector *chaos.Injector, verifier *oidc.Verifier, levels *logger.Levels, jobs *scheduler.Scheduler, tasks *taskqueue.Queue, l logger.Interface) {
 	// Options
 	app.Use(middleware.Logger(l))
 	app.Use(middleware.Recovery(l))
+	app.Use(middleware.QueryBudget(cfg.PG.QueryBudget, l))
 
 	// Prometheus metrics
 	if cfg.Metrics.Enabled {
@@ -52,9 +61,96 @@ func NewRouter(app *fiber.App, cfg *config.Config, pr *usecase.PRUseCase, users
 	// K8s probe
 	app.Get("/healthz", func(ctx *fiber.Ctx) error { return ctx.SendStatus(http.StatusOK) })
 
+	// Build and runtime info
+	status.NewHandler(cfg, schema, l).RegisterRoutes(app)
+
 	// Routers
 	apiV1Group := app.Group("/v1")
 	{
-		v1.NewHandler(pr, users, teams, prs, l).RegisterPRRoutes(apiV1Group)
+		// Per-client usage, including requests the limiter sheds
+		if usage != nil {
+			apiV1Group.Use(middleware.Usage(usage))
+		}
+
+		// Verbose capture for teams and PRs under debug
+		apiV1Group.Use(middleware.Capture(captureRecorder, pr.PRTeam))
+
+		// Per-client request quotas
+		if quotas != nil {
+			apiV1Group.Use(middleware.ClientQuota(quotas))
+		}
+
+		// Shed PR creation (direct or via VCS webhooks) under saturation before it reaches Postgres
+		limiter := middleware.Limiter(cfg.Limiter.MaxInFlight, cfg.Limiter.QueueTimeout, cfg.Limiter.RetryAfter)
+		apiV1Group.Use("/pullRequest/create", limiter)
+		apiV1Group.Use("/webhooks/github", limiter)
+		apiV1Group.Use("/webhooks/bitbucket", limiter)
+		apiV1Group.Use("/webhooks/gitlab", limiter)
+
+		// Only VCS integrations may report a PR's state there
+		apiV1Group.Use("/pullRequest/externalState", middleware.IntegrationAuth(cfg.Integrations.Secret))
+
+		// Who asks for lead-only team management: admin token, OIDC bearer or actor_id
+		var tokens middleware.TokenVerifier
+		if verifier != nil {
+			tokens = verifier
+		}
+		actor := middleware.Actor(cfg.Admin.Token, tokens, pr.ResolveCaller)
+		for _, path := range []string{
+			"/users/deactivateTeam", "/users/setIsActive", "/users/setProfile", "/users/absences/add", "/users/transferTeam",
+			"/pullRequest/reassign", "/pullRequest/addReviewer", "/pullRequest/removeReviewer", "/pullRequest/claim",
+			"/team/settings", "/team/rename", "/team/removeMember", "/team/delegations/add", "/team/delegations/revoke",
+			"/team/webhooks/add", "/team/webhooks/update", "/team/webhooks/delete", "/team/webhooks/test",
+			"/team/oncall/rotation", "/team/oncall/override", "/reviewRules/add", "/reviewExclusions/add",
+			"/users/linkIdentity",
+		} {
+			apiV1Group.Use(path, actor)
+		}
+		apiV1Group.Delete("/users/:user_id", actor)
+		apiV1Group.Delete("/team/:team_name", actor)
+
+		prHandler := v1.NewHandler(pr, teams, prs, search, l)
+		prHandler.RegisterPRRoutes(apiV1Group)
+
+		// Self-service routes for the caller identified by their OIDC token
+		if verifier != nil {
+			prHandler.RegisterMeRoutes(apiV1Group, middleware.UserAuth(verifier, pr.ResolveCaller))
+		}
+
+		// Every process has its own bus, so a prefork child would only stream
+		// the changes it served itself
+		if !cfg.HTTP.UsePreforkMode {
+			v1.NewEventsHandler(bus).RegisterRoutes(apiV1Group)
+		}
+
+		// Analytics endpoints are only mounted when ClickHouse is configured
+		if analytics != nil {
+			v1.NewAnalyticsHandler(analytics, l).RegisterRoutes(apiV1Group)
+		}
+
+		webhookHandler := v1.NewWebhookHandler(webhooks, cfg.Telegram.WebhookSecret, cfg.GitHub.WebhookSecret, cfg.Bitbucket.WebhookSecret, cfg.GitLab.WebhookSecret, l)
+		if cfg.Telegram.Token != "" {
+			webhookHandler.RegisterTelegramRoutes(apiV1Group)
+		}
+		if cfg.GitHub.WebhookSecret != "" {
+			webhookHandler.RegisterGitHubRoutes(apiV1Group)
+		}
+		if cfg.Bitbucket.WebhookSecret != "" {
+			webhookHandler.RegisterBitbucketRoutes(apiV1Group)
+		}
+		if cfg.GitLab.WebhookSecret != "" {
+			webhookHandler.RegisterGitLabRoutes(apiV1Group)
+		}
+	}
+
+	// Admin routes are only mounted when a token is configured. Under prefork
+	// they are served by children, which can't see or trigger the jobs of the
+	// parent.
+	if cfg.HTTP.UsePreforkMode {
+		jobs = nil
+	}
+	if cfg.Admin.Token != "" {
+		adminGroup := app.Group("/admin", middleware.AdminAuth(cfg.Admin.Token))
+		admin.NewHandler(pr, sandboxRecorder, captureRecorder, webhooks, audit, notifications, usage, quotas, injector, levels, jobs, tasks, l).RegisterRoutes(adminGroup)
 	}
 }
@@ -0,0 +1,111 @@
+// Package status serves the unauthenticated build and runtime introspection
+// endpoints used to tell deployments apart.
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evrone/go-clean-template/config"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/buildinfo"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// _schemaTimeout bounds the migration lookup so /status stays responsive
+// while Postgres is not.
+const _schemaTimeout = 2 * time.Second
+
+type Handler struct {
+	cfg    *config.Config
+	schema usecase.SchemaRepo
+	l      logger.Interface
+}
+
+func NewHandler(cfg *config.Config, schema usecase.SchemaRepo, l logger.Interface) *Handler {
+	return &Handler{cfg: cfg, schema: schema, l: l}
+}
+
+func (h *Handler) RegisterRoutes(router fiber.Router) {
+	router.Get("/version", h.version)
+	router.Get("/status", h.status)
+}
+
+type versionResponse struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	buildinfo.Info
+}
+
+// version implements GET /version
+func (h *Handler) version(c *fiber.Ctx) error {
+	return c.JSON(h.versionInfo())
+}
+
+// status implements GET /status. A failed migration lookup is reported in
+// the body rather than as an error status, since the process itself is up.
+func (h *Handler) status(c *fiber.Ctx) error {
+	migration := fiber.Map{}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), _schemaTimeout)
+	defer cancel()
+
+	v, dirty, err := h.schema.Version(ctx)
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		migration["error"] = "no migrations applied"
+	case err != nil:
+		h.l.Error(fmt.Errorf("http - status - schema.Version: %w", err))
+		migration["error"] = err.Error()
+	default:
+		migration["version"] = v
+		migration["dirty"] = dirty
+	}
+
+	return c.JSON(fiber.Map{
+		"build":          h.versionInfo(),
+		"env":            h.cfg.App.Env,
+		"started_at":     buildinfo.StartedAt().UTC(),
+		"uptime_seconds": int64(buildinfo.Uptime().Seconds()),
+		"features":       features(h.cfg),
+		"migration":      migration,
+	})
+}
+
+func (h *Handler) versionInfo() versionResponse {
+	return versionResponse{Name: h.cfg.App.Name, Version: h.cfg.App.Version, Info: buildinfo.Get()}
+}
+
+// features lists the optional subsystems cfg turns on.
+func features(cfg *config.Config) []string {
+	out := []string{}
+	for _, f := range []struct {
+		name string
+		on   bool
+	}{
+		{"metrics", cfg.Metrics.Enabled},
+		{"swagger", cfg.Swagger.Enabled},
+		{"admin", cfg.Admin.Token != ""},
+		{"sandbox", cfg.Integrations.Sandbox},
+		{"chaos", cfg.Chaos.Enabled},
+		{"analytics", cfg.ClickHouse.URL != ""},
+		{"telegram", cfg.Telegram.Token != ""},
+		{"slack", cfg.Slack.Token != ""},
+		{"email", cfg.SMTP.Host != ""},
+		{"github", cfg.GitHub.WebhookSecret != ""},
+		{"bitbucket", cfg.Bitbucket.WebhookSecret != ""},
+		{"gitlab", cfg.GitLab.WebhookSecret != ""},
+		{"usage_tracking", cfg.Usage.FlushInterval > 0},
+		{"client_quotas", cfg.ClientQuota.Enabled},
+		{"notification_tracking", cfg.Notify.TrackDeliveries},
+	} {
+		if f.on {
+			out = append(out, f.name)
+		}
+	}
+
+	return out
+}
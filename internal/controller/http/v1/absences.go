@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
+	"github.com/evrone/go-clean-template/internal/entity"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+// usersAbsencesList implements GET /users/absences?user_id=...
+func (h *PRHandler) usersAbsencesList(c *fiber.Ctx) error {
+	id := c.Query("user_id")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	absences, err := h.uc.ListAbsences(c.Context(), id)
+	if err != nil {
+		return absenceError(c, err)
+	}
+	return c.JSON(response.All(absences).With(fiber.Map{"user_id": id}))
+}
+
+// usersAbsencesAdd implements POST /users/absences/add
+// An absence that has already started hands the user's open reviews over at once.
+func (h *PRHandler) usersAbsencesAdd(c *fiber.Ctx) error {
+	var body struct {
+		entity.Absence
+		ActorID string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.UserID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	absence, err := h.uc.CreateAbsence(h.actorContext(c.Context(), c, body.ActorID), entity.Absence{
+		UserID:   body.UserID,
+		StartsAt: body.StartsAt,
+		EndsAt:   body.EndsAt,
+		Reason:   body.Reason,
+	})
+	if err != nil {
+		return absenceError(c, err)
+	}
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"absence": absence})
+}
+
+// usersAbsencesUpdate implements POST /users/absences/update
+func (h *PRHandler) usersAbsencesUpdate(c *fiber.Ctx) error {
+	var body struct {
+		ID       int64      `json:"id"`
+		StartsAt *time.Time `json:"starts_at"`
+		EndsAt   *time.Time `json:"ends_at"`
+		Reason   *string    `json:"reason"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	absence, err := h.uc.UpdateAbsence(c.Context(), body.ID, usecase.AbsenceUpdate{
+		StartsAt: body.StartsAt,
+		EndsAt:   body.EndsAt,
+		Reason:   body.Reason,
+	})
+	if err != nil {
+		return absenceError(c, err)
+	}
+	return c.JSON(fiber.Map{"absence": absence})
+}
+
+// usersAbsencesDelete implements POST /users/absences/delete
+func (h *PRHandler) usersAbsencesDelete(c *fiber.Ctx) error {
+	var body struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if err := h.uc.DeleteAbsence(c.Context(), body.ID); err != nil {
+		return absenceError(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func absenceError(c *fiber.Ctx, err error) error {
+	switch {
+	case err == usecase.ErrNotFound:
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "resource not found"}})
+	case err == usecase.ErrForbidden:
+		return forbidden(c)
+	case errors.Is(err, usecase.ErrInvalidAbsence):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	return internalError(c, err)
+}
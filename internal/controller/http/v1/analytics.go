@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
+	"github.com/evrone/go-clean-template/internal/entity"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	_defaultAnalyticsRange   = 30 * 24 * time.Hour
+	_defaultLeaderboardLimit = 10
+	_maxLeaderboardLimit     = 100
+)
+
+// AnalyticsHandler serves reporting endpoints from the analytics store.
+type AnalyticsHandler struct {
+	repo usecase.AnalyticsRepo
+	l    logger.Interface
+}
+
+func NewAnalyticsHandler(repo usecase.AnalyticsRepo, l logger.Interface) *AnalyticsHandler {
+	return &AnalyticsHandler{repo: repo, l: l}
+}
+
+func (h *AnalyticsHandler) RegisterRoutes(router fiber.Router) {
+	analyticsGroup := router.Group("/analytics")
+	analyticsGroup.Get("/timeseries", h.timeSeries)
+	analyticsGroup.Get("/heatmap", h.heatmap)
+	analyticsGroup.Get("/leaderboard", h.leaderboard)
+}
+
+// timeSeries implements GET /analytics/timeseries?from=...&to=...&bucket=day|week
+func (h *AnalyticsHandler) timeSeries(c *fiber.Ctx) error {
+	rng, err := parseAnalyticsRange(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	bucket := c.Query("bucket", entity.BucketDay)
+	if bucket != entity.BucketDay && bucket != entity.BucketWeek {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "bucket must be day or week"}})
+	}
+	buckets, err := h.repo.TimeSeries(c.Context(), rng, bucket)
+	if err != nil {
+		h.l.Error(fmt.Errorf("http - v1 - analytics - timeseries: %w", err))
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(buckets).With(fiber.Map{"bucket": bucket}))
+}
+
+// heatmap implements GET /analytics/heatmap?from=...&to=...&type=created
+func (h *AnalyticsHandler) heatmap(c *fiber.Ctx) error {
+	rng, err := parseAnalyticsRange(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	cells, err := h.repo.Heatmap(c.Context(), rng, c.Query("type", entity.PREventCreated))
+	if err != nil {
+		h.l.Error(fmt.Errorf("http - v1 - analytics - heatmap: %w", err))
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(cells))
+}
+
+// leaderboard implements GET /analytics/leaderboard?from=...&to=...&limit=...
+func (h *AnalyticsHandler) leaderboard(c *fiber.Ctx) error {
+	rng, err := parseAnalyticsRange(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	limit := c.QueryInt("limit", _defaultLeaderboardLimit)
+	if limit < 1 || limit > _maxLeaderboardLimit {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": fmt.Sprintf("limit must be within 1..%d", _maxLeaderboardLimit)}})
+	}
+	entries, err := h.repo.Leaderboard(c.Context(), rng, limit)
+	if err != nil {
+		h.l.Error(fmt.Errorf("http - v1 - analytics - leaderboard: %w", err))
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(entries))
+}
+
+// parseAnalyticsRange reads RFC 3339 from/to; the default is the last 30 days.
+func parseAnalyticsRange(c *fiber.Ctx) (entity.AnalyticsRange, error) {
+	rng := entity.AnalyticsRange{To: time.Now().UTC()}
+
+	if s := c.Query("to"); s != "" {
+		to, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return entity.AnalyticsRange{}, fmt.Errorf("to must be RFC 3339")
+		}
+		rng.To = to
+	}
+
+	rng.From = rng.To.Add(-_defaultAnalyticsRange)
+	if s := c.Query("from"); s != "" {
+		from, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return entity.AnalyticsRange{}, fmt.Errorf("from must be RFC 3339")
+		}
+		rng.From = from
+	}
+
+	if !rng.From.Before(rng.To) {
+		return entity.AnalyticsRange{}, fmt.Errorf("from must be before to")
+	}
+
+	return rng, nil
+}
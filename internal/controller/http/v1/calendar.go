@@ -0,0 +1,49 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/ical"
+	"github.com/gofiber/fiber/v2"
+)
+
+// _reviewSlot is how long the calendar block for a due review lasts.
+const _reviewSlot = 30 * time.Minute
+
+// usersReviewCalendar implements GET /users/reviewCalendar.ics?user_id=...
+func (h *PRHandler) usersReviewCalendar(c *fiber.Ctx) error {
+	id := c.Query("user_id")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	reviews, err := h.uc.ReviewCalendar(c.Context(), id)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		}
+		return internalError(c, err)
+	}
+
+	cal := ical.Calendar{
+		ProdID: "-//pr-service//review calendar//EN",
+		Name:   "Reviews for " + id,
+		Events: make([]ical.Event, 0, len(reviews)),
+	}
+	for _, r := range reviews {
+		cal.Events = append(cal.Events, ical.Event{
+			UID:         r.PullRequestID + "@pr-service",
+			Summary:     "Review: " + r.PullRequestName,
+			Description: fmt.Sprintf("PR %s by %s, assigned %s", r.PullRequestID, r.AuthorID, r.AssignedAt.Format(time.RFC3339)),
+			Start:       r.DueAt.Add(-_reviewSlot),
+			End:         r.DueAt,
+			Stamp:       r.UpdatedAt,
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="reviewCalendar.ics"`)
+	return c.Send(cal.Encode())
+}
@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/middleware"
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
+	"github.com/evrone/go-clean-template/internal/entity"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+// teamDelegationsList implements GET /team/delegations?team_name=...&active=...
+func (h *PRHandler) teamDelegationsList(c *fiber.Ctx) error {
+	name := c.Query("team_name")
+	if name == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	delegations, err := h.uc.ListDelegations(c.Context(), name, c.QueryBool("active"))
+	if err != nil {
+		return delegationError(c, err)
+	}
+	return c.JSON(response.All(delegations).With(fiber.Map{"team_name": name}))
+}
+
+// teamDelegationsAdd implements POST /team/delegations/add
+// starts_at defaults to now. The delegator is the acting lead; delegator_id
+// may only name someone else on admin-token requests.
+func (h *PRHandler) teamDelegationsAdd(c *fiber.Ctx) error {
+	var body struct {
+		TeamName    string    `json:"team_name"`
+		DelegatorID string    `json:"delegator_id"`
+		DelegateID  string    `json:"delegate_id"`
+		Scopes      []string  `json:"scopes"`
+		StartsAt    time.Time `json:"starts_at"`
+		EndsAt      time.Time `json:"ends_at"`
+		ActorID     string    `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.TeamName == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	delegation, err := h.uc.CreateDelegation(h.actorContext(c.Context(), c, body.ActorID), entity.Delegation{
+		TeamName:    body.TeamName,
+		DelegatorID: body.DelegatorID,
+		DelegateID:  body.DelegateID,
+		Scopes:      body.Scopes,
+		StartsAt:    body.StartsAt,
+		EndsAt:      body.EndsAt,
+	})
+	if err != nil {
+		return delegationError(c, err)
+	}
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"delegation": delegation})
+}
+
+// teamDelegationsRevoke implements POST /team/delegations/revoke
+func (h *PRHandler) teamDelegationsRevoke(c *fiber.Ctx) error {
+	var body struct {
+		ID      int64  `json:"id"`
+		ActorID string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	delegation, err := h.uc.RevokeDelegation(h.actorContext(c.Context(), c, body.ActorID), body.ID)
+	if err != nil {
+		return delegationError(c, err)
+	}
+	return c.JSON(fiber.Map{"delegation": delegation})
+}
+
+func delegationError(c *fiber.Ctx, err error) error {
+	switch {
+	case err == usecase.ErrNotFound:
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "resource not found"}})
+	case err == usecase.ErrForbidden:
+		return forbidden(c)
+	case errors.Is(err, usecase.ErrInvalidDelegation):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	return internalError(c, err)
+}
+
+// actorContext attaches who asks for a team management operation to ctx:
+// nobody for admin-token requests, which the use cases don't restrict, the
+// caller of a verified bearer token, or else the actor_id from the body. The
+// body's actor_id is self-asserted, so it is ignored when RBAC is enforced.
+func (h *PRHandler) actorContext(ctx context.Context, c *fiber.Ctx, actorID string) context.Context {
+	if admin, _ := c.Locals(middleware.AdminKey).(bool); admin {
+		return ctx
+	}
+	if h.uc.RBACEnforced() {
+		actorID = ""
+	}
+	if id := callerID(c); id != "" {
+		actorID = id
+	}
+	return usecase.WithActor(ctx, actorID)
+}
+
+// forbidden reports that the actor lacks the team permission for the request.
+func forbidden(c *fiber.Ctx) error {
+	return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": fiber.Map{"code": "FORBIDDEN", "message": "actor is not allowed to manage this team"}})
+}
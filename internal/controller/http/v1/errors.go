@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/gofiber/fiber/v2"
+)
+
+// _poolRetryAfter is the Retry-After hint, in seconds, sent with POOL_EXHAUSTED.
+const _poolRetryAfter = "1"
+
+// internalError answers an unexpected error: 503 POOL_EXHAUSTED when the
+// database pool stayed saturated, 409 PR_IMMUTABLE when an update would have
+// rewritten a merged PR's history, 500 INTERNAL otherwise.
+func internalError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, postgres.ErrPoolExhausted) {
+		c.Set(fiber.HeaderRetryAfter, _poolRetryAfter)
+		return c.Status(http.StatusServiceUnavailable).JSON(fiber.Map{"error": fiber.Map{"code": response.ErrorCodePoolExhausted, "message": "database is overloaded, retry later"}})
+	}
+	if errors.Is(err, usecase.ErrInvalidEntity) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	if errors.Is(err, usecase.ErrPRImmutable) {
+		return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": response.ErrorCodePRImmutable, "message": "merged PR author, reviewers and merge cannot be changed"}})
+	}
+	return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// _streamBuffer is how many events a slow stream client may fall behind
+	// before it misses some; the bus counts them as dropped.
+	_streamBuffer = 64
+	// _streamKeepAlive is how often an idle stream gets a comment, which is
+	// also how a client that went away is noticed.
+	_streamKeepAlive = 15 * time.Second
+)
+
+// EventsHandler streams PR and user events from the in-process bus.
+type EventsHandler struct {
+	bus *usecase.Bus
+}
+
+func NewEventsHandler(bus *usecase.Bus) *EventsHandler {
+	return &EventsHandler{bus: bus}
+}
+
+func (h *EventsHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/events/stream", h.stream)
+}
+
+// stream implements GET /events/stream?team_name=... as Server-Sent Events:
+// "pr" events carry an entity.PREvent and "user" events an entity.UserEvent.
+func (h *EventsHandler) stream(c *fiber.Ctx) error {
+	team := c.Query("team_name")
+
+	prs := h.bus.PRs.Subscribe("stream", _streamBuffer)
+	users := h.bus.Users.Subscribe("stream", _streamBuffer)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer prs.Cancel()
+		defer users.Cancel()
+
+		keepAlive := time.NewTicker(_streamKeepAlive)
+		defer keepAlive.Stop()
+
+		// A write to a client that went away fails on flush, which ends the
+		// stream and its subscriptions.
+		for {
+			var err error
+
+			select {
+			case e, ok := <-prs.C:
+				if !ok {
+					return
+				}
+				if team == "" || e.TeamName == team {
+					err = writeEvent(w, "pr", e)
+				}
+			case e, ok := <-users.C:
+				if !ok {
+					return
+				}
+				if team == "" || e.TeamName == team {
+					err = writeEvent(w, "user", e)
+				}
+			case <-keepAlive.C:
+				_, err = w.WriteString(": keep-alive\n\n")
+				if err == nil {
+					err = w.Flush()
+				}
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+func writeEvent[T entity.PREvent | entity.UserEvent](w *bufio.Writer, name string, e T) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("http - v1 - events - marshal: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
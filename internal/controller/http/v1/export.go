@@ -0,0 +1,365 @@
+package v1
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/pagination"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Export formats of GET /stats.
+const (
+	exportCSV  = "csv"
+	exportXLSX = "xlsx"
+)
+
+// Datasets an export can hold.
+const (
+	exportPullRequests = "pull_requests"
+	exportUsers        = "users"
+)
+
+// _exportTimeout bounds reading a whole export once the response has started.
+const _exportTimeout = 5 * time.Minute
+
+// exportStats implements GET /stats?format=csv|xlsx&dataset=pull_requests|users
+// A CSV export holds one dataset, pull_requests by default; an XLSX export has
+// a sheet per dataset, all of them unless dataset is given. Rows are streamed
+// as they are read, so an error after the first byte can only cut the output
+// short; it is logged.
+func (h *PRHandler) exportStats(c *fiber.Ctx, format string) error {
+	if format != exportCSV && format != exportXLSX {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "format must be json, csv or xlsx"}})
+	}
+
+	var datasets []string
+	switch dataset := c.Query("dataset"); {
+	case dataset == exportPullRequests || dataset == exportUsers:
+		datasets = []string{dataset}
+	case dataset != "":
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "dataset must be pull_requests or users"}})
+	case format == exportCSV:
+		datasets = []string{exportPullRequests}
+	default:
+		datasets = []string{exportPullRequests, exportUsers}
+	}
+
+	name := "stats"
+	if len(datasets) == 1 {
+		name = datasets[0]
+	}
+	c.Set(fiber.HeaderContentType, exportContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+
+	// The stream is written after the handler returns, when c is gone.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), _exportTimeout)
+		defer cancel()
+
+		enc := newExportEncoder(format, w)
+		for _, dataset := range datasets {
+			var err error
+			if dataset == exportUsers {
+				err = h.exportUsers(ctx, enc)
+			} else {
+				err = h.exportPullRequests(ctx, enc)
+			}
+			if err != nil {
+				h.l.Error(fmt.Errorf("http - v1 - exportStats - %s: %w", dataset, err))
+				return
+			}
+		}
+		if err := enc.Close(); err != nil {
+			h.l.Error(fmt.Errorf("http - v1 - exportStats: %w", err))
+		}
+	})
+
+	return nil
+}
+
+func (h *PRHandler) exportPullRequests(ctx context.Context, enc exportEncoder) error {
+	err := enc.Sheet(exportPullRequests, []string{
+		"pull_request_id", "pull_request_name", "author_id", "status", "priority", "repository",
+		"external_number", "assigned_reviewers", "approvals", "created_at", "merged_at", "closed_at",
+		"hours_to_merge",
+	})
+	if err != nil {
+		return err
+	}
+
+	list := func(ctx context.Context, page pagination.Page) ([]entity.PullRequest, error) {
+		return h.search.PullRequests(ctx, entity.PRListFilter{}, page)
+	}
+
+	return eachPage(ctx, list, func(pr entity.PullRequest) pagination.Cursor {
+		return prCursor(entity.PRSortNewest, pr)
+	}, func(prs []entity.PullRequest) error {
+		for _, pr := range prs {
+			hoursToMerge := ""
+			if pr.MergedAt != nil {
+				hoursToMerge = exportText(pr.MergedAt.Sub(pr.CreatedAt).Hours())
+			}
+			err := enc.Row(pr.PullRequestID, pr.PullRequestName, pr.AuthorID, string(pr.Status), pr.Priority,
+				pr.Repository, pr.ExternalNumber, strings.Join(pr.AssignedReviewers, ";"), len(pr.Approvals),
+				pr.CreatedAt, pr.MergedAt, pr.ClosedAt, hoursToMerge)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (h *PRHandler) exportUsers(ctx context.Context, enc exportEncoder) error {
+	err := enc.Sheet(exportUsers, []string{
+		"user_id", "username", "team_name", "is_active", "role", "max_open_reviews",
+		"assigned", "completed", "average_hours_to_merge", "open_reviews",
+	})
+	if err != nil {
+		return err
+	}
+
+	return eachPage(ctx, h.search.Users, func(u entity.User) pagination.Cursor {
+		return pagination.Cursor{ID: u.UserID}
+	}, func(users []entity.User) error {
+		ids := make([]string, len(users))
+		for i, u := range users {
+			ids[i] = u.UserID
+		}
+		stats, err := h.uc.UsersStats(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, u := range users {
+			s := stats[u.UserID]
+			err := enc.Row(u.UserID, u.Username, u.TeamName, u.IsActive, u.Role, u.MaxOpenReviews,
+				s.Assigned, s.Completed, s.AverageHoursToMerge, s.OpenReviews)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// eachPage walks a keyset-paginated listing from the start, handing fn one
+// full page at a time.
+func eachPage[T any](ctx context.Context, list func(context.Context, pagination.Page) ([]T, error),
+	key func(T) pagination.Cursor, fn func([]T) error,
+) error {
+	page := pagination.Page{Limit: pagination.MaxLimit}
+
+	for {
+		items, err := list(ctx, page)
+		if err != nil {
+			return err
+		}
+
+		more := len(items) > page.Limit
+		if more {
+			items = items[:page.Limit]
+		}
+		if err := fn(items); err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+
+		last := key(items[len(items)-1])
+		page.After = &last
+	}
+}
+
+// exportEncoder writes tables row by row as they are read, so exports never
+// hold a whole dataset in memory. Cells may be strings, ints, floats, bools
+// and times (nil *time.Time is an empty cell).
+type exportEncoder interface {
+	// Sheet starts a table; CSV output holds a single one.
+	Sheet(name string, header []string) error
+	Row(cells ...any) error
+	// Close flushes the output; it doesn't close the underlying writer.
+	Close() error
+}
+
+func newExportEncoder(format string, w io.Writer) exportEncoder {
+	if format == exportXLSX {
+		return &xlsxEncoder{zw: zip.NewWriter(w)}
+	}
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func exportContentType(format string) string {
+	if format == exportXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// exportText renders a cell for CSV and the text cells of XLSX. Strings a
+// spreadsheet would read as a formula (names are user input) get a leading
+// quote, so opening the export can't run =HYPERLINK(...) and the like.
+func exportText(v any) string {
+	switch v := v.(type) {
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) Sheet(_ string, header []string) error {
+	return e.w.Write(header)
+}
+
+func (e *csvEncoder) Row(cells ...any) error {
+	record := make([]string, len(cells))
+	for i, v := range cells {
+		record[i] = exportText(v)
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// xlsxEncoder writes a minimal Office Open XML workbook: one worksheet per
+// Sheet with inline strings, so no shared-strings table has to be built
+// before the rows are written. The workbook parts listing the sheets are
+// written by Close.
+type xlsxEncoder struct {
+	zw     *zip.Writer
+	sheet  io.Writer
+	sheets []string
+}
+
+func (e *xlsxEncoder) Sheet(name string, header []string) error {
+	if err := e.endSheet(); err != nil {
+		return err
+	}
+
+	e.sheets = append(e.sheets, name)
+
+	w, err := e.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(e.sheets)))
+	if err != nil {
+		return err
+	}
+	e.sheet = w
+
+	if _, err := io.WriteString(w, xml.Header+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+
+	cells := make([]any, len(header))
+	for i, h := range header {
+		cells[i] = h
+	}
+	return e.Row(cells...)
+}
+
+func (e *xlsxEncoder) Row(cells ...any) error {
+	var b strings.Builder
+
+	b.WriteString("<row>")
+	for _, v := range cells {
+		switch v := v.(type) {
+		case int, float64:
+			fmt.Fprintf(&b, "<c><v>%v</v></c>", v)
+		default:
+			b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			_ = xml.EscapeText(&b, []byte(exportText(v)))
+			b.WriteString("</t></is></c>")
+		}
+	}
+	b.WriteString("</row>")
+
+	_, err := io.WriteString(e.sheet, b.String())
+	return err
+}
+
+func (e *xlsxEncoder) endSheet() error {
+	if e.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(e.sheet, "</sheetData></worksheet>")
+	e.sheet = nil
+	return err
+}
+
+func (e *xlsxEncoder) Close() error {
+	if err := e.endSheet(); err != nil {
+		return err
+	}
+
+	var types, workbook, rels strings.Builder
+	for i, name := range e.sheets {
+		n := i + 1
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		workbook.WriteString(`<sheet name="`)
+		_ = xml.EscapeText(&workbook, []byte(name))
+		fmt.Fprintf(&workbook, `" sheetId="%d" r:id="rId%d"/>`, n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			types.String() + `</Types>`},
+		{"_rels/.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			workbook.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			rels.String() + `</Relationships>`},
+	}
+	for _, p := range parts {
+		w, err := e.zw.Create(p.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, xml.Header+p.body); err != nil {
+			return err
+		}
+	}
+
+	return e.zw.Close()
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fieldSet is the parsed ?fields=a,b,c selection. A nil set means "all fields".
+type fieldSet map[string]struct{}
+
+func parseFields(c *fiber.Ctx) fieldSet {
+	raw := c.Query("fields")
+	if raw == "" {
+		return nil
+	}
+
+	fs := make(fieldSet)
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fs[f] = struct{}{}
+		}
+	}
+
+	return fs
+}
+
+func (fs fieldSet) has(name string) bool {
+	if fs == nil {
+		return true
+	}
+	_, ok := fs[name]
+	return ok
+}
+
+// project drops every top-level JSON key of v that wasn't selected.
+func (fs fieldSet) project(v any) (any, error) {
+	if fs == nil {
+		return v, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	for k := range m {
+		if !fs.has(k) {
+			delete(m, k)
+		}
+	}
+
+	return m, nil
+}
+
+func projectAll[T any](fs fieldSet, items []T) ([]any, error) {
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		p, err := fs.project(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/middleware"
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterMeRoutes mounts the self-service routes behind auth, which must
+// store the caller's user ID under middleware.UserIDKey.
+func (h *PRHandler) RegisterMeRoutes(router fiber.Router, auth fiber.Handler) {
+	meGroup := router.Group("/me", auth)
+	meGroup.Get("", h.meGet)
+	meGroup.Get("/reviews", h.meReviews)
+	meGroup.Post("/setActive", h.meSetActive)
+	meGroup.Post("/vacation", h.meVacation)
+}
+
+func callerID(c *fiber.Ctx) string {
+	id, _ := c.Locals(middleware.UserIDKey).(string)
+	return id
+}
+
+// meGet implements GET /me
+func (h *PRHandler) meGet(c *fiber.Ctx) error {
+	u, err := h.uc.GetUser(c.Context(), callerID(c))
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(fiber.Map{"user": u})
+}
+
+// meReviews implements GET /me/reviews
+func (h *PRHandler) meReviews(c *fiber.Ctx) error {
+	return h.reviewsOf(c, callerID(c))
+}
+
+// meSetActive implements POST /me/setActive
+func (h *PRHandler) meSetActive(c *fiber.Ctx) error {
+	var body struct {
+		IsActive        bool   `json:"is_active"`
+		ExpectedVersion string `json:"expected_version"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	u, err := h.uc.SetUserActive(ctx, callerID(c), body.IsActive)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "user was modified since the given version"}, "user": u})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"user": u})
+}
+
+// meVacation implements POST /me/vacation
+// It records an absence for the caller; one already in progress hands their
+// open reviews over right away.
+func (h *PRHandler) meVacation(c *fiber.Ctx) error {
+	var body struct {
+		StartsAt time.Time `json:"starts_at"`
+		EndsAt   time.Time `json:"ends_at"`
+		Reason   string    `json:"reason"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	absence, err := h.uc.CreateAbsence(c.Context(), entity.Absence{
+		UserID:   callerID(c),
+		StartsAt: body.StartsAt,
+		EndsAt:   body.EndsAt,
+		Reason:   body.Reason,
+	})
+	if err != nil {
+		return absenceError(c, err)
+	}
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"absence": absence})
+}
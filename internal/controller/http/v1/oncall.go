@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+const _defaultOnCallWeeks = 4
+
+// teamOnCallGet implements GET /team/oncall?team_name=...&weeks=...
+func (h *PRHandler) teamOnCallGet(c *fiber.Ctx) error {
+	name := c.Query("team_name")
+	if name == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	rotation, schedule, err := h.uc.OnCallSchedule(c.Context(), name, c.QueryInt("weeks", _defaultOnCallWeeks))
+	if err != nil {
+		return onCallError(c, err)
+	}
+	return c.JSON(fiber.Map{"rotation": rotation, "schedule": schedule})
+}
+
+// teamOnCallRotation implements POST /team/oncall/rotation
+func (h *PRHandler) teamOnCallRotation(c *fiber.Ctx) error {
+	var body struct {
+		TeamName string   `json:"team_name"`
+		Members  []string `json:"members"`
+		ActorID  string   `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	rotation, err := h.uc.SetOnCallRotation(h.actorContext(c.Context(), c, body.ActorID), body.TeamName, body.Members)
+	if err != nil {
+		return onCallError(c, err)
+	}
+	return c.JSON(fiber.Map{"rotation": rotation})
+}
+
+// teamOnCallOverride implements POST /team/oncall/override
+// An empty user_id restores the rotation's pick for the week.
+func (h *PRHandler) teamOnCallOverride(c *fiber.Ctx) error {
+	var body struct {
+		TeamName  string `json:"team_name"`
+		WeekStart string `json:"week_start"`
+		UserID    string `json:"user_id"`
+		ActorID   string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	week, err := time.Parse(time.DateOnly, body.WeekStart)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "week_start must be YYYY-MM-DD"}})
+	}
+	shift, err := h.uc.OverrideOnCall(h.actorContext(c.Context(), c, body.ActorID), body.TeamName, week, body.UserID)
+	if err != nil {
+		return onCallError(c, err)
+	}
+	return c.JSON(fiber.Map{"team_name": body.TeamName, "shift": shift})
+}
+
+func onCallError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidOnCall):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	case err == usecase.ErrNotFound:
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+	case err == usecase.ErrForbidden:
+		return forbidden(c)
+	default:
+		return internalError(c, err)
+	}
+}
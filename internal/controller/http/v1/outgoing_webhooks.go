@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
+	"github.com/evrone/go-clean-template/internal/entity"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/webhook"
+	"github.com/gofiber/fiber/v2"
+)
+
+// teamWebhooksList implements GET /team/webhooks?team_name=...
+func (h *PRHandler) teamWebhooksList(c *fiber.Ctx) error {
+	name := c.Query("team_name")
+	if name == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	hooks, err := h.uc.ListOutgoingWebhooks(c.Context(), name)
+	if err != nil {
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(hooks).With(fiber.Map{"team_name": name}))
+}
+
+// teamWebhooksAdd implements POST /team/webhooks/add
+// The response is the only place the secret is shown.
+func (h *PRHandler) teamWebhooksAdd(c *fiber.Ctx) error {
+	var body struct {
+		entity.OutgoingWebhook
+		ActorID string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	hook, err := h.uc.CreateOutgoingWebhook(h.actorContext(c.Context(), c, body.ActorID), entity.OutgoingWebhook{
+		TeamName:   body.TeamName,
+		URL:        body.URL,
+		Secret:     body.Secret,
+		Events:     body.Events,
+		RequireAck: body.RequireAck,
+	})
+	if err != nil {
+		return webhookError(c, err)
+	}
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"webhook": hook})
+}
+
+// teamWebhooksUpdate implements POST /team/webhooks/update
+// Omitted fields are left unchanged.
+func (h *PRHandler) teamWebhooksUpdate(c *fiber.Ctx) error {
+	var body struct {
+		ID         int64     `json:"id"`
+		URL        *string   `json:"url"`
+		Secret     *string   `json:"secret"`
+		Events     *[]string `json:"events"`
+		RequireAck *bool     `json:"require_ack"`
+		ActorID    string    `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	hook, err := h.uc.UpdateOutgoingWebhook(h.actorContext(c.Context(), c, body.ActorID), body.ID, usecase.OutgoingWebhookUpdate{
+		URL:        body.URL,
+		Secret:     body.Secret,
+		Events:     body.Events,
+		RequireAck: body.RequireAck,
+	})
+	if err != nil {
+		return webhookError(c, err)
+	}
+	return c.JSON(fiber.Map{"webhook": hook})
+}
+
+// teamWebhooksDelete implements POST /team/webhooks/delete
+func (h *PRHandler) teamWebhooksDelete(c *fiber.Ctx) error {
+	var body struct {
+		ID      int64  `json:"id"`
+		ActorID string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if err := h.uc.DeleteOutgoingWebhook(h.actorContext(c.Context(), c, body.ActorID), body.ID); err != nil {
+		return webhookError(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// teamWebhooksTest implements POST /team/webhooks/test
+// The endpoint's status is reported even when it signals failure. Only leads,
+// delegates and admins may test a webhook.
+func (h *PRHandler) teamWebhooksTest(c *fiber.Ctx) error {
+	var body struct {
+		ID      int64  `json:"id"`
+		Event   string `json:"event"`
+		ActorID string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	result, err := h.uc.TestOutgoingWebhook(h.actorContext(c.Context(), c, body.ActorID), body.ID, body.Event)
+	if err != nil {
+		return webhookError(c, err)
+	}
+	return c.JSON(fiber.Map{"result": result})
+}
+
+// assignmentAck implements POST /webhooks/assignments/ack
+// The receiver of a reviewer.assigned delivery reports whether it applied the
+// assignment; the body is signed with the webhook's secret.
+func (h *PRHandler) assignmentAck(c *fiber.Ctx) error {
+	var body struct {
+		DeliveryID string `json:"delivery_id"`
+		Status     string `json:"status"`
+		Error      string `json:"error"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.DeliveryID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "delivery_id required"}})
+	}
+	ack, err := h.uc.AcknowledgeAssignment(c.Context(), body.DeliveryID, body.Status, body.Error, c.Body(), c.Get(webhook.HeaderSignature))
+	switch {
+	case err == nil:
+		return c.JSON(fiber.Map{"ack": ack})
+	case errors.Is(err, usecase.ErrInvalidAck):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	case err == usecase.ErrInvalidSignature:
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "invalid signature"}})
+	case err == usecase.ErrNotFound:
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "delivery not found"}})
+	default:
+		return internalError(c, err)
+	}
+}
+
+func webhookError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidWebhook):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	case err == usecase.ErrNotFound:
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team or webhook not found"}})
+	case err == usecase.ErrForbidden:
+		return forbidden(c)
+	default:
+		return internalError(c, err)
+	}
+}
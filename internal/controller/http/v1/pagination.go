@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/pagination"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parsePage reads ?cursor=...&limit=... shared by all list endpoints.
+func parsePage(c *fiber.Ctx) (pagination.Page, error) {
+	return pagination.NewPage(c.Query("cursor"), c.QueryInt("limit"))
+}
+
+// sendPage responds with one page of listing, totalled by search.Estimate.
+func sendPage[T any](c *fiber.Ctx, search usecase.SearchRepo, listing string, items []T, next string) error {
+	total, err := search.Estimate(c.Context(), listing)
+	if err != nil {
+		return internalError(c, err)
+	}
+	return c.JSON(response.NewList(items, next, total))
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+var errBadPrecondition = errors.New("invalid If-Unmodified-Since or expected_version")
+
+// preconditionContext reads If-Unmodified-Since and the optional expected_version
+// body field (an RFC3339 updated_at) and attaches them to the request context.
+func preconditionContext(c *fiber.Ctx, expectedVersion string) (context.Context, error) {
+	var p usecase.Precondition
+
+	if h := c.Get(fiber.HeaderIfUnmodifiedSince); h != "" {
+		t, err := http.ParseTime(h)
+		if err != nil {
+			return nil, errBadPrecondition
+		}
+		p.UnmodifiedSince = t
+	}
+
+	if expectedVersion != "" {
+		t, err := time.Parse(time.RFC3339Nano, expectedVersion)
+		if err != nil {
+			return nil, errBadPrecondition
+		}
+		p.ExpectedVersion = t
+	}
+
+	if p == (usecase.Precondition{}) {
+		return c.Context(), nil
+	}
+
+	return usecase.WithPrecondition(c.Context(), p), nil
+}
@@ -1,29 +1,39 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/evrone/go-clean-template/internal/controller/http/middleware"
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
 	"github.com/evrone/go-clean-template/internal/entity"
 	usecase "github.com/evrone/go-clean-template/internal/usecase"
 	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/evrone/go-clean-template/pkg/pagination"
 	"github.com/gofiber/fiber/v2"
 )
 
 type PRHandler struct {
-	uc    *usecase.PRUseCase
-	users usecase.UserRepo
-	teams usecase.TeamRepo
-	prs   usecase.PRRepo
-	l     logger.Interface
+	uc     *usecase.PRUseCase
+	teams  usecase.TeamRepo
+	prs    usecase.PRRepo
+	search usecase.SearchRepo
+	l      logger.Interface
 }
 
-func NewHandler(uc *usecase.PRUseCase, userRepo usecase.UserRepo, teamRepo usecase.TeamRepo, prRepo usecase.PRRepo, l logger.Interface) *PRHandler {
+func NewHandler(uc *usecase.PRUseCase, teamRepo usecase.TeamRepo, prRepo usecase.PRRepo, search usecase.SearchRepo, l logger.Interface) *PRHandler {
 	return &PRHandler{
-		uc:    uc,
-		teams: teamRepo,
-		users: userRepo,
-		prs:   prRepo,
-		l:     l,
+		uc:     uc,
+		teams:  teamRepo,
+		prs:    prRepo,
+		search: search,
+		l:      l,
 	}
 }
 
@@ -32,22 +42,90 @@ func (h *PRHandler) RegisterPRRoutes(router fiber.Router) {
 	teamGroup := router.Group("/team")
 	teamGroup.Post("/add", h.teamAdd)
 	teamGroup.Get("/get", h.teamGet)
+	teamGroup.Get("/list", h.teamList)
+	teamGroup.Post("/removeMember", h.teamRemoveMember)
+	teamGroup.Post("/rename", h.teamRename)
+	teamGroup.Delete("/:team_name", h.teamDelete)
+	teamGroup.Get("/health", h.teamHealth)
+	teamGroup.Get("/capacity", h.teamCapacity)
+	teamGroup.Get("/digest", h.teamDigest)
+	teamGroup.Get("/settings", h.teamSettingsGet)
+	teamGroup.Post("/settings", h.teamSettingsUpdate)
+	teamGroup.Get("/webhooks", h.teamWebhooksList)
+	teamGroup.Post("/webhooks/add", h.teamWebhooksAdd)
+	teamGroup.Post("/webhooks/update", h.teamWebhooksUpdate)
+	teamGroup.Post("/webhooks/delete", h.teamWebhooksDelete)
+	teamGroup.Post("/webhooks/test", h.teamWebhooksTest)
+	teamGroup.Get("/oncall", h.teamOnCallGet)
+	teamGroup.Post("/oncall/rotation", h.teamOnCallRotation)
+	teamGroup.Post("/oncall/override", h.teamOnCallOverride)
+	teamGroup.Get("/delegations", h.teamDelegationsList)
+	teamGroup.Post("/delegations/add", h.teamDelegationsAdd)
+	teamGroup.Post("/delegations/revoke", h.teamDelegationsRevoke)
+
+	// Acknowledgments of reviewer.assigned deliveries
+	router.Group("/webhooks").Post("/assignments/ack", h.assignmentAck)
+
+	// Organization directory
+	router.Group("/teams").Get("", h.teamsList)
 
 	// Users
 	userGroup := router.Group("/users")
+	userGroup.Get("", h.usersList)
 	userGroup.Post("/setIsActive", h.usersSetIsActive)
+	userGroup.Post("/setProfile", h.usersSetProfile)
 	userGroup.Get("/getReview", h.usersGetReview)
+	userGroup.Get("/reviewCalendar.ics", h.usersReviewCalendar)
 	userGroup.Post("/deactivateTeam", h.usersDeactivateTeam)
+	userGroup.Delete("/:user_id", h.usersDelete)
+	userGroup.Post("/transferTeam", h.usersTransferTeam)
+	userGroup.Post("/linkIdentity", h.usersLinkIdentity)
+	userGroup.Get("/absences", h.usersAbsencesList)
+	userGroup.Post("/absences/add", h.usersAbsencesAdd)
+	userGroup.Post("/absences/update", h.usersAbsencesUpdate)
+	userGroup.Post("/absences/delete", h.usersAbsencesDelete)
 
 	// Pull Requests
 	prGroup := router.Group("/pullRequest")
+	prGroup.Get("/get", h.pullRequestGet)
 	prGroup.Post("/create", h.pullRequestCreate)
+	prGroup.Post("/update", h.pullRequestUpdate)
 	prGroup.Post("/merge", h.pullRequestMerge)
+	prGroup.Post("/close", h.pullRequestClose)
+	prGroup.Post("/reopen", h.pullRequestReopen)
+	prGroup.Post("/markReady", h.pullRequestMarkReady)
+	prGroup.Post("/approve", h.pullRequestApprove)
+	prGroup.Post("/review", h.pullRequestReview)
+	prGroup.Get("/reviews", h.pullRequestReviews)
+	prGroup.Get("/decisions", h.pullRequestDecisions)
 	prGroup.Post("/reassign", h.pullRequestReassign)
+	prGroup.Post("/claim", h.pullRequestClaim)
+	prGroup.Post("/addReviewer", h.pullRequestAddReviewer)
+	prGroup.Post("/removeReviewer", h.pullRequestRemoveReviewer)
+	prGroup.Post("/externalState", h.pullRequestExternalState)
+	prGroup.Get("/list", h.pullRequestList)
+	prGroup.Get("/search", h.pullRequestSearch)
+	prGroup.Get("/explain", h.pullRequestExplain)
+
+	// Review rules
+	rulesGroup := router.Group("/reviewRules")
+	rulesGroup.Get("", h.reviewRulesList)
+	rulesGroup.Post("/add", h.reviewRulesAdd)
+	rulesGroup.Post("/delete", h.reviewRulesDelete)
+
+	// Reviewer exclusions
+	exclusionsGroup := router.Group("/reviewExclusions")
+	exclusionsGroup.Get("", h.reviewExclusionsList)
+	exclusionsGroup.Post("/add", h.reviewExclusionsAdd)
+	exclusionsGroup.Post("/delete", h.reviewExclusionsDelete)
 
 	// Stats
 	statsGroup := router.Group("/stats")
 	statsGroup.Get("", h.getStats)
+	statsGroup.Get("/history", h.getStatsHistory)
+	statsGroup.Get("/user", h.getUserStats)
+	statsGroup.Get("/sla", h.getStatsSLA)
+	statsGroup.Get("/leaderboard", h.getStatsLeaderboard)
 }
 
 // teamAdd implements POST /team/add
@@ -56,45 +134,273 @@ func (h *PRHandler) teamAdd(c *fiber.Ctx) error {
 	if err := c.BodyParser(&t); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
 	}
-	// check existing
-	if _, err := h.teams.GetByName(c.Context(), t.TeamName); err == nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "TEAM_EXISTS", "message": "team_name already exists"}})
-	}
-	if err := h.teams.Create(c.Context(), t); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	team, err := h.uc.CreateTeam(c.Context(), t)
+	if err != nil {
+		switch {
+		case err == usecase.ErrTeamExists:
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "TEAM_EXISTS", "message": "team_name already exists"}})
+		case errors.Is(err, usecase.ErrInvalidProfile):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		}
+		return internalError(c, err)
 	}
-	return c.Status(http.StatusCreated).JSON(fiber.Map{"team": t})
+	return c.Status(http.StatusCreated).JSON(fiber.Map{"team": team})
 }
 
-// teamGet implements GET /team/get?team_name=...
+// teamGet implements GET /team/get?team_name=...&fields=...
+// Members are only loaded when selected (or when fields is omitted).
 func (h *PRHandler) teamGet(c *fiber.Ctx) error {
 	name := c.Query("team_name")
 	if name == "" {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
 	}
-	t, err := h.teams.GetByName(c.Context(), name)
+	fields := parseFields(c)
+	t := entity.Team{TeamName: name}
+	if fields.has("members") {
+		var err error
+		if t, err = h.teams.GetByName(c.Context(), name); err != nil {
+			if errors.Is(err, usecase.ErrNotFound) {
+				return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+			}
+			return internalError(c, err)
+		}
+	} else {
+		exists, err := h.teams.Exists(c.Context(), name)
+		if err != nil {
+			return internalError(c, err)
+		}
+		if !exists {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+		}
+	}
+	out, err := fields.project(t)
+	if err != nil {
+		return internalError(c, err)
+	}
+	return c.JSON(out)
+}
+
+// teamList implements GET /team/list?cursor=...&limit=...&include_members=...
+// With include_members=false it returns the same teams with only their names
+// and member counts.
+func (h *PRHandler) teamList(c *fiber.Ctx) error {
+	page, err := parsePage(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	includeMembers, err := strconv.ParseBool(c.Query("include_members", "true"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "include_members must be true or false"}})
+	}
+	if !includeMembers {
+		teams, err := h.teams.ListBrief(c.Context(), page)
+		if err != nil {
+			return internalError(c, err)
+		}
+		teams, next := pagination.Trim(teams, page, func(t entity.TeamBrief) pagination.Cursor {
+			return pagination.Cursor{ID: t.TeamName}
+		})
+		return sendPage(c, h.search, "teams", teams, next)
+	}
+	teams, err := h.teams.ListAll(c.Context(), page)
+	if err != nil {
+		return internalError(c, err)
+	}
+	teams, next := pagination.Trim(teams, page, func(t entity.Team) pagination.Cursor {
+		return pagination.Cursor{ID: t.TeamName}
+	})
+	return sendPage(c, h.search, "teams", teams, next)
+}
+
+// teamHealth implements GET /team/health?team_name=... (team_name optional)
+func (h *PRHandler) teamHealth(c *fiber.Ctx) error {
+	health, err := h.uc.TeamHealth(c.Context(), time.Now(), c.Query("team_name"))
+	if err != nil {
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(health))
+}
+
+// teamDigest implements GET /team/digest?team_name=...&window=...&format=...
+// window defaults to 24h; format=markdown returns only the rendered text.
+func (h *PRHandler) teamDigest(c *fiber.Ctx) error {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	window := 24 * time.Hour
+	if s := c.Query("window"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "window must be a positive duration"}})
+		}
+		window = d
+	}
+	digest, err := h.uc.TeamDigest(c.Context(), teamName, time.Now(), window)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+		}
+		return internalError(c, err)
+	}
+	if c.Query("format") == "markdown" {
+		c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+		return c.SendString(digest.Markdown)
+	}
+	return c.JSON(fiber.Map{"digest": digest})
+}
+
+// teamCapacity implements GET /team/capacity?team_name=...
+func (h *PRHandler) teamCapacity(c *fiber.Ctx) error {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	capacity, err := h.uc.TeamCapacity(c.Context(), teamName)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(capacity)
+}
+
+// teamSettingsGet implements GET /team/settings?team_name=...
+func (h *PRHandler) teamSettingsGet(c *fiber.Ctx) error {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	settings, err := h.uc.GetTeamSettings(c.Context(), teamName)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(fiber.Map{"settings": settings})
+}
+
+// teamSettingsUpdate implements POST /team/settings. Omitted fields keep
+// their stored value; those listed in clear reset to service defaults.
+func (h *PRHandler) teamSettingsUpdate(c *fiber.Ctx) error {
+	var body struct {
+		entity.TeamSettings
+		Clear   []string `json:"clear"`
+		ActorID string   `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.TeamName == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	settings, err := h.uc.UpdateTeamSettings(h.actorContext(c.Context(), c, body.ActorID), body.TeamSettings, body.Clear)
+	if err != nil {
+		switch {
+		case err == usecase.ErrForbidden:
+			return forbidden(c)
+		case errors.Is(err, usecase.ErrInvalidSettings):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		case err == usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"settings": settings})
+}
+
+// teamsList implements GET /teams?cursor=...&limit=...
+func (h *PRHandler) teamsList(c *fiber.Ctx) error {
+	page, err := parsePage(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	teams, err := h.search.Teams(c.Context(), page)
 	if err != nil {
-		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+		return internalError(c, err)
 	}
-	return c.JSON(t)
+	teams, next := pagination.Trim(teams, page, func(t entity.TeamSummary) pagination.Cursor {
+		return pagination.Cursor{ID: t.TeamName}
+	})
+	return sendPage(c, h.search, "teams", teams, next)
 }
 
 // usersSetIsActive implements POST /users/setIsActive
 func (h *PRHandler) usersSetIsActive(c *fiber.Ctx) error {
 	var body struct {
-		UserID   string `json:"user_id"`
-		IsActive bool   `json:"is_active"`
+		UserID          string `json:"user_id"`
+		IsActive        bool   `json:"is_active"`
+		ExpectedVersion string `json:"expected_version"`
+		ActorID         string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	u, err := h.uc.SetUserActive(h.actorContext(ctx, c, body.ActorID), body.UserID, body.IsActive)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "user was modified since the given version"}, "user": u})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"user": u})
+}
+
+// usersSetProfile implements POST /users/setProfile
+// Omitted fields are left unchanged.
+func (h *PRHandler) usersSetProfile(c *fiber.Ctx) error {
+	var body struct {
+		UserID          string  `json:"user_id"`
+		Role            *string `json:"role"`
+		Title           *string `json:"title"`
+		Seniority       *string `json:"seniority"`
+		DisplayName     *string `json:"display_name"`
+		AvatarURL       *string `json:"avatar_url"`
+		MaxOpenReviews  *int    `json:"max_open_reviews"`
+		ExpectedVersion string  `json:"expected_version"`
+		ActorID         string  `json:"actor_id"`
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
 	}
-	u, err := h.users.GetByID(c.Context(), body.UserID)
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
 	if err != nil {
-		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
 	}
-	u.IsActive = body.IsActive
-	if err := h.users.Update(c.Context(), u); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	u, err := h.uc.SetUserProfile(h.actorContext(ctx, c, body.ActorID), body.UserID, usecase.ProfileUpdate{
+		Role:           body.Role,
+		Title:          body.Title,
+		Seniority:      body.Seniority,
+		DisplayName:    body.DisplayName,
+		AvatarURL:      body.AvatarURL,
+		MaxOpenReviews: body.MaxOpenReviews,
+	})
+	if err != nil {
+		switch {
+		case err == usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		case err == usecase.ErrForbidden:
+			return forbidden(c)
+		case err == usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "user was modified since the given version"}, "user": u})
+		case errors.Is(err, usecase.ErrInvalidProfile):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		default:
+			return internalError(c, err)
+		}
 	}
 	return c.JSON(fiber.Map{"user": u})
 }
@@ -105,27 +411,73 @@ func (h *PRHandler) usersGetReview(c *fiber.Ctx) error {
 	if id == "" {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
 	}
+	return h.reviewsOf(c, id)
+}
+
+// reviewsOf responds with the PRs user id reviews and their decision on each,
+// and the user's current load against their review limit.
+func (h *PRHandler) reviewsOf(c *fiber.Ctx, id string) error {
 	prs, err := h.prs.ListByReviewer(c.Context(), id)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+		return internalError(c, err)
+	}
+	decisions, err := h.uc.ReviewDecisions(c.Context(), id)
+	if err != nil {
+		return internalError(c, err)
+	}
+	// build short representation; pending marks open PRs still awaiting the user's decision
+	type assignedPR struct {
+		entity.PullRequestShort
+		Decision string `json:"decision,omitempty"`
+		Pending  bool   `json:"pending"`
 	}
-	// build short representation
-	short := make([]entity.PullRequestShort, 0, len(prs))
+	short := make([]assignedPR, 0, len(prs))
 	for _, p := range prs {
-		short = append(short, entity.PullRequestShort{
-			PullRequestID:   p.PullRequestID,
-			PullRequestName: p.PullRequestName,
-			AuthorID:        p.AuthorID,
-			Status:          p.Status,
+		latest, reviewed := decisions[p.PullRequestID]
+		short = append(short, assignedPR{
+			PullRequestShort: entity.PullRequestShort{
+				PullRequestID:   p.PullRequestID,
+				PullRequestName: p.PullRequestName,
+				AuthorID:        p.AuthorID,
+				Status:          p.Status,
+				ExternalState:   p.ExternalState,
+			},
+			Decision: latest.Decision,
+			Pending:  p.Status == entity.PRStatusOpen && !(reviewed && latest.Done()) && !slices.Contains(p.Approvals, id),
 		})
 	}
-	return c.JSON(fiber.Map{"user_id": id, "pull_requests": short})
+	load, err := h.uc.ReviewerLoad(c.Context(), id)
+	if err != nil && err != usecase.ErrNotFound {
+		return internalError(c, err)
+	}
+	fields := fiber.Map{"user_id": id}
+	if err == nil {
+		fields["load"] = load
+	}
+	return c.JSON(response.All(short).With(fields))
+}
+
+// usersList implements GET /users?cursor=...&limit=...
+func (h *PRHandler) usersList(c *fiber.Ctx) error {
+	page, err := parsePage(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	users, err := h.search.Users(c.Context(), page)
+	if err != nil {
+		return internalError(c, err)
+	}
+	users, next := pagination.Trim(users, page, func(u entity.User) pagination.Cursor {
+		return pagination.Cursor{ID: u.UserID}
+	})
+	return sendPage(c, h.search, "users", users, next)
 }
 
 // usersDeactivateTeam implements POST /users/deactivateTeam
 func (h *PRHandler) usersDeactivateTeam(c *fiber.Ctx) error {
 	var body struct {
 		TeamName string `json:"team_name"`
+		ActorID  string `json:"actor_id"`
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
@@ -133,86 +485,1175 @@ func (h *PRHandler) usersDeactivateTeam(c *fiber.Ctx) error {
 	if body.TeamName == "" {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
 	}
-	if err := h.uc.DeactivateTeam(c.Context(), body.TeamName); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+	if err := h.uc.DeactivateTeam(h.actorContext(c.Context(), c, body.ActorID), body.TeamName); err != nil {
+		if err == usecase.ErrForbidden {
+			return forbidden(c)
+		}
+		return internalError(c, err)
 	}
 	return c.Status(http.StatusOK).JSON(fiber.Map{"message": "team deactivated"})
 }
 
-// pullRequestCreate implements POST /pullRequest/create
-func (h *PRHandler) pullRequestCreate(c *fiber.Ctx) error {
+// usersDelete implements DELETE /users/:user_id?actor_id=...
+// The user is offboarded, their open PRs are closed and the row is kept for stats.
+func (h *PRHandler) usersDelete(c *fiber.Ctx) error {
+	deletion, err := h.uc.DeleteUser(h.actorContext(c.Context(), c, c.Query("actor_id")), c.Params("user_id"))
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"deletion": deletion})
+}
+
+// teamRemoveMember implements POST /team/removeMember
+// The user's open reviews are reassigned within the PR author's team.
+func (h *PRHandler) teamRemoveMember(c *fiber.Ctx) error {
+	var body struct {
+		TeamName string `json:"team_name"`
+		UserID   string `json:"user_id"`
+		ActorID  string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.TeamName == "" || body.UserID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name and user_id required"}})
+	}
+	change, err := h.uc.RemoveTeamMember(h.actorContext(c.Context(), c, body.ActorID), body.TeamName, body.UserID)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		case usecase.ErrNotTeamMember:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_TEAM_MEMBER", "message": "user is not a member of the team"}})
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"change": change})
+}
+
+// teamDelete implements DELETE /team/:team_name?open_reviews=block|reassign|cancel
+func (h *PRHandler) teamDelete(c *fiber.Ctx) error {
+	mode := c.Query("open_reviews", usecase.TeamDeleteBlock)
+	switch mode {
+	case usecase.TeamDeleteBlock, usecase.TeamDeleteReassign, usecase.TeamDeleteCancel:
+	default:
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "open_reviews must be block, reassign or cancel"}})
+	}
+	deletion, err := h.uc.DeleteTeam(h.actorContext(c.Context(), c, c.Query("actor_id")), c.Params("team_name"), mode)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+		case usecase.ErrTeamHasOpenPRs:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "TEAM_HAS_OPEN_PRS", "message": "open PRs reference team members; retry with open_reviews=reassign or cancel"}, "open_pull_requests": deletion.OpenPullRequests})
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"deletion": deletion})
+}
+
+// teamRename implements POST /team/rename
+// Members, settings, webhooks and rotations move to the new name.
+func (h *PRHandler) teamRename(c *fiber.Ctx) error {
+	var body struct {
+		TeamName    string `json:"team_name"`
+		NewTeamName string `json:"new_team_name"`
+		ActorID     string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.TeamName == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "team_name required"}})
+	}
+	rename, err := h.uc.RenameTeam(h.actorContext(c.Context(), c, body.ActorID), body.TeamName, body.NewTeamName)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidTeamName):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		case err == usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "team not found"}})
+		case err == usecase.ErrTeamExists:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "TEAM_EXISTS", "message": "new_team_name already exists"}})
+		case err == usecase.ErrForbidden:
+			return forbidden(c)
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"rename": rename})
+}
+
+// usersTransferTeam implements POST /users/transferTeam
+// The user's open reviews are reassigned within the PR author's team.
+func (h *PRHandler) usersTransferTeam(c *fiber.Ctx) error {
 	var body struct {
-		PullRequestID   string `json:"pull_request_id"`
-		PullRequestName string `json:"pull_request_name"`
-		AuthorID        string `json:"author_id"`
+		UserID   string `json:"user_id"`
+		TeamName string `json:"team_name"`
+		ActorID  string `json:"actor_id"`
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
 	}
-	pr, err := h.uc.CreatePR(c.Context(), body.PullRequestID, body.PullRequestName, body.AuthorID)
+	if body.UserID == "" || body.TeamName == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id and team_name required"}})
+	}
+	change, err := h.uc.TransferTeam(h.actorContext(c.Context(), c, body.ActorID), body.UserID, body.TeamName)
 	if err != nil {
 		switch err {
 		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user or team not found"}})
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"change": change})
+}
+
+// usersLinkIdentity implements POST /users/linkIdentity
+// Only the admin token may link any user; a caller with an OIDC bearer token
+// may link their own accounts in other systems, but not another OIDC login.
+func (h *PRHandler) usersLinkIdentity(c *fiber.Ctx) error {
+	var body entity.UserIdentity
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.UserID == "" || body.Provider == "" || body.ExternalID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id, provider and external_id required"}})
+	}
+	admin, _ := c.Locals(middleware.AdminKey).(bool)
+	caller := callerID(c)
+	if !admin && caller == "" {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "admin token or bearer token required"}})
+	}
+	if !admin && (caller != body.UserID || body.Provider == entity.IdentityProviderOIDC) {
+		return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": fiber.Map{"code": "FORBIDDEN", "message": "callers may only link their own accounts"}})
+	}
+	identity, err := h.uc.LinkIdentity(c.Context(), body)
+	if err != nil {
+		switch {
+		case err == usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		case errors.Is(err, usecase.ErrAlreadyExists):
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "IDENTITY_TAKEN", "message": "external account is linked to another user"}})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"identity": identity})
+}
+
+// pullRequestCreate implements POST /pullRequest/create; pull_request_id is generated when omitted
+func (h *PRHandler) pullRequestCreate(c *fiber.Ctx) error {
+	var body struct {
+		PullRequestID   string   `json:"pull_request_id"`
+		PullRequestName string   `json:"pull_request_name"`
+		AuthorID        string   `json:"author_id"`
+		Repository      string   `json:"repository"`
+		ExternalNumber  int      `json:"external_number"`
+		Labels          []string `json:"labels"`
+		Priority        string   `json:"priority"`
+		ChangedFiles    []string `json:"changed_files"`
+		Draft           bool     `json:"draft"`
+		StackID         string   `json:"stack_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	pr, warnings, err := h.uc.CreatePR(c.Context(), usecase.CreatePRInput{
+		PullRequestID:   body.PullRequestID,
+		PullRequestName: body.PullRequestName,
+		AuthorID:        body.AuthorID,
+		Repository:      body.Repository,
+		ExternalNumber:  body.ExternalNumber,
+		Labels:          body.Labels,
+		Priority:        body.Priority,
+		ChangedFiles:    body.ChangedFiles,
+		Draft:           body.Draft,
+		StackID:         body.StackID,
+	})
+	if err != nil {
+		switch {
+		case err == usecase.ErrNotFound:
 			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "author or team not found"}})
-		case usecase.ErrPRExists:
-			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_EXISTS", "message": "PR id already exists"}})
+		case err == usecase.ErrPRExists:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_EXISTS", "message": "PR id (or repository/number) already exists"}})
+		case err == usecase.ErrQuotaExceeded:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "QUOTA_EXCEEDED", "message": "author has too many open PRs; get existing ones reviewed first"}})
+		case errors.Is(err, usecase.ErrInvalidPriority), errors.Is(err, usecase.ErrInvalidStack):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
 		default:
-			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+			return internalError(c, err)
 		}
 	}
+	if len(warnings) > 0 {
+		return c.Status(http.StatusCreated).JSON(fiber.Map{"pr": pr, "warnings": warnings})
+	}
 	return c.Status(http.StatusCreated).JSON(fiber.Map{"pr": pr})
 }
 
+// pullRequestGet implements GET /pullRequest/get?pull_request_id=...&fields=...
+// The PR may also be given as ?repository=...&external_number=...; its
+// updatedAt is the version the write endpoints take as expected_version.
+func (h *PRHandler) pullRequestGet(c *fiber.Ctx) error {
+	ref := prRef{PullRequestID: c.Query("pull_request_id"), Repository: c.Query("repository"), ExternalNumber: c.QueryInt("external_number")}
+	if ref.PullRequestID == "" && ref.Repository == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "pull_request_id or repository and external_number required"}})
+	}
+	prID, err := h.resolvePRID(c, ref)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.GetPR(c.Context(), prID)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	out, err := parseFields(c).project(pr)
+	if err != nil {
+		return internalError(c, err)
+	}
+	return c.JSON(fiber.Map{"pr": out})
+}
+
 // pullRequestMerge implements POST /pullRequest/merge
 func (h *PRHandler) pullRequestMerge(c *fiber.Ctx) error {
 	var body struct {
-		PullRequestID string `json:"pull_request_id"`
+		prRef
+		ExpectedVersion string `json:"expected_version"`
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
 	}
-	pr, err := h.uc.MergePR(c.Context(), body.PullRequestID)
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
 	if err != nil {
 		if err == usecase.ErrNotFound {
 			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
 		}
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+		return internalError(c, err)
+	}
+	pr, err := h.uc.MergePR(ctx, prID)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		case usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot merge a closed PR"}})
+		case usecase.ErrPRDraft:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_DRAFT", "message": "cannot merge a draft PR; mark it ready first"}})
+		case usecase.ErrApprovalsRequired:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "APPROVALS_REQUIRED", "message": "pr lacks the approvals its team requires; assign more reviewers if it has fewer"}, "pr": pr})
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
 	}
 	return c.JSON(fiber.Map{"pr": pr})
 }
 
-// pullRequestReassign implements POST /pullRequest/reassign
-func (h *PRHandler) pullRequestReassign(c *fiber.Ctx) error {
+// pullRequestClose implements POST /pullRequest/close
+func (h *PRHandler) pullRequestClose(c *fiber.Ctx) error {
 	var body struct {
-		PullRequestID string `json:"pull_request_id"`
-		OldUserID     string `json:"old_user_id"`
+		prRef
+		ExpectedVersion string `json:"expected_version"`
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
 	}
-	pr, replacedBy, err := h.uc.ReassignReviewer(c.Context(), body.PullRequestID, body.OldUserID)
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.ClosePR(ctx, prID)
 	if err != nil {
 		switch err {
 		case usecase.ErrNotFound:
-			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr or user not found"}})
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
 		case usecase.ErrPRMerged:
-			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot reassign on merged PR"}})
-		case usecase.ErrNotAssigned:
-			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_ASSIGNED", "message": "reviewer is not assigned to this PR"}})
-		case usecase.ErrNoCandidate:
-			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NO_CANDIDATE", "message": "no active replacement candidate in team"}})
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot close a merged PR"}})
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
 		default:
-			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+			return internalError(c, err)
 		}
 	}
-	return c.JSON(fiber.Map{"pr": pr, "replaced_by": replacedBy})
+	return c.JSON(fiber.Map{"pr": pr})
 }
 
-// getStats implements GET /stats
-func (h *PRHandler) getStats(c *fiber.Ctx) error {
-	stats, err := h.uc.GetStats(c.Context())
+// pullRequestReopen implements POST /pullRequest/reopen
+func (h *PRHandler) pullRequestReopen(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		ExpectedVersion string `json:"expected_version"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.ReopenPR(ctx, prID)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		case usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot reopen a merged PR"}})
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// pullRequestMarkReady implements POST /pullRequest/markReady
+func (h *PRHandler) pullRequestMarkReady(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		ExpectedVersion string   `json:"expected_version"`
+		ChangedFiles    []string `json:"changed_files"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": fiber.Map{"code": "INTERNAL", "message": err.Error()}})
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, warnings, err := h.uc.MarkReady(ctx, prID, body.ChangedFiles)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr or author not found"}})
+		case usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot mark a merged PR ready"}})
+		case usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot mark a closed PR ready"}})
+		case usecase.ErrQuotaExceeded:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "QUOTA_EXCEEDED", "message": "author has too many open PRs; get existing ones reviewed first"}})
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
+	}
+	if len(warnings) > 0 {
+		return c.JSON(fiber.Map{"pr": pr, "warnings": warnings})
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// pullRequestApprove implements POST /pullRequest/approve
+func (h *PRHandler) pullRequestApprove(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		UserID          string `json:"user_id"`
+		ExpectedVersion string `json:"expected_version"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.UserID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.ApprovePR(ctx, prID, body.UserID)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		case usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot approve a merged PR"}})
+		case usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot approve a closed PR"}})
+		case usecase.ErrNotAssigned:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_ASSIGNED", "message": "user is not assigned to this PR"}})
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// pullRequestReview implements POST /pullRequest/review
+func (h *PRHandler) pullRequestReview(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		UserID          string `json:"user_id"`
+		Decision        string `json:"decision"`
+		Body            string `json:"body"`
+		ExpectedVersion string `json:"expected_version"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.UserID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, review, err := h.uc.SubmitReview(ctx, prID, body.UserID, body.Decision, body.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidReview):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		case err == usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		case err == usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot review a merged PR"}})
+		case err == usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot review a closed PR"}})
+		case err == usecase.ErrNotAssigned:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_ASSIGNED", "message": "user is not assigned to this PR"}})
+		case err == usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
+	}
+	if review == nil {
+		return c.JSON(fiber.Map{"pr": pr})
+	}
+	return c.JSON(fiber.Map{"pr": pr, "review": review})
+}
+
+// pullRequestReviews implements GET /pullRequest/reviews?pull_request_id=...
+func (h *PRHandler) pullRequestReviews(c *fiber.Ctx) error {
+	id := c.Query("pull_request_id")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "pull_request_id required"}})
+	}
+	reviews, err := h.uc.ListReviews(c.Context(), id)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(reviews).With(fiber.Map{"pull_request_id": id}))
+}
+
+// pullRequestDecisions implements GET /pullRequest/decisions?pull_request_id=...
+// It lists each assigned reviewer's current decision, unlike /reviews which
+// keeps the whole history.
+func (h *PRHandler) pullRequestDecisions(c *fiber.Ctx) error {
+	id := c.Query("pull_request_id")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "pull_request_id required"}})
+	}
+	decisions, err := h.uc.CurrentReviews(c.Context(), id)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(decisions).With(fiber.Map{"pull_request_id": id}))
+}
+
+// pullRequestReassign implements POST /pullRequest/reassign
+func (h *PRHandler) pullRequestReassign(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		OldUserID       string `json:"old_user_id"`
+		ExpectedVersion string `json:"expected_version"`
+		ActorID         string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr or user not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, replacedBy, err := h.uc.ReassignReviewer(h.actorContext(ctx, c, body.ActorID), prID, body.OldUserID)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr or user not found"}})
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		case usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot reassign on merged PR"}})
+		case usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot reassign on closed PR"}})
+		case usecase.ErrNotAssigned:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_ASSIGNED", "message": "reviewer is not assigned to this PR"}})
+		case usecase.ErrNoCandidate:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NO_CANDIDATE", "message": "no active replacement candidate in team"}})
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr, "replaced_by": replacedBy})
+}
+
+// pullRequestUpdate implements POST /pullRequest/update
+// Omitted fields are left unchanged; merged and closed PRs are rejected.
+func (h *PRHandler) pullRequestUpdate(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		PullRequestName *string        `json:"pull_request_name"`
+		Labels          *[]string      `json:"labels"`
+		LinkedIssues    *[]string      `json:"linked_issues"`
+		Size            *entity.PRSize `json:"size"`
+		Priority        *string        `json:"priority"`
+		ExpectedVersion string         `json:"expected_version"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.UpdatePR(ctx, prID, usecase.PRUpdate{
+		PullRequestName: body.PullRequestName,
+		Labels:          body.Labels,
+		LinkedIssues:    body.LinkedIssues,
+		Size:            body.Size,
+		Priority:        body.Priority,
+	})
+	if err != nil {
+		switch {
+		case err == usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		case err == usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot update merged PR"}})
+		case err == usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot update closed PR"}})
+		case err == usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		case errors.Is(err, usecase.ErrInvalidPRUpdate), errors.Is(err, usecase.ErrInvalidPriority):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// pullRequestAddReviewer implements POST /pullRequest/addReviewer
+func (h *PRHandler) pullRequestAddReviewer(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		UserID          string `json:"user_id"`
+		ActorID         string `json:"actor_id"`
+		ExpectedVersion string `json:"expected_version"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.UserID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.AddReviewer(h.actorContext(ctx, c, body.ActorID), prID, body.UserID)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr or user not found"}})
+		case usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot change reviewers of a merged PR"}})
+		case usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot change reviewers of a closed PR"}})
+		case usecase.ErrPRDraft:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_DRAFT", "message": "draft PRs get reviewers when marked ready"}})
+		case usecase.ErrNotEligible:
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_ELIGIBLE", "message": "user must be an active teammate of the author"}})
+		case usecase.ErrAlreadyAssigned:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "ALREADY_ASSIGNED", "message": "user already reviews this PR"}})
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// pullRequestRemoveReviewer implements POST /pullRequest/removeReviewer
+func (h *PRHandler) pullRequestRemoveReviewer(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		UserID          string `json:"user_id"`
+		ActorID         string `json:"actor_id"`
+		ExpectedVersion string `json:"expected_version"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.UserID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.RemoveReviewer(h.actorContext(ctx, c, body.ActorID), prID, body.UserID)
+	if err != nil {
+		switch err {
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		case usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot change reviewers of a merged PR"}})
+		case usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot change reviewers of a closed PR"}})
+		case usecase.ErrPRDraft:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_DRAFT", "message": "draft PRs get reviewers when marked ready"}})
+		case usecase.ErrNotAssigned:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_ASSIGNED", "message": "user is not assigned to this PR"}})
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// pullRequestExternalState implements POST /pullRequest/externalState
+// VCS integrations report merge conflicts or an outdated base here,
+// authenticated by the router with the integration secret.
+func (h *PRHandler) pullRequestExternalState(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		State           string `json:"state"`
+		ExpectedVersion string `json:"expected_version"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.SetExternalState(ctx, prID, body.State)
+	if err != nil {
+		switch {
+		case err == usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		case err == usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot update merged PR"}})
+		case err == usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot update closed PR"}})
+		case err == usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		case errors.Is(err, usecase.ErrInvalidExternalState):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// pullRequestClaim implements POST /pullRequest/claim
+// user_id takes a free review slot, or replace_user_id's slot when given.
+func (h *PRHandler) pullRequestClaim(c *fiber.Ctx) error {
+	var body struct {
+		prRef
+		UserID          string `json:"user_id"`
+		ReplaceUserID   string `json:"replace_user_id"`
+		ExpectedVersion string `json:"expected_version"`
+		ActorID         string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if body.UserID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	ctx, err := preconditionContext(c, body.ExpectedVersion)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	prID, err := h.resolvePRID(c, body.prRef)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr or user not found"}})
+		}
+		return internalError(c, err)
+	}
+	pr, err := h.uc.ClaimReview(h.actorContext(ctx, c, body.ActorID), prID, body.UserID, body.ReplaceUserID)
+	if err != nil {
+		switch err {
+		case usecase.ErrForbidden:
+			return forbidden(c)
+		case usecase.ErrNotFound:
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr or user not found"}})
+		case usecase.ErrPRMerged:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_MERGED", "message": "cannot claim on merged PR"}})
+		case usecase.ErrPRClosed:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_CLOSED", "message": "cannot claim on closed PR"}})
+		case usecase.ErrPRDraft:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "PR_DRAFT", "message": "cannot claim on draft PR"}})
+		case usecase.ErrNotEligible:
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_ELIGIBLE", "message": "user must be an active teammate of the author"}})
+		case usecase.ErrAlreadyAssigned:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "ALREADY_ASSIGNED", "message": "user already reviews this PR"}})
+		case usecase.ErrNoCapacity:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NO_CAPACITY", "message": "PR already has all its reviewers"}})
+		case usecase.ErrNotAssigned:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_ASSIGNED", "message": "replaced reviewer is not assigned to this PR"}})
+		case usecase.ErrAlreadyApproved:
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "ALREADY_APPROVED", "message": "replaced reviewer already approved"}})
+		case usecase.ErrPreconditionFailed:
+			return c.Status(http.StatusPreconditionFailed).JSON(fiber.Map{"error": fiber.Map{"code": "PRECONDITION_FAILED", "message": "pr was modified since the given version"}, "pr": pr})
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"pr": pr})
+}
+
+// pullRequestList implements GET /pullRequest/list?cursor=...&limit=...&fields=...
+// &status=...&author_id=...&team_name=...&created_after=...&created_before=...
+// &sort=newest|oldest|updated
+// The created bounds are RFC 3339 and exclusive; team_name matches the
+// author's team. A cursor is only valid with the sort it was returned for.
+// With sort=updated a PR that changes while the list is paged would move
+// past the cursor, so the next page fails with 409 CURSOR_STALE instead and
+// the list has to be read again from the start.
+// total_estimate is exact when any filter is set.
+func (h *PRHandler) pullRequestList(c *fiber.Ctx) error {
+	page, err := parsePage(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	f, err := parsePRListFilter(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	var asOf time.Time
+	if page.After != nil {
+		if page.After.Sort != f.Sort {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "cursor was issued for a different sort"}})
+		}
+		if f.Sort == entity.PRSortUpdated {
+			asOf = page.After.AsOf
+			changed, err := h.search.PullRequestsUpdatedSince(c.Context(), f, asOf)
+			if err != nil {
+				return internalError(c, err)
+			}
+			if changed {
+				return c.Status(http.StatusConflict).JSON(fiber.Map{"error": fiber.Map{"code": "CURSOR_STALE", "message": "pull requests changed since the first page; list again without a cursor"}})
+			}
+		}
+	}
+	prs, err := h.search.PullRequests(c.Context(), f, page)
+	if err != nil {
+		return internalError(c, err)
+	}
+	if f.Sort == entity.PRSortUpdated && page.After == nil && len(prs) > 0 {
+		asOf = prs[0].UpdatedAt
+	}
+	prs, next := pagination.Trim(prs, page, func(p entity.PullRequest) pagination.Cursor {
+		cur := prCursor(f.Sort, p)
+		cur.AsOf = asOf
+		return cur
+	})
+	items, err := projectAll(parseFields(c), prs)
+	if err != nil {
+		return internalError(c, err)
+	}
+	if !f.Filtered() {
+		return sendPage(c, h.search, "pull_requests", items, next)
+	}
+	total, err := h.search.CountPullRequests(c.Context(), f)
+	if err != nil {
+		return internalError(c, err)
+	}
+	return c.JSON(response.NewList(items, next, total))
+}
+
+// _maxSearchQuery bounds the length of a PR search query, in characters.
+const _maxSearchQuery = 200
+
+// pullRequestSearch implements GET /pullRequest/search?q=...&cursor=...&limit=...
+// q matches whole words of the PR name, and also any part of it when q has
+// three or more characters. Hits are newest first; total_estimate is the
+// exact number of matches, counted for the first page and carried in the
+// cursor to the next ones.
+func (h *PRHandler) pullRequestSearch(c *fiber.Ctx) error {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" || utf8.RuneCountInString(q) > _maxSearchQuery {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": fmt.Sprintf("q must have 1 to %d characters", _maxSearchQuery)}})
+	}
+	page, err := parsePage(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	hits, err := h.search.SearchPullRequests(c.Context(), q, page)
+	if err != nil {
+		return internalError(c, err)
+	}
+	var total int64
+	if page.After == nil {
+		if total, err = h.search.CountSearchHits(c.Context(), q); err != nil {
+			return internalError(c, err)
+		}
+	} else {
+		total = page.After.Total
+	}
+	hits, next := pagination.Trim(hits, page, func(hit entity.PRSearchHit) pagination.Cursor {
+		return pagination.Cursor{CreatedAt: hit.CreatedAt, ID: hit.PullRequestID, Total: total}
+	})
+	return c.JSON(response.NewList(hits, next, total))
+}
+
+func parsePRListFilter(c *fiber.Ctx) (entity.PRListFilter, error) {
+	f := entity.PRListFilter{
+		AuthorID: c.Query("author_id"),
+		TeamName: c.Query("team_name"),
+		Sort:     c.Query("sort", entity.PRSortNewest),
+	}
+
+	if !slices.Contains(entity.PRSorts, f.Sort) {
+		return entity.PRListFilter{}, errors.New("sort must be newest, oldest or updated")
+	}
+
+	if s := c.Query("status"); s != "" {
+		status, err := entity.ParsePRStatus(s)
+		if err != nil {
+			return entity.PRListFilter{}, errors.New("status must be OPEN, MERGED, CLOSED or DRAFT")
+		}
+		f.Status = status
+	}
+
+	for name, dst := range map[string]**time.Time{"created_after": &f.CreatedAfter, "created_before": &f.CreatedBefore} {
+		s := c.Query(name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return entity.PRListFilter{}, errors.New(name + " must be RFC 3339")
+		}
+		*dst = &t
+	}
+
+	return f, nil
+}
+
+// prCursor is the position after pr in the given PR list order.
+func prCursor(sort string, pr entity.PullRequest) pagination.Cursor {
+	if sort == entity.PRSortUpdated {
+		return pagination.Cursor{CreatedAt: pr.UpdatedAt, ID: pr.PullRequestID, Sort: sort}
+	}
+	return pagination.Cursor{CreatedAt: pr.CreatedAt, ID: pr.PullRequestID, Sort: sort}
+}
+
+// pullRequestExplain implements GET /pullRequest/explain?pull_request_id=...
+func (h *PRHandler) pullRequestExplain(c *fiber.Ctx) error {
+	id := c.Query("pull_request_id")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "pull_request_id required"}})
+	}
+	explanation, err := h.uc.ExplainAssignment(c.Context(), id)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "pr not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(explanation)
+}
+
+// getStats implements GET /stats?from=...&to=...&bucket=day|week&format=...
+// With any of the query parameters it adds created/merged/closed counts per
+// bucket between from and to (YYYY-MM-DD, UTC, both inclusive). format=csv or
+// xlsx downloads per-PR and per-user data instead (see exportStats).
+// Like every stats endpoint it sets "stale" when the numbers come from the
+// cache kept for a degraded database.
+func (h *PRHandler) getStats(c *fiber.Ctx) error {
+	if format := c.Query("format", "json"); format != "json" {
+		return h.exportStats(c, format)
+	}
+
+	stats, stale, err := h.uc.GetStats(c.Context())
+	if err != nil {
+		return internalError(c, err)
+	}
+
+	if c.Query("from") == "" && c.Query("to") == "" && c.Query("bucket") == "" {
+		return c.JSON(fiber.Map{"stats": stats, "stale": stale})
+	}
+
+	from, to, err := statsDays(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	to = to.Truncate(24 * time.Hour)
+	bucket := c.Query("bucket", entity.BucketDay)
+
+	buckets, staleSeries, err := h.uc.StatsSeries(c.Context(), from, to.AddDate(0, 0, 1), bucket)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidStatsRange) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(fiber.Map{"stats": stats, "stale": stale || staleSeries, "series": fiber.Map{
+		"from":    from.Format(time.DateOnly),
+		"to":      to.Format(time.DateOnly),
+		"bucket":  bucket,
+		"buckets": buckets,
+	}})
+}
+
+// getStatsSLA implements GET /stats/sla?from=...&to=...
+// from and to are as for GET /stats; the range defaults to the last 30 days.
+func (h *PRHandler) getStatsSLA(c *fiber.Ctx) error {
+	from, to, err := statsDays(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	to = to.Truncate(24 * time.Hour)
+
+	teams, stale, err := h.uc.TeamSLA(c.Context(), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidStatsRange) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(teams).With(fiber.Map{
+		"from":  from.Format(time.DateOnly),
+		"to":    to.Format(time.DateOnly),
+		"stale": stale,
+	}))
+}
+
+// getStatsLeaderboard implements GET /stats/leaderboard?from=...&to=...&sort=completed|open&team_name=...&limit=...
+// from and to are as for GET /stats and bound the completed reviews; open
+// assignments are current.
+func (h *PRHandler) getStatsLeaderboard(c *fiber.Ctx) error {
+	from, to, err := statsDays(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+	to = to.Truncate(24 * time.Hour)
+
+	entries, stale, err := h.uc.ReviewerLeaderboard(c.Context(), entity.ReviewerRankFilter{
+		From:     from,
+		To:       to.AddDate(0, 0, 1),
+		TeamName: c.Query("team_name"),
+		Sort:     c.Query("sort"),
+		Limit:    c.QueryInt("limit"),
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidStatsRange) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(entries).With(fiber.Map{
+		"from":  from.Format(time.DateOnly),
+		"to":    to.Format(time.DateOnly),
+		"stale": stale,
+	}))
+}
+
+// getUserStats implements GET /stats/user?user_id=...
+func (h *PRHandler) getUserStats(c *fiber.Ctx) error {
+	id := c.Query("user_id")
+	if id == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "user_id required"}})
+	}
+	stats, stale, err := h.uc.UserStats(c.Context(), id)
+	if err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "user not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(fiber.Map{"stats": stats, "stale": stale})
+}
+
+// _defaultStatsHistoryDays is the range GET /stats and /stats/history cover
+// without from.
+const _defaultStatsHistoryDays = 30
+
+// statsDays reads the from and to days (YYYY-MM-DD, UTC) of a stats query;
+// to defaults to now and from to _defaultStatsHistoryDays before it.
+func statsDays(c *fiber.Ctx) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if s := c.Query("to"); s != "" {
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be YYYY-MM-DD")
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -_defaultStatsHistoryDays)
+	if s := c.Query("from"); s != "" {
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be YYYY-MM-DD")
+		}
+		from = t
+	}
+	return from, to, nil
+}
+
+// getStatsHistory implements GET /stats/history?team_name=...&from=...&to=...&as_of=...
+// Dates are YYYY-MM-DD (UTC). With as_of it returns each team's latest
+// snapshot on or before that day instead of a range.
+func (h *PRHandler) getStatsHistory(c *fiber.Ctx) error {
+	team := c.Query("team_name")
+
+	if asOf := c.Query("as_of"); asOf != "" {
+		day, err := time.Parse(time.DateOnly, asOf)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "as_of must be YYYY-MM-DD"}})
+		}
+		snapshots, err := h.uc.StatsAsOf(c.Context(), team, day)
+		if err != nil {
+			return internalError(c, err)
+		}
+		return c.JSON(response.All(snapshots).With(fiber.Map{"as_of": asOf}))
+	}
+
+	from, to, err := statsDays(c)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+	}
+
+	snapshots, err := h.uc.StatsHistory(c.Context(), team, from, to)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidStatsRange) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		}
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(snapshots).With(fiber.Map{"from": from.Format(time.DateOnly), "to": to.Format(time.DateOnly)}))
+}
+
+// prRef identifies a PR either by service ID or by repository + VCS number.
+type prRef struct {
+	PullRequestID  string `json:"pull_request_id"`
+	Repository     string `json:"repository"`
+	ExternalNumber int    `json:"external_number"`
+}
+
+func (h *PRHandler) resolvePRID(c *fiber.Ctx, ref prRef) (string, error) {
+	if ref.PullRequestID != "" || ref.Repository == "" {
+		return ref.PullRequestID, nil
 	}
-	return c.JSON(fiber.Map{"stats": stats})
+	return h.uc.ResolvePRID(c.Context(), ref.Repository, ref.ExternalNumber)
 }
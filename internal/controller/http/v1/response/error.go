@@ -4,9 +4,15 @@ const (
 	ErrorCodeTeamExists  = "TEAM_EXISTS"
 	ErrorCodePRExists    = "PR_EXISTS"
 	ErrorCodePRMerged    = "PR_MERGED"
+	ErrorCodePRClosed    = "PR_CLOSED"
+	ErrorCodePRImmutable = "PR_IMMUTABLE"
 	ErrorCodeNotAssigned = "NOT_ASSIGNED"
 	ErrorCodeNoCandidate = "NO_CANDIDATE"
 	ErrorCodeNotFound    = "NOT_FOUND"
+
+	ErrorCodePreconditionFailed = "PRECONDITION_FAILED"
+	ErrorCodeQuotaExceeded      = "QUOTA_EXCEEDED"
+	ErrorCodePoolExhausted      = "POOL_EXHAUSTED"
 )
 
 type ErrorResponse struct {
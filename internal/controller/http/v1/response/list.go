@@ -0,0 +1,39 @@
+package response
+
+// List is the envelope of every list response. NextCursor is "" on the last
+// page and for lists that aren't paginated; TotalEstimate counts every item,
+// not just this page, and may be approximate for large tables.
+type List[T any] struct {
+	Items         []T    `json:"items"`
+	NextCursor    string `json:"next_cursor"`
+	TotalEstimate int64  `json:"total_estimate"`
+}
+
+// NewList -. A nil items slice is sent as [].
+func NewList[T any](items []T, next string, total int64) List[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return List[T]{Items: items, NextCursor: next, TotalEstimate: total}
+}
+
+// All wraps a complete, unpaginated list.
+func All[T any](items []T) List[T] {
+	return NewList(items, "", int64(len(items)))
+}
+
+// With returns l with fields sent alongside the envelope, for the context a
+// list is scoped to (user_id, team_name, a date range).
+func (l List[T]) With(fields map[string]any) map[string]any {
+	out := map[string]any{
+		"items":          l.Items,
+		"next_cursor":    l.NextCursor,
+		"total_estimate": l.TotalEstimate,
+	}
+	for k, v := range fields {
+		out[k] = v
+	}
+
+	return out
+}
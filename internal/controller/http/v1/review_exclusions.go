@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
+	"github.com/evrone/go-clean-template/internal/entity"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+// reviewExclusionsList implements GET /reviewExclusions
+func (h *PRHandler) reviewExclusionsList(c *fiber.Ctx) error {
+	exclusions, err := h.uc.ListReviewExclusions(c.Context())
+	if err != nil {
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(exclusions))
+}
+
+// reviewExclusionsAdd implements POST /reviewExclusions/add
+func (h *PRHandler) reviewExclusionsAdd(c *fiber.Ctx) error {
+	var body struct {
+		entity.ReviewExclusion
+		ActorID string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	exclusion, err := h.uc.SaveReviewExclusion(h.actorContext(c.Context(), c, body.ActorID), entity.ReviewExclusion{
+		Reviewers: body.Reviewers,
+		Authors:   body.Authors,
+		Labels:    body.Labels,
+		Reason:    body.Reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidExclusion):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		case err == usecase.ErrForbidden:
+			return forbidden(c)
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"exclusion": exclusion})
+}
+
+// reviewExclusionsDelete implements POST /reviewExclusions/delete
+func (h *PRHandler) reviewExclusionsDelete(c *fiber.Ctx) error {
+	var body struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if err := h.uc.DeleteReviewExclusion(c.Context(), body.ID); err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "exclusion not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/evrone/go-clean-template/internal/controller/http/v1/response"
+	"github.com/evrone/go-clean-template/internal/entity"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+// reviewRulesList implements GET /reviewRules?repository=...
+func (h *PRHandler) reviewRulesList(c *fiber.Ctx) error {
+	repository := c.Query("repository")
+	if repository == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "repository required"}})
+	}
+	rules, err := h.uc.ListReviewRules(c.Context(), repository)
+	if err != nil {
+		return internalError(c, err)
+	}
+	return c.JSON(response.All(rules).With(fiber.Map{"repository": repository}))
+}
+
+// reviewRulesAdd implements POST /reviewRules/add
+// A rule with an existing repository and pattern has its reviewers replaced.
+func (h *PRHandler) reviewRulesAdd(c *fiber.Ctx) error {
+	var body struct {
+		entity.ReviewRule
+		ActorID string `json:"actor_id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	rule, err := h.uc.SaveReviewRule(h.actorContext(c.Context(), c, body.ActorID), entity.ReviewRule{
+		Repository: body.Repository,
+		Pattern:    body.Pattern,
+		Reviewers:  body.Reviewers,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidRule):
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": err.Error()}})
+		case err == usecase.ErrForbidden:
+			return forbidden(c)
+		default:
+			return internalError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{"rule": rule})
+}
+
+// reviewRulesDelete implements POST /reviewRules/delete
+func (h *PRHandler) reviewRulesDelete(c *fiber.Ctx) error {
+	var body struct {
+		ID int64 `json:"id"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+	if err := h.uc.DeleteReviewRule(c.Context(), body.ID); err != nil {
+		if err == usecase.ErrNotFound {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": fiber.Map{"code": "NOT_FOUND", "message": "rule not found"}})
+		}
+		return internalError(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
@@ -0,0 +1,169 @@
+package v1
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/evrone/go-clean-template/internal/controller/telegram"
+	usecase "github.com/evrone/go-clean-template/internal/usecase"
+	bb "github.com/evrone/go-clean-template/pkg/bitbucket"
+	gh "github.com/evrone/go-clean-template/pkg/github"
+	gl "github.com/evrone/go-clean-template/pkg/gitlab"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TelegramSecretHeader carries the secret_token set via setWebhook.
+const TelegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// WebhookHandler accepts inbound provider webhooks and hands them to the
+// webhook use case, which stores them for replay before processing.
+type WebhookHandler struct {
+	webhooks        *usecase.WebhookUseCase
+	telegramSecret  string
+	githubSecret    string
+	bitbucketSecret string
+	gitlabSecret    string
+	l               logger.Interface
+}
+
+func NewWebhookHandler(webhooks *usecase.WebhookUseCase, telegramSecret, githubSecret, bitbucketSecret, gitlabSecret string, l logger.Interface) *WebhookHandler {
+	return &WebhookHandler{
+		webhooks:        webhooks,
+		telegramSecret:  telegramSecret,
+		githubSecret:    githubSecret,
+		bitbucketSecret: bitbucketSecret,
+		gitlabSecret:    gitlabSecret,
+		l:               l,
+	}
+}
+
+func (h *WebhookHandler) RegisterTelegramRoutes(router fiber.Router) {
+	router.Group("/webhooks").Post("/telegram", h.telegram)
+}
+
+func (h *WebhookHandler) RegisterGitHubRoutes(router fiber.Router) {
+	router.Group("/webhooks").Post("/github", h.github)
+}
+
+func (h *WebhookHandler) RegisterBitbucketRoutes(router fiber.Router) {
+	router.Group("/webhooks").Post("/bitbucket", h.bitbucket)
+}
+
+func (h *WebhookHandler) RegisterGitLabRoutes(router fiber.Router) {
+	router.Group("/webhooks").Post("/gitlab", h.gitlab)
+}
+
+// telegram implements POST /webhooks/telegram
+// Redelivered updates are acknowledged without being processed again.
+func (h *WebhookHandler) telegram(c *fiber.Ctx) error {
+	got := c.Get(TelegramSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(got), []byte(h.telegramSecret)) != 1 {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "invalid secret token"}})
+	}
+
+	var update struct {
+		UpdateID      int64           `json:"update_id"`
+		Message       json.RawMessage `json:"message"`
+		CallbackQuery json.RawMessage `json:"callback_query"`
+	}
+	if err := json.Unmarshal(c.Body(), &update); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+
+	event := "update"
+	switch {
+	case len(update.CallbackQuery) > 0:
+		event = telegram.EventCallbackQuery
+	case len(update.Message) > 0:
+		event = telegram.EventMessage
+	}
+
+	err := h.webhooks.Ingest(c.Context(), "telegram", strconv.FormatInt(update.UpdateID, 10), event, c.Body())
+	if err != nil && err != usecase.ErrDuplicateDelivery {
+		h.l.Error(fmt.Errorf("http - v1 - webhooks - telegram: %w", err))
+		return internalError(c, err)
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+// github implements POST /webhooks/github
+// Only pull_request events are stored; other events (ping, push, ...) are acknowledged and dropped.
+func (h *WebhookHandler) github(c *fiber.Ctx) error {
+	if !gh.VerifySignature(h.githubSecret, c.Body(), c.Get(gh.HeaderSignature)) {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "invalid signature"}})
+	}
+
+	event, deliveryID := c.Get(gh.HeaderEvent), c.Get(gh.HeaderDelivery)
+	if deliveryID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": gh.HeaderDelivery + " required"}})
+	}
+	if event != gh.EventPullRequest {
+		return c.SendStatus(http.StatusNoContent)
+	}
+	if !json.Valid(c.Body()) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+
+	err := h.webhooks.Ingest(c.Context(), "github", deliveryID, event, c.Body())
+	if err != nil && err != usecase.ErrDuplicateDelivery {
+		h.l.Error(fmt.Errorf("http - v1 - webhooks - github: %w", err))
+		return internalError(c, err)
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+// bitbucket implements POST /webhooks/bitbucket
+// Only pullrequest:created and pullrequest:fulfilled are stored; other events are acknowledged and dropped.
+func (h *WebhookHandler) bitbucket(c *fiber.Ctx) error {
+	if !bb.VerifySignature(h.bitbucketSecret, c.Body(), c.Get(bb.HeaderSignature)) {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "invalid signature"}})
+	}
+
+	event, deliveryID := c.Get(bb.HeaderEvent), c.Get(bb.HeaderDelivery)
+	if deliveryID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": bb.HeaderDelivery + " required"}})
+	}
+	if event != bb.EventPullRequestCreated && event != bb.EventPullRequestFulfilled {
+		return c.SendStatus(http.StatusNoContent)
+	}
+	if !json.Valid(c.Body()) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+
+	err := h.webhooks.Ingest(c.Context(), "bitbucket", deliveryID, event, c.Body())
+	if err != nil && err != usecase.ErrDuplicateDelivery {
+		h.l.Error(fmt.Errorf("http - v1 - webhooks - bitbucket: %w", err))
+		return internalError(c, err)
+	}
+	return c.SendStatus(http.StatusOK)
+}
+
+// gitlab implements POST /webhooks/gitlab
+// Only merge request events are stored; other events are acknowledged and dropped.
+func (h *WebhookHandler) gitlab(c *fiber.Ctx) error {
+	if !gl.VerifySecret(h.gitlabSecret, c.Get(gl.HeaderSecret)) {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": fiber.Map{"code": "UNAUTHORIZED", "message": "invalid secret token"}})
+	}
+
+	event, deliveryID := c.Get(gl.HeaderEvent), c.Get(gl.HeaderDelivery)
+	if deliveryID == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": gl.HeaderDelivery + " required"}})
+	}
+	if event != gl.EventMergeRequest {
+		return c.SendStatus(http.StatusNoContent)
+	}
+	if !json.Valid(c.Body()) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": fiber.Map{"code": "BAD_REQUEST", "message": "invalid body"}})
+	}
+
+	err := h.webhooks.Ingest(c.Context(), "gitlab", deliveryID, event, c.Body())
+	if err != nil && err != usecase.ErrDuplicateDelivery {
+		h.l.Error(fmt.Errorf("http - v1 - webhooks - gitlab: %w", err))
+		return internalError(c, err)
+	}
+	return c.SendStatus(http.StatusOK)
+}
@@ -0,0 +1,189 @@
+// Package telegram handles updates delivered to the Telegram bot webhook.
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+	"github.com/evrone/go-clean-template/pkg/telegram"
+)
+
+// Webhook event names for the update kinds the bot handles.
+const (
+	EventCallbackQuery = "callback_query"
+	EventMessage       = "message"
+)
+
+const _helpText = `Commands:
+/myreviews - your open reviews
+/reassign <pr_id> - hand your review to a teammate`
+
+// Bot turns inline button presses and chat commands into PR use case calls.
+// It is registered as the "telegram" webhook processor so failed updates
+// can be replayed.
+type Bot struct {
+	uc        *usecase.PRUseCase
+	client    *telegram.Client
+	snoozeFor time.Duration
+	l         logger.Interface
+}
+
+// NewBot -.
+func NewBot(uc *usecase.PRUseCase, client *telegram.Client, snoozeFor time.Duration, l logger.Interface) *Bot {
+	return &Bot{uc: uc, client: client, snoozeFor: snoozeFor, l: l}
+}
+
+// Process -. Updates other than callback queries and messages are ignored.
+func (b *Bot) Process(ctx context.Context, event string, payload []byte) error {
+	if event != EventCallbackQuery && event != EventMessage {
+		return nil
+	}
+
+	var u telegram.Update
+	if err := json.Unmarshal(payload, &u); err != nil {
+		return fmt.Errorf("telegram - decode update: %w", err)
+	}
+
+	switch {
+	case u.CallbackQuery != nil:
+		reply := b.handleCallback(ctx, u.CallbackQuery)
+
+		return b.client.AnswerCallbackQuery(ctx, u.CallbackQuery.ID, reply)
+	case u.Message != nil && u.Message.From != nil && strings.HasPrefix(u.Message.Text, "/"):
+		reply := b.handleCommand(ctx, u.Message)
+		chatID := strconv.FormatInt(u.Message.Chat.ID, 10)
+
+		return b.client.SendMessage(ctx, chatID, reply, nil)
+	}
+
+	return nil
+}
+
+// handleCommand runs a slash command and returns the reply text.
+func (b *Bot) handleCommand(ctx context.Context, m *telegram.Message) string {
+	args := strings.Fields(m.Text)
+	cmd, _, _ := strings.Cut(args[0], "@") // "/cmd@BotName" in group chats
+
+	origin := usecase.ChatOrigin{
+		Provider:       entity.IdentityProviderTelegram,
+		ExternalUserID: strconv.FormatInt(m.From.ID, 10),
+		ChannelID:      strconv.FormatInt(m.Chat.ID, 10),
+		MessageID:      strconv.FormatInt(m.MessageID, 10),
+	}
+
+	switch cmd {
+	case "/myreviews":
+		reviews, err := b.uc.ChatReviews(ctx, origin)
+		if err != nil {
+			b.l.Warn("telegram - myreviews by %s: %v", origin.ExternalUserID, err)
+			return describe(err)
+		}
+
+		return formatReviews(reviews)
+	case "/reassign":
+		if len(args) != 2 {
+			return "Usage: /reassign <pr_id>"
+		}
+
+		res, err := b.uc.RunChatAction(ctx, usecase.ChatActionRequest{
+			Origin:        origin,
+			Action:        usecase.ChatActionReassign,
+			PullRequestID: args[1],
+		})
+		if err != nil {
+			b.l.Warn("telegram - reassign on %s by %s: %v", args[1], origin.ExternalUserID, err)
+			return describe(err)
+		}
+
+		return "Review of " + args[1] + " passed to " + res.ReplacedBy
+	default:
+		return _helpText
+	}
+}
+
+func formatReviews(reviews []entity.ReviewDue) string {
+	if len(reviews) == 0 {
+		return "You have no open reviews"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("Your open reviews:")
+
+	for _, r := range reviews {
+		fmt.Fprintf(&sb, "\n%s %s (due %s)", r.PullRequestID, r.PullRequestName, r.DueAt.Format("Jan 2 15:04 MST"))
+	}
+
+	return sb.String()
+}
+
+// handleCallback runs the action and returns the text shown to the user.
+func (b *Bot) handleCallback(ctx context.Context, q *telegram.CallbackQuery) string {
+	kind, prID, ok := notifier.ParseCallbackData(q.Data)
+	if !ok {
+		return "Unknown action"
+	}
+
+	origin := usecase.ChatOrigin{
+		Provider:       entity.IdentityProviderTelegram,
+		ExternalUserID: strconv.FormatInt(q.From.ID, 10),
+	}
+	if q.Message != nil {
+		origin.ChannelID = strconv.FormatInt(q.Message.Chat.ID, 10)
+		origin.MessageID = strconv.FormatInt(q.Message.MessageID, 10)
+	}
+
+	res, err := b.uc.RunChatAction(ctx, usecase.ChatActionRequest{
+		Origin:        origin,
+		Action:        kind,
+		PullRequestID: prID,
+		SnoozeFor:     b.snoozeFor,
+	})
+	if err != nil {
+		b.l.Warn("telegram - %s on %s by %s: %v", kind, prID, origin.ExternalUserID, err)
+		return describe(err)
+	}
+
+	switch kind {
+	case notifier.ActionDecline:
+		return "Declined, review passed to " + res.ReplacedBy
+	case notifier.ActionSnooze:
+		return "Snoozed until " + res.SnoozedUntil.Format("Jan 2 15:04 MST")
+	default:
+		return "Approved"
+	}
+}
+
+func describe(err error) string {
+	switch {
+	case errors.Is(err, usecase.ErrUnknownAction):
+		return "Unknown action"
+	case errors.Is(err, usecase.ErrIdentityNotLinked):
+		return "Your Telegram account is not linked to a reviewer"
+	case errors.Is(err, usecase.ErrForbidden):
+		return "You are not allowed to do this on this PR"
+	case errors.Is(err, usecase.ErrNotFound):
+		return "PR not found"
+	case errors.Is(err, usecase.ErrPRMerged):
+		return "PR is already merged"
+	case errors.Is(err, usecase.ErrPRClosed):
+		return "PR is closed"
+	case errors.Is(err, usecase.ErrNotAssigned):
+		return "You are no longer assigned to this PR"
+	case errors.Is(err, usecase.ErrNoCandidate):
+		return "No teammate is available to take over"
+	default:
+		return "Something went wrong, try again later"
+	}
+}
+
+var _ usecase.WebhookProcessor = (*Bot)(nil)
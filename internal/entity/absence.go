@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// Absence is a window [StartsAt, EndsAt) during which the user gets no new
+// reviews. ReleasedAt is when their open reviews were handed over.
+type Absence struct {
+	ID         int64      `json:"id"`
+	UserID     string     `json:"user_id"`
+	StartsAt   time.Time  `json:"starts_at"`
+	EndsAt     time.Time  `json:"ends_at"`
+	Reason     string     `json:"reason,omitempty"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Covers reports whether t falls within the absence.
+func (a Absence) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
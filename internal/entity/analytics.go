@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// PR lifecycle event types mirrored to the analytics store.
+const (
+	PREventCreated            = "created"
+	PREventReviewerAssigned   = "reviewer_assigned"
+	PREventReviewerUnassigned = "reviewer_unassigned"
+	PREventApproved           = "approved"
+	PREventUpdated            = "updated"
+	PREventMerged             = "merged"
+	PREventClosed             = "closed"
+	PREventReopened           = "reopened"
+	PREventReadyForReview     = "ready_for_review"
+)
+
+// PREvent is one step in a PR's lifecycle. UserID is the reviewer for
+// reviewer_* and approved events and the author otherwise.
+type PREvent struct {
+	Type          string    `json:"type"`
+	PullRequestID string    `json:"pull_request_id"`
+	AuthorID      string    `json:"author_id"`
+	UserID        string    `json:"user_id"`
+	TeamName      string    `json:"team_name"`
+	Repository    string    `json:"repository"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// Time-series bucket sizes.
+const (
+	BucketDay  = "day"
+	BucketWeek = "week"
+)
+
+// AnalyticsRange bounds an analytics query to [From, To).
+type AnalyticsRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// TimeBucket counts lifecycle events in the bucket starting at Start.
+type TimeBucket struct {
+	Start   time.Time `json:"start"`
+	Created int       `json:"created"`
+	Merged  int       `json:"merged"`
+	Closed  int       `json:"closed"`
+}
+
+// HeatmapCell counts events on a weekday (1 = Monday) and UTC hour.
+type HeatmapCell struct {
+	Weekday int `json:"weekday"`
+	Hour    int `json:"hour"`
+	Count   int `json:"count"`
+}
+
+// LeaderboardEntry summarises a reviewer's activity over a range.
+type LeaderboardEntry struct {
+	UserID      string `json:"user_id"`
+	Assignments int    `json:"assignments"`
+	Approvals   int    `json:"approvals"`
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records a mutation together with where it originated.
+// ChannelID and MessageID identify the chat message for chat-originated actions.
+type AuditEntry struct {
+	ID            int64           `json:"id"`
+	ActorID       string          `json:"actor_id"`
+	Action        string          `json:"action"`
+	PullRequestID string          `json:"pull_request_id,omitempty"`
+	Source        string          `json:"source"`
+	ChannelID     string          `json:"channel_id,omitempty"`
+	MessageID     string          `json:"message_id,omitempty"`
+	Details       json.RawMessage `json:"details,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	ActorID       string
+	Source        string
+	PullRequestID string
+	Limit         int
+}
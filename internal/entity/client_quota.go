@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+const (
+	QuotaPeriodDay   = "day"
+	QuotaPeriodMonth = "month"
+)
+
+// ClientQuota caps the requests an API client may make per UTC day and per
+// calendar month; 0 leaves that period uncapped.
+type ClientQuota struct {
+	ClientID  string    `json:"client_id"`
+	Daily     int64     `json:"daily"`
+	Monthly   int64     `json:"monthly"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// QuotaWindow is a client's standing in one capped period.
+type QuotaWindow struct {
+	Period    string    `json:"period"`
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// QuotaStatus lists the client's capped periods; it is empty for an uncapped client.
+type QuotaStatus struct {
+	ClientID string        `json:"client_id"`
+	Windows  []QuotaWindow `json:"windows"`
+}
+
+// Tightest returns the window with the fewest requests left, the one that
+// resets later on a tie.
+func (s QuotaStatus) Tightest() (QuotaWindow, bool) {
+	if len(s.Windows) == 0 {
+		return QuotaWindow{}, false
+	}
+
+	w := s.Windows[0]
+	for _, o := range s.Windows[1:] {
+		if o.Remaining < w.Remaining || o.Remaining == w.Remaining && o.ResetAt.After(w.ResetAt) {
+			w = o
+		}
+	}
+
+	return w, true
+}
+
+// QuotaPeriodStart returns the start of the period containing t.
+func QuotaPeriodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	if period == QuotaPeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// QuotaPeriodEnd returns when the period containing t resets.
+func QuotaPeriodEnd(period string, t time.Time) time.Time {
+	start := QuotaPeriodStart(period, t)
+	if period == QuotaPeriodMonth {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
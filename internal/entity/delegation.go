@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Management scopes a team lead may delegate.
+const (
+	ScopeReassign = "reassign"
+	ScopeCapacity = "capacity"
+	ScopeSettings = "settings"
+)
+
+// Scopes lists every delegable scope.
+var Scopes = []string{ScopeReassign, ScopeCapacity, ScopeSettings}
+
+// Delegation lets DelegateID act with the lead's permissions for Scopes in
+// TeamName between StartsAt and EndsAt, unless revoked earlier.
+type Delegation struct {
+	ID          int64      `json:"id"`
+	TeamName    string     `json:"team_name"`
+	DelegatorID string     `json:"delegator_id"`
+	DelegateID  string     `json:"delegate_id"`
+	Scopes      []string   `json:"scopes"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      time.Time  `json:"ends_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ActiveAt reports whether the delegation is in effect at t.
+func (d Delegation) ActiveAt(t time.Time) bool {
+	return d.RevokedAt == nil && !t.Before(d.StartsAt) && t.Before(d.EndsAt)
+}
+
+// Covers reports whether the delegation grants scope at t.
+func (d Delegation) Covers(scope string, t time.Time) bool {
+	return d.ActiveAt(t) && slices.Contains(d.Scopes, scope)
+}
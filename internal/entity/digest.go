@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// TeamDigest summarizes a team's review activity since Since for standups.
+// PRs are those authored by team members; Markdown renders the same summary
+// for posting into chat.
+type TeamDigest struct {
+	TeamName    string             `json:"team_name"`
+	Since       time.Time          `json:"since"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	NewPRs      []PullRequestShort `json:"new_prs"`
+	Merged      []PullRequestShort `json:"merged"`
+	Overdue     []OverdueReview    `json:"overdue_reviews"`
+	Overloaded  []ReviewerLoad     `json:"overloaded"`
+	Markdown    string             `json:"markdown"`
+}
+
+// OverdueReview is an open PR past its review SLA and the reviewers who have
+// not approved it yet.
+type OverdueReview struct {
+	PullRequestID    string    `json:"pull_request_id"`
+	PullRequestName  string    `json:"pull_request_name"`
+	AuthorID         string    `json:"author_id"`
+	Priority         string    `json:"priority"`
+	DueAt            time.Time `json:"due_at"`
+	PendingReviewers []string  `json:"pending_reviewers"`
+}
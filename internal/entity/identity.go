@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+const (
+	IdentityProviderTelegram  = "telegram"
+	IdentityProviderGitHub    = "github"
+	IdentityProviderBitbucket = "bitbucket"
+	// IdentityProviderGitLab links the numeric GitLab user ID.
+	IdentityProviderGitLab = "gitlab"
+	IdentityProviderSlack  = "slack"
+	IdentityProviderEmail  = "email"
+	IdentityProviderOIDC   = "oidc"
+)
+
+// UserIdentity links a service user to their account in an external system.
+type UserIdentity struct {
+	UserID     string    `json:"user_id"`
+	Provider   string    `json:"provider"`
+	ExternalID string    `json:"external_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// NotificationDelivery records what one provider did with one notification:
+// Status is sent, skipped or failed, and Detail gives the skip reason or the
+// provider's error.
+type NotificationDelivery struct {
+	ID            int64     `json:"id"`
+	Provider      string    `json:"provider"`
+	Kind          string    `json:"kind"`
+	UserID        string    `json:"user_id,omitempty"`
+	TeamName      string    `json:"team_name,omitempty"`
+	PullRequestID string    `json:"pull_request_id,omitempty"`
+	Status        string    `json:"status"`
+	Detail        string    `json:"detail,omitempty"`
+	DurationMs    float64   `json:"duration_ms"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type NotificationFilter struct {
+	UserID        string
+	Provider      string
+	Status        string
+	Kind          string
+	PullRequestID string
+	Since         time.Time
+	Limit         int
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+// OffboardReport describes what removing users upstream changed.
+type OffboardReport struct {
+	Users   []OffboardedUser `json:"users"`
+	Unknown []string         `json:"unknown"`
+}
+
+// OffboardedUser is one deactivated user. Reassigned maps each open PR they
+// were reviewing to its new reviewer; Unassigned lists PRs that had no
+// replacement candidate. Reviews they already approved are kept.
+type OffboardedUser struct {
+	UserID            string            `json:"user_id"`
+	Reassigned        map[string]string `json:"reassigned"`
+	Unassigned        []string          `json:"unassigned"`
+	IdentitiesRevoked int64             `json:"identities_revoked"`
+}
+
+// UserDeletion describes a user deleted through the API: on top of being
+// offboarded, the open and draft PRs they authored were closed.
+type UserDeletion struct {
+	OffboardedUser
+	ClosedPullRequests []string  `json:"closed_pull_requests"`
+	DeletedAt          time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// UrgentLabels mark PRs that always get the team's on-call reviewer.
+var UrgentLabels = []string{"hotfix", "urgent"}
+
+// OnCallRotation is a team's weekly on-call order. Weeks start on Monday
+// (UTC) and are counted from the first Monday of the Unix epoch, so week n
+// falls to Members[n % len(Members)].
+type OnCallRotation struct {
+	TeamName  string    `json:"team_name"`
+	Members   []string  `json:"members"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OnCallOverride replaces the rotation's pick for one week.
+type OnCallOverride struct {
+	TeamName  string    `json:"team_name"`
+	WeekStart time.Time `json:"week_start"`
+	UserID    string    `json:"user_id"`
+}
+
+// OnCallShift is who is on call for the week starting at WeekStart.
+type OnCallShift struct {
+	WeekStart time.Time `json:"week_start"`
+	UserID    string    `json:"user_id,omitempty"`
+	Override  bool      `json:"override"`
+}
+
+// _epochMonday is the first Monday of the Unix epoch.
+var _epochMonday = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+// WeekStart returns midnight UTC on the Monday of t's week.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// At returns the rotation's pick for the week containing t, or "" when the
+// rotation is empty.
+func (r OnCallRotation) At(t time.Time) string {
+	if len(r.Members) == 0 {
+		return ""
+	}
+	week := int(WeekStart(t).Sub(_epochMonday).Hours() / (24 * 7))
+	return r.Members[week%len(r.Members)]
+}
+
+// IsUrgent reports whether labels include one of UrgentLabels, ignoring case.
+func IsUrgent(labels []string) bool {
+	return slices.ContainsFunc(labels, func(l string) bool {
+		return slices.Contains(UrgentLabels, strings.ToLower(l))
+	})
+}
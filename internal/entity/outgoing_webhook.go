@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Events delivered to outgoing webhooks.
+const (
+	HookEventPRCreated          = "pr.created"
+	HookEventPRUpdated          = "pr.updated"
+	HookEventPRMerged           = "pr.merged"
+	HookEventReviewerReassigned = "reviewer.reassigned"
+	// HookEventReviewerAssigned is sent per reviewer, however they were assigned,
+	// for mirroring assignments into the VCS.
+	HookEventReviewerAssigned = "reviewer.assigned"
+
+	// HookEventPing is only sent by test deliveries, whatever the subscription.
+	HookEventPing = "ping"
+)
+
+// HookEvents lists every event an outgoing webhook can subscribe to.
+var HookEvents = []string{HookEventPRCreated, HookEventPRUpdated, HookEventPRMerged, HookEventReviewerReassigned, HookEventReviewerAssigned}
+
+// OutgoingWebhook is a URL a team registered to receive PR events. An empty
+// Events list subscribes to all of them. Secret is only returned on creation.
+// With RequireAck the receiver must acknowledge each reviewer.assigned
+// delivery at its ack_url, or it is sent again.
+type OutgoingWebhook struct {
+	ID         int64     `json:"id"`
+	TeamName   string    `json:"team_name"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	Events     []string  `json:"events"`
+	RequireAck bool      `json:"require_ack"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Wants reports whether the webhook is subscribed to event.
+func (h OutgoingWebhook) Wants(event string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+
+	for _, e := range h.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HookEvent is the JSON body POSTed to outgoing webhooks.
+type HookEvent struct {
+	ID            string      `json:"id"`
+	Event         string      `json:"event"`
+	TeamName      string      `json:"team_name"`
+	OccurredAt    time.Time   `json:"occurred_at"`
+	PullRequest   PullRequest `json:"pull_request"`
+	OldReviewerID string      `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string      `json:"new_reviewer_id,omitempty"`
+	// AckURL is where the receiver confirms it applied the assignment; only set
+	// for webhooks that require acknowledgment.
+	AckURL string `json:"ack_url,omitempty"`
+}
+
+// HookTestResult reports a test delivery to an outgoing webhook. OK is set for
+// a 2xx response; Error explains a delivery that got no response at all.
+type HookTestResult struct {
+	DeliveryID string  `json:"delivery_id"`
+	Event      string  `json:"event"`
+	URL        string  `json:"url"`
+	OK         bool    `json:"ok"`
+	StatusCode int     `json:"status_code,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Assignment acknowledgment states. A pending delivery is resent until it is
+// acknowledged or runs out of attempts and becomes unacknowledged.
+const (
+	AckStatusPending        = "pending"
+	AckStatusApplied        = "applied"
+	AckStatusRejected       = "rejected"
+	AckStatusUnacknowledged = "unacknowledged"
+)
+
+// AssignmentAck tracks a reviewer.assigned delivery to a webhook that must
+// acknowledge it. Error is what the receiver reported with a rejection.
+type AssignmentAck struct {
+	DeliveryID    string          `json:"delivery_id"`
+	WebhookID     int64           `json:"webhook_id"`
+	TeamName      string          `json:"team_name"`
+	PullRequestID string          `json:"pull_request_id"`
+	ReviewerID    string          `json:"reviewer_id"`
+	Payload       json.RawMessage `json:"-"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastSentAt    time.Time       `json:"last_sent_at"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	AckedAt       *time.Time      `json:"acked_at,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type AssignmentAckFilter struct {
+	Status   string
+	TeamName string
+	Limit    int
+}
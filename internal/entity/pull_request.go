@@ -1,22 +1,180 @@
 package entity
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
+// PRStatus is the lifecycle state of a PR. Only the constants below are
+// valid; the zero value means unset.
 type PRStatus string
 
 const (
 	PRStatusOpen   PRStatus = "OPEN"
 	PRStatusMerged PRStatus = "MERGED"
+	PRStatusClosed PRStatus = "CLOSED"
+	// PRStatusDraft PRs have no reviewers until marked ready.
+	PRStatusDraft PRStatus = "DRAFT"
+)
+
+var PRStatuses = []PRStatus{PRStatusOpen, PRStatusMerged, PRStatusClosed, PRStatusDraft}
+
+// ErrUnknownPRStatus is returned for a status outside PRStatuses.
+var ErrUnknownPRStatus = errors.New("unknown PR status")
+
+// ParsePRStatus returns the status named s, matched exactly.
+func ParsePRStatus(s string) (PRStatus, error) {
+	status := PRStatus(s)
+	if !status.Valid() {
+		return "", fmt.Errorf("%w %q", ErrUnknownPRStatus, s)
+	}
+
+	return status, nil
+}
+
+// Valid reports whether s is one of PRStatuses.
+func (s PRStatus) Valid() bool {
+	switch s {
+	case PRStatusOpen, PRStatusMerged, PRStatusClosed, PRStatusDraft:
+		return true
+	}
+
+	return false
+}
+
+func (s PRStatus) String() string {
+	return string(s)
+}
+
+// MarshalText refuses unknown statuses, so a bad value fails the response
+// instead of reaching clients. The zero value encodes as "".
+func (s PRStatus) MarshalText() ([]byte, error) {
+	if s != "" && !s.Valid() {
+		return nil, fmt.Errorf("%w %q", ErrUnknownPRStatus, string(s))
+	}
+
+	return []byte(s), nil
+}
+
+// UnmarshalText accepts the same values as MarshalText.
+func (s *PRStatus) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*s = ""
+		return nil
+	}
+
+	status, err := ParsePRStatus(string(b))
+	if err != nil {
+		return err
+	}
+
+	*s = status
+
+	return nil
+}
+
+// PR priorities; they set the review SLA and reminder cadence.
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
 )
 
+var Priorities = []string{PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent}
+
+// External states report whether the PR's diff in the VCS is still reviewable.
+const (
+	ExternalStateClean     = "clean"
+	ExternalStateConflicts = "conflicts"
+	ExternalStateOutdated  = "outdated"
+)
+
+var ExternalStates = []string{ExternalStateClean, ExternalStateConflicts, ExternalStateOutdated}
+
 type PullRequest struct {
-	PullRequestID     string     `json:"pull_request_id"`
-	PullRequestName   string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            PRStatus   `json:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         time.Time  `json:"createdAt,omitempty"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	PullRequestID     string          `json:"pull_request_id"`
+	PullRequestName   string          `json:"pull_request_name"`
+	AuthorID          string          `json:"author_id"`
+	Status            PRStatus        `json:"status"`
+	Priority          string          `json:"priority"`
+	ExternalState     string          `json:"external_state"`
+	AssignedReviewers []string        `json:"assigned_reviewers"`
+	Approvals         []string        `json:"approvals"`
+	CreatedAt         time.Time       `json:"createdAt,omitempty"`
+	UpdatedAt         time.Time       `json:"updatedAt"`
+	MergedAt          *time.Time      `json:"mergedAt,omitempty"`
+	ClosedAt          *time.Time      `json:"closedAt,omitempty"`
+	CloseWarnedAt     *time.Time      `json:"close_warned_at,omitempty"`
+	Repository        string          `json:"repository,omitempty"`
+	ExternalNumber    int             `json:"external_number,omitempty"`
+	RosterSnapshot    *RosterSnapshot `json:"roster_snapshot,omitempty"`
+	Labels            []string        `json:"labels,omitempty"`
+	LinkedIssues      []string        `json:"linked_issues,omitempty"`
+	Size              *PRSize         `json:"size,omitempty"`
+	// StackID groups an author's related PRs, such as stacked changes.
+	StackID string `json:"stack_id,omitempty"`
+}
+
+// PRSize describes how large the change is, as reported by the VCS or author.
+type PRSize struct {
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+}
+
+// RosterSnapshot is the ranked candidate shortlist as it was when reviewers
+// were assigned, so later audits don't depend on the team's current membership.
+type RosterSnapshot struct {
+	TeamName   string   `json:"team_name"`
+	Strategy   string   `json:"strategy,omitempty"`
+	OnCall     string   `json:"on_call,omitempty"`
+	RuleOwners []string `json:"rule_owners,omitempty"`
+	Excluded   []string `json:"excluded,omitempty"`
+	Candidates []string `json:"candidates"`
+	// CoolingDown candidates were assigned recently and ranked last.
+	CoolingDown []string `json:"cooling_down,omitempty"`
+	// Stack lists the candidates reviewing other PRs of the stack, ranked
+	// first or last as StackReviewers says.
+	Stack          []string  `json:"stack,omitempty"`
+	StackReviewers string    `json:"stack_reviewers,omitempty"`
+	TakenAt        time.Time `json:"taken_at"`
+}
+
+// AssignmentExplanation describes why a PR got its reviewers.
+type AssignmentExplanation struct {
+	PullRequestID     string          `json:"pull_request_id"`
+	AuthorID          string          `json:"author_id"`
+	Strategy          string          `json:"strategy"`
+	Roster            *RosterSnapshot `json:"roster,omitempty"`
+	AssignedReviewers []string        `json:"assigned_reviewers"`
+}
+
+// PR list orders.
+const (
+	PRSortNewest  = "newest"
+	PRSortOldest  = "oldest"
+	PRSortUpdated = "updated"
+)
+
+var PRSorts = []string{PRSortNewest, PRSortOldest, PRSortUpdated}
+
+// PRListFilter narrows GET /pullRequest/list; zero fields match every PR.
+// TeamName matches the author's team and the Created bounds are exclusive.
+type PRListFilter struct {
+	Status        PRStatus
+	AuthorID      string
+	TeamName      string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Sort is one of PRSorts; PRSortNewest when empty.
+	Sort string
+}
+
+// Filtered reports whether f narrows the list at all.
+func (f PRListFilter) Filtered() bool {
+	return f.Status != "" || f.AuthorID != "" || f.TeamName != "" || f.CreatedAfter != nil || f.CreatedBefore != nil
 }
 
 type PullRequestShort struct {
@@ -24,4 +182,35 @@ type PullRequestShort struct {
 	PullRequestName string   `json:"pull_request_name"`
 	AuthorID        string   `json:"author_id"`
 	Status          PRStatus `json:"status"`
+	ExternalState   string   `json:"external_state"`
+}
+
+// PRSearchHit is a PR matching a GET /pullRequest/search query.
+type PRSearchHit struct {
+	PullRequestShort
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewDue is an open review assignment with its SLA deadline.
+type ReviewDue struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	Priority        string    `json:"priority"`
+	AssignedAt      time.Time `json:"assigned_at"`
+	DueAt           time.Time `json:"due_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PendingReview is an open PR a reviewer has not approved yet, with the state
+// reminders are scheduled from.
+type PendingReview struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	Priority        string
+	ReviewerID      string
+	AssignedAt      time.Time
+	RemindedAt      *time.Time
+	SnoozedUntil    *time.Time
 }
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+// Review decisions a reviewer can submit on a PR.
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes_requested"
+	ReviewCommented        = "commented"
+)
+
+var ReviewDecisions = []string{ReviewApproved, ReviewChangesRequested, ReviewCommented}
+
+// Review is one decision a reviewer submitted on a PR. A reviewer may submit
+// several; the latest one is their current stance.
+type Review struct {
+	ID            int64     `json:"id"`
+	PullRequestID string    `json:"pull_request_id"`
+	ReviewerID    string    `json:"reviewer_id"`
+	Decision      string    `json:"decision"`
+	Body          string    `json:"body,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Done reports whether the decision settles the reviewer's part until the PR
+// changes; a comment alone leaves the review pending.
+func (r Review) Done() bool {
+	return slices.Contains([]string{ReviewApproved, ReviewChangesRequested}, r.Decision)
+}
@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// ReviewExclusion keeps Reviewers off the PRs it covers: those authored by
+// one of Authors that carry one of Labels, where an empty list matches any PR.
+// Listing two users as both reviewers and authors keeps them from reviewing
+// each other; a group with only Labels set is barred from PRs with those labels.
+type ReviewExclusion struct {
+	ID        int64     `json:"id"`
+	Reviewers []string  `json:"reviewers"`
+	Authors   []string  `json:"authors"`
+	Labels    []string  `json:"labels"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Covers reports whether the exclusion applies to a PR by authorID with
+// labels. Labels compare case-insensitively.
+func (e ReviewExclusion) Covers(authorID string, labels []string) bool {
+	if len(e.Authors) > 0 && !slices.Contains(e.Authors, authorID) {
+		return false
+	}
+
+	return len(e.Labels) == 0 || slices.ContainsFunc(labels, func(l string) bool {
+		return slices.ContainsFunc(e.Labels, func(x string) bool { return strings.EqualFold(x, l) })
+	})
+}
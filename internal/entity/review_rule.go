@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// ReviewRule assigns Reviewers to PRs in Repository that change a file
+// matching Pattern (CODEOWNERS syntax). When several rules match a file, the
+// one added last wins.
+type ReviewRule struct {
+	ID         int64     `json:"id"`
+	Repository string    `json:"repository"`
+	Pattern    string    `json:"pattern"`
+	Reviewers  []string  `json:"reviewers"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Policies for reviewer changes made directly in a VCS that disagree with the
+// service's assignment. Merge takes over external additions but keeps
+// reviewers that were removed externally.
+const (
+	ReconcilePreferExternal = "prefer_external"
+	ReconcilePreferInternal = "prefer_internal"
+	ReconcileMerge          = "merge"
+)
+
+// External reviewer changes.
+const (
+	ReviewerChangeAdded   = "added"
+	ReviewerChangeRemoved = "removed"
+)
+
+// Conflict resolutions: the external change was taken over by the service,
+// undone in the VCS, or neither because the PR isn't mirrored to the VCS, so
+// the two disagree until one is changed by hand.
+const (
+	ConflictApplied    = "applied"
+	ConflictReverted   = "reverted"
+	ConflictUnresolved = "unresolved"
+)
+
+// ReviewerConflict records an external reviewer change and how it was
+// resolved. Error explains an addition that could not be applied because the
+// reviewer is not eligible.
+type ReviewerConflict struct {
+	ID            int64     `json:"id"`
+	PullRequestID string    `json:"pull_request_id"`
+	Provider      string    `json:"provider"`
+	ReviewerID    string    `json:"reviewer_id"`
+	Change        string    `json:"change"`
+	Policy        string    `json:"policy"`
+	Resolution    string    `json:"resolution"`
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ReviewerConflictFilter struct {
+	PullRequestID string
+	Provider      string
+	Limit         int
+}
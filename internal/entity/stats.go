@@ -0,0 +1,88 @@
+package entity
+
+import "time"
+
+// Stats is the service-wide summary served by GET /stats.
+type Stats struct {
+	TotalPRs         int     `json:"total_prs"`
+	TotalUsers       int     `json:"total_users"`
+	OpenPRs          int     `json:"open_prs"`
+	MergedPRs        int     `json:"merged_prs"`
+	ClosedPRs        int     `json:"closed_prs"`
+	ActiveUsers      int     `json:"active_users"`
+	AverageReviewers float64 `json:"average_reviewers"`
+	// AverageReviewTurnaroundHours is the mean time from a PR's creation to
+	// each reviewer's first submitted decision.
+	AverageReviewTurnaroundHours float64 `json:"average_review_turnaround_hours"`
+}
+
+// UserReviewStats summarizes a user's reviewing. Assigned counts the PRs they
+// were ever assigned to, Completed the merged ones they were still assigned
+// to at merge; AverageHoursToMerge runs from their first assignment to the
+// merge over the completed PRs.
+type UserReviewStats struct {
+	UserID              string  `json:"user_id"`
+	Assigned            int     `json:"assigned"`
+	Completed           int     `json:"completed"`
+	AverageHoursToMerge float64 `json:"average_hours_to_merge"`
+	OpenReviews         int     `json:"open_reviews"`
+}
+
+// TeamStatsSnapshot is one team's stats as recorded on Date (UTC). PRs count
+// toward the team their author belonged to when the snapshot was taken.
+type TeamStatsSnapshot struct {
+	TeamName         string    `json:"team_name"`
+	Date             time.Time `json:"date"`
+	TotalPRs         int       `json:"total_prs"`
+	OpenPRs          int       `json:"open_prs"`
+	MergedPRs        int       `json:"merged_prs"`
+	ClosedPRs        int       `json:"closed_prs"`
+	Members          int       `json:"members"`
+	ActiveMembers    int       `json:"active_members"`
+	AverageReviewers float64   `json:"average_reviewers"`
+	// AverageReviewTurnaroundHours is computed as in Stats.
+	AverageReviewTurnaroundHours float64   `json:"average_review_turnaround_hours"`
+	TakenAt                      time.Time `json:"taken_at"`
+}
+
+// TeamSLA is a team's review turnaround over a range, counting PRs toward
+// their author's current team. Time to merge runs from creation to merge over
+// the PRs merged in the range, time to first approval from creation to the
+// first approval over the PRs first approved in it. Percentiles are 0 when
+// their count is.
+type TeamSLA struct {
+	TeamName                   string  `json:"team_name"`
+	MergedPRs                  int     `json:"merged_prs"`
+	MedianHoursToMerge         float64 `json:"median_hours_to_merge"`
+	P90HoursToMerge            float64 `json:"p90_hours_to_merge"`
+	ApprovedPRs                int     `json:"approved_prs"`
+	MedianHoursToFirstApproval float64 `json:"median_hours_to_first_approval"`
+	P90HoursToFirstApproval    float64 `json:"p90_hours_to_first_approval"`
+}
+
+// Reviewer leaderboard orderings.
+const (
+	LeaderboardByCompleted = "completed"
+	LeaderboardByOpen      = "open"
+)
+
+// ReviewerRankFilter selects the reviewer leaderboard: completed reviews count
+// merges in [From, To), and users are ranked by Sort, then by the other count.
+type ReviewerRankFilter struct {
+	From     time.Time
+	To       time.Time
+	TeamName string
+	Sort     string
+	Limit    int
+}
+
+// ReviewerRank is a user's place on the reviewer leaderboard. Completed
+// counts the PRs merged in the period they were assigned to at merge; the
+// load is their current open assignments.
+type ReviewerRank struct {
+	Rank int `json:"rank"`
+	ReviewerLoad
+	TeamName  string `json:"team_name"`
+	IsActive  bool   `json:"is_active"`
+	Completed int    `json:"completed"`
+}
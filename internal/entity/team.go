@@ -1,12 +1,71 @@
 package entity
 
+import "time"
+
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	IsActive    bool   `json:"is_active"`
+	Role        string `json:"role"`
+	Title       string `json:"title,omitempty"`
+	Seniority   string `json:"seniority,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
 }
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName  string       `json:"team_name"`
+	Members   []TeamMember `json:"members"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TeamSummary is a directory row: counts only, no member list.
+type TeamSummary struct {
+	TeamName    string `json:"team_name"`
+	MemberCount int    `json:"member_count"`
+	ActiveCount int    `json:"active_count"`
+	OpenPRCount int    `json:"open_pr_count"`
+}
+
+// TeamBrief is a team's name and member count, without its members.
+type TeamBrief struct {
+	TeamName    string `json:"team_name"`
+	MemberCount int    `json:"member_count"`
+}
+
+// TeamHealth is a review-health scorecard for one team.
+type TeamHealth struct {
+	TeamName      string   `json:"team_name"`
+	Score         int      `json:"score"`
+	Status        string   `json:"status"`
+	OpenPRs       int      `json:"open_prs"`
+	CoverageRatio float64  `json:"coverage_ratio"`
+	SLABreachRate float64  `json:"sla_breach_rate"`
+	LoadImbalance int      `json:"load_imbalance"`
+	Violations    []string `json:"violations"`
+}
+
+const (
+	TeamHealthHealthy = "healthy"
+	TeamHealthAtRisk  = "at_risk"
+)
+
+// TeamRename reports a renamed team and the members that moved with it.
+type TeamRename struct {
+	OldName string   `json:"old_team_name"`
+	NewName string   `json:"new_team_name"`
+	Members []string `json:"members"`
+}
+
+// TeamDeletion reports a deleted team. Deactivated lists its former members;
+// Reassigned maps open PRs they were reviewing to new reviewers and
+// Unassigned lists PRs they were dropped from without a replacement.
+// OpenPullRequests lists the open PRs that blocked a deletion.
+type TeamDeletion struct {
+	TeamName         string            `json:"team_name"`
+	Deactivated      []string          `json:"deactivated"`
+	Reassigned       map[string]string `json:"reassigned"`
+	Unassigned       []string          `json:"unassigned"`
+	OpenPullRequests []string          `json:"open_pull_requests,omitempty"`
 }
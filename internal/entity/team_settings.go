@@ -0,0 +1,87 @@
+package entity
+
+import "time"
+
+const (
+	QuotaModeWarn    = "warn"
+	QuotaModeEnforce = "enforce"
+
+	AssignmentLeastLoaded = "least_loaded"
+	AssignmentRoundRobin  = "round_robin"
+	AssignmentRandom      = "random"
+
+	StackReviewersSame      = "same"
+	StackReviewersDiversify = "diversify"
+)
+
+// TeamSettingsFields are the JSON names of the settings a team can override,
+// and so reset.
+var TeamSettingsFields = []string{
+	"max_open_prs_per_author", "quota_mode", "slack_enabled", "slack_channel", "assignment_strategy",
+	"required_approvals", "require_all_approvals", "stack_reviewers",
+}
+
+// TeamSettings are per-team overrides; nil fields fall back to service config.
+type TeamSettings struct {
+	TeamName            string    `json:"team_name"`
+	MaxOpenPRsPerAuthor *int      `json:"max_open_prs_per_author,omitempty"`
+	QuotaMode           *string   `json:"quota_mode,omitempty"`
+	SlackEnabled        *bool     `json:"slack_enabled,omitempty"`
+	SlackChannel        *string   `json:"slack_channel,omitempty"`
+	AssignmentStrategy  *string   `json:"assignment_strategy,omitempty"`
+	RequiredApprovals   *int      `json:"required_approvals,omitempty"`
+	RequireAllApprovals *bool     `json:"require_all_approvals,omitempty"`
+	StackReviewers      *string   `json:"stack_reviewers,omitempty"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// SlackEnabledOr reports whether members get Slack DMs, falling back to def.
+func (s TeamSettings) SlackEnabledOr(def bool) bool {
+	if s.SlackEnabled == nil {
+		return def
+	}
+	return *s.SlackEnabled
+}
+
+// SlackChannelOr returns the channel team-addressed messages are posted to,
+// falling back to def; empty means they are not posted.
+func (s TeamSettings) SlackChannelOr(def string) string {
+	if s.SlackChannel == nil {
+		return def
+	}
+	return *s.SlackChannel
+}
+
+// AssignmentStrategyOr returns how reviewers are picked, falling back to def.
+func (s TeamSettings) AssignmentStrategyOr(def string) string {
+	if s.AssignmentStrategy == nil {
+		return def
+	}
+	return *s.AssignmentStrategy
+}
+
+// StackReviewersOr returns how a stacked PR's reviewers relate to the rest of
+// its stack's, falling back to def; empty means the stack is ignored.
+func (s TeamSettings) StackReviewersOr(def string) string {
+	if s.StackReviewers == nil {
+		return def
+	}
+	return *s.StackReviewers
+}
+
+// ApprovalsRequired returns how many of a PR's assigned reviewers must approve
+// before it can be merged: RequiredApprovals (falling back to def), and with
+// RequireAllApprovals every assigned one on top. It is not capped at
+// assigned, so removing reviewers doesn't lower the bar; a PR with fewer
+// reviewers than required can't be merged until more are assigned. Zero
+// means merging is never blocked.
+func (s TeamSettings) ApprovalsRequired(def, assigned int) int {
+	required := def
+	if s.RequiredApprovals != nil {
+		required = *s.RequiredApprovals
+	}
+	if s.RequireAllApprovals != nil && *s.RequireAllApprovals {
+		return max(required, assigned)
+	}
+	return required
+}
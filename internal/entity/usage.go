@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// AnonymousClient is the client ID of requests without an API key.
+const AnonymousClient = "anonymous"
+
+// APIRequest is one served request as seen by the usage middleware.
+type APIRequest struct {
+	ClientID string
+	Method   string
+	Route    string
+	Status   int
+	Latency  time.Duration
+	At       time.Time
+}
+
+// UsageBucket accumulates one client's requests to one route within the hour
+// starting at Bucket. LatencyMs is the sum over all Requests.
+type UsageBucket struct {
+	ClientID     string
+	Method       string
+	Route        string
+	Bucket       time.Time
+	Requests     int64
+	Errors       int64
+	LatencyMs    float64
+	MaxLatencyMs float64
+}
+
+// UsageSummary -. Errors counts responses with status 400 and above.
+type UsageSummary struct {
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	ErrorRate    float64 `json:"error_rate"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+}
+
+type EndpointUsage struct {
+	Method string `json:"method"`
+	Route  string `json:"route"`
+	UsageSummary
+}
+
+// ClientUsage is a client's traffic over a period, busiest endpoint first.
+type ClientUsage struct {
+	ClientID string `json:"client_id"`
+	UsageSummary
+	Endpoints []EndpointUsage `json:"endpoints"`
+}
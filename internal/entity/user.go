@@ -1,8 +1,92 @@
 package entity
 
+import "time"
+
+// Team roles; an empty role is stored as RoleMember.
+const (
+	RoleMember = "member"
+	RoleLead   = "lead"
+)
+
+// Seniority levels, from least to most experienced. Empty means unknown.
+const (
+	SeniorityJunior = "junior"
+	SeniorityMiddle = "middle"
+	SenioritySenior = "senior"
+)
+
+// SeniorityRank orders seniority levels for comparison; unknown levels rank 0.
+func SeniorityRank(s string) int {
+	switch s {
+	case SeniorityJunior:
+		return 1
+	case SeniorityMiddle:
+		return 2
+	case SenioritySenior:
+		return 3
+	default:
+		return 0
+	}
+}
+
 type User struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	TeamName  string `json:"team_name"`
+	IsActive  bool   `json:"is_active"`
+	Role      string `json:"role"`
+	Title     string `json:"title,omitempty"`
+	Seniority string `json:"seniority,omitempty"`
+	// DisplayName and AvatarURL are synced from VCS webhooks or set via the API.
+	DisplayName string    `json:"display_name,omitempty"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// FlaggedInactiveAt is set by the age-out policy when the user had no activity.
+	FlaggedInactiveAt *time.Time `json:"flagged_inactive_at,omitempty"`
+	// MaxOpenReviews caps the open reviews automatic assignment gives the
+	// user; 0 means no cap.
+	MaxOpenReviews int `json:"max_open_reviews"`
+	// DeletedAt is set once the user is deleted; they stay readable by ID.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Name is how the user is presented to humans: the display name when known,
+// otherwise the username, otherwise the ID.
+func (u User) Name() string {
+	switch {
+	case u.DisplayName != "":
+		return u.DisplayName
+	case u.Username != "":
+		return u.Username
+	default:
+		return u.UserID
+	}
+}
+
+// TeamChange is a user's move out of FromTeam, into ToTeam unless they were
+// only removed. Reassigned maps each open PR they were reviewing to its new
+// reviewer; Unassigned lists PRs that had no replacement candidate. Reviews
+// they already approved are kept.
+type TeamChange struct {
+	User       User              `json:"user"`
+	FromTeam   string            `json:"from_team"`
+	ToTeam     string            `json:"to_team,omitempty"`
+	Reassigned map[string]string `json:"reassigned"`
+	Unassigned []string          `json:"unassigned"`
+}
+
+// User event types. Updated covers profile, activity and team changes.
+const (
+	UserEventUpdated = "updated"
+	UserEventDeleted = "deleted"
+)
+
+// UserEvent reports a change to a user, with the state after it.
+type UserEvent struct {
+	Type       string    `json:"type"`
+	UserID     string    `json:"user_id"`
+	TeamName   string    `json:"team_name"`
+	IsActive   bool      `json:"is_active"`
+	OccurredAt time.Time `json:"occurred_at"`
 }
@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalid matches every Validate error with errors.Is.
+var ErrInvalid = errors.New("invalid entity")
+
+// ValidationError tells which rule an entity breaks.
+type ValidationError struct {
+	Reason string
+}
+
+func (e *ValidationError) Error() string { return e.Reason }
+
+// Is -.
+func (e *ValidationError) Is(target error) bool { return target == ErrInvalid }
+
+func invalid(format string, args ...any) error {
+	return &ValidationError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// ValidateProfile checks a role and an optional seniority.
+func ValidateProfile(role, seniority string) error {
+	switch role {
+	case RoleMember, RoleLead:
+	default:
+		return invalid("role must be %s or %s", RoleMember, RoleLead)
+	}
+
+	if seniority != "" && SeniorityRank(seniority) == 0 {
+		return invalid("seniority must be %s, %s or %s", SeniorityJunior, SeniorityMiddle, SenioritySenior)
+	}
+
+	return nil
+}
+
+// Validate checks the user can be stored.
+func (u User) Validate() error {
+	if strings.TrimSpace(u.UserID) == "" {
+		return invalid("user_id must not be empty")
+	}
+	if u.MaxOpenReviews < 0 {
+		return invalid("max_open_reviews must not be negative")
+	}
+
+	return ValidateProfile(u.Role, u.Seniority)
+}
+
+// Validate checks the member can be stored.
+func (m TeamMember) Validate() error {
+	if strings.TrimSpace(m.UserID) == "" {
+		return invalid("user_id must not be empty")
+	}
+
+	return ValidateProfile(m.Role, m.Seniority)
+}
+
+// Validate checks the team and each member, which must be listed once.
+func (t Team) Validate() error {
+	if strings.TrimSpace(t.TeamName) == "" {
+		return invalid("team_name must not be empty")
+	}
+
+	seen := make(map[string]bool, len(t.Members))
+	for _, m := range t.Members {
+		if err := m.Validate(); err != nil {
+			return fmt.Errorf("member %s: %w", m.UserID, err)
+		}
+		if seen[m.UserID] {
+			return invalid("member %s is listed twice", m.UserID)
+		}
+		seen[m.UserID] = true
+	}
+
+	return nil
+}
+
+// Validate checks the PR can be stored: its status, priority and external
+// state are known, its reviewers are distinct and not its author, and only
+// they have approved it. An empty priority or external state is stored as
+// the default.
+func (pr PullRequest) Validate() error {
+	if strings.TrimSpace(pr.PullRequestID) == "" {
+		return invalid("pull_request_id must not be empty")
+	}
+	if strings.TrimSpace(pr.AuthorID) == "" {
+		return invalid("author_id must not be empty")
+	}
+
+	if !pr.Status.Valid() {
+		return invalid("status must be OPEN, MERGED, CLOSED or DRAFT, got %q", string(pr.Status))
+	}
+	if pr.Status == PRStatusMerged && pr.MergedAt == nil {
+		return invalid("merged PR must have mergedAt")
+	}
+
+	if pr.Priority != "" && !slices.Contains(Priorities, pr.Priority) {
+		return invalid("priority must be one of %s", strings.Join(Priorities, ", "))
+	}
+	if pr.ExternalState != "" && !slices.Contains(ExternalStates, pr.ExternalState) {
+		return invalid("external_state must be one of %s", strings.Join(ExternalStates, ", "))
+	}
+
+	for i, id := range pr.AssignedReviewers {
+		switch {
+		case strings.TrimSpace(id) == "":
+			return invalid("assigned_reviewers must not contain empty IDs")
+		case id == pr.AuthorID:
+			return invalid("author %s cannot review their own PR", id)
+		case slices.Contains(pr.AssignedReviewers[:i], id):
+			return invalid("reviewer %s is assigned twice", id)
+		}
+	}
+
+	for _, id := range pr.Approvals {
+		if !slices.Contains(pr.AssignedReviewers, id) {
+			return invalid("approval by %s, who is not an assigned reviewer", id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookDelivery struct {
+	ID          int64           `json:"id"`
+	Provider    string          `json:"provider"`
+	DeliveryID  string          `json:"delivery_id"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	ReceivedAt  time.Time       `json:"received_at"`
+	ProcessedAt *time.Time      `json:"processed_at,omitempty"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+type WebhookDeliveryFilter struct {
+	Provider   string
+	FailedOnly bool
+	Limit      int
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// ReviewerLoad is the number of open PRs a user currently reviews.
+type ReviewerLoad struct {
+	UserID      string `json:"user_id"`
+	OpenReviews int    `json:"open_reviews"`
+	// MaxOpenReviews is the user's limit; 0 means none.
+	MaxOpenReviews int `json:"max_open_reviews"`
+}
+
+// AtCapacity reports whether the reviewer can't take more reviews.
+func (l ReviewerLoad) AtCapacity() bool {
+	return l.MaxOpenReviews > 0 && l.OpenReviews >= l.MaxOpenReviews
+}
+
+// TeamCapacity is the review load of each active team member as of RefreshedAt.
+type TeamCapacity struct {
+	TeamName    string         `json:"team_name"`
+	Members     []ReviewerLoad `json:"members"`
+	RefreshedAt time.Time      `json:"refreshed_at"`
+}
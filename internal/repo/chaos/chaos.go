@@ -0,0 +1,124 @@
+// Package chaos wraps repositories and the notifier with fault injection.
+// Only wired when CHAOS_ENABLED is set; methods not overridden here pass
+// straight through.
+package chaos
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/chaos"
+)
+
+// Injector targets: TargetRepo is shared by all repository wrappers.
+const (
+	TargetRepo     = "repo"
+	TargetNotifier = "notifier"
+)
+
+// Targets lists every target the wrappers inject under.
+var Targets = []string{TargetRepo, TargetNotifier}
+
+type PRRepo struct {
+	usecase.PRRepo
+	inj *chaos.Injector
+}
+
+func NewPRRepo(r usecase.PRRepo, inj *chaos.Injector) *PRRepo {
+	return &PRRepo{PRRepo: r, inj: inj}
+}
+
+func (r *PRRepo) Create(ctx context.Context, p entity.PullRequest) error {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return err
+	}
+	return r.PRRepo.Create(ctx, p)
+}
+
+func (r *PRRepo) GetByID(ctx context.Context, id string) (entity.PullRequest, error) {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return entity.PullRequest{}, err
+	}
+	return r.PRRepo.GetByID(ctx, id)
+}
+
+func (r *PRRepo) Update(ctx context.Context, p *entity.PullRequest) error {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return err
+	}
+	return r.PRRepo.Update(ctx, p)
+}
+
+func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]entity.PullRequest, error) {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return nil, err
+	}
+	return r.PRRepo.ListByReviewer(ctx, reviewerID)
+}
+
+type UserRepo struct {
+	usecase.UserRepo
+	inj *chaos.Injector
+}
+
+func NewUserRepo(r usecase.UserRepo, inj *chaos.Injector) *UserRepo {
+	return &UserRepo{UserRepo: r, inj: inj}
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, id string) (entity.User, error) {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return entity.User{}, err
+	}
+	return r.UserRepo.GetByID(ctx, id)
+}
+
+func (r *UserRepo) Update(ctx context.Context, u *entity.User) error {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return err
+	}
+	return r.UserRepo.Update(ctx, u)
+}
+
+func (r *UserRepo) ListByTeam(ctx context.Context, teamName string) ([]entity.User, error) {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return nil, err
+	}
+	return r.UserRepo.ListByTeam(ctx, teamName)
+}
+
+func (r *UserRepo) ListCandidates(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error) {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return nil, err
+	}
+	return r.UserRepo.ListCandidates(ctx, teamName, exclude, limit)
+}
+
+type TeamRepo struct {
+	usecase.TeamRepo
+	inj *chaos.Injector
+}
+
+func NewTeamRepo(r usecase.TeamRepo, inj *chaos.Injector) *TeamRepo {
+	return &TeamRepo{TeamRepo: r, inj: inj}
+}
+
+func (r *TeamRepo) Create(ctx context.Context, t entity.Team) error {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return err
+	}
+	return r.TeamRepo.Create(ctx, t)
+}
+
+func (r *TeamRepo) GetByName(ctx context.Context, name string) (entity.Team, error) {
+	if err := r.inj.Inject(ctx, TargetRepo); err != nil {
+		return entity.Team{}, err
+	}
+	return r.TeamRepo.GetByName(ctx, name)
+}
+
+var (
+	_ usecase.PRRepo   = (*PRRepo)(nil)
+	_ usecase.UserRepo = (*UserRepo)(nil)
+	_ usecase.TeamRepo = (*TeamRepo)(nil)
+)
@@ -0,0 +1,26 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/pkg/chaos"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+)
+
+type Notifier struct {
+	next notifier.Notifier
+	inj  *chaos.Injector
+}
+
+func NewNotifier(n notifier.Notifier, inj *chaos.Injector) *Notifier {
+	return &Notifier{next: n, inj: inj}
+}
+
+func (n *Notifier) Notify(ctx context.Context, m notifier.Message) error {
+	if err := n.inj.Inject(ctx, TargetNotifier); err != nil {
+		return err
+	}
+	return n.next.Notify(ctx, m)
+}
+
+var _ notifier.Notifier = (*Notifier)(nil)
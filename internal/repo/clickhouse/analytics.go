@@ -0,0 +1,115 @@
+package clickhouse
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/clickhouse"
+)
+
+// AnalyticsRepo -.
+type AnalyticsRepo struct {
+	client *clickhouse.Client
+}
+
+// NewAnalyticsRepo -.
+func NewAnalyticsRepo(client *clickhouse.Client) *AnalyticsRepo {
+	return &AnalyticsRepo{client: client}
+}
+
+const _rangeFilter = `occurred_at >= {from:DateTime64(3, 'UTC')} AND occurred_at < {to:DateTime64(3, 'UTC')}`
+
+func rangeParams(r entity.AnalyticsRange) map[string]string {
+	return map[string]string{
+		"from": r.From.UTC().Format(_timeLayout),
+		"to":   r.To.UTC().Format(_timeLayout),
+	}
+}
+
+// TimeSeries counts created, merged and closed PRs per day or week (weeks start on Monday).
+func (r *AnalyticsRepo) TimeSeries(ctx context.Context, rng entity.AnalyticsRange, bucket string) ([]entity.TimeBucket, error) {
+	start := "toStartOfDay(occurred_at)"
+	if bucket == entity.BucketWeek {
+		start = "toDateTime(toMonday(occurred_at), 'UTC')"
+	}
+
+	query := `
+		SELECT toUnixTimestamp(` + start + `) AS start,
+		       countIf(type = 'created') AS created,
+		       countIf(type = 'merged') AS merged,
+		       countIf(type = 'closed') AS closed
+		FROM ` + _eventsTable + `
+		WHERE ` + _rangeFilter + `
+		GROUP BY start
+		ORDER BY start
+	`
+
+	var rows []struct {
+		Start   int64 `json:"start"`
+		Created int   `json:"created"`
+		Merged  int   `json:"merged"`
+		Closed  int   `json:"closed"`
+	}
+	if err := r.client.Select(ctx, query, rangeParams(rng), &rows); err != nil {
+		return nil, err
+	}
+
+	buckets := make([]entity.TimeBucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, entity.TimeBucket{
+			Start:   time.Unix(row.Start, 0).UTC(),
+			Created: row.Created,
+			Merged:  row.Merged,
+			Closed:  row.Closed,
+		})
+	}
+
+	return buckets, nil
+}
+
+// Heatmap counts events of eventType by weekday and hour (UTC).
+func (r *AnalyticsRepo) Heatmap(ctx context.Context, rng entity.AnalyticsRange, eventType string) ([]entity.HeatmapCell, error) {
+	query := `
+		SELECT toDayOfWeek(occurred_at) AS weekday,
+		       toHour(occurred_at) AS hour,
+		       count() AS count
+		FROM ` + _eventsTable + `
+		WHERE type = {type:String} AND ` + _rangeFilter + `
+		GROUP BY weekday, hour
+		ORDER BY weekday, hour
+	`
+	params := rangeParams(rng)
+	params["type"] = eventType
+
+	cells := []entity.HeatmapCell{}
+	if err := r.client.Select(ctx, query, params, &cells); err != nil {
+		return nil, err
+	}
+
+	return cells, nil
+}
+
+// Leaderboard ranks reviewers by approvals, then by assignments received.
+func (r *AnalyticsRepo) Leaderboard(ctx context.Context, rng entity.AnalyticsRange, limit int) ([]entity.LeaderboardEntry, error) {
+	query := `
+		SELECT user_id,
+		       countIf(type = 'reviewer_assigned') AS assignments,
+		       countIf(type = 'approved') AS approvals
+		FROM ` + _eventsTable + `
+		WHERE type IN ('reviewer_assigned', 'approved') AND ` + _rangeFilter + `
+		GROUP BY user_id
+		ORDER BY approvals DESC, assignments DESC, user_id
+		LIMIT {limit:UInt32}
+	`
+	params := rangeParams(rng)
+	params["limit"] = strconv.Itoa(limit)
+
+	entries := []entity.LeaderboardEntry{}
+	if err := r.client.Select(ctx, query, params, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,169 @@
+// Package clickhouse mirrors PR lifecycle events into ClickHouse and serves
+// analytics queries from there, keeping that load off Postgres.
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/clickhouse"
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const _eventsTable = "pr_events"
+
+// _timeLayout is how DateTime64(3) values are written and passed as parameters.
+const _timeLayout = "2006-01-02 15:04:05.000"
+
+var (
+	_eventsDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_analytics_events_dropped_total",
+		Help: "PR events dropped because the analytics buffer was full or a flush failed.",
+	})
+	_eventsFlushed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_analytics_events_flushed_total",
+		Help: "PR events written to ClickHouse.",
+	})
+)
+
+// EnsureSchema creates the events table when it does not exist yet.
+func EnsureSchema(ctx context.Context, client *clickhouse.Client) error {
+	return client.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS `+_eventsTable+` (
+			type            LowCardinality(String),
+			pull_request_id String,
+			author_id       String,
+			user_id         String,
+			team_name       LowCardinality(String),
+			repository      String,
+			occurred_at     DateTime64(3, 'UTC')
+		)
+		ENGINE = MergeTree
+		PARTITION BY toYYYYMM(occurred_at)
+		ORDER BY (type, occurred_at)
+	`)
+}
+
+type eventRow struct {
+	Type          string `json:"type"`
+	PullRequestID string `json:"pull_request_id"`
+	AuthorID      string `json:"author_id"`
+	UserID        string `json:"user_id"`
+	TeamName      string `json:"team_name"`
+	Repository    string `json:"repository"`
+	OccurredAt    string `json:"occurred_at"`
+}
+
+// Sink buffers events in memory and writes them in batches, so publishing
+// never waits on ClickHouse. Events are dropped when the buffer is full.
+type Sink struct {
+	client     *clickhouse.Client
+	events     chan entity.PREvent
+	batchSize  int
+	flushEvery time.Duration
+	l          logger.Interface
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSink -.
+func NewSink(client *clickhouse.Client, bufferSize, batchSize int, flushEvery time.Duration, l logger.Interface) *Sink {
+	return &Sink{
+		client:     client,
+		events:     make(chan entity.PREvent, bufferSize),
+		batchSize:  batchSize,
+		flushEvery: flushEvery,
+		l:          l,
+		done:       make(chan struct{}),
+	}
+}
+
+// Publish -.
+func (s *Sink) Publish(_ context.Context, e entity.PREvent) {
+	select {
+	case s.events <- e:
+	default:
+		_eventsDropped.Inc()
+	}
+}
+
+// Start launches the background writer.
+func (s *Sink) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	go s.run(ctx)
+}
+
+// Stop flushes buffered events and waits for the writer to exit.
+func (s *Sink) Stop() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Sink) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushEvery)
+	defer ticker.Stop()
+
+	batch := make([]any, 0, s.batchSize)
+
+	for {
+		select {
+		case e := <-s.events:
+			batch = append(batch, toRow(e))
+			if len(batch) >= s.batchSize {
+				batch = s.flush(batch)
+			}
+		case <-ticker.C:
+			batch = s.flush(batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case e := <-s.events:
+					batch = append(batch, toRow(e))
+				default:
+					s.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes batch and returns it emptied for reuse.
+func (s *Sink) flush(batch []any) []any {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.client.InsertJSONEachRow(ctx, _eventsTable, batch); err != nil {
+		_eventsDropped.Add(float64(len(batch)))
+		s.l.Error(fmt.Errorf("clickhouse - sink - flush %d events: %w", len(batch), err))
+	} else {
+		_eventsFlushed.Add(float64(len(batch)))
+	}
+
+	return batch[:0]
+}
+
+func toRow(e entity.PREvent) eventRow {
+	return eventRow{
+		Type:          e.Type,
+		PullRequestID: e.PullRequestID,
+		AuthorID:      e.AuthorID,
+		UserID:        e.UserID,
+		TeamName:      e.TeamName,
+		Repository:    e.Repository,
+		OccurredAt:    e.OccurredAt.UTC().Format(_timeLayout),
+	}
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// AbsenceRepo stores users' vacation and other absence windows.
+type AbsenceRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) AbsenceRepo() *AbsenceRepo {
+	return &AbsenceRepo{db: p.db}
+}
+
+const absenceColumns = `id, user_id, starts_at, ends_at, reason, released_at, created_at, updated_at`
+
+// Create stores a and fills in its ID and timestamps.
+func (r *AbsenceRepo) Create(ctx context.Context, a *entity.Absence) error {
+	query := `
+		INSERT INTO absences (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+	if err := r.db.QueryRow(ctx, query, a.UserID, a.StartsAt, a.EndsAt, a.Reason).Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return err
+	}
+
+	a.CreatedAt = a.CreatedAt.UTC()
+	a.UpdatedAt = a.UpdatedAt.UTC()
+	return nil
+}
+
+func (r *AbsenceRepo) GetByID(ctx context.Context, id int64) (entity.Absence, error) {
+	a, err := scanAbsence(r.db.QueryRow(ctx, "SELECT "+absenceColumns+" FROM absences WHERE id = $1", id))
+	if err == pgx.ErrNoRows {
+		return entity.Absence{}, ErrNotFound
+	}
+
+	return a, err
+}
+
+// Update persists the window, reason and release time and refreshes a.UpdatedAt.
+func (r *AbsenceRepo) Update(ctx context.Context, a *entity.Absence) error {
+	query := `
+		UPDATE absences SET starts_at = $1, ends_at = $2, reason = $3, released_at = $4, updated_at = now()
+		WHERE id = $5
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(ctx, query, a.StartsAt, a.EndsAt, a.Reason, a.ReleasedAt, a.ID).Scan(&a.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	a.UpdatedAt = a.UpdatedAt.UTC()
+	return nil
+}
+
+func (r *AbsenceRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM absences WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns the user's absences that have not ended by since, earliest first.
+func (r *AbsenceRepo) ListByUser(ctx context.Context, userID string, since time.Time) ([]entity.Absence, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+absenceColumns+`
+		FROM absences WHERE user_id = $1 AND ends_at > $2
+		ORDER BY starts_at, id
+	`, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectAbsences(rows)
+}
+
+// ListCovering returns the absences in progress at at.
+func (r *AbsenceRepo) ListCovering(ctx context.Context, at time.Time) ([]entity.Absence, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+absenceColumns+`
+		FROM absences WHERE starts_at <= $1 AND ends_at > $1
+		ORDER BY starts_at, id
+	`, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectAbsences(rows)
+}
+
+func collectAbsences(rows pgx.Rows) ([]entity.Absence, error) {
+	defer rows.Close()
+
+	absences := []entity.Absence{}
+	for rows.Next() {
+		a, err := scanAbsence(rows)
+		if err != nil {
+			return nil, err
+		}
+		absences = append(absences, a)
+	}
+
+	return absences, rows.Err()
+}
+
+func scanAbsence(row pgx.Row) (entity.Absence, error) {
+	var (
+		a          entity.Absence
+		releasedAt sql.NullTime
+	)
+
+	if err := row.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &releasedAt, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return entity.Absence{}, err
+	}
+
+	a.StartsAt = a.StartsAt.UTC()
+	a.EndsAt = a.EndsAt.UTC()
+	a.CreatedAt = a.CreatedAt.UTC()
+	a.UpdatedAt = a.UpdatedAt.UTC()
+	if releasedAt.Valid {
+		t := releasedAt.Time.UTC()
+		a.ReleasedAt = &t
+	}
+
+	return a, nil
+}
+
+var _ usecase.AbsenceRepo = (*AbsenceRepo)(nil)
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+const _defaultAckLimit = 100
+
+// AssignmentAckRepo tracks reviewer.assigned deliveries awaiting acknowledgment.
+type AssignmentAckRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) AssignmentAckRepo() *AssignmentAckRepo {
+	return &AssignmentAckRepo{db: p.db}
+}
+
+const ackColumns = `delivery_id, webhook_id, team_name, pull_request_id, reviewer_id, payload, status,
+	attempts, last_sent_at, next_attempt_at, acked_at, error, created_at`
+
+// Create stores a as sent once and fills in its timestamps.
+func (r *AssignmentAckRepo) Create(ctx context.Context, a *entity.AssignmentAck) error {
+	query := `
+		INSERT INTO assignment_acks (delivery_id, webhook_id, team_name, pull_request_id, reviewer_id, payload, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING status, attempts, last_sent_at, created_at
+	`
+	err := r.db.QueryRow(ctx, query, a.DeliveryID, a.WebhookID, a.TeamName, a.PullRequestID, a.ReviewerID, a.Payload, a.NextAttemptAt).
+		Scan(&a.Status, &a.Attempts, &a.LastSentAt, &a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	a.LastSentAt = a.LastSentAt.UTC()
+	a.CreatedAt = a.CreatedAt.UTC()
+	return nil
+}
+
+func (r *AssignmentAckRepo) GetByID(ctx context.Context, deliveryID string) (entity.AssignmentAck, error) {
+	a, err := scanAck(r.db.QueryRow(ctx, "SELECT "+ackColumns+" FROM assignment_acks WHERE delivery_id = $1", deliveryID))
+	if err == pgx.ErrNoRows {
+		return entity.AssignmentAck{}, ErrNotFound
+	}
+
+	return a, err
+}
+
+// Update persists the delivery's state after a resend or an acknowledgment.
+func (r *AssignmentAckRepo) Update(ctx context.Context, a *entity.AssignmentAck) error {
+	query := `
+		UPDATE assignment_acks
+		SET status = $2, attempts = $3, last_sent_at = $4, next_attempt_at = $5, acked_at = $6, error = $7
+		WHERE delivery_id = $1
+	`
+	result, err := r.db.Exec(ctx, query, a.DeliveryID, a.Status, a.Attempts, a.LastSentAt, a.NextAttemptAt, a.AckedAt, a.Error)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListDue returns up to limit pending deliveries whose acknowledgment is
+// overdue at now, oldest first, locking them against concurrent retries.
+func (r *AssignmentAckRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]entity.AssignmentAck, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+ackColumns+`
+		FROM assignment_acks
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectAcks(rows)
+}
+
+// List returns deliveries matching f, newest first.
+func (r *AssignmentAckRepo) List(ctx context.Context, f entity.AssignmentAckFilter) ([]entity.AssignmentAck, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = _defaultAckLimit
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+ackColumns+`
+		FROM assignment_acks
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR team_name = $2)
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, f.Status, f.TeamName, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectAcks(rows)
+}
+
+func collectAcks(rows pgx.Rows) ([]entity.AssignmentAck, error) {
+	defer rows.Close()
+
+	acks := []entity.AssignmentAck{}
+	for rows.Next() {
+		a, err := scanAck(rows)
+		if err != nil {
+			return nil, err
+		}
+		acks = append(acks, a)
+	}
+
+	return acks, rows.Err()
+}
+
+func scanAck(row pgx.Row) (entity.AssignmentAck, error) {
+	var (
+		a       entity.AssignmentAck
+		ackedAt sql.NullTime
+	)
+
+	if err := row.Scan(&a.DeliveryID, &a.WebhookID, &a.TeamName, &a.PullRequestID, &a.ReviewerID, &a.Payload, &a.Status,
+		&a.Attempts, &a.LastSentAt, &a.NextAttemptAt, &ackedAt, &a.Error, &a.CreatedAt); err != nil {
+		return entity.AssignmentAck{}, err
+	}
+
+	a.LastSentAt = a.LastSentAt.UTC()
+	a.NextAttemptAt = a.NextAttemptAt.UTC()
+	a.CreatedAt = a.CreatedAt.UTC()
+	if ackedAt.Valid {
+		t := ackedAt.Time.UTC()
+		a.AckedAt = &t
+	}
+
+	return a, nil
+}
+
+var _ usecase.AssignmentAckRepo = (*AssignmentAckRepo)(nil)
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+const _defaultAuditLimit = 100
+
+type AuditRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) AuditRepo() *AuditRepo {
+	return &AuditRepo{db: p.db}
+}
+
+func (r *AuditRepo) Record(ctx context.Context, e entity.AuditEntry) error {
+	query := `
+		INSERT INTO audit_log (actor_id, action, pull_request_id, source, channel_id, message_id, details)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+	`
+	var details []byte
+	if len(e.Details) > 0 {
+		details = e.Details
+	}
+
+	_, err := r.db.Exec(ctx, query, e.ActorID, e.Action, e.PullRequestID, e.Source, e.ChannelID, e.MessageID, details)
+	return err
+}
+
+func (r *AuditRepo) List(ctx context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error) {
+	query := `
+		SELECT id, actor_id, action, COALESCE(pull_request_id, ''), source,
+		       COALESCE(channel_id, ''), COALESCE(message_id, ''), details, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR actor_id = $1)
+		  AND ($2 = '' OR source = $2)
+		  AND ($3 = '' OR pull_request_id = $3)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = _defaultAuditLimit
+	}
+
+	rows, err := r.db.Query(ctx, query, f.ActorID, f.Source, f.PullRequestID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []entity.AuditEntry{}
+	for rows.Next() {
+		e, err := scanAudit(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+func scanAudit(row pgx.Row) (entity.AuditEntry, error) {
+	var e entity.AuditEntry
+	var details []byte
+	var createdAt sql.NullTime
+
+	if err := row.Scan(
+		&e.ID, &e.ActorID, &e.Action, &e.PullRequestID, &e.Source,
+		&e.ChannelID, &e.MessageID, &details, &createdAt,
+	); err != nil {
+		return entity.AuditEntry{}, err
+	}
+
+	e.Details = details
+	e.CreatedAt = createdAt.Time.UTC()
+
+	return e, nil
+}
+
+var _ usecase.AuditRepo = (*AuditRepo)(nil)
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// ClientQuotaRepo stores per-client request quotas and their counters.
+type ClientQuotaRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) ClientQuotaRepo() *ClientQuotaRepo {
+	return &ClientQuotaRepo{db: p.db}
+}
+
+func (r *ClientQuotaRepo) Get(ctx context.Context, clientID string) (entity.ClientQuota, error) {
+	var q entity.ClientQuota
+	err := r.db.QueryRow(ctx, `
+		SELECT client_id, daily, monthly, updated_at FROM client_quotas WHERE client_id = $1
+	`, clientID).Scan(&q.ClientID, &q.Daily, &q.Monthly, &q.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return entity.ClientQuota{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.ClientQuota{}, err
+	}
+
+	q.UpdatedAt = q.UpdatedAt.UTC()
+	return q, nil
+}
+
+func (r *ClientQuotaRepo) List(ctx context.Context) ([]entity.ClientQuota, error) {
+	rows, err := r.db.Query(ctx, `SELECT client_id, daily, monthly, updated_at FROM client_quotas ORDER BY client_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotas := []entity.ClientQuota{}
+	for rows.Next() {
+		var q entity.ClientQuota
+		if err := rows.Scan(&q.ClientID, &q.Daily, &q.Monthly, &q.UpdatedAt); err != nil {
+			return nil, err
+		}
+		q.UpdatedAt = q.UpdatedAt.UTC()
+		quotas = append(quotas, q)
+	}
+
+	return quotas, rows.Err()
+}
+
+// Upsert stores q and fills in its UpdatedAt.
+func (r *ClientQuotaRepo) Upsert(ctx context.Context, q *entity.ClientQuota) error {
+	query := `
+		INSERT INTO client_quotas (client_id, daily, monthly)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (client_id) DO UPDATE SET
+			daily = EXCLUDED.daily, monthly = EXCLUDED.monthly, updated_at = now()
+		RETURNING updated_at
+	`
+	if err := r.db.QueryRow(ctx, query, q.ClientID, q.Daily, q.Monthly).Scan(&q.UpdatedAt); err != nil {
+		return err
+	}
+
+	q.UpdatedAt = q.UpdatedAt.UTC()
+	return nil
+}
+
+func (r *ClientQuotaRepo) Delete(ctx context.Context, clientID string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM client_quotas WHERE client_id = $1`, clientID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Used returns the requests counted for clientID in the periods starting at
+// starts, keyed by period.
+func (r *ClientQuotaRepo) Used(ctx context.Context, clientID string, starts map[string]time.Time) (map[string]int64, error) {
+	query := `
+		SELECT COALESCE(max(used), 0) FROM client_quota_usage
+		WHERE client_id = $1 AND period = $2 AND period_start = $3
+	`
+	return r.counters(ctx, query, clientID, starts)
+}
+
+// Lock is Used, but keeps the counters locked until the surrounding
+// transaction ends. Missing counters are created at 0 so they can be locked;
+// they are locked in period order, so concurrent callers can't deadlock.
+func (r *ClientQuotaRepo) Lock(ctx context.Context, clientID string, starts map[string]time.Time) (map[string]int64, error) {
+	query := `
+		INSERT INTO client_quota_usage (client_id, period, period_start)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (client_id, period, period_start) DO UPDATE SET used = client_quota_usage.used
+		RETURNING used
+	`
+	return r.counters(ctx, query, clientID, starts)
+}
+
+func (r *ClientQuotaRepo) counters(ctx context.Context, query, clientID string, starts map[string]time.Time) (map[string]int64, error) {
+	used := make(map[string]int64, len(starts))
+	for _, period := range slices.Sorted(maps.Keys(starts)) {
+		var n int64
+		if err := r.db.QueryRow(ctx, query, clientID, period, starts[period].UTC()).Scan(&n); err != nil {
+			return nil, err
+		}
+		used[period] = n
+	}
+
+	return used, nil
+}
+
+// Increment counts one request in each of the periods starting at starts.
+func (r *ClientQuotaRepo) Increment(ctx context.Context, clientID string, starts map[string]time.Time) error {
+	query := `
+		INSERT INTO client_quota_usage (client_id, period, period_start, used)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (client_id, period, period_start) DO UPDATE SET used = client_quota_usage.used + 1
+	`
+	for _, period := range slices.Sorted(maps.Keys(starts)) {
+		if _, err := r.db.Exec(ctx, query, clientID, period, starts[period].UTC()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var _ usecase.ClientQuotaRepo = (*ClientQuotaRepo)(nil)
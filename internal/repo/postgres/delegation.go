@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// DelegationRepo stores team leads' time-boxed delegations of their permissions.
+type DelegationRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) DelegationRepo() *DelegationRepo {
+	return &DelegationRepo{db: p.db}
+}
+
+const delegationColumns = `id, team_name, delegator_id, delegate_id, scopes, starts_at, ends_at, revoked_at, created_at`
+
+// Create stores d and fills in its ID and creation time.
+func (r *DelegationRepo) Create(ctx context.Context, d *entity.Delegation) error {
+	query := `
+		INSERT INTO team_delegations (team_name, delegator_id, delegate_id, scopes, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	if err := r.db.QueryRow(ctx, query, d.TeamName, d.DelegatorID, d.DelegateID, d.Scopes, d.StartsAt, d.EndsAt).Scan(&d.ID, &d.CreatedAt); err != nil {
+		return err
+	}
+
+	d.CreatedAt = d.CreatedAt.UTC()
+	return nil
+}
+
+func (r *DelegationRepo) GetByID(ctx context.Context, id int64) (entity.Delegation, error) {
+	d, err := scanDelegation(r.db.QueryRow(ctx, "SELECT "+delegationColumns+" FROM team_delegations WHERE id = $1", id))
+	if err == pgx.ErrNoRows {
+		return entity.Delegation{}, ErrNotFound
+	}
+
+	return d, err
+}
+
+// ListByTeam returns the team's delegations that have not ended by since,
+// revoked ones included, earliest first.
+func (r *DelegationRepo) ListByTeam(ctx context.Context, teamName string, since time.Time) ([]entity.Delegation, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+delegationColumns+`
+		FROM team_delegations WHERE team_name = $1 AND ends_at > $2
+		ORDER BY starts_at, id
+	`, teamName, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectDelegations(rows)
+}
+
+// ListActive returns the unrevoked delegations to delegateID in the team that
+// are in effect at at.
+func (r *DelegationRepo) ListActive(ctx context.Context, teamName, delegateID string, at time.Time) ([]entity.Delegation, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+delegationColumns+`
+		FROM team_delegations
+		WHERE team_name = $1 AND delegate_id = $2 AND revoked_at IS NULL AND starts_at <= $3 AND ends_at > $3
+		ORDER BY starts_at, id
+	`, teamName, delegateID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectDelegations(rows)
+}
+
+// Revoke ends the delegation at at; revoking twice keeps the first time.
+func (r *DelegationRepo) Revoke(ctx context.Context, id int64, at time.Time) (entity.Delegation, error) {
+	d, err := scanDelegation(r.db.QueryRow(ctx, `
+		UPDATE team_delegations SET revoked_at = COALESCE(revoked_at, $2)
+		WHERE id = $1
+		RETURNING `+delegationColumns, id, at))
+	if err == pgx.ErrNoRows {
+		return entity.Delegation{}, ErrNotFound
+	}
+
+	return d, err
+}
+
+func collectDelegations(rows pgx.Rows) ([]entity.Delegation, error) {
+	defer rows.Close()
+
+	delegations := []entity.Delegation{}
+	for rows.Next() {
+		d, err := scanDelegation(rows)
+		if err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, d)
+	}
+
+	return delegations, rows.Err()
+}
+
+func scanDelegation(row pgx.Row) (entity.Delegation, error) {
+	var (
+		d         entity.Delegation
+		revokedAt sql.NullTime
+	)
+
+	if err := row.Scan(&d.ID, &d.TeamName, &d.DelegatorID, &d.DelegateID, &d.Scopes, &d.StartsAt, &d.EndsAt, &revokedAt, &d.CreatedAt); err != nil {
+		return entity.Delegation{}, err
+	}
+
+	d.StartsAt = d.StartsAt.UTC()
+	d.EndsAt = d.EndsAt.UTC()
+	d.CreatedAt = d.CreatedAt.UTC()
+	if revokedAt.Valid {
+		t := revokedAt.Time.UTC()
+		d.RevokedAt = &t
+	}
+
+	return d, nil
+}
+
+var _ usecase.DelegationRepo = (*DelegationRepo)(nil)
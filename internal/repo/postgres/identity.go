@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type IdentityRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) IdentityRepo() *IdentityRepo {
+	return &IdentityRepo{db: p.db}
+}
+
+// Link stores the mapping, replacing the user's previous account at the same provider.
+func (r *IdentityRepo) Link(ctx context.Context, id *entity.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (provider, external_id, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, provider) DO UPDATE SET external_id = EXCLUDED.external_id, created_at = now()
+		RETURNING created_at
+	`
+	err := r.db.QueryRow(ctx, query, id.Provider, id.ExternalID, id.UserID).Scan(&id.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+
+	id.CreatedAt = id.CreatedAt.UTC()
+	return nil
+}
+
+func (r *IdentityRepo) UserIDFor(ctx context.Context, provider, externalID string) (string, error) {
+	var userID string
+	err := r.db.QueryRow(ctx,
+		"SELECT user_id FROM user_identities WHERE provider = $1 AND external_id = $2",
+		provider, externalID,
+	).Scan(&userID)
+	if err == pgx.ErrNoRows {
+		return "", ErrNotFound
+	}
+
+	return userID, err
+}
+
+func (r *IdentityRepo) ExternalIDFor(ctx context.Context, userID, provider string) (string, error) {
+	var externalID string
+	err := r.db.QueryRow(ctx,
+		"SELECT external_id FROM user_identities WHERE user_id = $1 AND provider = $2",
+		userID, provider,
+	).Scan(&externalID)
+	if err == pgx.ErrNoRows {
+		return "", ErrNotFound
+	}
+
+	return externalID, err
+}
+
+// RevokeAll removes every external account linked to the user.
+func (r *IdentityRepo) RevokeAll(ctx context.Context, userID string) (int64, error) {
+	result, err := r.db.Exec(ctx, `DELETE FROM user_identities WHERE user_id = $1`, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+var _ usecase.IdentityRepo = (*IdentityRepo)(nil)
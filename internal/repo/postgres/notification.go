@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+const _defaultNotificationLimit = 100
+
+// NotificationLogRepo keeps the outcome of every notification delivery.
+type NotificationLogRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) NotificationLogRepo() *NotificationLogRepo {
+	return &NotificationLogRepo{db: p.db}
+}
+
+// Add stores deliveries in a single statement.
+func (r *NotificationLogRepo) Add(ctx context.Context, deliveries []entity.NotificationDelivery) error {
+	query := `
+		INSERT INTO notification_deliveries (provider, kind, user_id, team_name, pull_request_id, status, detail, duration_ms, created_at)
+		SELECT provider, kind, NULLIF(user_id, ''), NULLIF(team_name, ''), NULLIF(pull_request_id, ''), status, detail, duration_ms, created_at
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[], $8::float8[], $9::timestamptz[])
+			AS d(provider, kind, user_id, team_name, pull_request_id, status, detail, duration_ms, created_at)
+	`
+
+	n := len(deliveries)
+	providers := make([]string, n)
+	kinds := make([]string, n)
+	users := make([]string, n)
+	teams := make([]string, n)
+	prs := make([]string, n)
+	statuses := make([]string, n)
+	details := make([]string, n)
+	durations := make([]float64, n)
+	createdAt := make([]time.Time, n)
+	for i, d := range deliveries {
+		providers[i], kinds[i], statuses[i], details[i] = d.Provider, d.Kind, d.Status, d.Detail
+		users[i], teams[i], prs[i] = d.UserID, d.TeamName, d.PullRequestID
+		durations[i] = d.DurationMs
+		createdAt[i] = d.CreatedAt.UTC()
+	}
+
+	_, err := r.db.Exec(ctx, query, providers, kinds, users, teams, prs, statuses, details, durations, createdAt)
+	return err
+}
+
+// List returns matching deliveries, newest first. Empty filter fields match anything.
+func (r *NotificationLogRepo) List(ctx context.Context, f entity.NotificationFilter) ([]entity.NotificationDelivery, error) {
+	query := `
+		SELECT id, provider, kind, COALESCE(user_id, ''), COALESCE(team_name, ''), COALESCE(pull_request_id, ''),
+		       status, detail, duration_ms, created_at
+		FROM notification_deliveries
+		WHERE ($1 = '' OR user_id = $1)
+		  AND ($2 = '' OR provider = $2)
+		  AND ($3 = '' OR status = $3)
+		  AND ($4 = '' OR kind = $4)
+		  AND ($5 = '' OR pull_request_id = $5)
+		  AND created_at >= $6
+		ORDER BY created_at DESC, id DESC
+		LIMIT $7
+	`
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = _defaultNotificationLimit
+	}
+
+	rows, err := r.db.Query(ctx, query, f.UserID, f.Provider, f.Status, f.Kind, f.PullRequestID, f.Since.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []entity.NotificationDelivery{}
+	for rows.Next() {
+		var d entity.NotificationDelivery
+		if err := rows.Scan(&d.ID, &d.Provider, &d.Kind, &d.UserID, &d.TeamName, &d.PullRequestID,
+			&d.Status, &d.Detail, &d.DurationMs, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.CreatedAt = d.CreatedAt.UTC()
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+var _ usecase.NotificationLogRepo = (*NotificationLogRepo)(nil)
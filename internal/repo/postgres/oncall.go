@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// OnCallRepo stores teams' on-call rotations and per-week overrides.
+type OnCallRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) OnCallRepo() *OnCallRepo {
+	return &OnCallRepo{db: p.db}
+}
+
+func (r *OnCallRepo) GetRotation(ctx context.Context, teamName string) (entity.OnCallRotation, error) {
+	query := `SELECT team_name, members, updated_at FROM oncall_rotations WHERE team_name = $1`
+
+	var rot entity.OnCallRotation
+
+	err := r.db.QueryRow(ctx, query, teamName).Scan(&rot.TeamName, &rot.Members, &rot.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return entity.OnCallRotation{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.OnCallRotation{}, err
+	}
+
+	rot.UpdatedAt = rot.UpdatedAt.UTC()
+	return rot, nil
+}
+
+// SetRotation replaces the team's rotation and refreshes rot.UpdatedAt.
+func (r *OnCallRepo) SetRotation(ctx context.Context, rot *entity.OnCallRotation) error {
+	query := `
+		INSERT INTO oncall_rotations (team_name, members)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name) DO UPDATE SET
+			members = EXCLUDED.members,
+			updated_at = now()
+		RETURNING updated_at
+	`
+	if err := r.db.QueryRow(ctx, query, rot.TeamName, rot.Members).Scan(&rot.UpdatedAt); err != nil {
+		return err
+	}
+
+	rot.UpdatedAt = rot.UpdatedAt.UTC()
+	return nil
+}
+
+// ListOverrides returns the team's overrides for weeks starting in [from, to).
+func (r *OnCallRepo) ListOverrides(ctx context.Context, teamName string, from, to time.Time) ([]entity.OnCallOverride, error) {
+	query := `
+		SELECT team_name, week_start, user_id
+		FROM oncall_overrides
+		WHERE team_name = $1 AND week_start >= $2 AND week_start < $3
+		ORDER BY week_start
+	`
+	rows, err := r.db.Query(ctx, query, teamName, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []entity.OnCallOverride{}
+	for rows.Next() {
+		var o entity.OnCallOverride
+		if err := rows.Scan(&o.TeamName, &o.WeekStart, &o.UserID); err != nil {
+			return nil, err
+		}
+		o.WeekStart = o.WeekStart.UTC()
+		overrides = append(overrides, o)
+	}
+
+	return overrides, rows.Err()
+}
+
+func (r *OnCallRepo) SetOverride(ctx context.Context, o entity.OnCallOverride) error {
+	query := `
+		INSERT INTO oncall_overrides (team_name, week_start, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_name, week_start) DO UPDATE SET user_id = EXCLUDED.user_id
+	`
+	_, err := r.db.Exec(ctx, query, o.TeamName, o.WeekStart, o.UserID)
+	return err
+}
+
+func (r *OnCallRepo) DeleteOverride(ctx context.Context, teamName string, weekStart time.Time) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM oncall_overrides WHERE team_name = $1 AND week_start = $2`, teamName, weekStart)
+	return err
+}
+
+var _ usecase.OnCallRepo = (*OnCallRepo)(nil)
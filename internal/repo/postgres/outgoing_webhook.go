@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// OutgoingWebhookRepo stores the webhook URLs teams registered.
+type OutgoingWebhookRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) OutgoingWebhookRepo() *OutgoingWebhookRepo {
+	return &OutgoingWebhookRepo{db: p.db}
+}
+
+const hookColumns = `id, team_name, url, secret, events, require_ack, created_at, updated_at`
+
+// Create stores h and fills in its ID and timestamps.
+func (r *OutgoingWebhookRepo) Create(ctx context.Context, h *entity.OutgoingWebhook) error {
+	query := `
+		INSERT INTO webhooks (team_name, url, secret, events, require_ack)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	if err := r.db.QueryRow(ctx, query, h.TeamName, h.URL, h.Secret, h.Events, h.RequireAck).Scan(&h.ID, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		return err
+	}
+
+	h.CreatedAt = h.CreatedAt.UTC()
+	h.UpdatedAt = h.UpdatedAt.UTC()
+	return nil
+}
+
+func (r *OutgoingWebhookRepo) GetByID(ctx context.Context, id int64) (entity.OutgoingWebhook, error) {
+	h, err := scanHook(r.db.QueryRow(ctx, "SELECT "+hookColumns+" FROM webhooks WHERE id = $1", id))
+	if err == pgx.ErrNoRows {
+		return entity.OutgoingWebhook{}, ErrNotFound
+	}
+
+	return h, err
+}
+
+func (r *OutgoingWebhookRepo) ListByTeam(ctx context.Context, teamName string) ([]entity.OutgoingWebhook, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+hookColumns+" FROM webhooks WHERE team_name = $1 ORDER BY id", teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []entity.OutgoingWebhook{}
+	for rows.Next() {
+		h, err := scanHook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, h)
+	}
+
+	return hooks, rows.Err()
+}
+
+// Update persists URL, Secret, Events and RequireAck and refreshes h.UpdatedAt.
+func (r *OutgoingWebhookRepo) Update(ctx context.Context, h *entity.OutgoingWebhook) error {
+	query := `
+		UPDATE webhooks SET url = $1, secret = $2, events = $3, require_ack = $4, updated_at = now()
+		WHERE id = $5
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(ctx, query, h.URL, h.Secret, h.Events, h.RequireAck, h.ID).Scan(&h.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	h.UpdatedAt = h.UpdatedAt.UTC()
+	return nil
+}
+
+func (r *OutgoingWebhookRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanHook(row pgx.Row) (entity.OutgoingWebhook, error) {
+	var h entity.OutgoingWebhook
+
+	if err := row.Scan(&h.ID, &h.TeamName, &h.URL, &h.Secret, &h.Events, &h.RequireAck, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		return entity.OutgoingWebhook{}, err
+	}
+
+	h.CreatedAt = h.CreatedAt.UTC()
+	h.UpdatedAt = h.UpdatedAt.UTC()
+	return h, nil
+}
+
+var _ usecase.OutgoingWebhookRepo = (*OutgoingWebhookRepo)(nil)
@@ -4,23 +4,30 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/evrone/go-clean-template/internal/entity"
 	"github.com/evrone/go-clean-template/internal/usecase"
+	"github.com/evrone/go-clean-template/pkg/pagination"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Repo errors alias the usecase ones so callers can match them with errors.Is.
 var (
-	ErrNotFound      = errors.New("not found")
-	ErrAlreadyExists = errors.New("already exists")
+	ErrNotFound           = usecase.ErrNotFound
+	ErrAlreadyExists      = usecase.ErrAlreadyExists
+	ErrPRImmutable        = usecase.ErrPRImmutable
+	ErrPreconditionFailed = usecase.ErrPreconditionFailed
 )
 
 type Postgres struct {
-	db *pgxpool.Pool
+	db   pgdb.DB // base wrapped to join transactions started by TxManager
+	base pgdb.DB
+	pool *pgxpool.Pool // owned pool, only set by New
 }
 
 func New(connString string) (*Postgres, error) {
@@ -41,58 +48,67 @@ func New(connString string) (*Postgres, error) {
 		return nil, fmt.Errorf("ping error: %w", err)
 	}
 
-	return &Postgres{db: pool}, nil
+	return &Postgres{db: txDB{pool}, base: pool, pool: pool}, nil
 }
 
-func NewWithPool(pool *pgxpool.Pool) (*Postgres, error) {
-	if pool == nil {
+// NewWithPool wraps a pool owned by the caller, e.g. *pgdb.Guarded.
+func NewWithPool(db pgdb.DB) (*Postgres, error) {
+	if db == nil {
 		return nil, fmt.Errorf("pool cannot be nil")
 	}
 
-	if err := pool.Ping(context.Background()); err != nil {
+	if err := db.Ping(context.Background()); err != nil {
 		return nil, fmt.Errorf("pool ping error: %w", err)
 	}
 
-	return &Postgres{db: pool}, nil
+	return &Postgres{db: txDB{db}, base: db}, nil
 }
 
 func (p *Postgres) Close() {
-	if p.db != nil {
-		p.db.Close()
+	if p.pool != nil {
+		p.pool.Close()
 	}
 }
 
 type UserRepo struct {
-	db *pgxpool.Pool
+	db pgdb.DB
 }
 
 func (p *Postgres) UserRepo() *UserRepo {
 	return &UserRepo{db: p.db}
 }
 
+// userColumns is the select list understood by scanUser.
+const userColumns = `user_id, username, COALESCE(team_name, ''), is_active, role, title, seniority,
+		       display_name, avatar_url, created_at, updated_at, flagged_inactive_at, deleted_at,
+		       max_open_reviews`
+
+// Create inserts u. An existing user only has the fields they may change
+// themselves updated: the team, role, seniority, activity and deletion are
+// kept, since only lead-authorized operations change those.
 func (r *UserRepo) Create(ctx context.Context, u entity.User) error {
 	query := `
-		INSERT INTO users (user_id, username, team_name, is_active)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (user_id, username, team_name, is_active, role, title, seniority, display_name, avatar_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		ON CONFLICT (user_id) DO UPDATE SET
 			username = EXCLUDED.username,
-			team_name = EXCLUDED.team_name,
-			is_active = EXCLUDED.is_active
+			title = EXCLUDED.title,
+			display_name = COALESCE(NULLIF(EXCLUDED.display_name, ''), users.display_name),
+			avatar_url = COALESCE(NULLIF(EXCLUDED.avatar_url, ''), users.avatar_url),
+			updated_at = now()
 	`
-	_, err := r.db.Exec(ctx, query, u.UserID, u.Username, u.TeamName, u.IsActive)
+	_, err := r.db.Exec(ctx, query, u.UserID, u.Username, u.TeamName, u.IsActive, roleOrDefault(u.Role), u.Title, u.Seniority,
+		u.DisplayName, u.AvatarURL)
 	return err
 }
 
 func (r *UserRepo) GetByID(ctx context.Context, id string) (entity.User, error) {
 	query := `
-		SELECT user_id, username, team_name, is_active
+		SELECT ` + userColumns + `
 		FROM users WHERE user_id = $1
 	`
-	var u entity.User
 
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&u.UserID, &u.Username, &u.TeamName, &u.IsActive,
-	)
+	u, err := scanUser(r.db.QueryRow(ctx, query, id))
 	if err == pgx.ErrNoRows {
 		return entity.User{}, ErrNotFound
 	}
@@ -103,79 +119,259 @@ func (r *UserRepo) GetByID(ctx context.Context, id string) (entity.User, error)
 	return u, nil
 }
 
-func (r *UserRepo) Update(ctx context.Context, u entity.User) error {
+func (r *UserRepo) GetForUpdate(ctx context.Context, id string) (entity.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users WHERE user_id = $1
+		FOR UPDATE
+	`
+
+	u, err := scanUser(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return entity.User{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.User{}, err
+	}
+
+	return u, nil
+}
+
+// Update persists u and refreshes u.UpdatedAt from the database. Deleted
+// users can't be updated. Under a precondition, u must still be at the
+// u.UpdatedAt it was read with, or the update fails with
+// ErrPreconditionFailed.
+func (r *UserRepo) Update(ctx context.Context, u *entity.User) error {
 	query := `
 		UPDATE users 
-		SET username = $1, team_name = $2, is_active = $3
-		WHERE user_id = $4
+		SET username = $1, team_name = NULLIF($2, ''), is_active = $3, role = $4, title = $5, seniority = $6,
+		    display_name = $7, avatar_url = $8, max_open_reviews = $10, updated_at = now()
+		WHERE user_id = $9 AND deleted_at IS NULL AND ($11::timestamptz IS NULL OR updated_at = $11)
+		RETURNING updated_at
 	`
-	result, err := r.db.Exec(ctx, query, u.Username, u.TeamName, u.IsActive, u.UserID)
+	version := guardedVersion(ctx, u.UpdatedAt)
+	err := r.db.QueryRow(ctx, query, u.Username, u.TeamName, u.IsActive, roleOrDefault(u.Role), u.Title, u.Seniority,
+		u.DisplayName, u.AvatarURL, u.UserID, u.MaxOpenReviews, version).Scan(&u.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return r.updateMissErr(ctx, u.UserID, version)
+	}
 	if err != nil {
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
+	u.UpdatedAt = u.UpdatedAt.UTC()
+	return nil
+}
+
+// updateMissErr tells why Update matched no row: the user is gone or changed
+// since version.
+func (r *UserRepo) updateMissErr(ctx context.Context, userID string, version *time.Time) error {
+	var updatedAt time.Time
+	err := r.db.QueryRow(ctx, "SELECT updated_at FROM users WHERE user_id = $1 AND deleted_at IS NULL", userID).Scan(&updatedAt)
+	if err == pgx.ErrNoRows {
 		return ErrNotFound
 	}
-	return nil
+	if err != nil {
+		return err
+	}
+
+	if version != nil && !updatedAt.Equal(*version) {
+		return ErrPreconditionFailed
+	}
+
+	return ErrNotFound
+}
+
+// guardedVersion returns the updated_at a write must still find under a
+// precondition on ctx, or nil when ctx has none.
+func guardedVersion(ctx context.Context, readAt time.Time) *time.Time {
+	if !usecase.HasPrecondition(ctx) || readAt.IsZero() {
+		return nil
+	}
+
+	return &readAt
 }
 
 func (r *UserRepo) ListByTeam(ctx context.Context, teamName string) ([]entity.User, error) {
 	query := `
-		SELECT user_id, username, team_name, is_active
-		FROM users WHERE team_name = $1
+		SELECT ` + userColumns + `
+		FROM users WHERE team_name = $1 AND deleted_at IS NULL
 	`
 	rows, err := r.db.Query(ctx, query, teamName)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var users []entity.User
-	for rows.Next() {
-		var u entity.User
+	return collectUsers(rows)
+}
 
-		if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
-			return nil, err
-		}
-		users = append(users, u)
+// ListCandidates returns up to limit active members of teamName, skipping
+// exclude, ordered by open review count (then user_id). Counts come from the
+// reviewer_workload view and lag by at most one refresh interval.
+func (r *UserRepo) ListCandidates(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users u
+		LEFT JOIN reviewer_workload w USING (user_id)
+		WHERE u.team_name = $1 AND u.is_active AND u.deleted_at IS NULL AND u.user_id <> ALL($2)
+		ORDER BY COALESCE(w.open_reviews, 0), u.user_id
+		LIMIT $3
+	`
+	if exclude == nil {
+		exclude = []string{}
+	}
+
+	rows, err := r.db.Query(ctx, query, teamName, exclude, limit)
+	if err != nil {
+		return nil, err
 	}
 
-	return users, nil
+	return collectUsers(rows)
 }
 
 func (r *UserRepo) ListAll(ctx context.Context) ([]entity.User, error) {
 	query := `
-		SELECT user_id, username, team_name, is_active
+		SELECT ` + userColumns + `
 		FROM users
+		WHERE deleted_at IS NULL
 	`
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
+
+	return collectUsers(rows)
+}
+
+// ListIdle returns active, unflagged users created before since who neither
+// authored nor were assigned to any PR created since then.
+func (r *UserRepo) ListIdle(ctx context.Context, since time.Time) ([]entity.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users u
+		WHERE u.is_active AND u.deleted_at IS NULL AND u.flagged_inactive_at IS NULL AND u.created_at < $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM pull_requests p
+			WHERE p.created_at >= $1
+			  AND (p.author_id = u.user_id OR p.assigned_reviewers ? u.user_id)
+		  )
+		ORDER BY u.user_id
+	`
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectUsers(rows)
+}
+
+// SetFlaggedInactive sets (or with a nil at, clears) the idle flag.
+func (r *UserRepo) SetFlaggedInactive(ctx context.Context, userID string, at *time.Time) error {
+	result, err := r.db.Exec(ctx, "UPDATE users SET flagged_inactive_at = $1, updated_at = now() WHERE user_id = $2", at, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SoftDelete deactivates the user and hides them from teams and listings. The
+// row stays for the PRs and reviews that reference it; GetByID still finds it.
+func (r *UserRepo) SoftDelete(ctx context.Context, userID string, at time.Time) error {
+	query := `
+		UPDATE users SET is_active = false, deleted_at = $2, updated_at = now()
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`
+	result, err := r.db.Exec(ctx, query, userID, at)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ClearIdleFlags unflags users who showed activity since the given time.
+func (r *UserRepo) ClearIdleFlags(ctx context.Context, since time.Time) (int64, error) {
+	query := `
+		UPDATE users u SET flagged_inactive_at = NULL, updated_at = now()
+		WHERE u.flagged_inactive_at IS NOT NULL
+		  AND EXISTS (
+			SELECT 1 FROM pull_requests p
+			WHERE p.created_at >= $1
+			  AND (p.author_id = u.user_id OR p.assigned_reviewers ? u.user_id)
+		  )
+	`
+	result, err := r.db.Exec(ctx, query, since)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func collectUsers(rows pgx.Rows) ([]entity.User, error) {
 	defer rows.Close()
 
 	var users []entity.User
 	for rows.Next() {
-		var u entity.User
-
-		if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
 		users = append(users, u)
 	}
 
-	return users, nil
+	return users, rows.Err()
+}
+
+func scanUser(row pgx.Row) (entity.User, error) {
+	var u entity.User
+	var flaggedAt, deletedAt sql.NullTime
+
+	if err := row.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.Role, &u.Title, &u.Seniority,
+		&u.DisplayName, &u.AvatarURL, &u.CreatedAt, &u.UpdatedAt, &flaggedAt, &deletedAt, &u.MaxOpenReviews); err != nil {
+		return entity.User{}, err
+	}
+
+	u.CreatedAt = u.CreatedAt.UTC()
+	u.UpdatedAt = u.UpdatedAt.UTC()
+	if flaggedAt.Valid {
+		t := flaggedAt.Time.UTC()
+		u.FlaggedInactiveAt = &t
+	}
+	if deletedAt.Valid {
+		t := deletedAt.Time.UTC()
+		u.DeletedAt = &t
+	}
+
+	return u, nil
+}
+
+// roleOrDefault maps an unset role to the column default.
+func roleOrDefault(role string) string {
+	if role == "" {
+		return entity.RoleMember
+	}
+
+	return role
 }
 
 type TeamRepo struct {
-	db *pgxpool.Pool
+	db pgdb.DB
 }
 
 func (p *Postgres) TeamRepo() *TeamRepo {
 	return &TeamRepo{db: p.db}
 }
 
+// Create inserts the team and its members. Existing users are upserted as
+// UserRepo.Create does, so they stay in their current team.
 func (r *TeamRepo) Create(ctx context.Context, t entity.Team) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
@@ -199,13 +395,16 @@ func (r *TeamRepo) Create(ctx context.Context, t entity.Team) error {
 
 	for _, member := range t.Members {
 		_, err = tx.Exec(ctx, `
-			INSERT INTO users (user_id, username, team_name, is_active)
-			VALUES ($1, $2, $3, $4)
+			INSERT INTO users (user_id, username, team_name, is_active, role, title, seniority, display_name, avatar_url)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 			ON CONFLICT (user_id) DO UPDATE SET
 				username = EXCLUDED.username,
-				team_name = EXCLUDED.team_name,
-				is_active = EXCLUDED.is_active
-		`, member.UserID, member.Username, t.TeamName, member.IsActive)
+				title = EXCLUDED.title,
+				display_name = COALESCE(NULLIF(EXCLUDED.display_name, ''), users.display_name),
+				avatar_url = COALESCE(NULLIF(EXCLUDED.avatar_url, ''), users.avatar_url),
+				updated_at = now()
+		`, member.UserID, member.Username, t.TeamName, member.IsActive, roleOrDefault(member.Role), member.Title, member.Seniority,
+			member.DisplayName, member.AvatarURL)
 		if err != nil {
 			return err
 		}
@@ -216,10 +415,12 @@ func (r *TeamRepo) Create(ctx context.Context, t entity.Team) error {
 
 func (r *TeamRepo) GetByName(ctx context.Context, name string) (entity.Team, error) {
 	query := `
-		SELECT user_id, username, is_active
-		FROM users 
-		WHERE team_name = $1
-		ORDER BY user_id
+		SELECT u.user_id, u.username, u.is_active, u.role, u.title, u.seniority, u.display_name, u.avatar_url,
+		       COALESCE(t.created_at, u.created_at), COALESCE(t.updated_at, u.updated_at)
+		FROM users u
+		LEFT JOIN teams t ON t.team_name = u.team_name
+		WHERE u.team_name = $1 AND u.deleted_at IS NULL
+		ORDER BY u.user_id
 	`
 	rows, err := r.db.Query(ctx, query, name)
 	if err != nil {
@@ -232,7 +433,8 @@ func (r *TeamRepo) GetByName(ctx context.Context, name string) (entity.Team, err
 
 	for rows.Next() {
 		var member entity.TeamMember
-		if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive); err != nil {
+		if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive, &member.Role, &member.Title, &member.Seniority,
+			&member.DisplayName, &member.AvatarURL, &team.CreatedAt, &team.UpdatedAt); err != nil {
 			return entity.Team{}, err
 		}
 		team.Members = append(team.Members, member)
@@ -242,17 +444,62 @@ func (r *TeamRepo) GetByName(ctx context.Context, name string) (entity.Team, err
 		return entity.Team{}, ErrNotFound
 	}
 
+	team.CreatedAt = team.CreatedAt.UTC()
+	team.UpdatedAt = team.UpdatedAt.UTC()
+
 	return team, nil
 }
 
-func (r *TeamRepo) ListAll(ctx context.Context) ([]entity.Team, error) {
+func (r *TeamRepo) Exists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", name).Scan(&exists)
+	return exists, err
+}
+
+// Delete removes the team row; its settings, webhooks and rotations cascade.
+// Members are left to the caller.
+func (r *TeamRepo) Delete(ctx context.Context, name string) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM teams WHERE team_name = $1", name)
+	return err
+}
+
+// Rename moves the team and its members to newName; tables keyed by the team
+// follow through ON UPDATE CASCADE. A team known only from its members gets a
+// teams row under the new name.
+func (r *TeamRepo) Rename(ctx context.Context, oldName, newName string) error {
+	tag, err := r.db.Exec(ctx, "UPDATE teams SET team_name = $2, updated_at = now() WHERE team_name = $1", oldName, newName)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.db.Exec(ctx, "INSERT INTO teams (team_name) VALUES ($1)", newName); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.db.Exec(ctx, "UPDATE users SET team_name = $2, updated_at = now() WHERE team_name = $1", oldName, newName)
+	return err
+}
+
+// ListAll returns one page of teams, ordered by name, with their members
+// loaded in the same query. Teams without members are included.
+func (r *TeamRepo) ListAll(ctx context.Context, page pagination.Page) ([]entity.Team, error) {
 	query := `
-		SELECT DISTINCT team_name 
-		FROM users 
-		WHERE team_name IS NOT NULL AND team_name != ''
-		ORDER BY team_name
+		WITH names AS (
+			SELECT team_name, created_at, updated_at
+			FROM teams
+			WHERE $1 = '' OR team_name > $1
+			ORDER BY team_name
+			LIMIT $2
+		)
+		SELECT n.team_name, COALESCE(u.user_id, ''), COALESCE(u.username, ''), COALESCE(u.is_active, false),
+		       COALESCE(u.role, ''), COALESCE(u.title, ''), COALESCE(u.seniority, ''), COALESCE(u.display_name, ''),
+		       COALESCE(u.avatar_url, ''), n.created_at, n.updated_at
+		FROM names n
+		LEFT JOIN users u ON u.team_name = n.team_name AND u.deleted_at IS NULL
+		ORDER BY n.team_name, u.user_id
 	`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := r.db.Query(ctx, query, page.AfterID(), page.Fetch())
 	if err != nil {
 		return nil, err
 	}
@@ -260,45 +507,107 @@ func (r *TeamRepo) ListAll(ctx context.Context) ([]entity.Team, error) {
 
 	var teams []entity.Team
 	for rows.Next() {
-		var teamName string
-		if err := rows.Scan(&teamName); err != nil {
+		var (
+			teamName             string
+			member               entity.TeamMember
+			createdAt, updatedAt time.Time
+		)
+		if err := rows.Scan(&teamName, &member.UserID, &member.Username, &member.IsActive, &member.Role, &member.Title,
+			&member.Seniority, &member.DisplayName, &member.AvatarURL, &createdAt, &updatedAt); err != nil {
 			return nil, err
 		}
 
-		team, err := r.GetByName(ctx, teamName)
-		if err != nil {
-			continue
+		if len(teams) == 0 || teams[len(teams)-1].TeamName != teamName {
+			teams = append(teams, entity.Team{TeamName: teamName, Members: []entity.TeamMember{},
+				CreatedAt: createdAt.UTC(), UpdatedAt: updatedAt.UTC()})
+		}
+		if member.UserID != "" {
+			team := &teams[len(teams)-1]
+			team.Members = append(team.Members, member)
 		}
-		teams = append(teams, team)
 	}
 
-	return teams, nil
+	return teams, rows.Err()
+}
+
+// ListBrief returns the page ListAll would, with member counts instead of
+// members.
+func (r *TeamRepo) ListBrief(ctx context.Context, page pagination.Page) ([]entity.TeamBrief, error) {
+	query := `
+		SELECT t.team_name, COUNT(u.user_id)
+		FROM teams t
+		LEFT JOIN users u ON u.team_name = t.team_name AND u.deleted_at IS NULL
+		WHERE $1 = '' OR t.team_name > $1
+		GROUP BY t.team_name
+		ORDER BY t.team_name
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, page.AfterID(), page.Fetch())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []entity.TeamBrief
+	for rows.Next() {
+		var t entity.TeamBrief
+		if err := rows.Scan(&t.TeamName, &t.MemberCount); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+
+	return teams, rows.Err()
 }
 
 type PRRepo struct {
-	db *pgxpool.Pool
+	db pgdb.DB
 }
 
 func (p *Postgres) PRRepo() *PRRepo {
 	return &PRRepo{db: p.db}
 }
 
+// prColumns is the select list understood by scanPR.
+const prColumns = `pull_request_id, pull_request_name, author_id, status,
+		       assigned_reviewers, created_at, updated_at, merged_at,
+		       COALESCE(repository, ''), COALESCE(external_number, 0), roster_snapshot,
+		       closed_at, close_warned_at, approvals, labels, linked_issues, size, priority,
+		       external_state, COALESCE(stack_id, '')`
+
 func (r *PRRepo) Create(ctx context.Context, pr entity.PullRequest) error {
 	query := `
-		INSERT INTO pull_requests (
-			pull_request_id, pull_request_name, author_id, status, 
-			assigned_reviewers, created_at, merged_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`
+		WITH created AS (
+			INSERT INTO pull_requests (
+				pull_request_id, pull_request_name, author_id, status, 
+				assigned_reviewers, created_at, updated_at, merged_at,
+				repository, external_number, roster_snapshot, labels, priority, stack_id
+			) VALUES ($1, $2, $3, $4, $5, $6, $6, $7, NULLIF($8, ''), NULLIF($9, 0), $10, $11, $12, NULLIF($13, ''))
+			RETURNING pull_request_id, assigned_reviewers, created_at
+		)
+		` + recordAssignments("created", "created_at")
 
 	reviewersJSON, err := json.Marshal(pr.AssignedReviewers)
 	if err != nil {
 		return err
 	}
 
+	labelsJSON, err := json.Marshal(nonNil(pr.Labels))
+	if err != nil {
+		return err
+	}
+
+	var rosterJSON []byte
+	if pr.RosterSnapshot != nil {
+		if rosterJSON, err = json.Marshal(pr.RosterSnapshot); err != nil {
+			return err
+		}
+	}
+
 	_, err = r.db.Exec(ctx, query,
 		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, string(pr.Status),
 		reviewersJSON, pr.CreatedAt, pr.MergedAt,
+		pr.Repository, pr.ExternalNumber, rosterJSON, labelsJSON, priorityOrDefault(pr.Priority), pr.StackID,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") {
@@ -312,21 +621,11 @@ func (r *PRRepo) Create(ctx context.Context, pr entity.PullRequest) error {
 
 func (r *PRRepo) GetByID(ctx context.Context, id string) (entity.PullRequest, error) {
 	query := `
-		SELECT pull_request_id, pull_request_name, author_id, status,
-		       assigned_reviewers, created_at, merged_at
+		SELECT ` + prColumns + `
 		FROM pull_requests WHERE pull_request_id = $1
 	`
 
-	var pr entity.PullRequest
-	var status string
-	var reviewersJSON []byte
-	var mergedAt sql.NullTime
-
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &status,
-		&reviewersJSON, &pr.CreatedAt, &mergedAt,
-	)
-
+	pr, err := scanPR(r.db.QueryRow(ctx, query, id))
 	if err == pgx.ErrNoRows {
 		return entity.PullRequest{}, ErrNotFound
 	}
@@ -334,25 +633,71 @@ func (r *PRRepo) GetByID(ctx context.Context, id string) (entity.PullRequest, er
 		return entity.PullRequest{}, err
 	}
 
-	pr.Status = entity.PRStatus(status)
+	return pr, nil
+}
 
-	if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
+func (r *PRRepo) GetForUpdate(ctx context.Context, id string) (entity.PullRequest, error) {
+	query := `
+		SELECT ` + prColumns + `
+		FROM pull_requests WHERE pull_request_id = $1
+		FOR UPDATE
+	`
+
+	pr, err := scanPR(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return entity.PullRequest{}, ErrNotFound
+	}
+	if err != nil {
 		return entity.PullRequest{}, err
 	}
 
-	if mergedAt.Valid {
-		pr.MergedAt = &mergedAt.Time
+	return pr, nil
+}
+
+func (r *PRRepo) GetByExternal(ctx context.Context, repository string, number int) (entity.PullRequest, error) {
+	query := `
+		SELECT ` + prColumns + `
+		FROM pull_requests WHERE repository = $1 AND external_number = $2
+	`
+
+	pr, err := scanPR(r.db.QueryRow(ctx, query, repository, number))
+	if err == pgx.ErrNoRows {
+		return entity.PullRequest{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.PullRequest{}, err
 	}
 
 	return pr, nil
 }
 
-func (r *PRRepo) Update(ctx context.Context, pr entity.PullRequest) error {
+// Update persists pr and refreshes pr.UpdatedAt from the database. Merged PRs
+// keep their author, reviewers, status and merge time: an update changing any
+// of them fails with ErrPRImmutable. Under a precondition, pr must still be at
+// the pr.UpdatedAt it was read with, or the update fails with
+// ErrPreconditionFailed.
+func (r *PRRepo) Update(ctx context.Context, pr *entity.PullRequest) error {
 	query := `
-		UPDATE pull_requests 
-		SET pull_request_name = $1, author_id = $2, status = $3,
-		    assigned_reviewers = $4, merged_at = $5
-		WHERE pull_request_id = $6
+		WITH updated AS (
+			UPDATE pull_requests 
+			SET pull_request_name = $1, author_id = $2, status = $3,
+			    assigned_reviewers = $4, merged_at = $5, closed_at = $6,
+			    close_warned_at = $7, approvals = $8,
+			    labels = $9, linked_issues = $10, size = $11, priority = $12,
+			    external_state = $13, roster_snapshot = COALESCE($15, roster_snapshot),
+			    updated_at = now()
+			WHERE pull_request_id = $14
+			  AND (status <> 'MERGED' OR (
+			      status = $3 AND author_id = $2 AND assigned_reviewers = $4
+			      AND merged_at IS NOT DISTINCT FROM $5))
+			  AND ($16::timestamptz IS NULL OR updated_at = $16)
+			RETURNING pull_request_id, assigned_reviewers, approvals, updated_at
+		), assigned AS (
+			` + recordAssignments("updated", "updated_at") + `
+		), approved AS (
+			` + recordApprovals("updated", "updated_at") + `
+		)
+		SELECT updated_at FROM updated
 	`
 
 	reviewersJSON, err := json.Marshal(pr.AssignedReviewers)
@@ -360,25 +705,106 @@ func (r *PRRepo) Update(ctx context.Context, pr entity.PullRequest) error {
 		return err
 	}
 
-	result, err := r.db.Exec(ctx, query,
+	approvals := pr.Approvals
+	if approvals == nil {
+		approvals = []string{}
+	}
+	approvalsJSON, err := json.Marshal(approvals)
+	if err != nil {
+		return err
+	}
+
+	labelsJSON, err := json.Marshal(nonNil(pr.Labels))
+	if err != nil {
+		return err
+	}
+
+	issuesJSON, err := json.Marshal(nonNil(pr.LinkedIssues))
+	if err != nil {
+		return err
+	}
+
+	var sizeJSON []byte
+	if pr.Size != nil {
+		if sizeJSON, err = json.Marshal(pr.Size); err != nil {
+			return err
+		}
+	}
+
+	// A nil snapshot keeps the stored one; only assignment ever replaces it.
+	var rosterJSON []byte
+	if pr.RosterSnapshot != nil {
+		if rosterJSON, err = json.Marshal(pr.RosterSnapshot); err != nil {
+			return err
+		}
+	}
+
+	version := guardedVersion(ctx, pr.UpdatedAt)
+	err = r.db.QueryRow(ctx, query,
 		pr.PullRequestName, pr.AuthorID, string(pr.Status),
-		reviewersJSON, pr.MergedAt, pr.PullRequestID,
-	)
+		reviewersJSON, pr.MergedAt, pr.ClosedAt,
+		pr.CloseWarnedAt, approvalsJSON,
+		labelsJSON, issuesJSON, sizeJSON, priorityOrDefault(pr.Priority),
+		externalStateOrDefault(pr.ExternalState), pr.PullRequestID, rosterJSON, version,
+	).Scan(&pr.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return r.updateMissErr(ctx, pr.PullRequestID, version)
+	}
 	if err != nil {
 		return err
 	}
 
-	if result.RowsAffected() == 0 {
+	pr.UpdatedAt = pr.UpdatedAt.UTC()
+	return nil
+}
+
+// recordAssignments is the statement that, given the CTE named from with the
+// written PR's pull_request_id and assigned_reviewers, keeps when each
+// reviewer was first assigned (the at column) in reviewer_assignments.
+func recordAssignments(from, at string) string {
+	return `
+		INSERT INTO reviewer_assignments (pull_request_id, reviewer_id, assigned_at)
+		SELECT w.pull_request_id, r.reviewer_id, w.` + at + `
+		FROM ` + from + ` w, jsonb_array_elements_text(w.assigned_reviewers) AS r(reviewer_id)
+		ON CONFLICT DO NOTHING
+	`
+}
+
+// recordApprovals is recordAssignments for approvals: it keeps when each
+// reviewer in the from CTE's approvals first approved, in
+// pull_request_approvals. A revoked approval keeps its time.
+func recordApprovals(from, at string) string {
+	return `
+		INSERT INTO pull_request_approvals (pull_request_id, reviewer_id, approved_at)
+		SELECT w.pull_request_id, a.reviewer_id, w.` + at + `
+		FROM ` + from + ` w, jsonb_array_elements_text(w.approvals) AS a(reviewer_id)
+		ON CONFLICT DO NOTHING
+	`
+}
+
+// updateMissErr tells why Update matched no row: the PR is gone, it changed
+// since version, or it is merged and the update would have rewritten its
+// author, reviewers or merge.
+func (r *PRRepo) updateMissErr(ctx context.Context, prID string, version *time.Time) error {
+	var updatedAt time.Time
+	err := r.db.QueryRow(ctx, "SELECT updated_at FROM pull_requests WHERE pull_request_id = $1", prID).Scan(&updatedAt)
+	if err == pgx.ErrNoRows {
 		return ErrNotFound
 	}
+	if err != nil {
+		return err
+	}
 
-	return nil
+	if version != nil && !updatedAt.Equal(*version) {
+		return ErrPreconditionFailed
+	}
+
+	return ErrPRImmutable
 }
 
 func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]entity.PullRequest, error) {
 	query := `
-		SELECT pull_request_id, pull_request_name, author_id, status,
-		       assigned_reviewers, created_at, merged_at
+		SELECT ` + prColumns + `
 		FROM pull_requests 
 		WHERE assigned_reviewers @> $1::jsonb
 		ORDER BY created_at DESC
@@ -393,80 +819,425 @@ func (r *PRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]entit
 	if err != nil {
 		return nil, err
 	}
+
+	return collectPRs(rows)
+}
+
+// AssignedAt returns when reviewerID was assigned to each open PR they
+// review, keyed by PR ID.
+func (r *PRRepo) AssignedAt(ctx context.Context, reviewerID string) (map[string]time.Time, error) {
+	query := `
+		SELECT a.pull_request_id, a.assigned_at
+		FROM reviewer_assignments a
+		JOIN pull_requests p ON p.pull_request_id = a.pull_request_id
+		WHERE a.reviewer_id = $1 AND p.status = 'OPEN'
+	`
+	rows, err := r.db.Query(ctx, query, reviewerID)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
-	var prs []entity.PullRequest
+	out := map[string]time.Time{}
 	for rows.Next() {
-		var pr entity.PullRequest
-		var status string
-		var reviewersJSON []byte
-		var mergedAt sql.NullTime
-
-		if err := rows.Scan(
-			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &status,
-			&reviewersJSON, &pr.CreatedAt, &mergedAt,
-		); err != nil {
+		var (
+			prID string
+			at   time.Time
+		)
+		if err := rows.Scan(&prID, &at); err != nil {
 			return nil, err
 		}
+		out[prID] = at.UTC()
+	}
+
+	return out, rows.Err()
+}
+
+func (r *PRRepo) ListByStatus(ctx context.Context, status entity.PRStatus) ([]entity.PullRequest, error) {
+	query := `
+		SELECT ` + prColumns + `
+		FROM pull_requests
+		WHERE status = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, string(status))
+	if err != nil {
+		return nil, err
+	}
+
+	return collectPRs(rows)
+}
+
+// ListOpenByAuthor returns the author's open and draft PRs, oldest first.
+func (r *PRRepo) ListOpenByAuthor(ctx context.Context, authorID string) ([]entity.PullRequest, error) {
+	query := `
+		SELECT ` + prColumns + `
+		FROM pull_requests
+		WHERE author_id = $1 AND status IN ('OPEN', 'DRAFT')
+		ORDER BY created_at, pull_request_id
+	`
+
+	rows, err := r.db.Query(ctx, query, authorID)
+	if err != nil {
+		return nil, err
+	}
 
-		pr.Status = entity.PRStatus(status)
+	return collectPRs(rows)
+}
 
-		if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
+func (r *PRRepo) CountOpenByAuthor(ctx context.Context, authorID string) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM pull_requests WHERE author_id = $1 AND status = 'OPEN'", authorID).Scan(&n)
+	return n, err
+}
+
+// ListTeamActivity returns the open and draft PRs of teamName's members plus
+// those merged since since, oldest first.
+func (r *PRRepo) ListTeamActivity(ctx context.Context, teamName string, since time.Time) ([]entity.PullRequest, error) {
+	query := `
+		SELECT ` + prColumns + `
+		FROM pull_requests
+		WHERE author_id IN (SELECT user_id FROM users WHERE team_name = $1)
+		  AND (status IN ('OPEN', 'DRAFT') OR (status = 'MERGED' AND merged_at >= $2))
+		ORDER BY created_at, pull_request_id
+	`
+
+	rows, err := r.db.Query(ctx, query, teamName, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectPRs(rows)
+}
+
+// StackReviewers lists who is assigned to the author's PRs in the stack
+// other than exceptPRID, leaving out closed ones. Stack IDs are the author's
+// own, so another author's stack of the same name is not included.
+func (r *PRRepo) StackReviewers(ctx context.Context, authorID, stackID, exceptPRID string) ([]string, error) {
+	query := `
+		SELECT DISTINCT reviewer_id
+		FROM pull_requests, jsonb_array_elements_text(assigned_reviewers) AS reviewer_id
+		WHERE author_id = $1 AND stack_id = $2 AND pull_request_id <> $3 AND status <> 'CLOSED'
+		ORDER BY reviewer_id
+	`
+	rows, err := r.db.Query(ctx, query, authorID, stackID, exceptPRID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
 			return nil, err
 		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
 
-		if mergedAt.Valid {
-			pr.MergedAt = &mergedAt.Time
+// RecentlyAssigned reads reviewer_assignments, so a reviewer counts from
+// their first assignment to each PR. A PR opened before horizon can't be
+// among the team's last ones, so its assignments aren't read either.
+func (r *PRRepo) RecentlyAssigned(ctx context.Context, teamName string, reviewerIDs []string, since *time.Time, horizon time.Time, lastPRs int) ([]string, error) {
+	query := `
+		WITH last_prs AS (
+			SELECT p.pull_request_id
+			FROM pull_requests p
+			JOIN users u ON u.user_id = p.author_id
+			WHERE u.team_name = $1 AND p.created_at > $4
+			ORDER BY p.created_at DESC, p.pull_request_id DESC
+			LIMIT $5
+		)
+		SELECT DISTINCT reviewer_id
+		FROM reviewer_assignments
+		WHERE reviewer_id = ANY($2) AND assigned_at > $4
+		  AND (($3::timestamptz IS NOT NULL AND assigned_at > $3)
+		       OR pull_request_id IN (SELECT pull_request_id FROM last_prs))
+		ORDER BY reviewer_id
+	`
+	rows, err := r.db.Query(ctx, query, teamName, reviewerIDs, since, horizon, lastPRs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
 		}
+		ids = append(ids, id)
+	}
 
-		prs = append(prs, pr)
+	return ids, rows.Err()
+}
+
+// CountOpenByReviewer returns the live number of open PRs each reviewer is
+// assigned to. Reviewers with no open reviews are absent from the map.
+func (r *PRRepo) CountOpenByReviewer(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	query := `
+		SELECT r.user_id, COUNT(*)
+		FROM pull_requests p, jsonb_array_elements_text(p.assigned_reviewers) AS r(user_id)
+		WHERE p.status = 'OPEN' AND p.assigned_reviewers ?| $1 AND r.user_id = ANY($1)
+		GROUP BY r.user_id
+	`
+	rows, err := r.db.Query(ctx, query, reviewerIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(reviewerIDs))
+	for rows.Next() {
+		var (
+			id string
+			n  int
+		)
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		counts[id] = n
 	}
 
-	return prs, nil
+	return counts, rows.Err()
 }
 
-func (r *PRRepo) ListAll(ctx context.Context) ([]entity.PullRequest, error) {
+// Snooze records that the reviewer postponed the review until the given time.
+func (r *PRRepo) Snooze(ctx context.Context, prID, userID string, until time.Time) error {
 	query := `
-		SELECT pull_request_id, pull_request_name, author_id, status,
-		       assigned_reviewers, created_at, merged_at
-		FROM pull_requests 
-		ORDER BY created_at DESC
+		INSERT INTO review_snoozes (pull_request_id, user_id, snoozed_until)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (pull_request_id, user_id) DO UPDATE SET snoozed_until = EXCLUDED.snoozed_until
 	`
+	_, err := r.db.Exec(ctx, query, prID, userID, until)
+	return err
+}
 
+// ListPendingReviews returns every reviewer of an open PR who has not
+// approved it yet, with when they were assigned, last reminded and snoozed
+// until.
+func (r *PRRepo) ListPendingReviews(ctx context.Context) ([]entity.PendingReview, error) {
+	query := `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.priority,
+		       rv.user_id, COALESCE(ra.assigned_at, p.created_at), rm.reminded_at, s.snoozed_until
+		FROM pull_requests p
+		CROSS JOIN LATERAL jsonb_array_elements_text(p.assigned_reviewers) AS rv(user_id)
+		LEFT JOIN reviewer_assignments ra ON ra.pull_request_id = p.pull_request_id AND ra.reviewer_id = rv.user_id
+		LEFT JOIN review_reminders rm ON rm.pull_request_id = p.pull_request_id AND rm.user_id = rv.user_id
+		LEFT JOIN review_snoozes s ON s.pull_request_id = p.pull_request_id AND s.user_id = rv.user_id
+		WHERE p.status = 'OPEN' AND NOT p.approvals ? rv.user_id
+		ORDER BY p.created_at, p.pull_request_id, rv.user_id
+	`
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var prs []entity.PullRequest
+	reviews := []entity.PendingReview{}
 	for rows.Next() {
-		var pr entity.PullRequest
-		var status string
-		var reviewersJSON []byte
-		var mergedAt sql.NullTime
-
+		var (
+			pr                     entity.PendingReview
+			remindedAt, snoozedTil sql.NullTime
+		)
 		if err := rows.Scan(
-			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &status,
-			&reviewersJSON, &pr.CreatedAt, &mergedAt,
+			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Priority,
+			&pr.ReviewerID, &pr.AssignedAt, &remindedAt, &snoozedTil,
 		); err != nil {
 			return nil, err
 		}
 
-		pr.Status = entity.PRStatus(status)
+		pr.AssignedAt = pr.AssignedAt.UTC()
+		if remindedAt.Valid {
+			t := remindedAt.Time.UTC()
+			pr.RemindedAt = &t
+		}
+		if snoozedTil.Valid {
+			t := snoozedTil.Time.UTC()
+			pr.SnoozedUntil = &t
+		}
+		reviews = append(reviews, pr)
+	}
+
+	return reviews, rows.Err()
+}
+
+// MarkReminded records that the reviewer was reminded about the PR at at.
+func (r *PRRepo) MarkReminded(ctx context.Context, prID, userID string, at time.Time) error {
+	query := `
+		INSERT INTO review_reminders (pull_request_id, user_id, reminded_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (pull_request_id, user_id) DO UPDATE SET reminded_at = EXCLUDED.reminded_at
+	`
+	_, err := r.db.Exec(ctx, query, prID, userID, at)
+	return err
+}
+
+// ListAbandoned returns open, not yet warned PRs with no activity since before.
+func (r *PRRepo) ListAbandoned(ctx context.Context, before time.Time) ([]entity.PullRequest, error) {
+	query := `
+		SELECT ` + prColumns + `
+		FROM pull_requests
+		WHERE status = 'OPEN' AND close_warned_at IS NULL AND updated_at < $1
+		ORDER BY updated_at
+	`
+	rows, err := r.db.Query(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectPRs(rows)
+}
+
+// ListCloseWarned returns open PRs whose authors were warned about auto-close.
+func (r *PRRepo) ListCloseWarned(ctx context.Context) ([]entity.PullRequest, error) {
+	query := `
+		SELECT ` + prColumns + `
+		FROM pull_requests
+		WHERE status = 'OPEN' AND close_warned_at IS NOT NULL
+		ORDER BY close_warned_at
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
+	return collectPRs(rows)
+}
+
+// SetCloseWarning sets (or with a nil at, clears) the auto-close warning.
+// updated_at is left alone so the warning itself doesn't count as activity.
+func (r *PRRepo) SetCloseWarning(ctx context.Context, prID string, at *time.Time) error {
+	result, err := r.db.Exec(ctx, "UPDATE pull_requests SET close_warned_at = $1 WHERE pull_request_id = $2", at, prID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// priorityOrDefault maps an unset priority to the column default.
+func priorityOrDefault(p string) string {
+	if p == "" {
+		return entity.PriorityNormal
+	}
+
+	return p
+}
+
+// externalStateOrDefault maps an unset external state to the column default.
+func externalStateOrDefault(s string) string {
+	if s == "" {
+		return entity.ExternalStateClean
+	}
+
+	return s
+}
+
+func collectPRs(rows pgx.Rows) ([]entity.PullRequest, error) {
+	defer rows.Close()
+
+	var prs []entity.PullRequest
+	for rows.Next() {
+		pr, err := scanPR(rows)
+		if err != nil {
 			return nil, err
 		}
+		prs = append(prs, pr)
+	}
+
+	return prs, rows.Err()
+}
+
+func scanPR(row pgx.Row) (entity.PullRequest, error) {
+	var pr entity.PullRequest
+	var status string
+	var reviewersJSON, rosterJSON, approvalsJSON, labelsJSON, issuesJSON, sizeJSON []byte
+	var mergedAt, closedAt, closeWarnedAt sql.NullTime
+
+	if err := row.Scan(
+		&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &status,
+		&reviewersJSON, &pr.CreatedAt, &pr.UpdatedAt, &mergedAt,
+		&pr.Repository, &pr.ExternalNumber, &rosterJSON,
+		&closedAt, &closeWarnedAt, &approvalsJSON,
+		&labelsJSON, &issuesJSON, &sizeJSON, &pr.Priority,
+		&pr.ExternalState, &pr.StackID,
+	); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	pr.CreatedAt = pr.CreatedAt.UTC()
+	pr.UpdatedAt = pr.UpdatedAt.UTC()
+
+	prStatus, err := entity.ParsePRStatus(status)
+	if err != nil {
+		return entity.PullRequest{}, fmt.Errorf("pull request %s: %w", pr.PullRequestID, err)
+	}
+	pr.Status = prStatus
+
+	if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
+		return entity.PullRequest{}, err
+	}
 
-		if mergedAt.Valid {
-			pr.MergedAt = &mergedAt.Time
+	if err := json.Unmarshal(approvalsJSON, &pr.Approvals); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if err := json.Unmarshal(labelsJSON, &pr.Labels); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if err := json.Unmarshal(issuesJSON, &pr.LinkedIssues); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if sizeJSON != nil {
+		pr.Size = &entity.PRSize{}
+		if err := json.Unmarshal(sizeJSON, pr.Size); err != nil {
+			return entity.PullRequest{}, err
 		}
+	}
 
-		prs = append(prs, pr)
+	if rosterJSON != nil {
+		pr.RosterSnapshot = &entity.RosterSnapshot{}
+		if err := json.Unmarshal(rosterJSON, pr.RosterSnapshot); err != nil {
+			return entity.PullRequest{}, err
+		}
+	}
+
+	if mergedAt.Valid {
+		t := mergedAt.Time.UTC()
+		pr.MergedAt = &t
+	}
+
+	if closedAt.Valid {
+		t := closedAt.Time.UTC()
+		pr.ClosedAt = &t
+	}
+
+	if closeWarnedAt.Valid {
+		t := closeWarnedAt.Time.UTC()
+		pr.CloseWarnedAt = &t
+	}
+
+	return pr, nil
+}
+
+// nonNil keeps empty lists as JSON [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
 	}
 
-	return prs, nil
+	return s
 }
 
 var (
@@ -0,0 +1,465 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/pagination"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+// StatsRepo answers aggregate reporting queries with SQL aggregates instead
+// of loading every row through the CRUD repositories.
+type StatsRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) StatsRepo() *StatsRepo {
+	return &StatsRepo{db: p.db}
+}
+
+// Stats reads each table once, counting with FILTER rather than a subquery
+// per figure, so its cost grows with one scan of pull_requests and users.
+func (r *StatsRepo) Stats(ctx context.Context) (entity.Stats, error) {
+	query := `
+		SELECT p.total, u.total, p.open, p.merged, p.closed, u.active, p.avg_reviewers,
+			(SELECT COALESCE(AVG(EXTRACT(EPOCH FROM f.first_at - pr.created_at)) / 3600, 0)::float8
+			 FROM (SELECT pull_request_id, MIN(created_at) AS first_at FROM reviews GROUP BY pull_request_id, reviewer_id) f
+			 JOIN pull_requests pr USING (pull_request_id))
+		FROM (
+			SELECT COUNT(*) AS total,
+				COUNT(*) FILTER (WHERE status = 'OPEN') AS open,
+				COUNT(*) FILTER (WHERE status = 'MERGED') AS merged,
+				COUNT(*) FILTER (WHERE status = 'CLOSED') AS closed,
+				COALESCE(AVG(jsonb_array_length(assigned_reviewers)), 0)::float8 AS avg_reviewers
+			FROM pull_requests
+		) p, (
+			SELECT COUNT(*) AS total, COUNT(*) FILTER (WHERE is_active) AS active
+			FROM users
+			WHERE deleted_at IS NULL
+		) u
+	`
+	var s entity.Stats
+
+	err := r.db.QueryRow(ctx, query).Scan(
+		&s.TotalPRs, &s.TotalUsers, &s.OpenPRs, &s.MergedPRs, &s.ClosedPRs, &s.ActiveUsers, &s.AverageReviewers,
+		&s.AverageReviewTurnaroundHours,
+	)
+	if err != nil {
+		return entity.Stats{}, err
+	}
+
+	return s, nil
+}
+
+// UserStats aggregates userID's assignments; the open and merged counts use
+// the current reviewer lists.
+func (r *StatsRepo) UserStats(ctx context.Context, userID string) (entity.UserReviewStats, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM reviewer_assignments WHERE reviewer_id = $1),
+			COUNT(*) FILTER (WHERE p.status = 'MERGED'),
+			COALESCE(AVG(EXTRACT(EPOCH FROM p.merged_at - a.assigned_at)) FILTER (WHERE p.status = 'MERGED'), 0)::float8 / 3600,
+			COUNT(*) FILTER (WHERE p.status = 'OPEN')
+		FROM pull_requests p
+		LEFT JOIN reviewer_assignments a ON a.pull_request_id = p.pull_request_id AND a.reviewer_id = $1
+		WHERE p.assigned_reviewers @> jsonb_build_array($1::text)
+	`
+	s := entity.UserReviewStats{UserID: userID}
+
+	err := r.db.QueryRow(ctx, query, userID).Scan(&s.Assigned, &s.Completed, &s.AverageHoursToMerge, &s.OpenReviews)
+	if err != nil {
+		return entity.UserReviewStats{}, err
+	}
+
+	return s, nil
+}
+
+// UsersStats is UserStats for many users in one query; users without any
+// assignment get zero counts.
+func (r *StatsRepo) UsersStats(ctx context.Context, userIDs []string) (map[string]entity.UserReviewStats, error) {
+	query := `
+		SELECT u.id,
+			(SELECT COUNT(*) FROM reviewer_assignments WHERE reviewer_id = u.id),
+			COUNT(p.pull_request_id) FILTER (WHERE p.status = 'MERGED'),
+			COALESCE(AVG(EXTRACT(EPOCH FROM p.merged_at - a.assigned_at)) FILTER (WHERE p.status = 'MERGED'), 0)::float8 / 3600,
+			COUNT(p.pull_request_id) FILTER (WHERE p.status = 'OPEN')
+		FROM unnest($1::text[]) AS u(id)
+		LEFT JOIN pull_requests p ON p.assigned_reviewers @> jsonb_build_array(u.id)
+		LEFT JOIN reviewer_assignments a ON a.pull_request_id = p.pull_request_id AND a.reviewer_id = u.id
+		GROUP BY u.id
+	`
+	rows, err := r.db.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make(map[string]entity.UserReviewStats, len(userIDs))
+	for rows.Next() {
+		var s entity.UserReviewStats
+		if err := rows.Scan(&s.UserID, &s.Assigned, &s.Completed, &s.AverageHoursToMerge, &s.OpenReviews); err != nil {
+			return nil, err
+		}
+		stats[s.UserID] = s
+	}
+
+	return stats, rows.Err()
+}
+
+// Series counts PRs by the UTC day or week (starting Monday) they were
+// created, merged or closed in, over [rng.From, rng.To), one row per bucket.
+func (r *StatsRepo) Series(ctx context.Context, rng entity.AnalyticsRange, bucket string) ([]entity.TimeBucket, error) {
+	query := `
+		WITH buckets AS (
+			SELECT generate_series(
+				date_trunc($3, $1::timestamptz AT TIME ZONE 'UTC'),
+				$2::timestamptz AT TIME ZONE 'UTC' - interval '1 microsecond',
+				('1 ' || $3)::interval
+			) AS start
+		), events AS (
+			SELECT date_trunc($3, created_at AT TIME ZONE 'UTC') AS start, 1 AS created, 0 AS merged, 0 AS closed
+			FROM pull_requests WHERE created_at >= $1 AND created_at < $2
+			UNION ALL
+			SELECT date_trunc($3, merged_at AT TIME ZONE 'UTC'), 0, 1, 0
+			FROM pull_requests WHERE merged_at >= $1 AND merged_at < $2
+			UNION ALL
+			SELECT date_trunc($3, closed_at AT TIME ZONE 'UTC'), 0, 0, 1
+			FROM pull_requests WHERE closed_at >= $1 AND closed_at < $2
+		)
+		SELECT b.start, COALESCE(SUM(e.created), 0), COALESCE(SUM(e.merged), 0), COALESCE(SUM(e.closed), 0)
+		FROM buckets b
+		LEFT JOIN events e USING (start)
+		GROUP BY b.start
+		ORDER BY b.start
+	`
+	rows, err := r.db.Query(ctx, query, rng.From, rng.To, bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []entity.TimeBucket{}
+	for rows.Next() {
+		var b entity.TimeBucket
+		if err := rows.Scan(&b.Start, &b.Created, &b.Merged, &b.Closed); err != nil {
+			return nil, err
+		}
+		b.Start = b.Start.UTC()
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
+}
+
+// TeamSLA computes each team's turnaround percentiles over [rng.From,
+// rng.To), ordered by team name. Teams with neither merges nor first
+// approvals in the range are left out.
+func (r *StatsRepo) TeamSLA(ctx context.Context, rng entity.AnalyticsRange) ([]entity.TeamSLA, error) {
+	query := `
+		WITH merged AS (
+			SELECT u.team_name, EXTRACT(EPOCH FROM p.merged_at - p.created_at) / 3600 AS hours
+			FROM pull_requests p
+			JOIN users u ON u.user_id = p.author_id
+			WHERE p.merged_at >= $1 AND p.merged_at < $2
+		), approved AS (
+			SELECT u.team_name, EXTRACT(EPOCH FROM f.first_at - p.created_at) / 3600 AS hours
+			FROM (SELECT pull_request_id, MIN(approved_at) AS first_at
+			      FROM pull_request_approvals GROUP BY pull_request_id) f
+			JOIN pull_requests p USING (pull_request_id)
+			JOIN users u ON u.user_id = p.author_id
+			WHERE f.first_at >= $1 AND f.first_at < $2
+		), merge_sla AS (
+			SELECT team_name, COUNT(*) AS n,
+			       percentile_cont(0.5) WITHIN GROUP (ORDER BY hours) AS p50,
+			       percentile_cont(0.9) WITHIN GROUP (ORDER BY hours) AS p90
+			FROM merged GROUP BY team_name
+		), approval_sla AS (
+			SELECT team_name, COUNT(*) AS n,
+			       percentile_cont(0.5) WITHIN GROUP (ORDER BY hours) AS p50,
+			       percentile_cont(0.9) WITHIN GROUP (ORDER BY hours) AS p90
+			FROM approved GROUP BY team_name
+		)
+		SELECT team_name,
+		       COALESCE(m.n, 0), COALESCE(m.p50, 0)::float8, COALESCE(m.p90, 0)::float8,
+		       COALESCE(a.n, 0), COALESCE(a.p50, 0)::float8, COALESCE(a.p90, 0)::float8
+		FROM merge_sla m
+		FULL JOIN approval_sla a USING (team_name)
+		ORDER BY team_name
+	`
+	rows, err := r.db.Query(ctx, query, rng.From, rng.To)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []entity.TeamSLA{}
+	for rows.Next() {
+		var t entity.TeamSLA
+		if err := rows.Scan(
+			&t.TeamName,
+			&t.MergedPRs, &t.MedianHoursToMerge, &t.P90HoursToMerge,
+			&t.ApprovedPRs, &t.MedianHoursToFirstApproval, &t.P90HoursToFirstApproval,
+		); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+
+	return teams, rows.Err()
+}
+
+// Leaderboard ranks users (not deleted, of f.TeamName when set) by their
+// completed reviews and open assignments; users without any are included
+// so free capacity shows up.
+func (r *StatsRepo) Leaderboard(ctx context.Context, f entity.ReviewerRankFilter) ([]entity.ReviewerRank, error) {
+	query := `
+		WITH load AS (
+			SELECT a.reviewer_id,
+			       COUNT(*) FILTER (WHERE p.status = 'MERGED') AS completed,
+			       COUNT(*) FILTER (WHERE p.status = 'OPEN') AS open
+			FROM pull_requests p, jsonb_array_elements_text(p.assigned_reviewers) AS a(reviewer_id)
+			WHERE p.status = 'OPEN' OR (p.status = 'MERGED' AND p.merged_at >= $1 AND p.merged_at < $2)
+			GROUP BY a.reviewer_id
+		)
+		SELECT u.user_id, COALESCE(u.team_name, ''), u.is_active, u.max_open_reviews,
+		       COALESCE(l.completed, 0), COALESCE(l.open, 0)
+		FROM users u
+		LEFT JOIN load l ON l.reviewer_id = u.user_id
+		WHERE u.deleted_at IS NULL AND ($3 = '' OR u.team_name = $3)
+		ORDER BY
+			CASE WHEN $4 = 'open' THEN COALESCE(l.open, 0) ELSE COALESCE(l.completed, 0) END DESC,
+			CASE WHEN $4 = 'open' THEN COALESCE(l.completed, 0) ELSE COALESCE(l.open, 0) END DESC,
+			u.user_id
+		LIMIT $5
+	`
+	rows, err := r.db.Query(ctx, query, f.From, f.To, f.TeamName, f.Sort, f.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []entity.ReviewerRank{}
+	for rows.Next() {
+		e := entity.ReviewerRank{Rank: len(entries) + 1}
+		if err := rows.Scan(&e.UserID, &e.TeamName, &e.IsActive, &e.MaxOpenReviews, &e.Completed, &e.OpenReviews); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// SearchRepo serves the paginated directory listings.
+type SearchRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) SearchRepo() *SearchRepo {
+	return &SearchRepo{db: p.db}
+}
+
+// Users returns one page of users ordered by user_id.
+func (r *SearchRepo) Users(ctx context.Context, page pagination.Page) ([]entity.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE deleted_at IS NULL AND ($1 = '' OR user_id > $1)
+		ORDER BY user_id
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, page.AfterID(), page.Fetch())
+	if err != nil {
+		return nil, err
+	}
+
+	return collectUsers(rows)
+}
+
+// Teams returns one page of teams (ordered by name) with member,
+// active-member and open-PR counts computed in a single query.
+func (r *SearchRepo) Teams(ctx context.Context, page pagination.Page) ([]entity.TeamSummary, error) {
+	query := `
+		SELECT t.team_name,
+		       COUNT(u.user_id),
+		       COUNT(u.user_id) FILTER (WHERE u.is_active),
+		       (SELECT COUNT(*)
+		        FROM pull_requests p
+		        JOIN users a ON a.user_id = p.author_id
+		        WHERE a.team_name = t.team_name AND p.status = 'OPEN')
+		FROM teams t
+		LEFT JOIN users u ON u.team_name = t.team_name AND u.deleted_at IS NULL
+		WHERE $1 = '' OR t.team_name > $1
+		GROUP BY t.team_name
+		ORDER BY t.team_name
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, page.AfterID(), page.Fetch())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []entity.TeamSummary
+	for rows.Next() {
+		var t entity.TeamSummary
+		if err := rows.Scan(&t.TeamName, &t.MemberCount, &t.ActiveCount, &t.OpenPRCount); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+
+	return teams, rows.Err()
+}
+
+// _likeEscaper escapes the LIKE wildcards in a search query.
+var _likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// _searchMatch is the condition SearchPullRequests and CountSearchHits share.
+const _searchMatch = `
+	to_tsvector('simple', pull_request_name) @@ websearch_to_tsquery('simple', $1)
+	OR (length($1) >= 3 AND pull_request_name ILIKE '%' || $2 || '%')
+`
+
+// SearchPullRequests matches q's words against the name's tsvector and, for
+// queries of three or more characters, q as a case-insensitive substring,
+// which the trigram index serves.
+func (r *SearchRepo) SearchPullRequests(ctx context.Context, q string, page pagination.Page) ([]entity.PRSearchHit, error) {
+	query := `
+		SELECT pull_request_id, pull_request_name, author_id, status, external_state, created_at
+		FROM pull_requests
+		WHERE (` + _searchMatch + `)
+		  AND ($3::timestamptz IS NULL OR (created_at, pull_request_id) < ($3, $4))
+		ORDER BY created_at DESC, pull_request_id DESC
+		LIMIT $5
+	`
+	rows, err := r.db.Query(ctx, query, q, _likeEscaper.Replace(q), page.AfterTime(), page.AfterID(), page.Fetch())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []entity.PRSearchHit
+	for rows.Next() {
+		var (
+			h      entity.PRSearchHit
+			status string
+		)
+		if err := rows.Scan(&h.PullRequestID, &h.PullRequestName, &h.AuthorID, &status, &h.ExternalState, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		if h.Status, err = entity.ParsePRStatus(status); err != nil {
+			return nil, fmt.Errorf("pull request %s: %w", h.PullRequestID, err)
+		}
+		h.CreatedAt = h.CreatedAt.UTC()
+		hits = append(hits, h)
+	}
+
+	return hits, rows.Err()
+}
+
+// CountSearchHits counts every PR SearchPullRequests matches for q.
+func (r *SearchRepo) CountSearchHits(ctx context.Context, q string) (int64, error) {
+	var n int64
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM pull_requests WHERE "+_searchMatch, q, _likeEscaper.Replace(q)).Scan(&n)
+	return n, err
+}
+
+// Estimate reads the row count the planner keeps for the listing's table,
+// which costs no scan but lags until the next ANALYZE. Tables never analyzed
+// are counted exactly. Soft-deleted users aren't listed, so their exact count
+// (a scan of idx_users_deleted) comes off the users estimate.
+func (r *SearchRepo) Estimate(ctx context.Context, listing string) (int64, error) {
+	var table string
+	switch listing {
+	case "teams", "users", "pull_requests":
+		table = listing
+	default:
+		return 0, fmt.Errorf("unknown listing %q", listing)
+	}
+
+	var n int64
+	if err := r.db.QueryRow(ctx, "SELECT reltuples::bigint FROM pg_class WHERE oid = $1::regclass", table).Scan(&n); err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM "+table).Scan(&n); err != nil {
+			return 0, err
+		}
+	}
+	if listing != "users" {
+		return n, nil
+	}
+
+	var deleted int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL").Scan(&deleted); err != nil {
+		return 0, err
+	}
+	return max(n-deleted, 0), nil
+}
+
+// _prSortKeys maps each PR list order to the column its cursor holds and
+// the seek direction.
+var _prSortKeys = map[string]struct{ column, dir, seek string }{
+	entity.PRSortNewest:  {"created_at", "DESC", "<"},
+	entity.PRSortOldest:  {"created_at", "ASC", ">"},
+	entity.PRSortUpdated: {"updated_at", "DESC", "<"},
+}
+
+// PullRequests returns one page of the PRs matching f in f.Sort order; the
+// page cursor holds the sort column and pull_request_id of the last row.
+func (r *SearchRepo) PullRequests(ctx context.Context, f entity.PRListFilter, page pagination.Page) ([]entity.PullRequest, error) {
+	sort := f.Sort
+	if sort == "" {
+		sort = entity.PRSortNewest
+	}
+	key, ok := _prSortKeys[sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown PR sort %q", f.Sort)
+	}
+
+	query := `
+		SELECT ` + prColumns + `
+		FROM pull_requests
+		WHERE ` + _prFilterWhere + `
+		  AND ($6::timestamptz IS NULL OR (` + key.column + `, pull_request_id) ` + key.seek + ` ($6, $7))
+		ORDER BY ` + key.column + ` ` + key.dir + `, pull_request_id ` + key.dir + `
+		LIMIT $8
+	`
+
+	rows, err := r.db.Query(ctx, query, string(f.Status), f.AuthorID, f.TeamName, f.CreatedAfter, f.CreatedBefore,
+		page.AfterTime(), page.AfterID(), page.Fetch())
+	if err != nil {
+		return nil, err
+	}
+
+	return collectPRs(rows)
+}
+
+// _prFilterWhere matches the PRs of an entity.PRListFilter bound as $1..$5.
+const _prFilterWhere = `($1 = '' OR status = $1)
+		  AND ($2 = '' OR author_id = $2)
+		  AND ($3 = '' OR author_id IN (SELECT user_id FROM users WHERE team_name = $3))
+		  AND ($4::timestamptz IS NULL OR created_at > $4)
+		  AND ($5::timestamptz IS NULL OR created_at < $5)`
+
+// CountPullRequests counts every PR matching f exactly.
+func (r *SearchRepo) CountPullRequests(ctx context.Context, f entity.PRListFilter) (int64, error) {
+	var n int64
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM pull_requests WHERE "+_prFilterWhere,
+		string(f.Status), f.AuthorID, f.TeamName, f.CreatedAfter, f.CreatedBefore).Scan(&n)
+	return n, err
+}
+
+// PullRequestsUpdatedSince reports whether any PR matching f was updated
+// after t.
+func (r *SearchRepo) PullRequestsUpdatedSince(ctx context.Context, f entity.PRListFilter, t time.Time) (bool, error) {
+	var changed bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pull_requests WHERE "+_prFilterWhere+" AND updated_at > $6)",
+		string(f.Status), f.AuthorID, f.TeamName, f.CreatedAfter, f.CreatedBefore, t).Scan(&changed)
+	return changed, err
+}
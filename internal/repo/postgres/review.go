@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+// ReviewRepo stores the decisions reviewers submit on PRs.
+type ReviewRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) ReviewRepo() *ReviewRepo {
+	return &ReviewRepo{db: p.db}
+}
+
+// Create stores rv and fills in its ID and CreatedAt.
+func (r *ReviewRepo) Create(ctx context.Context, rv *entity.Review) error {
+	query := `
+		INSERT INTO reviews (pull_request_id, reviewer_id, decision, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	if err := r.db.QueryRow(ctx, query, rv.PullRequestID, rv.ReviewerID, rv.Decision, rv.Body).Scan(&rv.ID, &rv.CreatedAt); err != nil {
+		return err
+	}
+
+	rv.CreatedAt = rv.CreatedAt.UTC()
+	return nil
+}
+
+// ListByPR returns every review submitted on the PR, oldest first.
+func (r *ReviewRepo) ListByPR(ctx context.Context, prID string) ([]entity.Review, error) {
+	query := `
+		SELECT id, pull_request_id, reviewer_id, decision, body, created_at
+		FROM reviews
+		WHERE pull_request_id = $1
+		ORDER BY created_at, id
+	`
+	return r.list(ctx, query, prID)
+}
+
+// LatestByPR returns each reviewer's most recent review on the PR.
+func (r *ReviewRepo) LatestByPR(ctx context.Context, prID string) ([]entity.Review, error) {
+	query := `
+		SELECT DISTINCT ON (reviewer_id) id, pull_request_id, reviewer_id, decision, body, created_at
+		FROM reviews
+		WHERE pull_request_id = $1
+		ORDER BY reviewer_id, created_at DESC, id DESC
+	`
+	return r.list(ctx, query, prID)
+}
+
+// LatestByReviewer returns the reviewer's most recent review on each PR they reviewed.
+func (r *ReviewRepo) LatestByReviewer(ctx context.Context, reviewerID string) ([]entity.Review, error) {
+	query := `
+		SELECT DISTINCT ON (pull_request_id) id, pull_request_id, reviewer_id, decision, body, created_at
+		FROM reviews
+		WHERE reviewer_id = $1
+		ORDER BY pull_request_id, created_at DESC, id DESC
+	`
+	return r.list(ctx, query, reviewerID)
+}
+
+func (r *ReviewRepo) list(ctx context.Context, query string, arg string) ([]entity.Review, error) {
+	rows, err := r.db.Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []entity.Review{}
+	for rows.Next() {
+		var rv entity.Review
+		if err := rows.Scan(&rv.ID, &rv.PullRequestID, &rv.ReviewerID, &rv.Decision, &rv.Body, &rv.CreatedAt); err != nil {
+			return nil, err
+		}
+		rv.CreatedAt = rv.CreatedAt.UTC()
+		reviews = append(reviews, rv)
+	}
+
+	return reviews, rows.Err()
+}
+
+var _ usecase.ReviewRepo = (*ReviewRepo)(nil)
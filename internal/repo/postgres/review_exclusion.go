@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+// ReviewExclusionRepo stores the rules that keep reviewers off certain PRs.
+type ReviewExclusionRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) ReviewExclusionRepo() *ReviewExclusionRepo {
+	return &ReviewExclusionRepo{db: p.db}
+}
+
+// Create stores e and fills in its ID and CreatedAt.
+func (r *ReviewExclusionRepo) Create(ctx context.Context, e *entity.ReviewExclusion) error {
+	query := `
+		INSERT INTO review_exclusions (reviewers, authors, labels, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	if err := r.db.QueryRow(ctx, query, e.Reviewers, e.Authors, e.Labels, e.Reason).Scan(&e.ID, &e.CreatedAt); err != nil {
+		return err
+	}
+
+	e.CreatedAt = e.CreatedAt.UTC()
+	return nil
+}
+
+// List returns every exclusion, oldest first.
+func (r *ReviewExclusionRepo) List(ctx context.Context) ([]entity.ReviewExclusion, error) {
+	query := `
+		SELECT id, reviewers, authors, labels, reason, created_at
+		FROM review_exclusions
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exclusions := []entity.ReviewExclusion{}
+	for rows.Next() {
+		var e entity.ReviewExclusion
+		if err := rows.Scan(&e.ID, &e.Reviewers, &e.Authors, &e.Labels, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.CreatedAt = e.CreatedAt.UTC()
+		exclusions = append(exclusions, e)
+	}
+
+	return exclusions, rows.Err()
+}
+
+func (r *ReviewExclusionRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM review_exclusions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+var _ usecase.ReviewExclusionRepo = (*ReviewExclusionRepo)(nil)
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+// ReviewRuleRepo stores per-repository path rules for reviewer selection.
+type ReviewRuleRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) ReviewRuleRepo() *ReviewRuleRepo {
+	return &ReviewRuleRepo{db: p.db}
+}
+
+// Upsert stores rule, replacing the reviewers of an existing rule with the
+// same repository and pattern, and fills in its ID and CreatedAt.
+func (r *ReviewRuleRepo) Upsert(ctx context.Context, rule *entity.ReviewRule) error {
+	query := `
+		INSERT INTO review_rules (repository, pattern, reviewers)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (repository, pattern) DO UPDATE SET reviewers = EXCLUDED.reviewers
+		RETURNING id, created_at
+	`
+	if err := r.db.QueryRow(ctx, query, rule.Repository, rule.Pattern, rule.Reviewers).Scan(&rule.ID, &rule.CreatedAt); err != nil {
+		return err
+	}
+
+	rule.CreatedAt = rule.CreatedAt.UTC()
+	return nil
+}
+
+// ListByRepository returns the repository's rules, oldest first.
+func (r *ReviewRuleRepo) ListByRepository(ctx context.Context, repository string) ([]entity.ReviewRule, error) {
+	query := `
+		SELECT id, repository, pattern, reviewers, created_at
+		FROM review_rules WHERE repository = $1
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []entity.ReviewRule{}
+	for rows.Next() {
+		var rule entity.ReviewRule
+		if err := rows.Scan(&rule.ID, &rule.Repository, &rule.Pattern, &rule.Reviewers, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rule.CreatedAt = rule.CreatedAt.UTC()
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func (r *ReviewRuleRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM review_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+var _ usecase.ReviewRuleRepo = (*ReviewRuleRepo)(nil)
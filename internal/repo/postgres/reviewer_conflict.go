@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+const _defaultConflictLimit = 100
+
+// ReviewerConflictRepo records external reviewer changes that disagreed with
+// the service's assignment.
+type ReviewerConflictRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) ReviewerConflictRepo() *ReviewerConflictRepo {
+	return &ReviewerConflictRepo{db: p.db}
+}
+
+func (r *ReviewerConflictRepo) Create(ctx context.Context, c *entity.ReviewerConflict) error {
+	query := `
+		INSERT INTO reviewer_conflicts (pull_request_id, provider, reviewer_id, change, policy, resolution, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query, c.PullRequestID, c.Provider, c.ReviewerID, c.Change, c.Policy, c.Resolution, c.Error).
+		Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	c.CreatedAt = c.CreatedAt.UTC()
+	return nil
+}
+
+// List returns conflicts matching f, newest first.
+func (r *ReviewerConflictRepo) List(ctx context.Context, f entity.ReviewerConflictFilter) ([]entity.ReviewerConflict, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = _defaultConflictLimit
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, pull_request_id, provider, reviewer_id, change, policy, resolution, error, created_at
+		FROM reviewer_conflicts
+		WHERE ($1 = '' OR pull_request_id = $1) AND ($2 = '' OR provider = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`, f.PullRequestID, f.Provider, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conflicts := []entity.ReviewerConflict{}
+	for rows.Next() {
+		var c entity.ReviewerConflict
+		if err := rows.Scan(&c.ID, &c.PullRequestID, &c.Provider, &c.ReviewerID, &c.Change, &c.Policy,
+			&c.Resolution, &c.Error, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.CreatedAt = c.CreatedAt.UTC()
+		conflicts = append(conflicts, c)
+	}
+
+	return conflicts, rows.Err()
+}
+
+var _ usecase.ReviewerConflictRepo = (*ReviewerConflictRepo)(nil)
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+// RotationRepo keeps each team's round-robin cursor: the last member picked.
+type RotationRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) RotationRepo() *RotationRepo {
+	return &RotationRepo{db: p.db}
+}
+
+// Next returns up to limit active members of teamName, skipping exclude, in
+// user_id order starting just after the cursor and wrapping around. The
+// cursor row is locked, so concurrent assignments in a transaction take turns.
+func (r *RotationRepo) Next(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error) {
+	query := `
+		WITH cursor AS (
+			SELECT last_user_id FROM team_rotation WHERE team_name = $1 FOR UPDATE
+		)
+		SELECT ` + userColumns + `
+		FROM users u
+		WHERE u.team_name = $1 AND u.is_active AND u.user_id <> ALL($2)
+		ORDER BY COALESCE(u.user_id <= (SELECT last_user_id FROM cursor), false), u.user_id
+		LIMIT $3
+	`
+	if exclude == nil {
+		exclude = []string{}
+	}
+
+	rows, err := r.db.Query(ctx, query, teamName, exclude, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectUsers(rows)
+}
+
+// Advance moves the team's cursor to userID.
+func (r *RotationRepo) Advance(ctx context.Context, teamName, userID string) error {
+	query := `
+		INSERT INTO team_rotation (team_name, last_user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name) DO UPDATE SET
+			last_user_id = EXCLUDED.last_user_id,
+			updated_at = now()
+	`
+	_, err := r.db.Exec(ctx, query, teamName, userID)
+	return err
+}
+
+var _ usecase.RotationRepo = (*RotationRepo)(nil)
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// SchemaRepo reads the migration state golang-migrate keeps in schema_migrations.
+type SchemaRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) SchemaRepo() *SchemaRepo {
+	return &SchemaRepo{db: p.db}
+}
+
+// Version returns the applied migration version and whether the last
+// migration failed midway. A database that was never migrated is ErrNotFound.
+func (r *SchemaRepo) Version(ctx context.Context) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err := r.db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err == pgx.ErrNoRows {
+		return 0, false, ErrNotFound
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return uint(version), dirty, nil
+}
+
+var _ usecase.SchemaRepo = (*SchemaRepo)(nil)
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// StatsHistoryRepo keeps daily per-team stats snapshots.
+type StatsHistoryRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) StatsHistoryRepo() *StatsHistoryRepo {
+	return &StatsHistoryRepo{db: p.db}
+}
+
+const statsHistoryColumns = `team_name, snapshot_date, total_prs, open_prs, merged_prs, closed_prs,
+	members, active_members, average_reviewers, average_review_turnaround_hours, taken_at`
+
+// Snapshot records every team's current stats under day, replacing a snapshot
+// already taken that day, and returns how many teams were recorded.
+func (r *StatsHistoryRepo) Snapshot(ctx context.Context, day time.Time) (int, error) {
+	query := `
+		WITH team_names AS (
+			SELECT team_name FROM teams
+			UNION
+			SELECT team_name FROM users WHERE team_name IS NOT NULL AND team_name <> ''
+		), team_prs AS (
+			SELECT u.team_name, p.pull_request_id, p.status, p.assigned_reviewers, p.created_at
+			FROM pull_requests p
+			JOIN users u ON u.user_id = p.author_id
+		)
+		INSERT INTO stats_history (team_name, snapshot_date, total_prs, open_prs, merged_prs, closed_prs,
+			members, active_members, average_reviewers, average_review_turnaround_hours)
+		SELECT t.team_name, $1::date,
+			(SELECT COUNT(*) FROM team_prs p WHERE p.team_name = t.team_name),
+			(SELECT COUNT(*) FROM team_prs p WHERE p.team_name = t.team_name AND p.status = 'OPEN'),
+			(SELECT COUNT(*) FROM team_prs p WHERE p.team_name = t.team_name AND p.status = 'MERGED'),
+			(SELECT COUNT(*) FROM team_prs p WHERE p.team_name = t.team_name AND p.status = 'CLOSED'),
+			(SELECT COUNT(*) FROM users u WHERE u.team_name = t.team_name AND u.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM users u WHERE u.team_name = t.team_name AND u.is_active),
+			(SELECT COALESCE(AVG(jsonb_array_length(p.assigned_reviewers)), 0)::float8
+			 FROM team_prs p WHERE p.team_name = t.team_name),
+			(SELECT COALESCE(AVG(EXTRACT(EPOCH FROM f.first_at - p.created_at)) / 3600, 0)::float8
+			 FROM (SELECT pull_request_id, MIN(created_at) AS first_at FROM reviews GROUP BY pull_request_id, reviewer_id) f
+			 JOIN team_prs p USING (pull_request_id)
+			 WHERE p.team_name = t.team_name)
+		FROM team_names t
+		ON CONFLICT (team_name, snapshot_date) DO UPDATE SET
+			total_prs = EXCLUDED.total_prs,
+			open_prs = EXCLUDED.open_prs,
+			merged_prs = EXCLUDED.merged_prs,
+			closed_prs = EXCLUDED.closed_prs,
+			members = EXCLUDED.members,
+			active_members = EXCLUDED.active_members,
+			average_reviewers = EXCLUDED.average_reviewers,
+			average_review_turnaround_hours = EXCLUDED.average_review_turnaround_hours,
+			taken_at = now()
+	`
+	tag, err := r.db.Exec(ctx, query, day.UTC().Format(time.DateOnly))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// List returns the snapshots taken between from and to inclusive, oldest
+// first; an empty teamName covers every team.
+func (r *StatsHistoryRepo) List(ctx context.Context, teamName string, from, to time.Time) ([]entity.TeamStatsSnapshot, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+statsHistoryColumns+`
+		FROM stats_history
+		WHERE ($1 = '' OR team_name = $1) AND snapshot_date BETWEEN $2::date AND $3::date
+		ORDER BY snapshot_date, team_name
+	`, teamName, from.UTC().Format(time.DateOnly), to.UTC().Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+
+	return collectStatsSnapshots(rows)
+}
+
+// AsOf returns, per team, the latest snapshot taken on or before day.
+func (r *StatsHistoryRepo) AsOf(ctx context.Context, teamName string, day time.Time) ([]entity.TeamStatsSnapshot, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT ON (team_name) `+statsHistoryColumns+`
+		FROM stats_history
+		WHERE ($1 = '' OR team_name = $1) AND snapshot_date <= $2::date
+		ORDER BY team_name, snapshot_date DESC
+	`, teamName, day.UTC().Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+
+	return collectStatsSnapshots(rows)
+}
+
+func collectStatsSnapshots(rows pgx.Rows) ([]entity.TeamStatsSnapshot, error) {
+	defer rows.Close()
+
+	snapshots := []entity.TeamStatsSnapshot{}
+	for rows.Next() {
+		var s entity.TeamStatsSnapshot
+		if err := rows.Scan(&s.TeamName, &s.Date, &s.TotalPRs, &s.OpenPRs, &s.MergedPRs, &s.ClosedPRs,
+			&s.Members, &s.ActiveMembers, &s.AverageReviewers, &s.AverageReviewTurnaroundHours, &s.TakenAt); err != nil {
+			return nil, err
+		}
+		s.Date = s.Date.UTC()
+		s.TakenAt = s.TakenAt.UTC()
+		snapshots = append(snapshots, s)
+	}
+
+	return snapshots, rows.Err()
+}
+
+var _ usecase.StatsHistoryRepo = (*StatsHistoryRepo)(nil)
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/evrone/go-clean-template/pkg/taskqueue"
+	"github.com/jackc/pgx/v5"
+)
+
+const _defaultTaskLimit = 100
+
+// TaskRepo stores the task queue. Enqueue joins the transaction in ctx;
+// claiming uses SKIP LOCKED, so any number of workers can poll at once. The
+// attempt count a claim sets is its token: Complete and Fail match it, so a
+// worker whose claim expired can't touch the task's next run.
+type TaskRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) TaskRepo() *TaskRepo {
+	return &TaskRepo{db: p.db}
+}
+
+const taskColumns = `id, kind, ordering_key, payload, status, attempts, max_attempts, run_at, last_error, created_at, updated_at`
+
+func (r *TaskRepo) Enqueue(ctx context.Context, t *taskqueue.Task) error {
+	query := `
+		INSERT INTO tasks (kind, ordering_key, payload, max_attempts)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + taskColumns
+
+	return scanTask(r.db.QueryRow(ctx, query, t.Kind, t.Key, t.Payload, t.MaxAttempts), t)
+}
+
+// Claim takes the oldest due task of kinds: a pending one, or a running one
+// whose visibility timeout has expired. A keyed task is skipped while an
+// earlier task with its key is still pending or running.
+func (r *TaskRepo) Claim(ctx context.Context, kinds []string, visibility time.Duration) (*taskqueue.Task, error) {
+	query := `
+		UPDATE tasks
+		SET status = 'running', attempts = attempts + 1,
+		    run_at = now() + make_interval(secs => $2), updated_at = now()
+		WHERE id = (
+			SELECT id FROM tasks t
+			WHERE status <> 'dead' AND run_at <= now() AND kind = ANY($1)
+			  AND (ordering_key = '' OR NOT EXISTS (
+			      SELECT 1 FROM tasks e
+			      WHERE e.ordering_key = t.ordering_key AND e.id < t.id AND e.status <> 'dead'
+			  ))
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + taskColumns
+
+	var t taskqueue.Task
+	err := scanTask(r.db.QueryRow(ctx, query, kinds, visibility.Seconds()), &t)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (r *TaskRepo) Complete(ctx context.Context, id int64, attempt int) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM tasks WHERE id = $1 AND attempts = $2 AND status = 'running'", id, attempt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return taskqueue.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *TaskRepo) Fail(ctx context.Context, id int64, attempt int, errText string, retryAt time.Time, dead bool) error {
+	query := `
+		UPDATE tasks
+		SET status = CASE WHEN $5 THEN 'dead' ELSE 'pending' END,
+		    run_at = $4, last_error = $3, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'
+	`
+	tag, err := r.db.Exec(ctx, query, id, attempt, errText, retryAt, dead)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return taskqueue.ErrNotFound
+	}
+
+	return nil
+}
+
+// List returns tasks matching status and kind (either empty for all), most
+// recently updated first.
+func (r *TaskRepo) List(ctx context.Context, status, kind string, limit int) ([]taskqueue.Task, error) {
+	if limit <= 0 {
+		limit = _defaultTaskLimit
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR kind = $2)
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $3
+	`, status, kind, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []taskqueue.Task{}
+	for rows.Next() {
+		var t taskqueue.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+
+	return tasks, rows.Err()
+}
+
+func (r *TaskRepo) Retry(ctx context.Context, id int64) error {
+	query := `
+		UPDATE tasks
+		SET status = 'pending', attempts = 0, run_at = now(), updated_at = now()
+		WHERE id = $1 AND status = 'dead'
+	`
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return taskqueue.ErrNotFound
+	}
+
+	return nil
+}
+
+func scanTask(row pgx.Row, t *taskqueue.Task) error {
+	err := row.Scan(&t.ID, &t.Kind, &t.Key, &t.Payload, &t.Status, &t.Attempts, &t.MaxAttempts,
+		&t.RunAt, &t.LastError, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	t.RunAt, t.CreatedAt, t.UpdatedAt = t.RunAt.UTC(), t.CreatedAt.UTC(), t.UpdatedAt.UTC()
+	return nil
+}
+
+var _ taskqueue.Store = (*TaskRepo)(nil)
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type TeamSettingsRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) TeamSettingsRepo() *TeamSettingsRepo {
+	return &TeamSettingsRepo{db: p.db}
+}
+
+func (r *TeamSettingsRepo) Get(ctx context.Context, teamName string) (entity.TeamSettings, error) {
+	query := `
+		SELECT team_name, max_open_prs_per_author, quota_mode, slack_enabled, assignment_strategy,
+			required_approvals, require_all_approvals, slack_channel, stack_reviewers, updated_at
+		FROM team_settings WHERE team_name = $1
+	`
+	var s entity.TeamSettings
+
+	err := r.db.QueryRow(ctx, query, teamName).Scan(
+		&s.TeamName, &s.MaxOpenPRsPerAuthor, &s.QuotaMode, &s.SlackEnabled, &s.AssignmentStrategy,
+		&s.RequiredApprovals, &s.RequireAllApprovals, &s.SlackChannel, &s.StackReviewers, &s.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return entity.TeamSettings{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.TeamSettings{}, err
+	}
+
+	s.UpdatedAt = s.UpdatedAt.UTC()
+	return s, nil
+}
+
+// Upsert stores the team's non-nil settings and resets the fields named in
+// clear, keeping the rest, then reads the whole row back into s.
+func (r *TeamSettingsRepo) Upsert(ctx context.Context, s *entity.TeamSettings, clear []string) error {
+	query := `
+		INSERT INTO team_settings (team_name, max_open_prs_per_author, quota_mode, slack_enabled, assignment_strategy,
+			required_approvals, require_all_approvals, slack_channel, stack_reviewers)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (team_name) DO UPDATE SET
+			max_open_prs_per_author = CASE WHEN 'max_open_prs_per_author' = ANY($10) THEN NULL
+				ELSE COALESCE(EXCLUDED.max_open_prs_per_author, team_settings.max_open_prs_per_author) END,
+			quota_mode = CASE WHEN 'quota_mode' = ANY($10) THEN NULL
+				ELSE COALESCE(EXCLUDED.quota_mode, team_settings.quota_mode) END,
+			slack_enabled = CASE WHEN 'slack_enabled' = ANY($10) THEN NULL
+				ELSE COALESCE(EXCLUDED.slack_enabled, team_settings.slack_enabled) END,
+			assignment_strategy = CASE WHEN 'assignment_strategy' = ANY($10) THEN NULL
+				ELSE COALESCE(EXCLUDED.assignment_strategy, team_settings.assignment_strategy) END,
+			required_approvals = CASE WHEN 'required_approvals' = ANY($10) THEN NULL
+				ELSE COALESCE(EXCLUDED.required_approvals, team_settings.required_approvals) END,
+			require_all_approvals = CASE WHEN 'require_all_approvals' = ANY($10) THEN NULL
+				ELSE COALESCE(EXCLUDED.require_all_approvals, team_settings.require_all_approvals) END,
+			slack_channel = CASE WHEN 'slack_channel' = ANY($10) THEN NULL
+				ELSE COALESCE(EXCLUDED.slack_channel, team_settings.slack_channel) END,
+			stack_reviewers = CASE WHEN 'stack_reviewers' = ANY($10) THEN NULL
+				ELSE COALESCE(EXCLUDED.stack_reviewers, team_settings.stack_reviewers) END,
+			updated_at = now()
+		RETURNING max_open_prs_per_author, quota_mode, slack_enabled, assignment_strategy,
+			required_approvals, require_all_approvals, slack_channel, stack_reviewers, updated_at
+	`
+	err := r.db.QueryRow(ctx, query, s.TeamName, s.MaxOpenPRsPerAuthor, s.QuotaMode, s.SlackEnabled, s.AssignmentStrategy,
+		s.RequiredApprovals, s.RequireAllApprovals, s.SlackChannel, s.StackReviewers, nonNil(clear)).Scan(
+		&s.MaxOpenPRsPerAuthor, &s.QuotaMode, &s.SlackEnabled, &s.AssignmentStrategy,
+		&s.RequiredApprovals, &s.RequireAllApprovals, &s.SlackChannel, &s.StackReviewers, &s.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.UpdatedAt = s.UpdatedAt.UTC()
+	return nil
+}
+
+var _ usecase.TeamSettingsRepo = (*TeamSettingsRepo)(nil)
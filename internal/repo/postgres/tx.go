@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type txKey struct{}
+
+// TxManager runs use case operations in a single database transaction.
+// Repositories created by the same Postgres join the transaction found in ctx.
+type TxManager struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) TxManager() *TxManager {
+	return &TxManager{db: p.base}
+}
+
+// WithinTx calls fn with a ctx bound to a new transaction, committing when fn
+// returns nil and rolling back otherwise. Nested calls join the outer transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			return errors.Join(err, fmt.Errorf("rollback: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return nil
+}
+
+// txDB sends queries to the transaction in ctx, if any, and to the pool otherwise.
+type txDB struct {
+	pgdb.DB
+}
+
+func (d txDB) conn(ctx context.Context) pgdb.DB {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return txConn{tx}
+	}
+	return d.DB
+}
+
+func (d txDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return d.conn(ctx).Exec(ctx, sql, args...)
+}
+
+func (d txDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return d.conn(ctx).Query(ctx, sql, args...)
+}
+
+func (d txDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return d.conn(ctx).QueryRow(ctx, sql, args...)
+}
+
+// Begin opens a savepoint when called inside a transaction.
+func (d txDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return d.conn(ctx).Begin(ctx)
+}
+
+// txConn adapts pgx.Tx to pgdb.DB.
+type txConn struct {
+	pgx.Tx
+}
+
+func (c txConn) Ping(ctx context.Context) error {
+	return c.Tx.Conn().Ping(ctx)
+}
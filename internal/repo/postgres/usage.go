@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+)
+
+// UsageRepo keeps hourly per-client request counters.
+type UsageRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) UsageRepo() *UsageRepo {
+	return &UsageRepo{db: p.db}
+}
+
+var _ usecase.UsageRepo = (*UsageRepo)(nil)
+
+// Add folds buckets into the stored counters.
+func (r *UsageRepo) Add(ctx context.Context, buckets []entity.UsageBucket) error {
+	query := `
+		INSERT INTO client_usage (client_id, method, route, bucket, requests, errors, latency_ms, max_latency_ms)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (client_id, method, route, bucket) DO UPDATE SET
+			requests = client_usage.requests + EXCLUDED.requests,
+			errors = client_usage.errors + EXCLUDED.errors,
+			latency_ms = client_usage.latency_ms + EXCLUDED.latency_ms,
+			max_latency_ms = GREATEST(client_usage.max_latency_ms, EXCLUDED.max_latency_ms)
+	`
+	for _, b := range buckets {
+		if _, err := r.db.Exec(ctx, query, b.ClientID, b.Method, b.Route, b.Bucket.UTC(),
+			b.Requests, b.Errors, b.LatencyMs, b.MaxLatencyMs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List returns the counters of buckets starting at or after since, summed per
+// client and route. An empty clientID returns every client.
+func (r *UsageRepo) List(ctx context.Context, since time.Time, clientID string) ([]entity.UsageBucket, error) {
+	query := `
+		SELECT client_id, method, route, min(bucket), sum(requests), sum(errors), sum(latency_ms), max(max_latency_ms)
+		FROM client_usage
+		WHERE bucket >= date_trunc('hour', $1::timestamptz) AND ($2 = '' OR client_id = $2)
+		GROUP BY client_id, method, route
+		ORDER BY client_id, method, route
+	`
+	rows, err := r.db.Query(ctx, query, since.UTC(), clientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []entity.UsageBucket{}
+	for rows.Next() {
+		var b entity.UsageBucket
+		if err := rows.Scan(&b.ClientID, &b.Method, &b.Route, &b.Bucket, &b.Requests, &b.Errors, &b.LatencyMs, &b.MaxLatencyMs); err != nil {
+			return nil, err
+		}
+		b.Bucket = b.Bucket.UTC()
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/internal/usecase"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+const _defaultDeliveryLimit = 100
+
+type WebhookDeliveryRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) WebhookDeliveryRepo() *WebhookDeliveryRepo {
+	return &WebhookDeliveryRepo{db: p.db}
+}
+
+func (r *WebhookDeliveryRepo) Create(ctx context.Context, d entity.WebhookDelivery) (int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (provider, delivery_id, event, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	var id int64
+
+	err := r.db.QueryRow(ctx, query, d.Provider, d.DeliveryID, d.Event, []byte(d.Payload)).Scan(&id)
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") {
+			return 0, ErrAlreadyExists
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *WebhookDeliveryRepo) GetByID(ctx context.Context, id int64) (entity.WebhookDelivery, error) {
+	query := `
+		SELECT id, provider, delivery_id, event, payload, received_at,
+		       processed_at, attempts, last_error
+		FROM webhook_deliveries WHERE id = $1
+	`
+
+	d, err := scanDelivery(r.db.QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return entity.WebhookDelivery{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	return d, nil
+}
+
+func (r *WebhookDeliveryRepo) List(ctx context.Context, f entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, error) {
+	query := `
+		SELECT id, provider, delivery_id, event, payload, received_at,
+		       processed_at, attempts, last_error
+		FROM webhook_deliveries
+		WHERE ($1 = '' OR provider = $1)
+		  AND (NOT $2 OR processed_at IS NULL)
+		ORDER BY received_at DESC
+		LIMIT $3
+	`
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = _defaultDeliveryLimit
+	}
+
+	rows, err := r.db.Query(ctx, query, f.Provider, f.FailedOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []entity.WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *WebhookDeliveryRepo) MarkAttempt(ctx context.Context, id int64, processErr error) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1,
+		    processed_at = CASE WHEN $2::text IS NULL THEN now() ELSE processed_at END,
+		    last_error = $2
+		WHERE id = $1
+	`
+
+	var lastError *string
+	if processErr != nil {
+		msg := processErr.Error()
+		lastError = &msg
+	}
+
+	result, err := r.db.Exec(ctx, query, id, lastError)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanDelivery(row pgx.Row) (entity.WebhookDelivery, error) {
+	var d entity.WebhookDelivery
+	var payload []byte
+	var processedAt sql.NullTime
+	var lastError sql.NullString
+
+	if err := row.Scan(
+		&d.ID, &d.Provider, &d.DeliveryID, &d.Event, &payload, &d.ReceivedAt,
+		&processedAt, &d.Attempts, &lastError,
+	); err != nil {
+		return entity.WebhookDelivery{}, err
+	}
+
+	d.Payload = payload
+	d.ReceivedAt = d.ReceivedAt.UTC()
+	if processedAt.Valid {
+		t := processedAt.Time.UTC()
+		d.ProcessedAt = &t
+	}
+	d.LastError = lastError.String
+
+	return d, nil
+}
+
+var _ usecase.WebhookDeliveryRepo = (*WebhookDeliveryRepo)(nil)
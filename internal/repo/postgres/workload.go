@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	pgdb "github.com/evrone/go-clean-template/pkg/postgres"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// workloadRefreshedAt is the latest refreshed_at this process has seen in
+// reviewer_workload, in unix nanoseconds. Reads update it too, so prefork
+// children that never refresh still report staleness.
+var workloadRefreshedAt atomic.Int64
+
+var _ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+	Name: "pr_service_workload_staleness_seconds",
+	Help: "Age of the reviewer_workload materialized view as last observed by this process.",
+}, func() float64 {
+	at := workloadRefreshedAt.Load()
+	if at == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, at)).Seconds()
+})
+
+func observeWorkload(at time.Time) {
+	n := at.UnixNano()
+	for {
+		cur := workloadRefreshedAt.Load()
+		if n <= cur || workloadRefreshedAt.CompareAndSwap(cur, n) {
+			return
+		}
+	}
+}
+
+// WorkloadRepo reads the reviewer_workload materialized view of open review counts.
+type WorkloadRepo struct {
+	db pgdb.DB
+}
+
+func (p *Postgres) WorkloadRepo() *WorkloadRepo {
+	return &WorkloadRepo{db: p.db}
+}
+
+// Refresh recomputes the view without blocking readers and returns the new refresh time.
+func (r *WorkloadRepo) Refresh(ctx context.Context) (time.Time, error) {
+	if _, err := r.db.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY reviewer_workload"); err != nil {
+		return time.Time{}, err
+	}
+
+	var at time.Time
+	if err := r.db.QueryRow(ctx, "SELECT COALESCE(max(refreshed_at), now()) FROM reviewer_workload").Scan(&at); err != nil {
+		return time.Time{}, err
+	}
+
+	observeWorkload(at)
+	return at.UTC(), nil
+}
+
+// ListByTeam returns the load of each active member of teamName, least loaded first.
+// Members added since the last refresh count as having no reviews.
+func (r *WorkloadRepo) ListByTeam(ctx context.Context, teamName string) ([]entity.ReviewerLoad, time.Time, error) {
+	query := `
+		SELECT u.user_id, COALESCE(w.open_reviews, 0), u.max_open_reviews, w.refreshed_at
+		FROM users u
+		LEFT JOIN reviewer_workload w USING (user_id)
+		WHERE u.team_name = $1 AND u.is_active
+		ORDER BY 2, u.user_id
+	`
+	rows, err := r.db.Query(ctx, query, teamName)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer rows.Close()
+
+	loads := []entity.ReviewerLoad{}
+	var refreshedAt time.Time
+
+	for rows.Next() {
+		var (
+			l  entity.ReviewerLoad
+			at *time.Time
+		)
+		if err := rows.Scan(&l.UserID, &l.OpenReviews, &l.MaxOpenReviews, &at); err != nil {
+			return nil, time.Time{}, err
+		}
+		if at != nil && at.After(refreshedAt) {
+			refreshedAt = *at
+		}
+		loads = append(loads, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if !refreshedAt.IsZero() {
+		observeWorkload(refreshedAt)
+	}
+
+	return loads, refreshedAt.UTC(), nil
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var ErrInvalidAbsence = errors.New("invalid absence")
+
+type noAbsences struct{}
+
+func (noAbsences) Create(context.Context, *entity.Absence) error {
+	return fmt.Errorf("absences are not configured")
+}
+
+func (noAbsences) GetByID(context.Context, int64) (entity.Absence, error) {
+	return entity.Absence{}, ErrNotFound
+}
+
+func (noAbsences) Update(context.Context, *entity.Absence) error {
+	return ErrNotFound
+}
+
+func (noAbsences) Delete(context.Context, int64) error {
+	return ErrNotFound
+}
+
+func (noAbsences) ListByUser(context.Context, string, time.Time) ([]entity.Absence, error) {
+	return []entity.Absence{}, nil
+}
+
+func (noAbsences) ListCovering(context.Context, time.Time) ([]entity.Absence, error) {
+	return []entity.Absence{}, nil
+}
+
+// AbsenceUpdate changes the fields that are set and keeps the nil ones.
+type AbsenceUpdate struct {
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	Reason   *string
+}
+
+// ListAbsences returns the user's current and upcoming absences.
+func (uc *PRUseCase) ListAbsences(ctx context.Context, userID string) ([]entity.Absence, error) {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, lookupErr(err)
+	}
+
+	return uc.absences.ListByUser(ctx, userID, time.Now())
+}
+
+// CreateAbsence records an absence. One already in progress hands the user's
+// open reviews over right away, in the same transaction.
+func (uc *PRUseCase) CreateAbsence(ctx context.Context, a entity.Absence) (entity.Absence, error) {
+	u, err := uc.userRepo.GetByID(ctx, a.UserID)
+	if err != nil {
+		return entity.Absence{}, lookupErr(err)
+	}
+
+	if err := uc.authorize(ctx, u.TeamName, entity.ScopeCapacity, u.UserID); err != nil {
+		return entity.Absence{}, err
+	}
+
+	if err := validateAbsence(&a); err != nil {
+		return entity.Absence{}, err
+	}
+
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.absences.Create(ctx, &a); err != nil {
+			return err
+		}
+
+		// The absence was authorized as a whole; the hand-over it triggers
+		// isn't checked again against the actor.
+		a, err = uc.releaseIfStarted(withoutActor(ctx), a, time.Now())
+		return err
+	})
+	if err != nil {
+		return entity.Absence{}, err
+	}
+
+	return a, nil
+}
+
+// UpdateAbsence -. Moving the start re-arms the hand-over of open reviews.
+func (uc *PRUseCase) UpdateAbsence(ctx context.Context, id int64, upd AbsenceUpdate) (entity.Absence, error) {
+	a, err := uc.absences.GetByID(ctx, id)
+	if err != nil {
+		return entity.Absence{}, lookupErr(err)
+	}
+
+	if upd.StartsAt != nil && !upd.StartsAt.Equal(a.StartsAt) {
+		a.StartsAt = *upd.StartsAt
+		a.ReleasedAt = nil
+	}
+	if upd.EndsAt != nil {
+		a.EndsAt = *upd.EndsAt
+	}
+	if upd.Reason != nil {
+		a.Reason = *upd.Reason
+	}
+
+	if err := validateAbsence(&a); err != nil {
+		return entity.Absence{}, err
+	}
+
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.absences.Update(ctx, &a); err != nil {
+			return lookupErr(err)
+		}
+
+		a, err = uc.releaseIfStarted(ctx, a, time.Now())
+		return err
+	})
+	if err != nil {
+		return entity.Absence{}, err
+	}
+
+	return a, nil
+}
+
+func (uc *PRUseCase) DeleteAbsence(ctx context.Context, id int64) error {
+	return lookupErr(uc.absences.Delete(ctx, id))
+}
+
+type AbsenceReport struct {
+	Released []int64 `json:"released"`
+}
+
+// StartAbsences hands over the open reviews of users whose absence is in
+// progress at now and has not been handled yet. Each absence is handed over
+// in its own transaction; one that fails is left for the next run and does
+// not stop the others, and the errors are returned together.
+func (uc *PRUseCase) StartAbsences(ctx context.Context, now time.Time) (AbsenceReport, error) {
+	report := AbsenceReport{Released: []int64{}}
+
+	covering, err := uc.absences.ListCovering(ctx, now.UTC())
+	if err != nil {
+		return report, err
+	}
+
+	var errs []error
+	for _, a := range covering {
+		if a.ReleasedAt != nil {
+			continue
+		}
+		if _, err := uc.releaseIfStarted(ctx, a, now); err != nil {
+			errs = append(errs, fmt.Errorf("absence %d: %w", a.ID, err))
+			continue
+		}
+		report.Released = append(report.Released, a.ID)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// releaseIfStarted reassigns the absent user's pending reviews when a covers
+// now and that has not happened yet, then marks a released. Notifications and
+// VCS calls wait for the commit.
+func (uc *PRUseCase) releaseIfStarted(ctx context.Context, a entity.Absence, now time.Time) (entity.Absence, error) {
+	now = now.UTC()
+	if a.ReleasedAt != nil || !a.Covers(now) {
+		return a, nil
+	}
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		u, err := uc.userRepo.GetByID(ctx, a.UserID)
+		if err != nil {
+			return lookupErr(err)
+		}
+
+		if _, _, err := uc.releaseReviews(ctx, u); err != nil {
+			return err
+		}
+
+		a.ReleasedAt = &now
+		return uc.absences.Update(ctx, &a)
+	})
+	if err != nil {
+		return entity.Absence{}, err
+	}
+
+	return a, nil
+}
+
+// absentAt returns the users whose absence covers t.
+func (uc *PRUseCase) absentAt(ctx context.Context, t time.Time) ([]string, error) {
+	covering, err := uc.absences.ListCovering(ctx, t.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(covering))
+	for _, a := range covering {
+		if !contains(ids, a.UserID) {
+			ids = append(ids, a.UserID)
+		}
+	}
+
+	return ids, nil
+}
+
+func (uc *PRUseCase) isAbsent(ctx context.Context, userID string, t time.Time) (bool, error) {
+	absences, err := uc.absences.ListByUser(ctx, userID, t.UTC())
+	if err != nil {
+		return false, err
+	}
+
+	for _, a := range absences {
+		if a.Covers(t) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func validateAbsence(a *entity.Absence) error {
+	a.Reason = strings.TrimSpace(a.Reason)
+	a.StartsAt, a.EndsAt = a.StartsAt.UTC(), a.EndsAt.UTC()
+
+	if a.StartsAt.IsZero() || a.EndsAt.IsZero() {
+		return fmt.Errorf("%w: starts_at and ends_at required", ErrInvalidAbsence)
+	}
+	if !a.EndsAt.After(a.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidAbsence)
+	}
+
+	return nil
+}
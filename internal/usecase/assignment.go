@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// AssignmentStrategy ranks reviewer candidates. Teams choose one by Name via
+// their settings; custom strategies are registered with WithStrategy.
+type AssignmentStrategy interface {
+	Name() string
+	// Rank returns up to limit active members of teamName not in exclude, best first.
+	Rank(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error)
+	// Assigned is told which of the ranked members became reviewers. It runs
+	// in the assigning transaction, if any.
+	Assigned(ctx context.Context, teamName string, reviewers []string) error
+}
+
+type noRotation struct{}
+
+func (noRotation) Next(context.Context, string, []string, int) ([]entity.User, error) {
+	return []entity.User{}, nil
+}
+
+func (noRotation) Advance(context.Context, string, string) error {
+	return nil
+}
+
+// leastLoaded shortlists members from the workload view, which may lag, and
+// re-ranks them by live open review count and then user_id, so back-to-back
+// PRs don't land on the same reviewers before the view refreshes.
+type leastLoaded struct {
+	users UserRepo
+	prs   PRRepo
+}
+
+func (leastLoaded) Name() string { return entity.AssignmentLeastLoaded }
+
+func (s leastLoaded) Rank(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error) {
+	candidates, err := s.users.ListCandidates(ctx, teamName, exclude, limit)
+	if err != nil || len(candidates) == 0 {
+		return candidates, err
+	}
+
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.UserID
+	}
+
+	load, err := s.prs.CountOpenByReviewer(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(candidates, func(a, b entity.User) int {
+		if d := load[a.UserID] - load[b.UserID]; d != 0 {
+			return d
+		}
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return candidates, nil
+}
+
+func (leastLoaded) Assigned(context.Context, string, []string) error { return nil }
+
+// roundRobin takes members in user_id order after the last one assigned.
+type roundRobin struct {
+	rotation RotationRepo
+}
+
+func (roundRobin) Name() string { return entity.AssignmentRoundRobin }
+
+func (s roundRobin) Rank(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error) {
+	return s.rotation.Next(ctx, teamName, exclude, limit)
+}
+
+func (s roundRobin) Assigned(ctx context.Context, teamName string, reviewers []string) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+	return s.rotation.Advance(ctx, teamName, reviewers[len(reviewers)-1])
+}
+
+// random shuffles the team's active members.
+type random struct {
+	users UserRepo
+}
+
+func (random) Name() string { return entity.AssignmentRandom }
+
+func (s random) Rank(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error) {
+	members, err := s.users.ListByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := slices.DeleteFunc(members, func(u entity.User) bool {
+		return !u.IsActive || slices.Contains(exclude, u.UserID)
+	})
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	return candidates[:min(limit, len(candidates))], nil
+}
+
+func (random) Assigned(context.Context, string, []string) error { return nil }
+
+// assignmentStrategy returns the team's strategy, falling back to the
+// service default when the team has none or names an unregistered one.
+func (uc *PRUseCase) assignmentStrategy(ctx context.Context, teamName string) (AssignmentStrategy, error) {
+	s, err := uc.teamSettings.Get(ctx, teamName)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if strategy, ok := uc.strategies[s.AssignmentStrategyOr(uc.strategy)]; ok {
+		return strategy, nil
+	}
+	return uc.strategies[entity.AssignmentLeastLoaded], nil
+}
+
+// strategyNames lists the registered strategies for validation messages.
+func (uc *PRUseCase) strategyNames() []string {
+	names := make([]string, 0, len(uc.strategies))
+	for name := range uc.strategies {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/webhook"
+)
+
+// _ackBatch bounds how many overdue deliveries one retry pass resends.
+const _ackBatch = 100
+
+var (
+	ErrInvalidAck       = errors.New("invalid acknowledgment")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// AckPolicy says where receivers acknowledge reviewer.assigned deliveries, how
+// long to wait for it before sending again and how many sends to make before
+// flagging the assignment as unacknowledged.
+type AckPolicy struct {
+	URL         string
+	Timeout     time.Duration
+	MaxAttempts int
+}
+
+// AckRetryReport summarizes a RetryAssignmentAcks pass.
+type AckRetryReport struct {
+	Resent  int `json:"resent"`
+	Flagged int `json:"flagged"`
+}
+
+type noAcks struct{}
+
+func (noAcks) Create(context.Context, *entity.AssignmentAck) error {
+	return fmt.Errorf("assignment acknowledgments are not configured")
+}
+
+func (noAcks) GetByID(context.Context, string) (entity.AssignmentAck, error) {
+	return entity.AssignmentAck{}, ErrNotFound
+}
+
+func (noAcks) Update(context.Context, *entity.AssignmentAck) error { return ErrNotFound }
+
+func (noAcks) ListDue(context.Context, time.Time, int) ([]entity.AssignmentAck, error) {
+	return []entity.AssignmentAck{}, nil
+}
+
+func (noAcks) List(context.Context, entity.AssignmentAckFilter) ([]entity.AssignmentAck, error) {
+	return []entity.AssignmentAck{}, nil
+}
+
+// sendForAck delivers a reviewer.assigned event to a webhook that must
+// acknowledge it, under its own delivery ID, and starts waiting for the ack.
+// It runs after the assignment commits. When no ack URL is configured or the
+// delivery can't be recorded, the event goes out without an ack_url, as to
+// any other webhook, since nothing could take the receiver's answer.
+func (uc *PRUseCase) sendForAck(ctx context.Context, h entity.OutgoingWebhook, e entity.HookEvent) {
+	e.ID = uc.ids.New()
+
+	plain, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if uc.ackPolicy.URL == "" {
+		uc.hookSender.Send(h.URL, h.Secret, e.Event, e.ID, plain)
+		return
+	}
+
+	e.AckURL = uc.ackPolicy.URL
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	a := entity.AssignmentAck{
+		DeliveryID:    e.ID,
+		WebhookID:     h.ID,
+		TeamName:      e.TeamName,
+		PullRequestID: e.PullRequest.PullRequestID,
+		ReviewerID:    e.NewReviewerID,
+		Payload:       payload,
+		NextAttemptAt: e.OccurredAt.Add(uc.ackPolicy.Timeout),
+	}
+	if err := uc.acks.Create(ctx, &a); err != nil {
+		uc.hookSender.Send(h.URL, h.Secret, e.Event, e.ID, plain)
+		return
+	}
+
+	uc.hookSender.Send(h.URL, h.Secret, e.Event, e.ID, payload)
+}
+
+// AcknowledgeAssignment records the receiver's answer to a reviewer.assigned
+// delivery: status is entity.AckStatusApplied or entity.AckStatusRejected with
+// reason. body must be signed with the webhook's secret like the delivery
+// was. Acknowledging twice keeps the first answer; late answers to flagged
+// deliveries are accepted.
+func (uc *PRUseCase) AcknowledgeAssignment(ctx context.Context, deliveryID, status, reason string, body []byte, signature string) (entity.AssignmentAck, error) {
+	if status != entity.AckStatusApplied && status != entity.AckStatusRejected {
+		return entity.AssignmentAck{}, fmt.Errorf("%w: status must be %s or %s", ErrInvalidAck, entity.AckStatusApplied, entity.AckStatusRejected)
+	}
+
+	a, err := uc.acks.GetByID(ctx, deliveryID)
+	if err != nil {
+		return entity.AssignmentAck{}, lookupErr(err)
+	}
+
+	h, err := uc.hooks.GetByID(ctx, a.WebhookID)
+	if err != nil {
+		return entity.AssignmentAck{}, lookupErr(err)
+	}
+	if !webhook.Verify(h.Secret, body, signature) {
+		return entity.AssignmentAck{}, ErrInvalidSignature
+	}
+
+	if a.Status == entity.AckStatusApplied || a.Status == entity.AckStatusRejected {
+		return a, nil
+	}
+
+	now := time.Now().UTC()
+	a.Status = status
+	a.AckedAt = &now
+	a.Error = ""
+	if status == entity.AckStatusRejected {
+		a.Error = reason
+	}
+
+	if err := uc.acks.Update(ctx, &a); err != nil {
+		return entity.AssignmentAck{}, lookupErr(err)
+	}
+
+	return a, nil
+}
+
+// RetryAssignmentAcks resends reviewer.assigned deliveries whose
+// acknowledgment is overdue at now, and flags those that have used up their
+// attempts as unacknowledged.
+func (uc *PRUseCase) RetryAssignmentAcks(ctx context.Context, now time.Time) (AckRetryReport, error) {
+	var report AckRetryReport
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		due, err := uc.acks.ListDue(ctx, now, _ackBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range due {
+			if a.Attempts >= uc.ackPolicy.MaxAttempts {
+				a.Status = entity.AckStatusUnacknowledged
+				if err := uc.acks.Update(ctx, &a); err != nil {
+					return err
+				}
+				report.Flagged++
+				continue
+			}
+
+			if err := uc.resendForAck(ctx, &a, now); err != nil {
+				return err
+			}
+			report.Resent++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return AckRetryReport{}, err
+	}
+
+	return report, nil
+}
+
+// RetryAssignmentAck sends a delivery again right away, giving a flagged one a
+// fresh set of attempts.
+func (uc *PRUseCase) RetryAssignmentAck(ctx context.Context, deliveryID string) (entity.AssignmentAck, error) {
+	a, err := uc.acks.GetByID(ctx, deliveryID)
+	if err != nil {
+		return entity.AssignmentAck{}, lookupErr(err)
+	}
+
+	if a.Status == entity.AckStatusUnacknowledged {
+		a.Attempts = 0
+	}
+	a.Status = entity.AckStatusPending
+	a.AckedAt = nil
+	a.Error = ""
+
+	if err := uc.resendForAck(ctx, &a, time.Now().UTC()); err != nil {
+		return entity.AssignmentAck{}, err
+	}
+
+	return a, nil
+}
+
+// ListAssignmentAcks returns tracked deliveries matching f, newest first;
+// filter by entity.AckStatusUnacknowledged to see the flagged ones.
+func (uc *PRUseCase) ListAssignmentAcks(ctx context.Context, f entity.AssignmentAckFilter) ([]entity.AssignmentAck, error) {
+	return uc.acks.List(ctx, f)
+}
+
+func (uc *PRUseCase) resendForAck(ctx context.Context, a *entity.AssignmentAck, now time.Time) error {
+	h, err := uc.hooks.GetByID(ctx, a.WebhookID)
+	if err != nil {
+		return lookupErr(err)
+	}
+
+	a.Attempts++
+	a.LastSentAt = now
+	a.NextAttemptAt = now.Add(uc.ackPolicy.Timeout)
+	if err := uc.acks.Update(ctx, a); err != nil {
+		return err
+	}
+
+	id, payload := a.DeliveryID, a.Payload
+	afterCommit(ctx, func(context.Context) {
+		uc.hookSender.Send(h.URL, h.Secret, entity.HookEventReviewerAssigned, id, payload)
+	})
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/eventbus"
+)
+
+// Bus carries PR and user events to in-process subscribers: analytics, the
+// live event stream and stats cache invalidation. Events are published once
+// their change commits. It is both an EventSink and a UserEventSink.
+type Bus struct {
+	PRs   *eventbus.Topic[entity.PREvent]
+	Users *eventbus.Topic[entity.UserEvent]
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		PRs:   eventbus.NewTopic[entity.PREvent]("pr"),
+		Users: eventbus.NewTopic[entity.UserEvent]("user"),
+	}
+}
+
+func (b *Bus) Publish(_ context.Context, e entity.PREvent) {
+	b.PRs.Publish(e)
+}
+
+func (b *Bus) PublishUser(_ context.Context, e entity.UserEvent) {
+	b.Users.Publish(e)
+}
+
+// Close closes every subscription.
+func (b *Bus) Close() {
+	b.PRs.Close()
+	b.Users.Close()
+}
+
+var (
+	_ EventSink     = (*Bus)(nil)
+	_ UserEventSink = (*Bus)(nil)
+)
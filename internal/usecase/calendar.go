@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// ReviewCalendar lists the user's open review assignments with due dates
+// derived from the review SLA for each PR's priority, counted from when the
+// user was assigned.
+func (uc *PRUseCase) ReviewCalendar(ctx context.Context, userID string) ([]entity.ReviewDue, error) {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, lookupErr(err)
+	}
+
+	prs, err := uc.prRepo.ListByReviewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignedAt, err := uc.prRepo.AssignedAt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.ReviewDue, 0, len(prs))
+	for _, pr := range prs {
+		if pr.Status != entity.PRStatusOpen {
+			continue
+		}
+
+		at, ok := assignedAt[pr.PullRequestID]
+		if !ok {
+			at = pr.CreatedAt
+		}
+
+		out = append(out, entity.ReviewDue{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			Priority:        pr.Priority,
+			AssignedAt:      at,
+			DueAt:           at.Add(uc.health.SLA(pr.Priority)),
+			UpdatedAt:       pr.UpdatedAt,
+		})
+	}
+
+	return out, nil
+}
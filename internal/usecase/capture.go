@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// noCapture is used until a DecisionCapture is configured.
+type noCapture struct{}
+
+func (noCapture) CaptureDecision(string, string, interface{}) {}
+
+// assignmentDecision is what debug capture records for an assignment.
+type assignmentDecision struct {
+	Roster    *entity.RosterSnapshot `json:"roster"`
+	Absent    []string               `json:"absent"`
+	Saturated []string               `json:"saturated"`
+	Reviewers []string               `json:"reviewers"`
+}
+
+// PRTeam returns the team of the PR's author.
+func (uc *PRUseCase) PRTeam(ctx context.Context, prID string) (string, error) {
+	pr, err := uc.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return "", err
+	}
+
+	author, err := uc.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return "", err
+	}
+
+	return author.TeamName, nil
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+)
+
+var (
+	ErrIdentityNotLinked = errors.New("IDENTITY_NOT_LINKED")
+	ErrForbidden         = errors.New("FORBIDDEN")
+	ErrUnknownAction     = errors.New("UNKNOWN_ACTION")
+)
+
+// ChatOrigin identifies who sent a chat command and from which message.
+type ChatOrigin struct {
+	Provider       string
+	ExternalUserID string
+	ChannelID      string
+	MessageID      string
+}
+
+// ChatActionReassign is sent as a typed command rather than a button; it
+// hands the sender's review to a teammate like ActionDecline.
+const ChatActionReassign = "reassign"
+
+type ChatActionRequest struct {
+	Origin        ChatOrigin
+	Action        string
+	PullRequestID string
+	SnoozeFor     time.Duration
+}
+
+type ChatActionResult struct {
+	ActorID      string
+	PR           entity.PullRequest
+	ReplacedBy   string
+	SnoozedUntil time.Time
+}
+
+// chatPermissions lists, per action, what the resolved user must be allowed
+// to do on the PR. Actions missing here are rejected with ErrUnknownAction.
+var chatPermissions = map[string]func(u entity.User, pr entity.PullRequest) bool{
+	notifier.ActionApprove: isAssignedReviewer,
+	notifier.ActionDecline: isAssignedReviewer,
+	notifier.ActionSnooze:  isAssignedReviewer,
+	ChatActionReassign:     isAssignedReviewer,
+}
+
+func isAssignedReviewer(u entity.User, pr entity.PullRequest) bool {
+	return contains(pr.AssignedReviewers, u.UserID)
+}
+
+// RunChatAction maps the chat identity to an active service user, checks the
+// user may perform the action on the PR, runs it and records it in the audit log.
+func (uc *PRUseCase) RunChatAction(ctx context.Context, req ChatActionRequest) (ChatActionResult, error) {
+	allowed, ok := chatPermissions[req.Action]
+	if !ok {
+		return ChatActionResult{}, ErrUnknownAction
+	}
+
+	user, err := uc.chatUser(ctx, req.Origin)
+	if err != nil {
+		return ChatActionResult{}, err
+	}
+
+	res := ChatActionResult{ActorID: user.UserID}
+
+	pr, err := uc.prRepo.GetByID(ctx, req.PullRequestID)
+	if err != nil {
+		return res, lookupErr(err)
+	}
+
+	if !user.IsActive || !allowed(user, pr) {
+		return res, ErrForbidden
+	}
+
+	details := map[string]any{}
+
+	switch req.Action {
+	case notifier.ActionApprove:
+		res.PR, err = uc.ApprovePR(ctx, pr.PullRequestID, user.UserID)
+	case notifier.ActionDecline, ChatActionReassign:
+		res.PR, res.ReplacedBy, err = uc.DeclineReview(ctx, pr.PullRequestID, user.UserID)
+		details["replaced_by"] = res.ReplacedBy
+	case notifier.ActionSnooze:
+		res.SnoozedUntil, err = uc.SnoozeReview(ctx, pr.PullRequestID, user.UserID, req.SnoozeFor)
+		details["snoozed_until"] = res.SnoozedUntil
+	}
+	if err != nil {
+		return res, err
+	}
+
+	uc.audit(ctx, entity.AuditEntry{
+		ActorID:       user.UserID,
+		Action:        "pr." + req.Action,
+		PullRequestID: pr.PullRequestID,
+		Source:        req.Origin.Provider,
+		ChannelID:     req.Origin.ChannelID,
+		MessageID:     req.Origin.MessageID,
+	}, details)
+
+	return res, nil
+}
+
+// ChatReviews lists the open reviews of the user linked to the chat identity.
+func (uc *PRUseCase) ChatReviews(ctx context.Context, origin ChatOrigin) ([]entity.ReviewDue, error) {
+	user, err := uc.chatUser(ctx, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.ReviewCalendar(ctx, user.UserID)
+}
+
+// chatUser maps a chat identity to the linked service user.
+func (uc *PRUseCase) chatUser(ctx context.Context, origin ChatOrigin) (entity.User, error) {
+	userID, err := uc.identities.UserIDFor(ctx, origin.Provider, origin.ExternalUserID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return entity.User{}, ErrIdentityNotLinked
+		}
+		return entity.User{}, err
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.User{}, ErrIdentityNotLinked
+	}
+
+	return user, nil
+}
+
+// audit records e; a failed write never fails the mutation that already happened.
+func (uc *PRUseCase) audit(ctx context.Context, e entity.AuditEntry, details map[string]any) {
+	if len(details) > 0 {
+		e.Details, _ = json.Marshal(details)
+	}
+
+	_ = uc.auditLog.Record(ctx, e)
+}
+
+// noAudit is used until an AuditRepo is configured.
+type noAudit struct{}
+
+func (noAudit) Record(context.Context, entity.AuditEntry) error { return nil }
+
+func (noAudit) List(context.Context, entity.AuditFilter) ([]entity.AuditEntry, error) {
+	return []entity.AuditEntry{}, nil
+}
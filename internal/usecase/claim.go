@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var (
+	ErrNotEligible     = errors.New("NOT_ELIGIBLE")
+	ErrAlreadyAssigned = errors.New("ALREADY_ASSIGNED")
+	ErrNoCapacity      = errors.New("NO_CAPACITY")
+	ErrAlreadyApproved = errors.New("ALREADY_APPROVED")
+)
+
+// ClaimReview lets userID take a review slot on an open PR. Without
+// replaceUserID the PR must have fewer than the usual number of reviewers;
+// with it, userID takes over that reviewer's slot, which is only allowed
+// before they approved. The claimant must pass the same rules as automatic
+// assignment: an active member of the author's team who is not the author
+// and not barred from the PR by a review exclusion. Only userID, a lead of the
+// author's team or a delegate with the reassign scope may claim, and only the
+// replaced reviewer or such a lead or delegate may hand over their slot.
+// On ErrPreconditionFailed the current PR is returned alongside the error.
+func (uc *PRUseCase) ClaimReview(ctx context.Context, prID, userID, replaceUserID string) (entity.PullRequest, error) {
+	pr, err := uc.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return entity.PullRequest{}, lookupErr(err)
+	}
+
+	switch pr.Status {
+	case entity.PRStatusMerged:
+		return entity.PullRequest{}, ErrPRMerged
+	case entity.PRStatusClosed:
+		return entity.PullRequest{}, ErrPRClosed
+	case entity.PRStatusDraft:
+		return entity.PullRequest{}, ErrPRDraft
+	}
+
+	if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+		return pr, err
+	}
+
+	author, err := uc.eligibleReviewer(ctx, pr, userID)
+	if err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if err := uc.authorize(ctx, author.TeamName, entity.ScopeReassign, userID); err != nil {
+		return entity.PullRequest{}, err
+	}
+	if replaceUserID != "" {
+		if err := uc.authorize(ctx, author.TeamName, entity.ScopeReassign, replaceUserID); err != nil {
+			return entity.PullRequest{}, err
+		}
+	}
+
+	if contains(pr.AssignedReviewers, userID) {
+		return entity.PullRequest{}, ErrAlreadyAssigned
+	}
+
+	if replaceUserID == "" {
+		if len(pr.AssignedReviewers) >= _reviewersPerPR {
+			return entity.PullRequest{}, ErrNoCapacity
+		}
+	} else {
+		if !contains(pr.AssignedReviewers, replaceUserID) {
+			return entity.PullRequest{}, ErrNotAssigned
+		}
+		if contains(pr.Approvals, replaceUserID) {
+			return entity.PullRequest{}, ErrAlreadyApproved
+		}
+		pr.AssignedReviewers = without(pr.AssignedReviewers, replaceUserID)
+	}
+
+	pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+
+	if err := uc.prRepo.Update(ctx, &pr); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if replaceUserID != "" {
+		uc.publish(ctx, entity.PREventReviewerUnassigned, pr, author.TeamName, replaceUserID)
+		uc.emitHook(ctx, entity.HookEvent{
+			Event:         entity.HookEventReviewerReassigned,
+			TeamName:      author.TeamName,
+			PullRequest:   pr,
+			OldReviewerID: replaceUserID,
+			NewReviewerID: userID,
+		})
+	}
+	uc.publish(ctx, entity.PREventReviewerAssigned, pr, author.TeamName, userID)
+
+	details := map[string]any{}
+	if replaceUserID != "" {
+		details["replaced"] = replaceUserID
+	}
+
+	uc.audit(ctx, entity.AuditEntry{
+		ActorID:       userID,
+		Action:        "pr.claim",
+		PullRequestID: pr.PullRequestID,
+		Source:        "api",
+	}, details)
+
+	return pr, nil
+}
+
+// eligibleReviewer checks userID against the rules automatic assignment
+// follows for pr: an active member of the author's team who is not the
+// author and not barred by a review exclusion. It returns the author.
+func (uc *PRUseCase) eligibleReviewer(ctx context.Context, pr entity.PullRequest, userID string) (entity.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.User{}, lookupErr(err)
+	}
+
+	author, err := uc.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return entity.User{}, lookupErr(err)
+	}
+
+	if !user.IsActive || user.UserID == pr.AuthorID || user.TeamName != author.TeamName {
+		return entity.User{}, ErrNotEligible
+	}
+
+	barred, err := uc.barredReviewers(ctx, pr.AuthorID, pr.Labels)
+	if err != nil {
+		return entity.User{}, err
+	}
+	if contains(barred, userID) {
+		return entity.User{}, ErrNotEligible
+	}
+
+	return author, nil
+}
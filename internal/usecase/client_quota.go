@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var ErrInvalidClientQuota = errors.New("invalid client quota")
+
+// ClientQuotaUseCase enforces per-client request quotas. A client's own quota
+// replaces the defaults. Callers without one, whether their key is missing,
+// unknown or freshly rotated, are counted under a fallback identity the
+// controller derives from the connection, so a new key is not a new quota.
+type ClientQuotaUseCase struct {
+	repo     ClientQuotaRepo
+	tx       TxManager
+	defaults entity.ClientQuota
+}
+
+func NewClientQuotaUseCase(repo ClientQuotaRepo, tx TxManager, defaults entity.ClientQuota) *ClientQuotaUseCase {
+	return &ClientQuotaUseCase{repo: repo, tx: tx, defaults: defaults}
+}
+
+// Consume counts one request by clientID at now unless that would exceed one
+// of its quotas, in which case allowed is false and nothing is counted.
+// Without a quota of its own the request is counted against the defaults
+// under fallbackID.
+func (uc *ClientQuotaUseCase) Consume(ctx context.Context, clientID, fallbackID string, now time.Time) (status entity.QuotaStatus, allowed bool, err error) {
+	q, own, err := uc.quota(ctx, clientID)
+	if err != nil {
+		return entity.QuotaStatus{}, false, err
+	}
+	if !own {
+		q.ClientID = fallbackID
+	}
+	clientID = q.ClientID
+
+	starts := quotaStarts(q, now)
+	if len(starts) == 0 {
+		return entity.QuotaStatus{ClientID: clientID, Windows: []entity.QuotaWindow{}}, true, nil
+	}
+
+	err = uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		used, err := uc.repo.Lock(ctx, clientID, starts)
+		if err != nil {
+			return err
+		}
+
+		status = quotaStatus(q, used, now)
+		for _, w := range status.Windows {
+			if w.Remaining == 0 {
+				return nil
+			}
+		}
+
+		if err := uc.repo.Increment(ctx, clientID, starts); err != nil {
+			return err
+		}
+
+		for i := range status.Windows {
+			status.Windows[i].Used++
+			status.Windows[i].Remaining--
+		}
+		allowed = true
+
+		return nil
+	})
+	if err != nil {
+		return entity.QuotaStatus{}, false, err
+	}
+
+	return status, allowed, nil
+}
+
+// Status reports clientID's quota and how much of it is used at now.
+func (uc *ClientQuotaUseCase) Status(ctx context.Context, clientID string, now time.Time) (entity.ClientQuota, entity.QuotaStatus, error) {
+	q, _, err := uc.quota(ctx, clientID)
+	if err != nil {
+		return entity.ClientQuota{}, entity.QuotaStatus{}, err
+	}
+
+	used, err := uc.repo.Used(ctx, clientID, quotaStarts(q, now))
+	if err != nil {
+		return entity.ClientQuota{}, entity.QuotaStatus{}, err
+	}
+
+	return q, quotaStatus(q, used, now), nil
+}
+
+// Defaults returns the quota of clients without one of their own.
+func (uc *ClientQuotaUseCase) Defaults() entity.ClientQuota {
+	return uc.defaults
+}
+
+func (uc *ClientQuotaUseCase) List(ctx context.Context) ([]entity.ClientQuota, error) {
+	return uc.repo.List(ctx)
+}
+
+// Set gives q.ClientID its own quota in place of the defaults.
+func (uc *ClientQuotaUseCase) Set(ctx context.Context, q entity.ClientQuota) (entity.ClientQuota, error) {
+	q.ClientID = strings.TrimSpace(q.ClientID)
+	if q.ClientID == "" {
+		return entity.ClientQuota{}, fmt.Errorf("%w: client_id required", ErrInvalidClientQuota)
+	}
+	if q.Daily < 0 || q.Monthly < 0 {
+		return entity.ClientQuota{}, fmt.Errorf("%w: limits must not be negative", ErrInvalidClientQuota)
+	}
+
+	if err := uc.repo.Upsert(ctx, &q); err != nil {
+		return entity.ClientQuota{}, err
+	}
+
+	return q, nil
+}
+
+// Delete returns clientID to the defaults.
+func (uc *ClientQuotaUseCase) Delete(ctx context.Context, clientID string) error {
+	return lookupErr(uc.repo.Delete(ctx, clientID))
+}
+
+// quota returns clientID's own quota, with own set, or else the defaults.
+func (uc *ClientQuotaUseCase) quota(ctx context.Context, clientID string) (q entity.ClientQuota, own bool, err error) {
+	q, err = uc.repo.Get(ctx, clientID)
+	if err == nil {
+		return q, true, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return entity.ClientQuota{}, false, err
+	}
+
+	q = uc.defaults
+	q.ClientID = clientID
+	return q, false, nil
+}
+
+func quotaLimits(q entity.ClientQuota) map[string]int64 {
+	limits := map[string]int64{}
+	if q.Daily > 0 {
+		limits[entity.QuotaPeriodDay] = q.Daily
+	}
+	if q.Monthly > 0 {
+		limits[entity.QuotaPeriodMonth] = q.Monthly
+	}
+	return limits
+}
+
+// quotaStarts maps each capped period of q to its start at now. Repositories
+// must go through it in sorted period order, so concurrent requests of one
+// client lock its counters in the same order.
+func quotaStarts(q entity.ClientQuota, now time.Time) map[string]time.Time {
+	starts := map[string]time.Time{}
+	for period := range quotaLimits(q) {
+		starts[period] = entity.QuotaPeriodStart(period, now)
+	}
+	return starts
+}
+
+func quotaStatus(q entity.ClientQuota, used map[string]int64, now time.Time) entity.QuotaStatus {
+	status := entity.QuotaStatus{ClientID: q.ClientID, Windows: []entity.QuotaWindow{}}
+	limits := quotaLimits(q)
+
+	for _, period := range []string{entity.QuotaPeriodDay, entity.QuotaPeriodMonth} {
+		limit, ok := limits[period]
+		if !ok {
+			continue
+		}
+		status.Windows = append(status.Windows, entity.QuotaWindow{
+			Period:    period,
+			Limit:     limit,
+			Used:      used[period],
+			Remaining: max(0, limit-used[period]),
+			ResetAt:   entity.QuotaPeriodEnd(period, now),
+		})
+	}
+
+	return status
+}
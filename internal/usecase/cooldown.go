@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// _cooldownHorizon bounds how far back the team's last PRs are looked for.
+const _cooldownHorizon = 30 * 24 * time.Hour
+
+// ReviewCooldown keeps back-to-back PRs off the same reviewers: a candidate
+// assigned within Window, or to one of the team's last PRs, ranks after
+// everyone else. It reorders rather than excludes, so a small team still gets
+// reviewers. Zero values turn either rule off.
+type ReviewCooldown struct {
+	Window time.Duration
+	PRs    int
+}
+
+func (c ReviewCooldown) enabled() bool {
+	return c.Window > 0 || c.PRs > 0
+}
+
+// coolDown moves the candidates in their cooldown after the others, keeping
+// the strategy's order within each group, and returns who they were.
+func (uc *PRUseCase) coolDown(ctx context.Context, teamName string, candidates []entity.User, now time.Time) ([]entity.User, []string, error) {
+	if !uc.cooldown.enabled() || len(candidates) == 0 {
+		return candidates, nil, nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.UserID
+	}
+
+	var since *time.Time
+	if uc.cooldown.Window > 0 {
+		t := now.Add(-uc.cooldown.Window)
+		since = &t
+	}
+
+	horizon := now.Add(-max(uc.cooldown.Window, _cooldownHorizon))
+	cooling, err := uc.prRepo.RecentlyAssigned(ctx, teamName, ids, since, horizon, uc.cooldown.PRs)
+	if err != nil || len(cooling) == 0 {
+		return candidates, nil, err
+	}
+
+	ranked := slices.Clone(candidates)
+	slices.SortStableFunc(ranked, func(a, b entity.User) int {
+		return boolRank(slices.Contains(cooling, a.UserID)) - boolRank(slices.Contains(cooling, b.UserID))
+	})
+
+	return ranked, cooling, nil
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var ErrInvalidDelegation = errors.New("invalid delegation")
+
+type noDelegations struct{}
+
+func (noDelegations) Create(context.Context, *entity.Delegation) error {
+	return fmt.Errorf("delegations are not configured")
+}
+
+func (noDelegations) GetByID(context.Context, int64) (entity.Delegation, error) {
+	return entity.Delegation{}, ErrNotFound
+}
+
+func (noDelegations) ListByTeam(context.Context, string, time.Time) ([]entity.Delegation, error) {
+	return []entity.Delegation{}, nil
+}
+
+func (noDelegations) ListActive(context.Context, string, string, time.Time) ([]entity.Delegation, error) {
+	return []entity.Delegation{}, nil
+}
+
+func (noDelegations) Revoke(context.Context, int64, time.Time) (entity.Delegation, error) {
+	return entity.Delegation{}, ErrNotFound
+}
+
+type actorKey struct{}
+
+// WithActor records on ctx which user asked for the operation, so management
+// use cases can check the user's permissions. Use cases run without an actor
+// (jobs, webhooks, admin API) are not restricted.
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+func actorFrom(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(actorKey{}).(string)
+	return id, ok
+}
+
+// RBACEnforced reports whether management requests need an authenticated
+// actor; controllers then ignore actor IDs the request merely asserts.
+func (uc *PRUseCase) RBACEnforced() bool {
+	return uc.enforceRBAC
+}
+
+// withoutActor drops the actor from ctx, for the steps of an operation that
+// was already authorized as a whole.
+func withoutActor(ctx context.Context) context.Context {
+	return context.WithValue(ctx, actorKey{}, nil)
+}
+
+// authorize checks that the actor on ctx may perform a scope operation in the
+// team. Operations on a single user pass it as subjectID, and users may always
+// act on themselves; lead-only operations pass none. Otherwise the actor must
+// be an active lead of the team or hold an active delegation covering scope.
+// Delegated operations are recorded in the audit log.
+func (uc *PRUseCase) authorize(ctx context.Context, teamName, scope, subjectID string) error {
+	actorID, ok := actorFrom(ctx)
+	if !ok {
+		return nil
+	}
+	if actorID == "" {
+		if uc.enforceRBAC {
+			return ErrForbidden
+		}
+		return nil
+	}
+	if subjectID != "" && actorID == subjectID {
+		return nil
+	}
+
+	lead, err := uc.isTeamLead(ctx, teamName, actorID)
+	if err != nil || lead {
+		return err
+	}
+
+	now := time.Now()
+	delegations, err := uc.delegations.ListActive(ctx, teamName, actorID, now)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range delegations {
+		if !d.Covers(scope, now) {
+			continue
+		}
+		// A delegation lapses with its delegator's lead role.
+		delegatorLead, err := uc.isTeamLead(ctx, teamName, d.DelegatorID)
+		if err != nil {
+			return err
+		}
+		if !delegatorLead {
+			continue
+		}
+
+		uc.audit(ctx, entity.AuditEntry{
+			ActorID: actorID,
+			Action:  "team.delegated_action",
+			Source:  "api",
+		}, map[string]any{
+			"team_name":     teamName,
+			"scope":         scope,
+			"delegation_id": d.ID,
+			"delegator_id":  d.DelegatorID,
+			"subject_id":    subjectID,
+		})
+
+		return nil
+	}
+
+	return ErrForbidden
+}
+
+// authorizeLead is authorize for lead-only operations that reach outside the
+// service: they need a lead, a delegate or an admin even when RBAC isn't
+// enforced.
+func (uc *PRUseCase) authorizeLead(ctx context.Context, teamName, scope string) error {
+	if actorID, ok := actorFrom(ctx); ok && actorID == "" {
+		return ErrForbidden
+	}
+
+	return uc.authorize(ctx, teamName, scope, "")
+}
+
+func (uc *PRUseCase) isTeamLead(ctx context.Context, teamName, userID string) (bool, error) {
+	if teamName == "" {
+		return false, nil
+	}
+
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return u.IsActive && u.TeamName == teamName && u.Role == entity.RoleLead, nil
+}
+
+// ListDelegations returns the team's delegations that have not ended yet,
+// including revoked ones; activeOnly keeps only those in effect now.
+func (uc *PRUseCase) ListDelegations(ctx context.Context, teamName string, activeOnly bool) ([]entity.Delegation, error) {
+	if err := uc.requireTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delegations, err := uc.delegations.ListByTeam(ctx, teamName, now)
+	if err != nil || !activeOnly {
+		return delegations, err
+	}
+
+	active := make([]entity.Delegation, 0, len(delegations))
+	for _, d := range delegations {
+		if d.ActiveAt(now) {
+			active = append(active, d)
+		}
+	}
+
+	return active, nil
+}
+
+// CreateDelegation lets an active team lead hand some of their management
+// scopes to another active member of the team until d.EndsAt. The delegator
+// is the actor on ctx: an empty d.DelegatorID defaults to it and any other
+// value is forbidden. Only admins, who have no actor, may name the delegator.
+func (uc *PRUseCase) CreateDelegation(ctx context.Context, d entity.Delegation) (entity.Delegation, error) {
+	if actorID, ok := actorFrom(ctx); ok {
+		switch {
+		case actorID == "":
+			return entity.Delegation{}, ErrForbidden
+		case d.DelegatorID == "":
+			d.DelegatorID = actorID
+		case d.DelegatorID != actorID:
+			return entity.Delegation{}, ErrForbidden
+		}
+	}
+
+	if err := uc.requireTeam(ctx, d.TeamName); err != nil {
+		return entity.Delegation{}, err
+	}
+
+	if err := validateDelegation(&d, time.Now()); err != nil {
+		return entity.Delegation{}, err
+	}
+
+	lead, err := uc.isTeamLead(ctx, d.TeamName, d.DelegatorID)
+	if err != nil {
+		return entity.Delegation{}, err
+	}
+	if !lead {
+		return entity.Delegation{}, ErrForbidden
+	}
+
+	delegate, err := uc.userRepo.GetByID(ctx, d.DelegateID)
+	if err != nil {
+		return entity.Delegation{}, lookupErr(err)
+	}
+	if !delegate.IsActive || delegate.TeamName != d.TeamName {
+		return entity.Delegation{}, fmt.Errorf("%w: delegate_id must be an active member of the team", ErrInvalidDelegation)
+	}
+
+	if err := uc.delegations.Create(ctx, &d); err != nil {
+		return entity.Delegation{}, err
+	}
+
+	uc.audit(ctx, entity.AuditEntry{
+		ActorID: d.DelegatorID,
+		Action:  "team.delegation_grant",
+		Source:  "api",
+	}, map[string]any{
+		"team_name":     d.TeamName,
+		"delegation_id": d.ID,
+		"delegate_id":   d.DelegateID,
+		"scopes":        d.Scopes,
+		"starts_at":     d.StartsAt,
+		"ends_at":       d.EndsAt,
+	})
+
+	return d, nil
+}
+
+// RevokeDelegation ends a delegation early. A lead of the team, the
+// delegate or an admin may revoke it.
+func (uc *PRUseCase) RevokeDelegation(ctx context.Context, id int64) (entity.Delegation, error) {
+	d, err := uc.delegations.GetByID(ctx, id)
+	if err != nil {
+		return entity.Delegation{}, lookupErr(err)
+	}
+
+	actorID, hasActor := actorFrom(ctx)
+	if hasActor {
+		if err := uc.authorizeRevoke(ctx, d, actorID); err != nil {
+			return entity.Delegation{}, err
+		}
+	}
+
+	d, err = uc.delegations.Revoke(ctx, id, time.Now().UTC())
+	if err != nil {
+		return entity.Delegation{}, lookupErr(err)
+	}
+
+	uc.audit(ctx, entity.AuditEntry{
+		ActorID: actorID,
+		Action:  "team.delegation_revoke",
+		Source:  "api",
+	}, map[string]any{
+		"team_name":     d.TeamName,
+		"delegation_id": d.ID,
+		"delegate_id":   d.DelegateID,
+	})
+
+	return d, nil
+}
+
+func (uc *PRUseCase) authorizeRevoke(ctx context.Context, d entity.Delegation, actorID string) error {
+	switch {
+	case actorID == "":
+		return ErrForbidden
+	case actorID == d.DelegateID:
+		return nil
+	}
+
+	lead, err := uc.isTeamLead(ctx, d.TeamName, actorID)
+	if err != nil {
+		return err
+	}
+	if !lead {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+func validateDelegation(d *entity.Delegation, now time.Time) error {
+	if d.DelegatorID == "" || d.DelegateID == "" {
+		return fmt.Errorf("%w: delegator_id and delegate_id are required", ErrInvalidDelegation)
+	}
+	if d.DelegatorID == d.DelegateID {
+		return fmt.Errorf("%w: delegate_id must differ from delegator_id", ErrInvalidDelegation)
+	}
+
+	if len(d.Scopes) == 0 {
+		return fmt.Errorf("%w: scopes must not be empty", ErrInvalidDelegation)
+	}
+	scopes := make([]string, 0, len(d.Scopes))
+	for _, s := range d.Scopes {
+		if !slices.Contains(entity.Scopes, s) {
+			return fmt.Errorf("%w: scopes must be among %s", ErrInvalidDelegation, strings.Join(entity.Scopes, ", "))
+		}
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	d.Scopes = scopes
+
+	if d.StartsAt.IsZero() {
+		d.StartsAt = now
+	}
+	d.StartsAt = d.StartsAt.UTC()
+	d.EndsAt = d.EndsAt.UTC()
+
+	if !d.EndsAt.After(d.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidDelegation)
+	}
+	if !d.EndsAt.After(now) {
+		return fmt.Errorf("%w: ends_at must be in the future", ErrInvalidDelegation)
+	}
+
+	return nil
+}
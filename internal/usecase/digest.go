@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// TeamDigest summarizes teamName's PRs for a standup: PRs opened and merged
+// within window before now, open reviews past their SLA, and active members
+// whose open reviews exceed the least loaded member's by more than the health
+// MaxImbalance.
+func (uc *PRUseCase) TeamDigest(ctx context.Context, teamName string, now time.Time, window time.Duration) (entity.TeamDigest, error) {
+	members, err := uc.userRepo.ListByTeam(ctx, teamName)
+	if err != nil {
+		return entity.TeamDigest{}, err
+	}
+	if len(members) == 0 {
+		exists, err := uc.teamRepo.Exists(ctx, teamName)
+		if err != nil {
+			return entity.TeamDigest{}, err
+		}
+		if !exists {
+			return entity.TeamDigest{}, ErrNotFound
+		}
+	}
+
+	now = now.UTC()
+	d := entity.TeamDigest{
+		TeamName:    teamName,
+		Since:       now.Add(-window),
+		GeneratedAt: now,
+		NewPRs:      []entity.PullRequestShort{},
+		Merged:      []entity.PullRequestShort{},
+		Overdue:     []entity.OverdueReview{},
+		Overloaded:  []entity.ReviewerLoad{},
+	}
+
+	prs, err := uc.prRepo.ListTeamActivity(ctx, teamName, d.Since)
+	if err != nil {
+		return entity.TeamDigest{}, err
+	}
+
+	for _, pr := range prs {
+		short := entity.PullRequestShort{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			Status:          pr.Status,
+			ExternalState:   pr.ExternalState,
+		}
+
+		if pr.Status == entity.PRStatusMerged {
+			d.Merged = append(d.Merged, short)
+			continue
+		}
+		if !pr.CreatedAt.Before(d.Since) {
+			d.NewPRs = append(d.NewPRs, short)
+		}
+
+		due := pr.CreatedAt.Add(uc.health.SLA(pr.Priority))
+		if pr.Status != entity.PRStatusOpen || !now.After(due) {
+			continue
+		}
+		var pending []string
+		for _, r := range pr.AssignedReviewers {
+			if !contains(pr.Approvals, r) {
+				pending = append(pending, r)
+			}
+		}
+		if len(pending) > 0 {
+			d.Overdue = append(d.Overdue, entity.OverdueReview{
+				PullRequestID:    pr.PullRequestID,
+				PullRequestName:  pr.PullRequestName,
+				AuthorID:         pr.AuthorID,
+				Priority:         pr.Priority,
+				DueAt:            due,
+				PendingReviewers: pending,
+			})
+		}
+	}
+
+	d.Overloaded, err = uc.overloaded(ctx, members)
+	if err != nil {
+		return entity.TeamDigest{}, err
+	}
+
+	names := make(map[string]string, len(members))
+	for _, m := range members {
+		names[m.UserID] = m.Name()
+	}
+	d.Markdown = renderDigest(d, func(userID string) string {
+		if n, ok := names[userID]; ok {
+			return n
+		}
+		return uc.displayName(ctx, userID)
+	})
+
+	return d, nil
+}
+
+// overloaded returns the active members whose open reviews exceed the least
+// loaded active member's by more than the health MaxImbalance, busiest first.
+func (uc *PRUseCase) overloaded(ctx context.Context, members []entity.User) ([]entity.ReviewerLoad, error) {
+	var ids []string
+	for _, m := range members {
+		if m.IsActive {
+			ids = append(ids, m.UserID)
+		}
+	}
+	if len(ids) == 0 {
+		return []entity.ReviewerLoad{}, nil
+	}
+
+	counts, err := uc.prRepo.CountOpenByReviewer(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	least := counts[ids[0]]
+	for _, id := range ids {
+		least = min(least, counts[id])
+	}
+
+	out := []entity.ReviewerLoad{}
+	for _, id := range ids {
+		if counts[id]-least > uc.health.MaxImbalance {
+			out = append(out, entity.ReviewerLoad{UserID: id, OpenReviews: counts[id]})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].OpenReviews != out[j].OpenReviews {
+			return out[i].OpenReviews > out[j].OpenReviews
+		}
+		return out[i].UserID < out[j].UserID
+	})
+
+	return out, nil
+}
+
+func renderDigest(d entity.TeamDigest, name func(userID string) string) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "**Review digest for %s** (since %s)\n", d.TeamName, d.Since.Format("Jan 2 15:04 MST"))
+
+	fmt.Fprintf(&sb, "\n**New PRs** (%d)\n", len(d.NewPRs))
+	for _, pr := range d.NewPRs {
+		fmt.Fprintf(&sb, "- %s %q by %s\n", pr.PullRequestID, pr.PullRequestName, name(pr.AuthorID))
+	}
+
+	fmt.Fprintf(&sb, "\n**Merged** (%d)\n", len(d.Merged))
+	for _, pr := range d.Merged {
+		fmt.Fprintf(&sb, "- %s %q by %s\n", pr.PullRequestID, pr.PullRequestName, name(pr.AuthorID))
+	}
+
+	fmt.Fprintf(&sb, "\n**Overdue reviews** (%d)\n", len(d.Overdue))
+	for _, r := range d.Overdue {
+		waiting := make([]string, len(r.PendingReviewers))
+		for i, id := range r.PendingReviewers {
+			waiting[i] = name(id)
+		}
+		fmt.Fprintf(&sb, "- %s %q (%s, due %s): waiting on %s\n",
+			r.PullRequestID, r.PullRequestName, r.Priority, r.DueAt.Format("Jan 2 15:04 MST"), strings.Join(waiting, ", "))
+	}
+
+	fmt.Fprintf(&sb, "\n**Overloaded** (%d)\n", len(d.Overloaded))
+	for _, l := range d.Overloaded {
+		fmt.Fprintf(&sb, "- %s: %d open reviews\n", name(l.UserID), l.OpenReviews)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// noEvents is used until an EventSink or UserEventSink is configured.
+type noEvents struct{}
+
+func (noEvents) Publish(context.Context, entity.PREvent) {}
+
+func (noEvents) PublishUser(context.Context, entity.UserEvent) {}
+
+// noMirror is used until a ReviewerMirror is configured.
+type noMirror struct{}
+
+func (noMirror) ReviewerAssigned(context.Context, entity.PullRequest, string) {}
+
+func (noMirror) ReviewerUnassigned(context.Context, entity.PullRequest, string) {}
+
+func (noMirror) Mirrors(entity.PullRequest) bool { return false }
+
+// ReviewerMirrors keeps several VCSs in step.
+type ReviewerMirrors []ReviewerMirror
+
+func (ms ReviewerMirrors) ReviewerAssigned(ctx context.Context, pr entity.PullRequest, userID string) {
+	for _, m := range ms {
+		m.ReviewerAssigned(ctx, pr, userID)
+	}
+}
+
+func (ms ReviewerMirrors) ReviewerUnassigned(ctx context.Context, pr entity.PullRequest, userID string) {
+	for _, m := range ms {
+		m.ReviewerUnassigned(ctx, pr, userID)
+	}
+}
+
+func (ms ReviewerMirrors) Mirrors(pr entity.PullRequest) bool {
+	return slices.ContainsFunc(ms, func(m ReviewerMirror) bool { return m.Mirrors(pr) })
+}
+
+// publishUser reports a change to u once it commits.
+func (uc *PRUseCase) publishUser(ctx context.Context, eventType string, u entity.User) {
+	e := entity.UserEvent{
+		Type:       eventType,
+		UserID:     u.UserID,
+		TeamName:   u.TeamName,
+		IsActive:   u.IsActive,
+		OccurredAt: time.Now().UTC(),
+	}
+	afterCommit(ctx, func(ctx context.Context) { uc.userEvents.PublishUser(ctx, e) })
+}
+
+// publish mirrors a lifecycle event of pr once it commits; userID defaults to
+// the author.
+func (uc *PRUseCase) publish(ctx context.Context, eventType string, pr entity.PullRequest, teamName, userID string) {
+	if userID == "" {
+		userID = pr.AuthorID
+	}
+
+	e := entity.PREvent{
+		Type:          eventType,
+		PullRequestID: pr.PullRequestID,
+		AuthorID:      pr.AuthorID,
+		UserID:        userID,
+		TeamName:      teamName,
+		Repository:    pr.Repository,
+		OccurredAt:    time.Now().UTC(),
+	}
+	afterCommit(ctx, func(ctx context.Context) { uc.events.Publish(ctx, e) })
+
+	// Every assignment path ends up here, so this is where reviewer.assigned
+	// goes out and where the VCS is kept in step, once the change commits.
+	// The deferred calls get their own copy of the reviewers, which the
+	// caller may still change.
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	switch eventType {
+	case entity.PREventReviewerAssigned:
+		afterCommit(ctx, func(ctx context.Context) { uc.mirror.ReviewerAssigned(ctx, pr, userID) })
+		uc.emitHook(ctx, entity.HookEvent{
+			Event:         entity.HookEventReviewerAssigned,
+			TeamName:      teamName,
+			PullRequest:   pr,
+			NewReviewerID: userID,
+		})
+	case entity.PREventReviewerUnassigned:
+		afterCommit(ctx, func(ctx context.Context) { uc.mirror.ReviewerUnassigned(ctx, pr, userID) })
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+)
+
+var ErrInvalidExternalState = errors.New("invalid external state")
+
+// SetExternalState records the PR's state in the VCS, as reported by its
+// webhooks. Reviewers who have not approved yet are told when the diff goes
+// stale and when it is reviewable again. Setting the current state is a no-op.
+// On ErrPreconditionFailed the current PR is returned alongside the error.
+func (uc *PRUseCase) SetExternalState(ctx context.Context, prID, state string) (entity.PullRequest, error) {
+	if !slices.Contains(entity.ExternalStates, state) {
+		return entity.PullRequest{}, fmt.Errorf("%w: state must be one of clean, conflicts, outdated", ErrInvalidExternalState)
+	}
+
+	pr, err := uc.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return entity.PullRequest{}, lookupErr(err)
+	}
+
+	switch pr.Status {
+	case entity.PRStatusMerged:
+		return entity.PullRequest{}, ErrPRMerged
+	case entity.PRStatusClosed:
+		return entity.PullRequest{}, ErrPRClosed
+	}
+
+	if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+		return pr, err
+	}
+
+	if pr.ExternalState == state {
+		return pr, nil
+	}
+
+	pr.ExternalState = state
+
+	if err := uc.prRepo.Update(ctx, &pr); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	uc.notifyExternalState(ctx, pr)
+
+	uc.publish(ctx, entity.PREventUpdated, pr, "", "")
+	uc.emitHook(ctx, entity.HookEvent{Event: entity.HookEventPRUpdated, PullRequest: pr})
+
+	return pr, nil
+}
+
+// notifyExternalState tells reviewers who have not approved about pr's new
+// external state.
+func (uc *PRUseCase) notifyExternalState(ctx context.Context, pr entity.PullRequest) {
+	var what string
+	switch pr.ExternalState {
+	case entity.ExternalStateConflicts:
+		what = "has merge conflicts; hold off reviewing until they are resolved"
+	case entity.ExternalStateOutdated:
+		what = "is out of date with its base branch; hold off reviewing until it is updated"
+	default:
+		what = "is up to date and ready for review again"
+	}
+
+	text := fmt.Sprintf("%q by %s %s", pr.PullRequestName, uc.displayName(ctx, pr.AuthorID), what)
+
+	for _, r := range pr.AssignedReviewers {
+		if contains(pr.Approvals, r) {
+			continue
+		}
+		_ = uc.notifier.Notify(ctx, notifier.Message{
+			Kind:          "pr_external_state",
+			UserID:        r,
+			PullRequestID: pr.PullRequestID,
+			Text:          text,
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// HealthThresholds define when a team counts as at risk.
+type HealthThresholds struct {
+	// ReviewSLA is how long an open PR may wait before it breaches.
+	ReviewSLA time.Duration
+	// PrioritySLA overrides ReviewSLA for PRs of the given priority.
+	PrioritySLA map[string]time.Duration
+	// MinCoverage is the minimal share of open PRs with at least one reviewer.
+	MinCoverage float64
+	// MaxBreachRate is the maximal share of open PRs past ReviewSLA.
+	MaxBreachRate float64
+	// MaxImbalance is the maximal gap in open reviews between the busiest and
+	// the least busy active member.
+	MaxImbalance int
+}
+
+func DefaultHealthThresholds() HealthThresholds {
+	return HealthThresholds{
+		ReviewSLA:     48 * time.Hour,
+		MinCoverage:   0.8,
+		MaxBreachRate: 0.2,
+		MaxImbalance:  3,
+	}
+}
+
+// SLA returns the review SLA for a PR of the given priority.
+func (t HealthThresholds) SLA(priority string) time.Duration {
+	if d, ok := t.PrioritySLA[priority]; ok {
+		return d
+	}
+	return t.ReviewSLA
+}
+
+// TeamHealth scores every team (or only teamName when set) on reviewer
+// coverage, SLA breaches and review-load imbalance.
+func (uc *PRUseCase) TeamHealth(ctx context.Context, now time.Time, teamName string) ([]entity.TeamHealth, error) {
+	open, err := uc.prRepo.ListByStatus(ctx, entity.PRStatusOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := uc.userRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teamOf := make(map[string]string, len(users))
+	load := make(map[string]map[string]int) // team -> active user -> open reviews
+	for _, u := range users {
+		teamOf[u.UserID] = u.TeamName
+		if !u.IsActive || u.TeamName == "" || (teamName != "" && u.TeamName != teamName) {
+			continue
+		}
+		if load[u.TeamName] == nil {
+			load[u.TeamName] = make(map[string]int)
+		}
+		load[u.TeamName][u.UserID] = 0
+	}
+
+	type tally struct{ open, covered, breached int }
+	tallies := make(map[string]*tally)
+	for team := range load {
+		tallies[team] = &tally{}
+	}
+
+	for _, pr := range open {
+		team := teamOf[pr.AuthorID]
+		if team == "" || (teamName != "" && team != teamName) {
+			continue
+		}
+		t := tallies[team]
+		if t == nil {
+			t = &tally{}
+			tallies[team] = t
+		}
+		t.open++
+		if len(pr.AssignedReviewers) > 0 {
+			t.covered++
+		}
+		if now.Sub(pr.CreatedAt) > uc.health.SLA(pr.Priority) {
+			t.breached++
+		}
+		for _, r := range pr.AssignedReviewers {
+			if _, ok := load[teamOf[r]][r]; ok {
+				load[teamOf[r]][r]++
+			}
+		}
+	}
+
+	result := make([]entity.TeamHealth, 0, len(tallies))
+	for team, t := range tallies {
+		h := entity.TeamHealth{
+			TeamName:      team,
+			OpenPRs:       t.open,
+			CoverageRatio: 1,
+			Violations:    []string{},
+		}
+		if t.open > 0 {
+			h.CoverageRatio = float64(t.covered) / float64(t.open)
+			h.SLABreachRate = float64(t.breached) / float64(t.open)
+		}
+		h.LoadImbalance = imbalance(load[team])
+
+		if h.CoverageRatio < uc.health.MinCoverage {
+			h.Violations = append(h.Violations, "coverage")
+		}
+		if h.SLABreachRate > uc.health.MaxBreachRate {
+			h.Violations = append(h.Violations, "sla_breach")
+		}
+		if h.LoadImbalance > uc.health.MaxImbalance {
+			h.Violations = append(h.Violations, "load_imbalance")
+		}
+
+		h.Status = entity.TeamHealthHealthy
+		if len(h.Violations) > 0 {
+			h.Status = entity.TeamHealthAtRisk
+		}
+		h.Score = healthScore(h, uc.health.MaxImbalance)
+
+		result = append(result, h)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score < result[j].Score
+		}
+		return result[i].TeamName < result[j].TeamName
+	})
+
+	return result, nil
+}
+
+func imbalance(load map[string]int) int {
+	if len(load) == 0 {
+		return 0
+	}
+	lo, hi := math.MaxInt, 0
+	for _, n := range load {
+		lo = min(lo, n)
+		hi = max(hi, n)
+	}
+	return hi - lo
+}
+
+// healthScore averages the three normalized dimensions into 0..100.
+func healthScore(h entity.TeamHealth, maxImbalance int) int {
+	balance := 1.0
+	if maxImbalance > 0 {
+		balance = 1 - math.Min(float64(h.LoadImbalance)/float64(2*maxImbalance), 1)
+	}
+	return int(math.Round(100 * (h.CoverageRatio + (1 - h.SLABreachRate) + balance) / 3))
+}
@@ -2,28 +2,298 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/pagination"
+	"github.com/evrone/go-clean-template/pkg/webhook"
 )
 
 type PRRepo interface {
 	Create(ctx context.Context, p entity.PullRequest) error
 	GetByID(ctx context.Context, id string) (entity.PullRequest, error)
-	Update(ctx context.Context, p entity.PullRequest) error
+	GetByExternal(ctx context.Context, repository string, number int) (entity.PullRequest, error)
+	// GetForUpdate is GetByID that also locks the row until the transaction
+	// on ctx ends.
+	GetForUpdate(ctx context.Context, id string) (entity.PullRequest, error)
+	Update(ctx context.Context, p *entity.PullRequest) error
 	ListByReviewer(ctx context.Context, reviewerID string) ([]entity.PullRequest, error)
-	ListAll(ctx context.Context) ([]entity.PullRequest, error)
+	// AssignedAt returns when reviewerID was assigned to each open PR they
+	// review, keyed by PR ID.
+	AssignedAt(ctx context.Context, reviewerID string) (map[string]time.Time, error)
+	ListByStatus(ctx context.Context, status entity.PRStatus) ([]entity.PullRequest, error)
+	ListTeamActivity(ctx context.Context, teamName string, since time.Time) ([]entity.PullRequest, error)
+	ListOpenByAuthor(ctx context.Context, authorID string) ([]entity.PullRequest, error)
+	CountOpenByAuthor(ctx context.Context, authorID string) (int, error)
+	CountOpenByReviewer(ctx context.Context, reviewerIDs []string) (map[string]int, error)
+	// RecentlyAssigned returns which of reviewerIDs were assigned after since
+	// (when set) or to one of the last lastPRs PRs teamName's members opened.
+	// Nothing before horizon is considered.
+	RecentlyAssigned(ctx context.Context, teamName string, reviewerIDs []string, since *time.Time, horizon time.Time, lastPRs int) ([]string, error)
+	// StackReviewers returns who reviews authorID's open or merged PRs in
+	// the stack other than exceptPRID.
+	StackReviewers(ctx context.Context, authorID, stackID, exceptPRID string) ([]string, error)
+	ListAbandoned(ctx context.Context, before time.Time) ([]entity.PullRequest, error)
+	ListCloseWarned(ctx context.Context) ([]entity.PullRequest, error)
+	SetCloseWarning(ctx context.Context, prID string, at *time.Time) error
+	Snooze(ctx context.Context, prID, userID string, until time.Time) error
+	ListPendingReviews(ctx context.Context) ([]entity.PendingReview, error)
+	MarkReminded(ctx context.Context, prID, userID string, at time.Time) error
 }
 
 type UserRepo interface {
 	Create(ctx context.Context, u entity.User) error
 	GetByID(ctx context.Context, id string) (entity.User, error)
-	Update(ctx context.Context, u entity.User) error
+	// GetForUpdate is GetByID that also locks the row until the transaction
+	// on ctx ends.
+	GetForUpdate(ctx context.Context, id string) (entity.User, error)
+	Update(ctx context.Context, u *entity.User) error
 	ListByTeam(ctx context.Context, teamName string) ([]entity.User, error)
+	ListCandidates(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error)
 	ListAll(ctx context.Context) ([]entity.User, error)
+	ListIdle(ctx context.Context, since time.Time) ([]entity.User, error)
+	SetFlaggedInactive(ctx context.Context, userID string, at *time.Time) error
+	ClearIdleFlags(ctx context.Context, since time.Time) (int64, error)
+	SoftDelete(ctx context.Context, userID string, at time.Time) error
 }
 
 type TeamRepo interface {
 	Create(ctx context.Context, t entity.Team) error
 	GetByName(ctx context.Context, name string) (entity.Team, error)
-	ListAll(ctx context.Context) ([]entity.Team, error)
+	Exists(ctx context.Context, name string) (bool, error)
+	// ListAll returns one page of teams with their members, ordered by name.
+	ListAll(ctx context.Context, page pagination.Page) ([]entity.Team, error)
+	// ListBrief returns the same page as ListAll with member counts only.
+	ListBrief(ctx context.Context, page pagination.Page) ([]entity.TeamBrief, error)
+	Delete(ctx context.Context, name string) error
+	Rename(ctx context.Context, oldName, newName string) error
+}
+
+type IdentityRepo interface {
+	Link(ctx context.Context, id *entity.UserIdentity) error
+	UserIDFor(ctx context.Context, provider, externalID string) (string, error)
+	ExternalIDFor(ctx context.Context, userID, provider string) (string, error)
+	RevokeAll(ctx context.Context, userID string) (int64, error)
+}
+
+type AuditRepo interface {
+	Record(ctx context.Context, e entity.AuditEntry) error
+	List(ctx context.Context, f entity.AuditFilter) ([]entity.AuditEntry, error)
+}
+
+type ReviewRepo interface {
+	Create(ctx context.Context, r *entity.Review) error
+	ListByPR(ctx context.Context, prID string) ([]entity.Review, error)
+	LatestByPR(ctx context.Context, prID string) ([]entity.Review, error)
+	LatestByReviewer(ctx context.Context, reviewerID string) ([]entity.Review, error)
+}
+
+type SchemaRepo interface {
+	Version(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+type NotificationLogRepo interface {
+	// Add stores deliveries; either all of them or none are stored.
+	Add(ctx context.Context, deliveries []entity.NotificationDelivery) error
+	List(ctx context.Context, f entity.NotificationFilter) ([]entity.NotificationDelivery, error)
+}
+
+type TeamSettingsRepo interface {
+	Get(ctx context.Context, teamName string) (entity.TeamSettings, error)
+	// Upsert stores s's non-nil fields, resets those named in clear and
+	// keeps the rest, leaving the stored row in s.
+	Upsert(ctx context.Context, s *entity.TeamSettings, clear []string) error
+}
+
+// OutgoingWebhookRepo stores the webhook URLs teams registered for PR events.
+type OutgoingWebhookRepo interface {
+	Create(ctx context.Context, h *entity.OutgoingWebhook) error
+	GetByID(ctx context.Context, id int64) (entity.OutgoingWebhook, error)
+	ListByTeam(ctx context.Context, teamName string) ([]entity.OutgoingWebhook, error)
+	Update(ctx context.Context, h *entity.OutgoingWebhook) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// AssignmentAckRepo tracks reviewer.assigned deliveries awaiting acknowledgment.
+type AssignmentAckRepo interface {
+	Create(ctx context.Context, a *entity.AssignmentAck) error
+	GetByID(ctx context.Context, deliveryID string) (entity.AssignmentAck, error)
+	Update(ctx context.Context, a *entity.AssignmentAck) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]entity.AssignmentAck, error)
+	List(ctx context.Context, f entity.AssignmentAckFilter) ([]entity.AssignmentAck, error)
+}
+
+// ReviewerConflictRepo records external reviewer changes that disagreed with
+// the service's assignment.
+type ReviewerConflictRepo interface {
+	Create(ctx context.Context, c *entity.ReviewerConflict) error
+	List(ctx context.Context, f entity.ReviewerConflictFilter) ([]entity.ReviewerConflict, error)
+}
+
+// HookSender delivers a signed payload to an outgoing webhook URL, retrying
+// failures in the background. Send must not block the caller.
+type HookSender interface {
+	Send(url, secret, event, deliveryID string, payload []byte)
+	// Deliver makes one delivery synchronously and reports the response.
+	Deliver(ctx context.Context, url, secret, event, deliveryID string, payload []byte) (webhook.Result, error)
+}
+
+type WebhookDeliveryRepo interface {
+	Create(ctx context.Context, d entity.WebhookDelivery) (int64, error)
+	GetByID(ctx context.Context, id int64) (entity.WebhookDelivery, error)
+	List(ctx context.Context, f entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, error)
+	MarkAttempt(ctx context.Context, id int64, processErr error) error
+}
+
+// StatsRepo serves aggregate reporting queries. It is kept apart from the
+// CRUD repositories so it can be pointed at a read replica or an analytics store.
+type StatsRepo interface {
+	Stats(ctx context.Context) (entity.Stats, error)
+	UserStats(ctx context.Context, userID string) (entity.UserReviewStats, error)
+	UsersStats(ctx context.Context, userIDs []string) (map[string]entity.UserReviewStats, error)
+	Series(ctx context.Context, rng entity.AnalyticsRange, bucket string) ([]entity.TimeBucket, error)
+	TeamSLA(ctx context.Context, rng entity.AnalyticsRange) ([]entity.TeamSLA, error)
+	Leaderboard(ctx context.Context, f entity.ReviewerRankFilter) ([]entity.ReviewerRank, error)
+}
+
+type StatsHistoryRepo interface {
+	Snapshot(ctx context.Context, day time.Time) (int, error)
+	List(ctx context.Context, teamName string, from, to time.Time) ([]entity.TeamStatsSnapshot, error)
+	AsOf(ctx context.Context, teamName string, day time.Time) ([]entity.TeamStatsSnapshot, error)
+}
+
+// SearchRepo serves paginated listings for the directory endpoints; like
+// StatsRepo it may use a backend other than the transactional database.
+type SearchRepo interface {
+	Teams(ctx context.Context, page pagination.Page) ([]entity.TeamSummary, error)
+	Users(ctx context.Context, page pagination.Page) ([]entity.User, error)
+	PullRequests(ctx context.Context, f entity.PRListFilter, page pagination.Page) ([]entity.PullRequest, error)
+	// CountPullRequests counts every PR matching f, for the totals of
+	// filtered lists.
+	CountPullRequests(ctx context.Context, f entity.PRListFilter) (int64, error)
+	// PullRequestsUpdatedSince reports whether a PR matching f changed after
+	// t, which invalidates a sort=updated cursor taken at t.
+	PullRequestsUpdatedSince(ctx context.Context, f entity.PRListFilter, t time.Time) (bool, error)
+	// SearchPullRequests returns one page of the PRs whose name matches q,
+	// newest first.
+	SearchPullRequests(ctx context.Context, q string, page pagination.Page) ([]entity.PRSearchHit, error)
+	// CountSearchHits returns how many PRs SearchPullRequests matches for q.
+	CountSearchHits(ctx context.Context, q string) (int64, error)
+	// Estimate approximates how many items a listing ("teams", "users" or
+	// "pull_requests") holds in all, for list totals.
+	Estimate(ctx context.Context, listing string) (int64, error)
+}
+
+// EventSink receives PR lifecycle events for analytics. Publish must not
+// block the caller; sinks are expected to buffer and drop on overload.
+type EventSink interface {
+	Publish(ctx context.Context, e entity.PREvent)
+}
+
+// UserEventSink receives user changes. Like EventSink, it must not block the
+// caller.
+type UserEventSink interface {
+	PublishUser(ctx context.Context, e entity.UserEvent)
+}
+
+// ReviewerMirror reflects reviewer assignments onto the PR in the VCS. Like
+// EventSink, it must not block the caller. It is called after the change
+// commits, outside any transaction.
+type ReviewerMirror interface {
+	ReviewerAssigned(ctx context.Context, pr entity.PullRequest, userID string)
+	ReviewerUnassigned(ctx context.Context, pr entity.PullRequest, userID string)
+	// Mirrors reports whether changes to pr's reviewers reach the VCS.
+	Mirrors(pr entity.PullRequest) bool
+}
+
+// DecisionCapture receives reviewer assignment decisions for debug capture;
+// it keeps only those covered by an active capture session.
+type DecisionCapture interface {
+	CaptureDecision(teamName, prID string, decision interface{})
+}
+
+// AnalyticsRepo serves the heavy reporting queries from the analytics store.
+type AnalyticsRepo interface {
+	TimeSeries(ctx context.Context, r entity.AnalyticsRange, bucket string) ([]entity.TimeBucket, error)
+	Heatmap(ctx context.Context, r entity.AnalyticsRange, eventType string) ([]entity.HeatmapCell, error)
+	Leaderboard(ctx context.Context, r entity.AnalyticsRange, limit int) ([]entity.LeaderboardEntry, error)
+}
+
+// RotationRepo orders candidates for round-robin teams and keeps their cursor.
+type RotationRepo interface {
+	Next(ctx context.Context, teamName string, exclude []string, limit int) ([]entity.User, error)
+	Advance(ctx context.Context, teamName, userID string) error
+}
+
+type OnCallRepo interface {
+	GetRotation(ctx context.Context, teamName string) (entity.OnCallRotation, error)
+	SetRotation(ctx context.Context, r *entity.OnCallRotation) error
+	ListOverrides(ctx context.Context, teamName string, from, to time.Time) ([]entity.OnCallOverride, error)
+	SetOverride(ctx context.Context, o entity.OnCallOverride) error
+	DeleteOverride(ctx context.Context, teamName string, weekStart time.Time) error
+}
+
+type ReviewRuleRepo interface {
+	Upsert(ctx context.Context, r *entity.ReviewRule) error
+	ListByRepository(ctx context.Context, repository string) ([]entity.ReviewRule, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type ReviewExclusionRepo interface {
+	Create(ctx context.Context, e *entity.ReviewExclusion) error
+	List(ctx context.Context) ([]entity.ReviewExclusion, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type AbsenceRepo interface {
+	Create(ctx context.Context, a *entity.Absence) error
+	GetByID(ctx context.Context, id int64) (entity.Absence, error)
+	Update(ctx context.Context, a *entity.Absence) error
+	Delete(ctx context.Context, id int64) error
+	ListByUser(ctx context.Context, userID string, since time.Time) ([]entity.Absence, error)
+	ListCovering(ctx context.Context, at time.Time) ([]entity.Absence, error)
+}
+
+type DelegationRepo interface {
+	Create(ctx context.Context, d *entity.Delegation) error
+	GetByID(ctx context.Context, id int64) (entity.Delegation, error)
+	ListByTeam(ctx context.Context, teamName string, since time.Time) ([]entity.Delegation, error)
+	ListActive(ctx context.Context, teamName, delegateID string, at time.Time) ([]entity.Delegation, error)
+	Revoke(ctx context.Context, id int64, at time.Time) (entity.Delegation, error)
+}
+
+type UsageRepo interface {
+	Add(ctx context.Context, buckets []entity.UsageBucket) error
+	List(ctx context.Context, since time.Time, clientID string) ([]entity.UsageBucket, error)
+}
+
+type ClientQuotaRepo interface {
+	Get(ctx context.Context, clientID string) (entity.ClientQuota, error)
+	List(ctx context.Context) ([]entity.ClientQuota, error)
+	Upsert(ctx context.Context, q *entity.ClientQuota) error
+	Delete(ctx context.Context, clientID string) error
+	Used(ctx context.Context, clientID string, starts map[string]time.Time) (map[string]int64, error)
+	Lock(ctx context.Context, clientID string, starts map[string]time.Time) (map[string]int64, error)
+	Increment(ctx context.Context, clientID string, starts map[string]time.Time) error
+}
+
+// WorkloadRepo reads precomputed open review counts per user.
+type WorkloadRepo interface {
+	Refresh(ctx context.Context) (time.Time, error)
+	ListByTeam(ctx context.Context, teamName string) ([]entity.ReviewerLoad, time.Time, error)
+}
+
+// TxManager runs fn in one database transaction; repositories called with
+// the ctx passed to fn take part in it.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// WebhookProcessor maps a provider's event payload onto use case calls.
+type WebhookProcessor interface {
+	Process(ctx context.Context, event string, payload []byte) error
+}
+
+type IDGenerator interface {
+	New() string
 }
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// _deliveryBacklog bounds how many unflushed deliveries are kept; beyond it
+// the oldest are dropped.
+const _deliveryBacklog = 10000
+
+var _droppedDeliveries = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "pr_service_notification_deliveries_dropped_total",
+	Help: "Delivery outcomes dropped unrecorded because the backlog was full.",
+})
+
+// NotificationLogUseCase keeps delivery outcomes. They are buffered in memory
+// and written to the repo by Flush, so notifying never waits on Postgres.
+type NotificationLogUseCase struct {
+	repo NotificationLogRepo
+
+	mu      sync.Mutex
+	pending []entity.NotificationDelivery
+}
+
+func NewNotificationLogUseCase(repo NotificationLogRepo) *NotificationLogUseCase {
+	return &NotificationLogUseCase{repo: repo}
+}
+
+// Record buffers d.
+func (uc *NotificationLogUseCase) Record(d entity.NotificationDelivery) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.pending = append(uc.pending, d)
+	if n := len(uc.pending) - _deliveryBacklog; n > 0 {
+		uc.pending = uc.pending[n:]
+		_droppedDeliveries.Add(float64(n))
+	}
+}
+
+// Flush writes the buffered deliveries to the repo. On failure they are kept
+// and retried by the next Flush.
+func (uc *NotificationLogUseCase) Flush(ctx context.Context) error {
+	uc.mu.Lock()
+	pending := uc.pending
+	uc.pending = nil
+	uc.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := uc.repo.Add(ctx, pending); err != nil {
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+
+		uc.pending = append(pending, uc.pending...)
+		if n := len(uc.pending) - _deliveryBacklog; n > 0 {
+			uc.pending = uc.pending[n:]
+			_droppedDeliveries.Add(float64(n))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// List returns matching deliveries, newest first. Deliveries this process
+// hasn't flushed yet are included; other processes' are not.
+func (uc *NotificationLogUseCase) List(ctx context.Context, f entity.NotificationFilter) ([]entity.NotificationDelivery, error) {
+	if err := uc.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.List(ctx, f)
+}
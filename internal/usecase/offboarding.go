@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// OffboardUsers reconciles users removed from the upstream directory: each is
+// deactivated, their pending reviews on open PRs are reassigned (or dropped
+// when the team has nobody left) and their identity mappings are revoked.
+// Everything, including the audit trail, happens in one transaction; source
+// names the directory for the audit log. Unknown IDs are reported, not fatal.
+func (uc *PRUseCase) OffboardUsers(ctx context.Context, userIDs []string, source string) (entity.OffboardReport, error) {
+	var report entity.OffboardReport
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		report = entity.OffboardReport{Users: []entity.OffboardedUser{}, Unknown: []string{}}
+
+		for _, id := range normalizeTags(userIDs) {
+			u, err := uc.userRepo.GetByID(ctx, id)
+			if errors.Is(err, ErrNotFound) {
+				report.Unknown = append(report.Unknown, id)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			done, err := uc.offboard(ctx, u)
+			if err != nil {
+				return err
+			}
+
+			uc.audit(ctx, entity.AuditEntry{
+				ActorID: id,
+				Action:  "user.offboard",
+				Source:  source,
+			}, map[string]any{
+				"reassigned":         done.Reassigned,
+				"unassigned":         done.Unassigned,
+				"identities_revoked": done.IdentitiesRevoked,
+			})
+
+			report.Users = append(report.Users, done)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.OffboardReport{}, err
+	}
+
+	return report, nil
+}
+
+// DeleteUser offboards the user like OffboardUsers, closes the open and draft
+// PRs they authored and soft-deletes them: they disappear from their team and
+// listings, while the PRs and reviews referencing them keep counting in stats.
+// Only a lead of the user's team or a delegate with the capacity scope may ask
+// for it. Deleted users are not found again.
+func (uc *PRUseCase) DeleteUser(ctx context.Context, userID string) (entity.UserDeletion, error) {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.UserDeletion{}, lookupErr(err)
+	}
+	if u.DeletedAt != nil {
+		return entity.UserDeletion{}, ErrNotFound
+	}
+
+	if err := uc.authorize(ctx, u.TeamName, entity.ScopeCapacity, ""); err != nil {
+		return entity.UserDeletion{}, err
+	}
+	actorID, _ := actorFrom(ctx)
+
+	var deletion entity.UserDeletion
+
+	err = uc.tx.WithinTx(withoutActor(ctx), func(ctx context.Context) error {
+		done, err := uc.offboard(ctx, u)
+		if err != nil {
+			return err
+		}
+
+		deletion = entity.UserDeletion{OffboardedUser: done, ClosedPullRequests: []string{}, DeletedAt: time.Now().UTC()}
+
+		authored, err := uc.prRepo.ListOpenByAuthor(ctx, u.UserID)
+		if err != nil {
+			return err
+		}
+		for _, pr := range authored {
+			if _, err := uc.ClosePR(ctx, pr.PullRequestID); err != nil {
+				return err
+			}
+			deletion.ClosedPullRequests = append(deletion.ClosedPullRequests, pr.PullRequestID)
+		}
+
+		if err := uc.userRepo.SoftDelete(ctx, u.UserID, deletion.DeletedAt); err != nil {
+			return lookupErr(err)
+		}
+		uc.publishUser(ctx, entity.UserEventDeleted, u)
+
+		uc.audit(ctx, entity.AuditEntry{
+			ActorID: actorID,
+			Action:  "user.delete",
+			Source:  "api",
+		}, map[string]any{
+			"user_id":              u.UserID,
+			"team_name":            u.TeamName,
+			"reassigned":           done.Reassigned,
+			"unassigned":           done.Unassigned,
+			"closed_pull_requests": deletion.ClosedPullRequests,
+			"identities_revoked":   done.IdentitiesRevoked,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return entity.UserDeletion{}, err
+	}
+
+	return deletion, nil
+}
+
+func (uc *PRUseCase) offboard(ctx context.Context, u entity.User) (entity.OffboardedUser, error) {
+	done := entity.OffboardedUser{UserID: u.UserID}
+
+	// Deactivate first so the reassignments below can't pick the user again.
+	if u.IsActive {
+		u.IsActive = false
+		if err := uc.userRepo.Update(ctx, &u); err != nil {
+			return done, err
+		}
+		uc.publishUser(ctx, entity.UserEventUpdated, u)
+	}
+
+	var err error
+	if done.Reassigned, done.Unassigned, err = uc.releaseReviews(ctx, u); err != nil {
+		return done, err
+	}
+
+	if done.IdentitiesRevoked, err = uc.identities.RevokeAll(ctx, u.UserID); err != nil {
+		return done, err
+	}
+
+	return done, nil
+}
+
+// releaseReviews hands u's pending reviews on open PRs to other teammates.
+// Reassigned maps each PR to its new reviewer; PRs without a candidate just
+// lose u and are listed in unassigned. Reviews u already approved are kept.
+func (uc *PRUseCase) releaseReviews(ctx context.Context, u entity.User) (reassigned map[string]string, unassigned []string, err error) {
+	reassigned, unassigned = map[string]string{}, []string{}
+
+	prs, err := uc.prRepo.ListByReviewer(ctx, u.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, pr := range prs {
+		if pr.Status != entity.PRStatusOpen || contains(pr.Approvals, u.UserID) {
+			continue
+		}
+
+		_, newReviewerID, err := uc.ReassignReviewer(ctx, pr.PullRequestID, u.UserID)
+		switch {
+		case err == nil:
+			reassigned[pr.PullRequestID] = newReviewerID
+		case errors.Is(err, ErrNoCandidate):
+			pr.AssignedReviewers = without(pr.AssignedReviewers, u.UserID)
+			if err := uc.prRepo.Update(ctx, &pr); err != nil {
+				return nil, nil, err
+			}
+			uc.publish(ctx, entity.PREventReviewerUnassigned, pr, u.TeamName, u.UserID)
+			unassigned = append(unassigned, pr.PullRequestID)
+		default:
+			return nil, nil, err
+		}
+	}
+
+	return reassigned, unassigned, nil
+}
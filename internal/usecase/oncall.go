@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+const _maxOnCallWeeks = 52
+
+var ErrInvalidOnCall = errors.New("invalid on-call")
+
+type noOnCall struct{}
+
+func (noOnCall) GetRotation(context.Context, string) (entity.OnCallRotation, error) {
+	return entity.OnCallRotation{}, ErrNotFound
+}
+
+func (noOnCall) SetRotation(context.Context, *entity.OnCallRotation) error {
+	return nil
+}
+
+func (noOnCall) ListOverrides(context.Context, string, time.Time, time.Time) ([]entity.OnCallOverride, error) {
+	return []entity.OnCallOverride{}, nil
+}
+
+func (noOnCall) SetOverride(context.Context, entity.OnCallOverride) error {
+	return nil
+}
+
+func (noOnCall) DeleteOverride(context.Context, string, time.Time) error {
+	return nil
+}
+
+// OnCallSchedule returns the team's rotation and who is on call for each of
+// the next weeks, starting with the current one.
+func (uc *PRUseCase) OnCallSchedule(ctx context.Context, teamName string, weeks int) (entity.OnCallRotation, []entity.OnCallShift, error) {
+	if err := uc.requireTeam(ctx, teamName); err != nil {
+		return entity.OnCallRotation{}, nil, err
+	}
+
+	weeks = max(1, min(weeks, _maxOnCallWeeks))
+
+	rot, err := uc.onCall.GetRotation(ctx, teamName)
+	if errors.Is(err, ErrNotFound) {
+		rot, err = entity.OnCallRotation{TeamName: teamName, Members: []string{}}, nil
+	}
+	if err != nil {
+		return entity.OnCallRotation{}, nil, err
+	}
+
+	from := entity.WeekStart(time.Now())
+	overrides, err := uc.onCall.ListOverrides(ctx, teamName, from, from.AddDate(0, 0, 7*weeks))
+	if err != nil {
+		return entity.OnCallRotation{}, nil, err
+	}
+
+	byWeek := make(map[time.Time]string, len(overrides))
+	for _, o := range overrides {
+		byWeek[o.WeekStart] = o.UserID
+	}
+
+	shifts := make([]entity.OnCallShift, weeks)
+	for i := range shifts {
+		week := from.AddDate(0, 0, 7*i)
+		if userID, ok := byWeek[week]; ok {
+			shifts[i] = entity.OnCallShift{WeekStart: week, UserID: userID, Override: true}
+		} else {
+			shifts[i] = entity.OnCallShift{WeekStart: week, UserID: rot.At(week)}
+		}
+	}
+
+	return rot, shifts, nil
+}
+
+// SetOnCallRotation replaces the team's on-call order. Every member must
+// belong to the team; an empty list disables the rotation. Only a lead of the
+// team, a delegate with the settings scope or an admin may set it.
+func (uc *PRUseCase) SetOnCallRotation(ctx context.Context, teamName string, members []string) (entity.OnCallRotation, error) {
+	if err := uc.requireTeam(ctx, teamName); err != nil {
+		return entity.OnCallRotation{}, err
+	}
+
+	if err := uc.authorizeLead(ctx, teamName, entity.ScopeSettings); err != nil {
+		return entity.OnCallRotation{}, err
+	}
+
+	members = normalizeTags(members)
+	for _, id := range members {
+		if err := uc.requireTeamMember(ctx, teamName, id); err != nil {
+			return entity.OnCallRotation{}, err
+		}
+	}
+
+	rot := entity.OnCallRotation{TeamName: teamName, Members: members}
+	if err := uc.onCall.SetRotation(ctx, &rot); err != nil {
+		return entity.OnCallRotation{}, err
+	}
+
+	return rot, nil
+}
+
+// OverrideOnCall puts userID on call for the week containing week instead of
+// the rotation's pick. An empty userID removes the override. It is authorized
+// as SetOnCallRotation is.
+func (uc *PRUseCase) OverrideOnCall(ctx context.Context, teamName string, week time.Time, userID string) (entity.OnCallShift, error) {
+	if err := uc.requireTeam(ctx, teamName); err != nil {
+		return entity.OnCallShift{}, err
+	}
+
+	if err := uc.authorizeLead(ctx, teamName, entity.ScopeSettings); err != nil {
+		return entity.OnCallShift{}, err
+	}
+
+	weekStart := entity.WeekStart(week)
+
+	if userID == "" {
+		if err := uc.onCall.DeleteOverride(ctx, teamName, weekStart); err != nil {
+			return entity.OnCallShift{}, err
+		}
+		userID, err := uc.onCallAt(ctx, teamName, weekStart)
+		return entity.OnCallShift{WeekStart: weekStart, UserID: userID}, err
+	}
+
+	if err := uc.requireTeamMember(ctx, teamName, userID); err != nil {
+		return entity.OnCallShift{}, err
+	}
+
+	o := entity.OnCallOverride{TeamName: teamName, WeekStart: weekStart, UserID: userID}
+	if err := uc.onCall.SetOverride(ctx, o); err != nil {
+		return entity.OnCallShift{}, err
+	}
+
+	return entity.OnCallShift{WeekStart: weekStart, UserID: userID, Override: true}, nil
+}
+
+// needsOnCall reports whether a PR must include the on-call reviewer: it is
+// labelled with one of entity.UrgentLabels or has urgent priority.
+func needsOnCall(labels []string, priority string) bool {
+	return priority == entity.PriorityUrgent || entity.IsUrgent(labels)
+}
+
+// onCallAt returns who is on call for the team at t, or "" when nobody is.
+func (uc *PRUseCase) onCallAt(ctx context.Context, teamName string, t time.Time) (string, error) {
+	week := entity.WeekStart(t)
+
+	overrides, err := uc.onCall.ListOverrides(ctx, teamName, week, week.AddDate(0, 0, 7))
+	if err != nil {
+		return "", err
+	}
+	if len(overrides) > 0 {
+		return overrides[0].UserID, nil
+	}
+
+	rot, err := uc.onCall.GetRotation(ctx, teamName)
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return rot.At(week), nil
+}
+
+// onCallReviewer returns the on-call member who can review author's PR at t,
+// or "" when nobody is on call, they are inactive or they are the author.
+func (uc *PRUseCase) onCallReviewer(ctx context.Context, author entity.User, t time.Time) (string, error) {
+	userID, err := uc.onCallAt(ctx, author.TeamName, t)
+	if err != nil || userID == "" || userID == author.UserID {
+		return "", err
+	}
+
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !u.IsActive || u.TeamName != author.TeamName {
+		return "", nil
+	}
+
+	if absent, err := uc.isAbsent(ctx, userID, t); err != nil || absent {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+// addOnCall makes the on-call member a reviewer of pr, unless they are
+// barred or at their open review limit. When pr already has a full set of
+// reviewers, the last one who hasn't approved makes way and is returned as
+// replaced; if all of them approved, on-call isn't added.
+func (uc *PRUseCase) addOnCall(ctx context.Context, pr *entity.PullRequest, author entity.User) (added, replaced string, err error) {
+	onCall, err := uc.onCallReviewer(ctx, author, time.Now())
+	if err != nil || onCall == "" || contains(pr.AssignedReviewers, onCall) {
+		return "", "", err
+	}
+
+	barred, err := uc.barredReviewers(ctx, pr.AuthorID, pr.Labels)
+	if err != nil || contains(barred, onCall) {
+		return "", "", err
+	}
+
+	saturated, err := uc.saturatedReviewers(ctx, author.TeamName)
+	if err != nil || contains(saturated, onCall) {
+		return "", "", err
+	}
+
+	if len(pr.AssignedReviewers) >= _reviewersPerPR {
+		for i := len(pr.AssignedReviewers) - 1; i >= 0; i-- {
+			if r := pr.AssignedReviewers[i]; !contains(pr.Approvals, r) {
+				replaced = r
+				break
+			}
+		}
+		if replaced == "" {
+			return "", "", nil
+		}
+		pr.AssignedReviewers = without(pr.AssignedReviewers, replaced)
+	}
+
+	pr.AssignedReviewers = append(pr.AssignedReviewers, onCall)
+
+	return onCall, replaced, nil
+}
+
+func (uc *PRUseCase) requireTeam(ctx context.Context, teamName string) error {
+	exists, err := uc.teamRepo.Exists(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (uc *PRUseCase) requireTeamMember(ctx context.Context, teamName, userID string) error {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%w: user %s not found", ErrInvalidOnCall, userID)
+	}
+	if err != nil {
+		return err
+	}
+	if u.TeamName != teamName {
+		return fmt.Errorf("%w: user %s is not a member of %s", ErrInvalidOnCall, userID, teamName)
+	}
+	return nil
+}
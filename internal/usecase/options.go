@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/evrone/go-clean-template/pkg/notifier"
+)
+
+// Option -.
+type Option func(*PRUseCase)
+
+// WithIDGenerator sets the generator used when clients omit IDs on create.
+func WithIDGenerator(g IDGenerator) Option {
+	return func(uc *PRUseCase) {
+		uc.ids = g
+	}
+}
+
+// WithNotifier sets where user-facing notifications are delivered.
+func WithNotifier(n notifier.Notifier) Option {
+	return func(uc *PRUseCase) {
+		uc.notifier = n
+	}
+}
+
+// WithHealthThresholds sets the limits used by TeamHealth.
+func WithHealthThresholds(t HealthThresholds) Option {
+	return func(uc *PRUseCase) {
+		uc.health = t
+	}
+}
+
+// WithTeamSettingsRepo sets where per-team overrides are stored.
+func WithTeamSettingsRepo(r TeamSettingsRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.teamSettings = r
+	}
+}
+
+// WithQuota sets the service-wide open-PR quota defaults.
+func WithQuota(q QuotaPolicy) Option {
+	return func(uc *PRUseCase) {
+		uc.quota = q
+	}
+}
+
+// WithIdentityRepo sets where links to external accounts are stored.
+func WithIdentityRepo(r IdentityRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.identities = r
+	}
+}
+
+// WithWorkloadRepo sets where per-user review load is read from.
+func WithWorkloadRepo(r WorkloadRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.workload = r
+	}
+}
+
+// WithStatsRepo sets where reporting aggregates are read from.
+func WithStatsRepo(r StatsRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.stats = r
+	}
+}
+
+// WithStatsShedding makes the stats methods remember their results and
+// return one at most maxAge old, flagged stale, while degraded reports the
+// database overloaded or when their query fails.
+func WithStatsShedding(degraded func() bool, maxAge time.Duration) Option {
+	return func(uc *PRUseCase) {
+		uc.statsCache = newStatsCache(degraded, maxAge)
+	}
+}
+
+// WithStatsHistoryRepo sets where daily per-team stats snapshots are kept.
+func WithStatsHistoryRepo(r StatsHistoryRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.statsHistory = r
+	}
+}
+
+// WithEventSink sets where PR lifecycle events are mirrored for analytics.
+func WithEventSink(s EventSink) Option {
+	return func(uc *PRUseCase) {
+		uc.events = s
+	}
+}
+
+// WithUserEventSink sets where user changes are published.
+func WithUserEventSink(s UserEventSink) Option {
+	return func(uc *PRUseCase) {
+		uc.userEvents = s
+	}
+}
+
+// WithReviewerMirror sets where reviewer assignments are mirrored in the VCS.
+func WithReviewerMirror(m ReviewerMirror) Option {
+	return func(uc *PRUseCase) {
+		uc.mirror = m
+	}
+}
+
+// WithOutgoingWebhooks sets where team webhook registrations are stored and
+// how PR events are delivered to them.
+func WithOutgoingWebhooks(repo OutgoingWebhookRepo, sender HookSender) Option {
+	return func(uc *PRUseCase) {
+		uc.hooks = repo
+		uc.hookSender = sender
+	}
+}
+
+// WithAssignmentAcks makes webhooks with RequireAck acknowledge reviewer.assigned
+// deliveries under p, tracked in r.
+func WithAssignmentAcks(r AssignmentAckRepo, p AckPolicy) Option {
+	return func(uc *PRUseCase) {
+		uc.acks = r
+		uc.ackPolicy = p
+	}
+}
+
+// WithReviewerReconciliation resolves reviewer changes made directly in a VCS
+// under policy and records the conflicts in r.
+func WithReviewerReconciliation(r ReviewerConflictRepo, policy string) Option {
+	return func(uc *PRUseCase) {
+		uc.conflicts = r
+		uc.reconcilePolicy = policy
+	}
+}
+
+// WithTxManager sets how multi-repository operations are made atomic.
+func WithTxManager(m TxManager) Option {
+	return func(uc *PRUseCase) {
+		uc.tx = hookedTx{m}
+	}
+}
+
+// WithAuditRepo sets where audited mutations are recorded.
+func WithAuditRepo(r AuditRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.auditLog = r
+	}
+}
+
+// WithRotationRepo sets where round-robin teams keep their cursor.
+func WithRotationRepo(r RotationRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.rotation = r
+	}
+}
+
+// WithStrategy registers s so teams can select it by name, replacing a
+// built-in strategy of the same name.
+func WithStrategy(s AssignmentStrategy) Option {
+	return func(uc *PRUseCase) {
+		uc.strategies[s.Name()] = s
+	}
+}
+
+// WithReviewCooldown ranks recently assigned candidates last; see ReviewCooldown.
+func WithReviewCooldown(c ReviewCooldown) Option {
+	return func(uc *PRUseCase) {
+		uc.cooldown = c
+	}
+}
+
+// WithAssignmentStrategy names the strategy for teams that don't choose one.
+func WithAssignmentStrategy(s string) Option {
+	return func(uc *PRUseCase) {
+		uc.strategy = s
+	}
+}
+
+// WithOnCallRepo sets where teams' on-call rotations are kept.
+func WithOnCallRepo(r OnCallRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.onCall = r
+	}
+}
+
+// WithReviewRuleRepo sets where per-repository path rules are kept.
+func WithReviewRuleRepo(r ReviewRuleRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.reviewRules = r
+	}
+}
+
+// WithReviewExclusionRepo sets where reviewer exclusions are kept.
+func WithReviewExclusionRepo(r ReviewExclusionRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.exclusions = r
+	}
+}
+
+// WithReviewRepo sets where reviewers' decisions are kept.
+func WithReviewRepo(r ReviewRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.reviews = r
+	}
+}
+
+// WithDecisionCapture sets where assignment decisions are offered for debug capture.
+func WithDecisionCapture(c DecisionCapture) Option {
+	return func(uc *PRUseCase) {
+		uc.capture = c
+	}
+}
+
+// WithRequiredApprovals sets how many approvals MergePR requires of PRs whose
+// team has no requirement of its own; 0 requires none.
+func WithRequiredApprovals(n int) Option {
+	return func(uc *PRUseCase) {
+		uc.requiredApprovals = n
+	}
+}
+
+// WithAbsenceRepo sets where users' absences are kept.
+func WithAbsenceRepo(r AbsenceRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.absences = r
+	}
+}
+
+// WithDelegationRepo sets where team leads' delegations are kept.
+func WithDelegationRepo(r DelegationRepo) Option {
+	return func(uc *PRUseCase) {
+		uc.delegations = r
+	}
+}
+
+// WithRBAC makes management requests without an actor fail with ErrForbidden
+// instead of being allowed, and has the controllers trust only authenticated
+// actors (see RBACEnforced).
+func WithRBAC(enforce bool) Option {
+	return func(uc *PRUseCase) {
+		uc.enforceRBAC = enforce
+	}
+}
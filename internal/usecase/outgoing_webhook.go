@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/webhook"
+)
+
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// CreateOutgoingWebhook registers h for the team. A secret is generated when
+// none is given; it is only returned here. Webhooks are managed by leads of
+// their team, delegates with the settings scope and admins.
+func (uc *PRUseCase) CreateOutgoingWebhook(ctx context.Context, h entity.OutgoingWebhook) (entity.OutgoingWebhook, error) {
+	exists, err := uc.teamRepo.Exists(ctx, h.TeamName)
+	if err != nil {
+		return entity.OutgoingWebhook{}, err
+	}
+	if !exists {
+		return entity.OutgoingWebhook{}, ErrNotFound
+	}
+
+	if err := uc.authorizeLead(ctx, h.TeamName, entity.ScopeSettings); err != nil {
+		return entity.OutgoingWebhook{}, err
+	}
+
+	if err := validateHook(&h, uc.ackPolicy.URL); err != nil {
+		return entity.OutgoingWebhook{}, err
+	}
+
+	if h.Secret == "" {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		h.Secret = hex.EncodeToString(b)
+	}
+
+	if err := uc.hooks.Create(ctx, &h); err != nil {
+		return entity.OutgoingWebhook{}, err
+	}
+
+	return h, nil
+}
+
+// ListOutgoingWebhooks returns the team's webhooks without their secrets.
+func (uc *PRUseCase) ListOutgoingWebhooks(ctx context.Context, teamName string) ([]entity.OutgoingWebhook, error) {
+	hooks, err := uc.hooks.ListByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+
+	return hooks, nil
+}
+
+// OutgoingWebhookUpdate changes the fields that are set and keeps the nil ones.
+type OutgoingWebhookUpdate struct {
+	URL        *string
+	Secret     *string
+	Events     *[]string
+	RequireAck *bool
+}
+
+// UpdateOutgoingWebhook -. The returned webhook has no secret.
+func (uc *PRUseCase) UpdateOutgoingWebhook(ctx context.Context, id int64, upd OutgoingWebhookUpdate) (entity.OutgoingWebhook, error) {
+	h, err := uc.hooks.GetByID(ctx, id)
+	if err != nil {
+		return entity.OutgoingWebhook{}, lookupErr(err)
+	}
+
+	if err := uc.authorizeLead(ctx, h.TeamName, entity.ScopeSettings); err != nil {
+		return entity.OutgoingWebhook{}, err
+	}
+
+	if upd.URL != nil {
+		h.URL = *upd.URL
+	}
+	if upd.Secret != nil && *upd.Secret != "" {
+		h.Secret = *upd.Secret
+	}
+	if upd.Events != nil {
+		h.Events = *upd.Events
+	}
+	if upd.RequireAck != nil {
+		h.RequireAck = *upd.RequireAck
+	}
+
+	if err := validateHook(&h, uc.ackPolicy.URL); err != nil {
+		return entity.OutgoingWebhook{}, err
+	}
+
+	if err := uc.hooks.Update(ctx, &h); err != nil {
+		return entity.OutgoingWebhook{}, lookupErr(err)
+	}
+
+	h.Secret = ""
+	return h, nil
+}
+
+func (uc *PRUseCase) DeleteOutgoingWebhook(ctx context.Context, id int64) error {
+	h, err := uc.hooks.GetByID(ctx, id)
+	if err != nil {
+		return lookupErr(err)
+	}
+
+	if err := uc.authorizeLead(ctx, h.TeamName, entity.ScopeSettings); err != nil {
+		return err
+	}
+
+	return lookupErr(uc.hooks.Delete(ctx, id))
+}
+
+// _hookTestTimeout bounds how long a test delivery waits for the endpoint.
+const _hookTestTimeout = 10 * time.Second
+
+// TestOutgoingWebhook delivers a sample event to the webhook right away and
+// reports the endpoint's status code and latency. event defaults to
+// entity.HookEventPing; the webhook's subscriptions don't apply. Only a lead
+// of the webhook's team, a delegate with the settings scope or an admin may
+// test it.
+func (uc *PRUseCase) TestOutgoingWebhook(ctx context.Context, id int64, event string) (entity.HookTestResult, error) {
+	if event == "" {
+		event = entity.HookEventPing
+	}
+	if event != entity.HookEventPing && !slices.Contains(entity.HookEvents, event) {
+		return entity.HookTestResult{}, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+	}
+
+	h, err := uc.hooks.GetByID(ctx, id)
+	if err != nil {
+		return entity.HookTestResult{}, lookupErr(err)
+	}
+
+	if err := uc.authorizeLead(ctx, h.TeamName, entity.ScopeSettings); err != nil {
+		return entity.HookTestResult{}, err
+	}
+
+	e := sampleHookEvent(event, h.TeamName)
+	e.ID = uc.ids.New()
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return entity.HookTestResult{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, _hookTestTimeout)
+	defer cancel()
+
+	res, err := uc.hookSender.Deliver(ctx, h.URL, h.Secret, e.Event, e.ID, payload)
+
+	result := entity.HookTestResult{
+		DeliveryID: e.ID,
+		Event:      e.Event,
+		URL:        h.URL,
+		OK:         err == nil && res.StatusCode >= 200 && res.StatusCode < 300,
+		StatusCode: res.StatusCode,
+		DurationMs: float64(res.Duration) / float64(time.Millisecond),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result, nil
+}
+
+// sampleHookEvent builds a made-up event of the given type for teamName.
+func sampleHookEvent(event, teamName string) entity.HookEvent {
+	now := time.Now().UTC()
+
+	e := entity.HookEvent{
+		Event:      event,
+		TeamName:   teamName,
+		OccurredAt: now,
+		PullRequest: entity.PullRequest{
+			PullRequestID:     "pr-test",
+			PullRequestName:   "Test delivery",
+			AuthorID:          "u-author",
+			Status:            entity.PRStatusOpen,
+			AssignedReviewers: []string{"u-reviewer-1", "u-reviewer-2"},
+			Approvals:         []string{},
+			Labels:            []string{},
+			Priority:          entity.PriorityNormal,
+			ExternalState:     entity.ExternalStateClean,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		},
+	}
+
+	switch event {
+	case entity.HookEventPRMerged:
+		e.PullRequest.Status = entity.PRStatusMerged
+		e.PullRequest.MergedAt = &now
+	case entity.HookEventReviewerReassigned:
+		e.OldReviewerID = "u-reviewer-2"
+		e.NewReviewerID = "u-reviewer-3"
+		e.PullRequest.AssignedReviewers = []string{"u-reviewer-1", "u-reviewer-3"}
+	case entity.HookEventReviewerAssigned:
+		e.NewReviewerID = "u-reviewer-2"
+	}
+
+	return e
+}
+
+// validateHook checks h; require_ack needs ackURL, where receivers confirm.
+// Hostnames are only checked when deliveries dial them, but an IP address
+// must be public.
+func validateHook(h *entity.OutgoingWebhook, ackURL string) error {
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !webhook.IsPublic(ip) {
+		return fmt.Errorf("%w: url must not point to a loopback, private or link-local address", ErrInvalidWebhook)
+	}
+
+	if h.RequireAck && ackURL == "" {
+		return fmt.Errorf("%w: require_ack needs HOOKS_PUBLIC_URL to be configured", ErrInvalidWebhook)
+	}
+
+	if h.Events == nil {
+		h.Events = []string{}
+	}
+
+	for _, e := range h.Events {
+		if !slices.Contains(entity.HookEvents, e) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, e)
+		}
+	}
+
+	return nil
+}
+
+// emitHook sends e to every webhook of the team subscribed to it once the
+// transaction in ctx commits. The team defaults to the author's. Failures
+// never affect the PR operation.
+func (uc *PRUseCase) emitHook(ctx context.Context, e entity.HookEvent) {
+	e.PullRequest.AssignedReviewers = slices.Clone(e.PullRequest.AssignedReviewers)
+	afterCommit(ctx, func(ctx context.Context) { uc.deliverHook(ctx, e) })
+}
+
+func (uc *PRUseCase) deliverHook(ctx context.Context, e entity.HookEvent) {
+	if e.TeamName == "" {
+		author, err := uc.userRepo.GetByID(ctx, e.PullRequest.AuthorID)
+		if err != nil {
+			return
+		}
+		e.TeamName = author.TeamName
+	}
+
+	hooks, err := uc.hooks.ListByTeam(ctx, e.TeamName)
+	if err != nil || len(hooks) == 0 {
+		return
+	}
+
+	e.ID = uc.ids.New()
+	e.OccurredAt = time.Now().UTC()
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	for _, h := range hooks {
+		if !h.Wants(e.Event) {
+			continue
+		}
+		if h.RequireAck && e.Event == entity.HookEventReviewerAssigned {
+			uc.sendForAck(ctx, h, e)
+			continue
+		}
+		uc.hookSender.Send(h.URL, h.Secret, e.Event, e.ID, payload)
+	}
+}
+
+// noHooks is used until outgoing webhooks are configured.
+type noHooks struct{}
+
+func (noHooks) Create(context.Context, *entity.OutgoingWebhook) error {
+	return fmt.Errorf("outgoing webhooks are not configured")
+}
+
+func (noHooks) GetByID(context.Context, int64) (entity.OutgoingWebhook, error) {
+	return entity.OutgoingWebhook{}, ErrNotFound
+}
+
+func (noHooks) ListByTeam(context.Context, string) ([]entity.OutgoingWebhook, error) {
+	return []entity.OutgoingWebhook{}, nil
+}
+
+func (noHooks) Update(context.Context, *entity.OutgoingWebhook) error { return ErrNotFound }
+
+func (noHooks) Delete(context.Context, int64) error { return ErrNotFound }
+
+func (noHooks) Send(string, string, string, string, []byte) {}
+
+func (noHooks) Deliver(context.Context, string, string, string, string, []byte) (webhook.Result, error) {
+	return webhook.Result{}, fmt.Errorf("outgoing webhooks are not configured")
+}
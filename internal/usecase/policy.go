@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+)
+
+// AgeOutPolicy flags users without reviews or authored PRs for InactiveAfter,
+// optionally deactivating them so they stop receiving assignments.
+type AgeOutPolicy struct {
+	InactiveAfter  time.Duration
+	AutoDeactivate bool
+}
+
+type AgeOutReport struct {
+	Flagged     []string `json:"flagged"`
+	Deactivated []string `json:"deactivated"`
+	Cleared     int64    `json:"cleared"`
+}
+
+func (uc *PRUseCase) AgeOutInactiveUsers(ctx context.Context, now time.Time, p AgeOutPolicy) (AgeOutReport, error) {
+	report := AgeOutReport{Flagged: []string{}, Deactivated: []string{}}
+	since := now.Add(-p.InactiveAfter)
+
+	cleared, err := uc.userRepo.ClearIdleFlags(ctx, since)
+	if err != nil {
+		return report, err
+	}
+	report.Cleared = cleared
+
+	idle, err := uc.userRepo.ListIdle(ctx, since)
+	if err != nil {
+		return report, err
+	}
+
+	days := int(p.InactiveAfter.Hours() / 24)
+
+	for _, u := range idle {
+		flaggedAt := now.UTC()
+		if err := uc.userRepo.SetFlaggedInactive(ctx, u.UserID, &flaggedAt); err != nil {
+			return report, err
+		}
+		report.Flagged = append(report.Flagged, u.UserID)
+
+		text := fmt.Sprintf("%s (%s) had no reviews or PRs for %d days", u.Username, u.UserID, days)
+
+		if p.AutoDeactivate {
+			u.IsActive = false
+			if err := uc.userRepo.Update(ctx, &u); err != nil {
+				return report, err
+			}
+			uc.publishUser(ctx, entity.UserEventUpdated, u)
+			report.Deactivated = append(report.Deactivated, u.UserID)
+			text += " and was deactivated"
+		}
+
+		// Addressed to the team: leads pick it up from the team channel.
+		_ = uc.notifier.Notify(ctx, notifier.Message{
+			Kind:     "user_idle",
+			TeamName: u.TeamName,
+			Text:     text,
+		})
+	}
+
+	return report, nil
+}
+
+// AutoClosePolicy closes PRs idle for AbandonedAfter. The author is warned
+// first and any activity during GracePeriod cancels the close.
+type AutoClosePolicy struct {
+	AbandonedAfter time.Duration
+	GracePeriod    time.Duration
+}
+
+type AutoCloseReport struct {
+	Warned    []string `json:"warned"`
+	Closed    []string `json:"closed"`
+	Reprieved []string `json:"reprieved"`
+}
+
+func (uc *PRUseCase) AutoCloseAbandonedPRs(ctx context.Context, now time.Time, p AutoClosePolicy) (AutoCloseReport, error) {
+	report := AutoCloseReport{Warned: []string{}, Closed: []string{}, Reprieved: []string{}}
+	now = now.UTC()
+
+	warned, err := uc.prRepo.ListCloseWarned(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, pr := range warned {
+		if pr.UpdatedAt.After(*pr.CloseWarnedAt) {
+			if err := uc.prRepo.SetCloseWarning(ctx, pr.PullRequestID, nil); err != nil {
+				return report, err
+			}
+			report.Reprieved = append(report.Reprieved, pr.PullRequestID)
+
+			continue
+		}
+
+		if now.Before(pr.CloseWarnedAt.Add(p.GracePeriod)) {
+			continue
+		}
+
+		pr.Status = entity.PRStatusClosed
+		pr.ClosedAt = &now
+		pr.CloseWarnedAt = nil
+		if err := uc.prRepo.Update(ctx, &pr); err != nil {
+			return report, err
+		}
+		report.Closed = append(report.Closed, pr.PullRequestID)
+		uc.publish(ctx, entity.PREventClosed, pr, "", "")
+
+		_ = uc.notifier.Notify(ctx, notifier.Message{
+			Kind:          "pr_auto_closed",
+			UserID:        pr.AuthorID,
+			PullRequestID: pr.PullRequestID,
+			Text:          fmt.Sprintf("%q was closed after %s without activity", pr.PullRequestName, p.AbandonedAfter+p.GracePeriod),
+		})
+	}
+
+	abandoned, err := uc.prRepo.ListAbandoned(ctx, now.Add(-p.AbandonedAfter))
+	if err != nil {
+		return report, err
+	}
+
+	for _, pr := range abandoned {
+		if err := uc.prRepo.SetCloseWarning(ctx, pr.PullRequestID, &now); err != nil {
+			return report, err
+		}
+		report.Warned = append(report.Warned, pr.PullRequestID)
+
+		_ = uc.notifier.Notify(ctx, notifier.Message{
+			Kind:          "pr_close_warning",
+			UserID:        pr.AuthorID,
+			PullRequestID: pr.PullRequestID,
+			Text: fmt.Sprintf("%q has had no activity for %s and will be closed in %s unless it is updated",
+				pr.PullRequestName, p.AbandonedAfter, p.GracePeriod),
+		})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrPreconditionFailed = errors.New("PRECONDITION_FAILED")
+
+// Precondition guards a mutation against stale clients: it fails when the
+// stored resource changed after the version the client last saw.
+type Precondition struct {
+	// UnmodifiedSince comes from If-Unmodified-Since and has second precision.
+	UnmodifiedSince time.Time
+	// ExpectedVersion is the exact updated_at the client read.
+	ExpectedVersion time.Time
+}
+
+type preconditionKey struct{}
+
+// WithPrecondition attaches p to ctx for the mutating use case to enforce.
+func WithPrecondition(ctx context.Context, p Precondition) context.Context {
+	return context.WithValue(ctx, preconditionKey{}, p)
+}
+
+// HasPrecondition reports whether ctx carries a precondition. Repos then only
+// write a resource still at the version the use case read, so a change made
+// between the check and the write fails it too.
+func HasPrecondition(ctx context.Context) bool {
+	_, ok := ctx.Value(preconditionKey{}).(Precondition)
+	return ok
+}
+
+// checkPrecondition compares the stored updated_at with the precondition on ctx, if any.
+func checkPrecondition(ctx context.Context, updatedAt time.Time) error {
+	p, ok := ctx.Value(preconditionKey{}).(Precondition)
+	if !ok {
+		return nil
+	}
+
+	if !p.UnmodifiedSince.IsZero() && updatedAt.Truncate(time.Second).After(p.UnmodifiedSince) {
+		return ErrPreconditionFailed
+	}
+
+	if !p.ExpectedVersion.IsZero() && !updatedAt.Equal(p.ExpectedVersion) {
+		return ErrPreconditionFailed
+	}
+
+	return nil
+}
@@ -3,105 +3,858 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/idgen"
+	"github.com/evrone/go-clean-template/pkg/notifier"
 )
 
 var (
-	ErrNotFound    = errors.New("not found")
-	ErrPRExists    = errors.New("PR exists")
-	ErrPRMerged    = errors.New("PR_MERGED")
-	ErrNotAssigned = errors.New("NOT_ASSIGNED")
-	ErrNoCandidate = errors.New("NO_CANDIDATE")
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrPRExists      = errors.New("PR exists")
+	ErrTeamExists    = errors.New("TEAM_EXISTS")
+	ErrPRMerged      = errors.New("PR_MERGED")
+	ErrPRClosed      = errors.New("PR_CLOSED")
+	ErrPRDraft       = errors.New("PR_DRAFT")
+	// ErrPRImmutable rejects changes to the author, reviewers or merge of a merged PR.
+	ErrPRImmutable   = errors.New("PR_IMMUTABLE")
+	ErrNotAssigned   = errors.New("NOT_ASSIGNED")
+	ErrNoCandidate   = errors.New("NO_CANDIDATE")
+	ErrNotTeamMember = errors.New("NOT_TEAM_MEMBER")
+
+	ErrApprovalsRequired = errors.New("APPROVALS_REQUIRED")
+
+	ErrInvalidPRUpdate = errors.New("invalid PR update")
+	ErrInvalidPriority = errors.New("invalid priority")
+	ErrInvalidStack    = errors.New("invalid stack")
+)
+
+const (
+	// _reviewersPerPR is how many reviewers CreatePR assigns at most.
+	_reviewersPerPR = 2
+	// _candidatePool is how many members are shortlisted as candidates.
+	_candidatePool = 10
 )
 
 type PRUseCase struct {
 	prRepo   PRRepo
 	userRepo UserRepo
 	teamRepo TeamRepo
+	ids      IDGenerator
+	notifier notifier.Notifier
+	health   HealthThresholds
+
+	teamSettings TeamSettingsRepo
+	identities   IdentityRepo
+	auditLog     AuditRepo
+	workload     WorkloadRepo
+	stats        StatsRepo
+	statsHistory StatsHistoryRepo
+	statsCache   *statsCache
+	events       EventSink
+	userEvents   UserEventSink
+	mirror       ReviewerMirror
+	conflicts    ReviewerConflictRepo
+	hooks        OutgoingWebhookRepo
+	hookSender   HookSender
+	acks         AssignmentAckRepo
+	ackPolicy    AckPolicy
+	tx           TxManager
+	quota        QuotaPolicy
+	rotation     RotationRepo
+	onCall       OnCallRepo
+	reviewRules  ReviewRuleRepo
+	exclusions   ReviewExclusionRepo
+	absences     AbsenceRepo
+	delegations  DelegationRepo
+	reviews      ReviewRepo
+	capture      DecisionCapture
+	// requiredApprovals applies to teams that set no requirement of their own.
+	requiredApprovals int
+	// enforceRBAC rejects management requests that name no actor.
+	enforceRBAC bool
+	// reconcilePolicy resolves external reviewer changes (entity.Reconcile*).
+	reconcilePolicy string
+	strategy        string
+	strategies      map[string]AssignmentStrategy
+	cooldown        ReviewCooldown
 }
 
-func NewPRUseCase(prRepo PRRepo, userRepo UserRepo, teamRepo TeamRepo) *PRUseCase {
-	return &PRUseCase{
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		teamRepo: teamRepo,
+func NewPRUseCase(prRepo PRRepo, userRepo UserRepo, teamRepo TeamRepo, opts ...Option) *PRUseCase {
+	uc := &PRUseCase{
+		prRepo:   validatedPRs{prRepo},
+		userRepo: validatedUsers{userRepo},
+		teamRepo: validatedTeams{teamRepo},
+		ids:      idgen.UUIDv7{},
+		notifier: notifier.Nop{},
+		health:   DefaultHealthThresholds(),
+
+		teamSettings: noTeamSettings{},
+		identities:   noIdentities{},
+		auditLog:     noAudit{},
+		workload:     noWorkload{},
+		stats:        noStats{},
+		statsHistory: noStatsHistory{},
+		events:       noEvents{},
+		userEvents:   noEvents{},
+		mirror:       noMirror{},
+		conflicts:    noConflicts{},
+		hooks:        noHooks{},
+		hookSender:   noHooks{},
+		acks:         noAcks{},
+		tx:           hookedTx{noTx{}},
+		quota:        QuotaPolicy{Mode: entity.QuotaModeWarn},
+		rotation:     noRotation{},
+		onCall:       noOnCall{},
+		reviewRules:  noReviewRules{},
+		exclusions:   noReviewExclusions{},
+		absences:     noAbsences{},
+		delegations:  noDelegations{},
+		reviews:      noReviews{},
+		capture:      noCapture{},
+		strategy:     entity.AssignmentLeastLoaded,
+		strategies:   map[string]AssignmentStrategy{},
+
+		reconcilePolicy: entity.ReconcileMerge,
 	}
+
+	for _, opt := range opts {
+		opt(uc)
+	}
+
+	for _, s := range []AssignmentStrategy{
+		leastLoaded{users: uc.userRepo, prs: uc.prRepo},
+		roundRobin{rotation: uc.rotation},
+		random{users: uc.userRepo},
+	} {
+		if _, ok := uc.strategies[s.Name()]; !ok {
+			uc.strategies[s.Name()] = s
+		}
+	}
+
+	return uc
+}
+
+// CreatePRInput -. Repository and ExternalNumber identify the PR in the VCS;
+// when both are set they must be unique together.
+type CreatePRInput struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	Repository      string
+	ExternalNumber  int
+	Labels          []string
+	// Priority defaults to normal.
+	Priority string
+	// ChangedFiles are matched against the repository's review rules.
+	ChangedFiles []string
+	// Draft PRs are stored without reviewers; MarkReady assigns them.
+	Draft bool
+	// StackID ties the PR to the author's other PRs in a stack; see stackOrder.
+	StackID string
 }
 
-func (uc *PRUseCase) CreatePR(ctx context.Context, prID, prName, authorID string) (entity.PullRequest, error) {
-	existing, err := uc.prRepo.GetByID(ctx, prID)
-	if err == nil && existing.PullRequestID != "" {
-		return entity.PullRequest{}, ErrPRExists
+// CreatePR creates a PR and assigns reviewers. An empty ID is generated server-side.
+// Warnings report soft-quota breaches that did not block creation. An urgent PR
+// (see needsOnCall) always gets the team's on-call member as a reviewer; the
+// remaining slots go to owners of ChangedFiles under the repository's review
+// rules, then to the team's assignment strategy, which skips members at their
+// max_open_reviews. A stacked PR's candidates are reordered by the team's
+// stack_reviewers setting (see stackOrder). Draft PRs skip the quota check
+// and assignment.
+func (uc *PRUseCase) CreatePR(ctx context.Context, in CreatePRInput) (entity.PullRequest, []Warning, error) {
+	prID, authorID := in.PullRequestID, in.AuthorID
+	labels := normalizeTags(in.Labels)
+
+	priority := in.Priority
+	if priority == "" {
+		priority = entity.PriorityNormal
+	}
+	if err := validatePriority(priority); err != nil {
+		return entity.PullRequest{}, nil, err
 	}
 
-	author, err := uc.userRepo.GetByID(ctx, authorID)
+	stackID := strings.TrimSpace(in.StackID)
+	if len(stackID) > _maxStackID {
+		return entity.PullRequest{}, nil, fmt.Errorf("%w: stack_id must be at most %d bytes", ErrInvalidStack, _maxStackID)
+	}
+
+	if prID == "" {
+		prID = uc.ids.New()
+	} else {
+		existing, err := uc.prRepo.GetByID(ctx, prID)
+		if err == nil && existing.PullRequestID != "" {
+			return entity.PullRequest{}, nil, ErrPRExists
+		}
+	}
+
+	if in.Repository != "" && in.ExternalNumber > 0 {
+		if _, err := uc.prRepo.GetByExternal(ctx, in.Repository, in.ExternalNumber); err == nil {
+			return entity.PullRequest{}, nil, ErrPRExists
+		}
+	}
+
+	var (
+		pr       entity.PullRequest
+		warnings []Warning
+		teamName string
+	)
+
+	// The author's row stays locked until the insert commits, so concurrent
+	// creates by the same author pass the open-PR quota one at a time.
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		author, err := uc.userRepo.GetForUpdate(ctx, authorID)
+		if err != nil {
+			return lookupErr(err)
+		}
+		teamName = author.TeamName
+
+		now := time.Now().UTC()
+		pr = entity.PullRequest{
+			PullRequestID:     prID,
+			PullRequestName:   in.PullRequestName,
+			AuthorID:          authorID,
+			Status:            entity.PRStatusOpen,
+			AssignedReviewers: []string{},
+			Approvals:         []string{},
+			CreatedAt:         now,
+			UpdatedAt:         now,
+			Repository:        in.Repository,
+			ExternalNumber:    in.ExternalNumber,
+			Labels:            labels,
+			Priority:          priority,
+			ExternalState:     entity.ExternalStateClean,
+			StackID:           stackID,
+		}
+
+		if in.Draft {
+			pr.Status = entity.PRStatusDraft
+		} else {
+			warnings, err = uc.checkAuthorQuota(ctx, author)
+			if err != nil {
+				return err
+			}
+
+			saturation, err := uc.assignReviewers(ctx, &pr, author, in.ChangedFiles, now)
+			if err != nil {
+				return err
+			}
+			warnings = append(warnings, saturation...)
+		}
+
+		err = uc.prRepo.Create(ctx, pr)
+		if errors.Is(err, ErrAlreadyExists) {
+			return ErrPRExists
+		}
+		return err
+	})
 	if err != nil {
-		return entity.PullRequest{}, ErrNotFound
+		return entity.PullRequest{}, nil, err
 	}
 
-	teamMembers, err := uc.userRepo.ListByTeam(ctx, author.TeamName)
+	uc.notifyAssigned(ctx, pr, teamName, pr.AssignedReviewers...)
+	if pr.Status == entity.PRStatusOpen {
+		uc.notifyUnderstaffed(ctx, pr, teamName)
+	}
+
+	uc.publish(ctx, entity.PREventCreated, pr, teamName, "")
+	uc.emitHook(ctx, entity.HookEvent{Event: entity.HookEventPRCreated, TeamName: teamName, PullRequest: pr})
+	for _, r := range pr.AssignedReviewers {
+		uc.publish(ctx, entity.PREventReviewerAssigned, pr, teamName, r)
+	}
+
+	return pr, warnings, nil
+}
+
+// assignReviewers picks pr's reviewers for author and records the roster they
+// came from. It must run inside the transaction that saves pr. Members at
+// their max_open_reviews are skipped whichever way they would be picked (on
+// call, rule owner or candidate); when that leaves pr short of reviewers a
+// REVIEWERS_SATURATED warning is returned.
+func (uc *PRUseCase) assignReviewers(ctx context.Context, pr *entity.PullRequest, author entity.User, changedFiles []string, now time.Time) ([]Warning, error) {
+	strategy, err := uc.assignmentStrategy(ctx, author.TeamName)
 	if err != nil {
-		return entity.PullRequest{}, ErrNotFound
+		return nil, err
+	}
+
+	absent, err := uc.absentAt(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	barred, err := uc.barredReviewers(ctx, pr.AuthorID, pr.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	saturated, err := uc.saturatedReviewers(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	exclude := append([]string{pr.AuthorID}, absent...)
+	exclude = append(exclude, saturated...)
+	exclude = append(exclude, barred...)
+	candidates, err := strategy.Rank(ctx, author.TeamName, exclude, _candidatePool)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, cooling, err := uc.coolDown(ctx, author.TeamName, candidates, now)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, stackMode, stack, err := uc.stackOrder(ctx, pr, author.TeamName, candidates, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	roster := &entity.RosterSnapshot{
+		TeamName: author.TeamName, Strategy: strategy.Name(), Excluded: barred, Candidates: []string{},
+		CoolingDown: cooling, Stack: stack, StackReviewers: stackMode, TakenAt: now,
+	}
+
+	var reviewers, picked []string
+	if needsOnCall(pr.Labels, pr.Priority) {
+		roster.OnCall, err = uc.onCallReviewer(ctx, author, now)
+		if err != nil {
+			return nil, err
+		}
+		if roster.OnCall != "" && !contains(barred, roster.OnCall) && !contains(saturated, roster.OnCall) {
+			reviewers = append(reviewers, roster.OnCall)
+		}
+	}
+
+	roster.RuleOwners, err = uc.ruleOwners(ctx, pr.Repository, changedFiles, author)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range roster.RuleOwners {
+		if len(reviewers) < _reviewersPerPR && !contains(reviewers, id) && !contains(barred, id) && !contains(saturated, id) {
+			reviewers = append(reviewers, id)
+		}
 	}
 
-	var reviewers []string
-	for _, member := range teamMembers {
-		if member.UserID != authorID && member.IsActive && len(reviewers) < 2 {
+	for _, member := range candidates {
+		roster.Candidates = append(roster.Candidates, member.UserID)
+		if len(reviewers) < _reviewersPerPR && !contains(reviewers, member.UserID) {
 			reviewers = append(reviewers, member.UserID)
+			picked = append(picked, member.UserID)
 		}
 	}
 
-	pr := entity.PullRequest{
-		PullRequestID:     prID,
-		PullRequestName:   prName,
-		AuthorID:          authorID,
-		Status:            entity.PRStatusOpen,
-		AssignedReviewers: reviewers,
-		CreatedAt:         time.Now(),
+	if err := strategy.Assigned(ctx, author.TeamName, picked); err != nil {
+		return nil, err
+	}
+
+	pr.AssignedReviewers = reviewers
+	pr.RosterSnapshot = roster
+
+	uc.capture.CaptureDecision(author.TeamName, pr.PullRequestID, assignmentDecision{Roster: roster, Absent: absent, Saturated: saturated, Reviewers: reviewers})
+
+	if len(reviewers) < _reviewersPerPR && len(saturated) > 0 {
+		return []Warning{{
+			Code: "REVIEWERS_SATURATED",
+			Message: fmt.Sprintf("%d of %d reviewers assigned; %d team members are at their open review limit",
+				len(reviewers), _reviewersPerPR, len(saturated)),
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+// MarkReady takes a draft PR out of draft and assigns its reviewers as
+// CreatePR would, including the author's open-PR quota check. Marking an open
+// PR ready is a no-op. On ErrPreconditionFailed the current PR is returned
+// alongside the error.
+func (uc *PRUseCase) MarkReady(ctx context.Context, prID string, changedFiles []string) (entity.PullRequest, []Warning, error) {
+	var (
+		pr       entity.PullRequest
+		warnings []Warning
+		teamName string
+		ready    bool
+	)
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = uc.prRepo.GetByID(ctx, prID)
+		if err != nil {
+			return lookupErr(err)
+		}
+
+		switch pr.Status {
+		case entity.PRStatusOpen:
+			return nil
+		case entity.PRStatusMerged:
+			return ErrPRMerged
+		case entity.PRStatusClosed:
+			return ErrPRClosed
+		}
+
+		if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+			return err
+		}
+
+		// Locked like in CreatePR, for the same quota check.
+		author, err := uc.userRepo.GetForUpdate(ctx, pr.AuthorID)
+		if err != nil {
+			return lookupErr(err)
+		}
+		teamName = author.TeamName
+
+		warnings, err = uc.checkAuthorQuota(ctx, author)
+		if err != nil {
+			return err
+		}
+
+		saturation, err := uc.assignReviewers(ctx, &pr, author, changedFiles, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		warnings = append(warnings, saturation...)
+		pr.Status = entity.PRStatusOpen
+		ready = true
+
+		return uc.prRepo.Update(ctx, &pr)
+	})
+	if errors.Is(err, ErrPreconditionFailed) {
+		return pr, nil, err
+	}
+	if err != nil {
+		return entity.PullRequest{}, nil, err
+	}
+	if !ready {
+		return pr, nil, nil
 	}
 
-	err = uc.prRepo.Create(ctx, pr)
+	uc.notifyAssigned(ctx, pr, teamName, pr.AssignedReviewers...)
+	if pr.Status == entity.PRStatusOpen {
+		uc.notifyUnderstaffed(ctx, pr, teamName)
+	}
+
+	uc.publish(ctx, entity.PREventReadyForReview, pr, teamName, "")
+	uc.emitHook(ctx, entity.HookEvent{Event: entity.HookEventPRUpdated, TeamName: teamName, PullRequest: pr})
+	for _, r := range pr.AssignedReviewers {
+		uc.publish(ctx, entity.PREventReviewerAssigned, pr, teamName, r)
+	}
+
+	return pr, warnings, nil
+}
+
+// ExplainAssignment reports the roster snapshot the PR's reviewers were picked from.
+func (uc *PRUseCase) ExplainAssignment(ctx context.Context, prID string) (entity.AssignmentExplanation, error) {
+	pr, err := uc.prRepo.GetByID(ctx, prID)
 	if err != nil {
-		return entity.PullRequest{}, err
+		return entity.AssignmentExplanation{}, lookupErr(err)
+	}
+
+	// PRs created before strategies were selectable have none recorded.
+	strategy := entity.AssignmentLeastLoaded
+	if pr.RosterSnapshot != nil && pr.RosterSnapshot.Strategy != "" {
+		strategy = pr.RosterSnapshot.Strategy
+	}
+
+	return entity.AssignmentExplanation{
+		PullRequestID:     pr.PullRequestID,
+		AuthorID:          pr.AuthorID,
+		Strategy:          strategy,
+		Roster:            pr.RosterSnapshot,
+		AssignedReviewers: pr.AssignedReviewers,
+	}, nil
+}
+
+// GetPR returns the PR with its reviewers, approvals and roster snapshot.
+func (uc *PRUseCase) GetPR(ctx context.Context, prID string) (entity.PullRequest, error) {
+	pr, err := uc.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return entity.PullRequest{}, lookupErr(err)
 	}
 
 	return pr, nil
 }
 
+// ResolvePRID maps a (repository, external number) pair to the service PR ID.
+func (uc *PRUseCase) ResolvePRID(ctx context.Context, repository string, number int) (string, error) {
+	pr, err := uc.prRepo.GetByExternal(ctx, repository, number)
+	if err != nil {
+		return "", lookupErr(err)
+	}
+	return pr.PullRequestID, nil
+}
+
+// MergePR marks the PR merged; merging twice is a no-op. While the team's
+// required approvals are missing it fails with ErrApprovalsRequired. On that
+// error and ErrPreconditionFailed the current PR is returned alongside it.
 func (uc *PRUseCase) MergePR(ctx context.Context, prID string) (entity.PullRequest, error) {
+	pr, _, err := uc.merge(ctx, prID, true, false)
+	return pr, err
+}
+
+// MarkMerged records a merge that already happened in the VCS, so the team's
+// approval requirement is not checked.
+func (uc *PRUseCase) MarkMerged(ctx context.Context, prID string) (entity.PullRequest, error) {
+	pr, _, err := uc.merge(ctx, prID, false, true)
+	return pr, err
+}
+
+// ForceMerge merges the PR whatever approvals it has, recording in the audit
+// log that actorID overrode the requirement and why. Drafts are still
+// rejected with ErrPRDraft.
+func (uc *PRUseCase) ForceMerge(ctx context.Context, prID, actorID, reason string) (entity.PullRequest, error) {
+	pr, merged, err := uc.merge(ctx, prID, false, false)
+	if err != nil || !merged {
+		return pr, err
+	}
+
+	uc.audit(ctx, entity.AuditEntry{
+		ActorID:       actorID,
+		Action:        "pr.merge_override",
+		PullRequestID: pr.PullRequestID,
+		Source:        "admin",
+	}, map[string]any{"reason": reason, "approvals": pr.Approvals})
+
+	return pr, nil
+}
+
+// merge reports whether this call merged the PR, as opposed to finding it
+// already merged. Drafts fail with ErrPRDraft unless allowDraft is set.
+func (uc *PRUseCase) merge(ctx context.Context, prID string, checkApprovals, allowDraft bool) (entity.PullRequest, bool, error) {
 	pr, err := uc.prRepo.GetByID(ctx, prID)
 	if err != nil {
-		return entity.PullRequest{}, ErrNotFound
+		return entity.PullRequest{}, false, lookupErr(err)
 	}
 
 	if pr.Status == entity.PRStatusMerged {
-		return pr, nil
+		return pr, false, nil
+	}
+
+	if pr.Status == entity.PRStatusClosed {
+		return entity.PullRequest{}, false, ErrPRClosed
 	}
 
-	now := time.Now()
+	if pr.Status == entity.PRStatusDraft && !allowDraft {
+		return entity.PullRequest{}, false, ErrPRDraft
+	}
+
+	if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+		return pr, false, err
+	}
+
+	if checkApprovals {
+		if err := uc.checkApprovals(ctx, pr); err != nil {
+			return pr, false, err
+		}
+	}
+
+	now := time.Now().UTC()
 	pr.Status = entity.PRStatusMerged
 	pr.MergedAt = &now
 
-	err = uc.prRepo.Update(ctx, pr)
+	err = uc.prRepo.Update(ctx, &pr)
+	if err != nil {
+		return entity.PullRequest{}, false, err
+	}
+
+	uc.notifyMerged(ctx, pr)
+	uc.publish(ctx, entity.PREventMerged, pr, "", "")
+	uc.emitHook(ctx, entity.HookEvent{Event: entity.HookEventPRMerged, PullRequest: pr})
+
+	return pr, true, nil
+}
+
+// PRUpdate changes the fields that are set and keeps the nil ones.
+type PRUpdate struct {
+	PullRequestName *string
+	Labels          *[]string
+	LinkedIssues    *[]string
+	Size            *entity.PRSize
+	Priority        *string
+}
+
+// UpdatePR changes an open PR's title and metadata. Labels and linked issues
+// are trimmed and deduplicated. A PR that becomes urgent (see needsOnCall)
+// gets the team's on-call member as a reviewer, within the same limits as on
+// creation. On ErrPreconditionFailed the current PR is returned alongside the
+// error.
+func (uc *PRUseCase) UpdatePR(ctx context.Context, prID string, upd PRUpdate) (entity.PullRequest, error) {
+	var (
+		pr               entity.PullRequest
+		author           entity.User
+		onCall, replaced string
+	)
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = uc.prRepo.GetForUpdate(ctx, prID)
+		if err != nil {
+			return lookupErr(err)
+		}
+
+		switch pr.Status {
+		case entity.PRStatusMerged:
+			return ErrPRMerged
+		case entity.PRStatusClosed:
+			return ErrPRClosed
+		}
+
+		if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+			return err
+		}
+
+		if upd.PullRequestName != nil {
+			name := strings.TrimSpace(*upd.PullRequestName)
+			if name == "" {
+				return fmt.Errorf("%w: pull_request_name must not be empty", ErrInvalidPRUpdate)
+			}
+			pr.PullRequestName = name
+		}
+		wasUrgent := needsOnCall(pr.Labels, pr.Priority)
+		if upd.Labels != nil {
+			pr.Labels = normalizeTags(*upd.Labels)
+		}
+		if upd.Priority != nil {
+			if err := validatePriority(*upd.Priority); err != nil {
+				return err
+			}
+			pr.Priority = *upd.Priority
+		}
+		if upd.LinkedIssues != nil {
+			pr.LinkedIssues = normalizeTags(*upd.LinkedIssues)
+		}
+		if s := upd.Size; s != nil {
+			if s.Additions < 0 || s.Deletions < 0 || s.ChangedFiles < 0 {
+				return fmt.Errorf("%w: size values must be >= 0", ErrInvalidPRUpdate)
+			}
+			pr.Size = s
+		}
+
+		if !wasUrgent && needsOnCall(pr.Labels, pr.Priority) {
+			if author, err = uc.userRepo.GetByID(ctx, pr.AuthorID); err != nil {
+				return lookupErr(err)
+			}
+			if onCall, replaced, err = uc.addOnCall(ctx, &pr, author); err != nil {
+				return err
+			}
+		}
+
+		return uc.prRepo.Update(ctx, &pr)
+	})
+	if errors.Is(err, ErrPreconditionFailed) {
+		return pr, err
+	}
 	if err != nil {
 		return entity.PullRequest{}, err
 	}
 
+	uc.publish(ctx, entity.PREventUpdated, pr, "", "")
+	uc.emitHook(ctx, entity.HookEvent{Event: entity.HookEventPRUpdated, PullRequest: pr})
+
+	switch {
+	case onCall != "" && replaced != "":
+		uc.notifyReassigned(ctx, pr, author.TeamName, replaced, onCall)
+		uc.publish(ctx, entity.PREventReviewerUnassigned, pr, author.TeamName, replaced)
+		uc.publish(ctx, entity.PREventReviewerAssigned, pr, author.TeamName, onCall)
+		uc.emitHook(ctx, entity.HookEvent{
+			Event:         entity.HookEventReviewerReassigned,
+			TeamName:      author.TeamName,
+			PullRequest:   pr,
+			OldReviewerID: replaced,
+			NewReviewerID: onCall,
+		})
+	case onCall != "":
+		uc.notifyAssigned(ctx, pr, author.TeamName, onCall)
+		uc.publish(ctx, entity.PREventReviewerAssigned, pr, author.TeamName, onCall)
+	}
+
 	return pr, nil
 }
 
+func validatePriority(p string) error {
+	if !slices.Contains(entity.Priorities, p) {
+		return fmt.Errorf("%w: priority must be one of low, normal, high, urgent", ErrInvalidPriority)
+	}
+	return nil
+}
+
+// normalizeTags trims each value and drops blanks and repeats, keeping order.
+func normalizeTags(in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, v := range in {
+		if v = strings.TrimSpace(v); v != "" && !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// ClosePR marks the PR closed without merging; closing twice is a no-op. On
+// ErrPreconditionFailed the current PR is returned alongside the error.
+func (uc *PRUseCase) ClosePR(ctx context.Context, prID string) (entity.PullRequest, error) {
+	var (
+		pr     entity.PullRequest
+		closed bool
+	)
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = uc.prRepo.GetForUpdate(ctx, prID)
+		if err != nil {
+			return lookupErr(err)
+		}
+
+		switch pr.Status {
+		case entity.PRStatusClosed:
+			return nil
+		case entity.PRStatusMerged:
+			return ErrPRMerged
+		}
+
+		if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		pr.Status = entity.PRStatusClosed
+		pr.ClosedAt = &now
+		pr.CloseWarnedAt = nil
+		closed = true
+
+		return uc.prRepo.Update(ctx, &pr)
+	})
+	if errors.Is(err, ErrPreconditionFailed) {
+		return pr, err
+	}
+	if err != nil {
+		return entity.PullRequest{}, err
+	}
+	if !closed {
+		return pr, nil
+	}
+
+	uc.publish(ctx, entity.PREventClosed, pr, "", "")
+
+	return pr, nil
+}
+
+// ReopenPR moves a closed PR back to OPEN with its reviewers and approvals
+// intact. A PR closed without reviewers, such as a closed draft, gets them
+// assigned as when it is marked ready. Reopening an open or draft PR is a
+// no-op and merged PRs stay merged. On ErrPreconditionFailed the current PR is
+// returned alongside the error.
+func (uc *PRUseCase) ReopenPR(ctx context.Context, prID string) (entity.PullRequest, error) {
+	var (
+		pr       entity.PullRequest
+		teamName string
+		assigned bool
+		reopened bool
+	)
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = uc.prRepo.GetForUpdate(ctx, prID)
+		if err != nil {
+			return lookupErr(err)
+		}
+
+		switch pr.Status {
+		case entity.PRStatusOpen, entity.PRStatusDraft:
+			return nil
+		case entity.PRStatusMerged:
+			return ErrPRMerged
+		}
+
+		if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+			return err
+		}
+
+		pr.Status = entity.PRStatusOpen
+		pr.ClosedAt = nil
+		pr.CloseWarnedAt = nil
+		reopened = true
+
+		if len(pr.AssignedReviewers) == 0 {
+			author, err := uc.userRepo.GetByID(ctx, pr.AuthorID)
+			if err != nil {
+				return lookupErr(err)
+			}
+			teamName = author.TeamName
+
+			if _, err := uc.assignReviewers(ctx, &pr, author, nil, time.Now().UTC()); err != nil {
+				return err
+			}
+			assigned = true
+		}
+
+		return uc.prRepo.Update(ctx, &pr)
+	})
+	if errors.Is(err, ErrPreconditionFailed) {
+		return pr, err
+	}
+	if err != nil {
+		return entity.PullRequest{}, err
+	}
+	if !reopened {
+		return pr, nil
+	}
+
+	uc.publish(ctx, entity.PREventReopened, pr, teamName, "")
+	if assigned {
+		uc.notifyAssigned(ctx, pr, teamName, pr.AssignedReviewers...)
+		uc.notifyUnderstaffed(ctx, pr, teamName)
+		for _, r := range pr.AssignedReviewers {
+			uc.publish(ctx, entity.PREventReviewerAssigned, pr, teamName, r)
+		}
+	}
+
+	return pr, nil
+}
+
+// ReassignReviewer replaces oldUserID on prID with the best remaining
+// candidate, ranked as for a new PR, the stack_reviewers setting included.
+// Candidates are ranked in the transaction that saves the replacement.
 func (uc *PRUseCase) ReassignReviewer(ctx context.Context, prID, oldUserID string) (entity.PullRequest, string, error) {
-	pr, err := uc.prRepo.GetByID(ctx, prID)
+	var (
+		pr            entity.PullRequest
+		newReviewerID string
+	)
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, newReviewerID, err = uc.reassignReviewer(ctx, prID, oldUserID)
+		return err
+	})
 	if err != nil {
-		return entity.PullRequest{}, "", ErrNotFound
+		return pr, "", err
+	}
+
+	return pr, newReviewerID, nil
+}
+
+func (uc *PRUseCase) reassignReviewer(ctx context.Context, prID, oldUserID string) (entity.PullRequest, string, error) {
+	pr, err := uc.prRepo.GetForUpdate(ctx, prID)
+	if err != nil {
+		return entity.PullRequest{}, "", lookupErr(err)
 	}
 
 	if pr.Status == entity.PRStatusMerged {
 		return entity.PullRequest{}, "", ErrPRMerged
 	}
 
+	if pr.Status == entity.PRStatusClosed {
+		return entity.PullRequest{}, "", ErrPRClosed
+	}
+
+	if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+		return pr, "", err
+	}
+
 	found := false
 	for i, reviewer := range pr.AssignedReviewers {
 		if reviewer == oldUserID {
@@ -116,96 +869,134 @@ func (uc *PRUseCase) ReassignReviewer(ctx context.Context, prID, oldUserID strin
 
 	author, err := uc.userRepo.GetByID(ctx, pr.AuthorID)
 	if err != nil {
-		return entity.PullRequest{}, "", ErrNotFound
+		return entity.PullRequest{}, "", lookupErr(err)
 	}
 
-	teamMembers, err := uc.userRepo.ListByTeam(ctx, author.TeamName)
+	if err := uc.authorize(ctx, author.TeamName, entity.ScopeReassign, ""); err != nil {
+		return entity.PullRequest{}, "", err
+	}
+
+	absent, err := uc.absentAt(ctx, time.Now())
 	if err != nil {
-		return entity.PullRequest{}, "", ErrNotFound
+		return entity.PullRequest{}, "", err
 	}
 
-	var newReviewerID string
-	for _, member := range teamMembers {
-		if member.UserID != pr.AuthorID &&
-			member.IsActive &&
-			!contains(pr.AssignedReviewers, member.UserID) &&
-			member.UserID != oldUserID {
-			newReviewerID = member.UserID
-			break
-		}
+	barred, err := uc.barredReviewers(ctx, pr.AuthorID, pr.Labels)
+	if err != nil {
+		return entity.PullRequest{}, "", err
 	}
 
-	if newReviewerID == "" {
-		return entity.PullRequest{}, "", ErrNoCandidate
+	saturated, err := uc.saturatedReviewers(ctx, author.TeamName)
+	if err != nil {
+		return entity.PullRequest{}, "", err
 	}
 
-	pr.AssignedReviewers = append(pr.AssignedReviewers, newReviewerID)
+	exclude := append([]string{pr.AuthorID, oldUserID}, pr.AssignedReviewers...)
+	exclude = append(exclude, absent...)
+	exclude = append(exclude, barred...)
+	exclude = append(exclude, saturated...)
 
-	err = uc.prRepo.Update(ctx, pr)
+	strategy, err := uc.assignmentStrategy(ctx, author.TeamName)
 	if err != nil {
 		return entity.PullRequest{}, "", err
 	}
 
-	return pr, newReviewerID, nil
-}
+	candidates, err := strategy.Rank(ctx, author.TeamName, exclude, _candidatePool)
+	if err != nil {
+		return entity.PullRequest{}, "", err
+	}
 
-func (uc *PRUseCase) DeactivateTeam(ctx context.Context, teamName string) error {
-	users, err := uc.userRepo.ListByTeam(ctx, teamName)
+	candidates, _, err = uc.coolDown(ctx, author.TeamName, candidates, time.Now().UTC())
 	if err != nil {
-		return err
+		return entity.PullRequest{}, "", err
 	}
 
-	for _, user := range users {
-		user.IsActive = false
-		if err := uc.userRepo.Update(ctx, user); err != nil {
-			return err
-		}
+	candidates, _, _, err = uc.stackOrder(ctx, &pr, author.TeamName, candidates, exclude)
+	if err != nil {
+		return entity.PullRequest{}, "", err
 	}
 
-	return nil
-}
+	if len(candidates) == 0 {
+		return entity.PullRequest{}, "", ErrNoCandidate
+	}
 
-func (uc *PRUseCase) GetStats(ctx context.Context) (map[string]interface{}, error) {
-	prs, err := uc.prRepo.ListAll(ctx)
-	if err != nil {
-		return nil, err
+	newReviewerID := candidates[0].UserID
+
+	if err := strategy.Assigned(ctx, author.TeamName, []string{newReviewerID}); err != nil {
+		return entity.PullRequest{}, "", err
 	}
 
-	users, err := uc.userRepo.ListAll(ctx)
+	pr.AssignedReviewers = append(pr.AssignedReviewers, newReviewerID)
+	pr.Approvals = without(pr.Approvals, oldUserID)
+
+	err = uc.prRepo.Update(ctx, &pr)
 	if err != nil {
-		return nil, err
+		return entity.PullRequest{}, "", err
 	}
 
-	stats := map[string]interface{}{
-		"total_prs":         len(prs),
-		"total_users":       len(users),
-		"open_prs":          0,
-		"merged_prs":        0,
-		"active_users":      0,
-		"average_reviewers": 0.0,
+	notified := pr
+	notified.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	afterCommit(ctx, func(ctx context.Context) {
+		uc.notifyReassigned(ctx, notified, author.TeamName, oldUserID, newReviewerID)
+	})
+
+	uc.publish(ctx, entity.PREventReviewerUnassigned, pr, author.TeamName, oldUserID)
+	uc.publish(ctx, entity.PREventReviewerAssigned, pr, author.TeamName, newReviewerID)
+	uc.emitHook(ctx, entity.HookEvent{
+		Event:         entity.HookEventReviewerReassigned,
+		TeamName:      author.TeamName,
+		PullRequest:   pr,
+		OldReviewerID: oldUserID,
+		NewReviewerID: newReviewerID,
+	})
+
+	return pr, newReviewerID, nil
+}
+
+// DeactivateTeam deactivates every member; a failure leaves all of them unchanged.
+// Only a lead of the team or a delegate with the capacity scope may ask for it.
+func (uc *PRUseCase) DeactivateTeam(ctx context.Context, teamName string) error {
+	if err := uc.authorize(ctx, teamName, entity.ScopeCapacity, ""); err != nil {
+		return err
 	}
 
-	totalReviewers := 0
-	for _, pr := range prs {
-		if pr.Status == entity.PRStatusOpen {
-			stats["open_prs"] = stats["open_prs"].(int) + 1
-		} else if pr.Status == entity.PRStatusMerged {
-			stats["merged_prs"] = stats["merged_prs"].(int) + 1
+	return uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		users, err := uc.userRepo.ListByTeam(ctx, teamName)
+		if err != nil {
+			return err
 		}
-		totalReviewers += len(pr.AssignedReviewers)
-	}
 
-	for _, user := range users {
-		if user.IsActive {
-			stats["active_users"] = stats["active_users"].(int) + 1
+		for _, user := range users {
+			user.IsActive = false
+			if err := uc.userRepo.Update(ctx, &user); err != nil {
+				return err
+			}
+			uc.publishUser(ctx, entity.UserEventUpdated, user)
 		}
-	}
 
-	if len(prs) > 0 {
-		stats["average_reviewers"] = float64(totalReviewers) / float64(len(prs))
+		return nil
+	})
+}
+
+// lookupErr keeps ErrNotFound for missing rows and passes infrastructure
+// errors (a saturated pool, a dropped connection) through unchanged, so they
+// aren't reported to clients as 404s.
+func lookupErr(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return ErrNotFound
 	}
+	return err
+}
 
-	return stats, nil
+// without returns slice minus every occurrence of item.
+func without(slice []string, item string) []string {
+	out := make([]string, 0, len(slice))
+	for _, s := range slice {
+		if s != item {
+			out = append(out, s)
+		}
+	}
+	return out
 }
 
 func contains(slice []string, item string) bool {
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var (
+	ErrQuotaExceeded   = errors.New("QUOTA_EXCEEDED")
+	ErrInvalidSettings = errors.New("invalid settings")
+)
+
+// Warning is a non-fatal notice returned alongside a successful mutation.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// QuotaPolicy is the service-wide default for the open-PR quota;
+// MaxOpenPRsPerAuthor of zero disables it.
+type QuotaPolicy struct {
+	MaxOpenPRsPerAuthor int
+	Mode                string
+}
+
+// noTeamSettings is used until a TeamSettingsRepo is configured, so every
+// team falls back to the service-wide defaults.
+type noTeamSettings struct{}
+
+func (noTeamSettings) Get(context.Context, string) (entity.TeamSettings, error) {
+	return entity.TeamSettings{}, ErrNotFound
+}
+
+func (noTeamSettings) Upsert(context.Context, *entity.TeamSettings, []string) error {
+	return errors.New("team settings are not configured")
+}
+
+// GetTeamSettings returns the team's overrides; a team without a row gets empty settings.
+func (uc *PRUseCase) GetTeamSettings(ctx context.Context, teamName string) (entity.TeamSettings, error) {
+	exists, err := uc.teamRepo.Exists(ctx, teamName)
+	if err != nil {
+		return entity.TeamSettings{}, err
+	}
+	if !exists {
+		return entity.TeamSettings{}, ErrNotFound
+	}
+
+	s, err := uc.teamSettings.Get(ctx, teamName)
+	if errors.Is(err, ErrNotFound) {
+		return entity.TeamSettings{TeamName: teamName}, nil
+	}
+
+	return s, err
+}
+
+// UpdateTeamSettings validates and stores the team's non-nil overrides and
+// resets the fields named in clear to the service defaults; other stored
+// overrides are kept.
+func (uc *PRUseCase) UpdateTeamSettings(ctx context.Context, s entity.TeamSettings, clear []string) (entity.TeamSettings, error) {
+	set := map[string]bool{
+		"max_open_prs_per_author": s.MaxOpenPRsPerAuthor != nil,
+		"quota_mode":              s.QuotaMode != nil,
+		"slack_enabled":           s.SlackEnabled != nil,
+		"slack_channel":           s.SlackChannel != nil,
+		"assignment_strategy":     s.AssignmentStrategy != nil,
+		"required_approvals":      s.RequiredApprovals != nil,
+		"require_all_approvals":   s.RequireAllApprovals != nil,
+		"stack_reviewers":         s.StackReviewers != nil,
+	}
+	for _, field := range clear {
+		if !slices.Contains(entity.TeamSettingsFields, field) {
+			return entity.TeamSettings{}, fmt.Errorf("%w: clear must list fields of %s",
+				ErrInvalidSettings, strings.Join(entity.TeamSettingsFields, ", "))
+		}
+		if set[field] {
+			return entity.TeamSettings{}, fmt.Errorf("%w: %s is both set and cleared", ErrInvalidSettings, field)
+		}
+	}
+
+	if s.MaxOpenPRsPerAuthor != nil && *s.MaxOpenPRsPerAuthor < 0 {
+		return entity.TeamSettings{}, fmt.Errorf("%w: max_open_prs_per_author must be >= 0", ErrInvalidSettings)
+	}
+	if s.RequiredApprovals != nil && *s.RequiredApprovals < 0 {
+		return entity.TeamSettings{}, fmt.Errorf("%w: required_approvals must be >= 0", ErrInvalidSettings)
+	}
+	if s.QuotaMode != nil && *s.QuotaMode != entity.QuotaModeWarn && *s.QuotaMode != entity.QuotaModeEnforce {
+		return entity.TeamSettings{}, fmt.Errorf("%w: quota_mode must be warn or enforce", ErrInvalidSettings)
+	}
+	if s.SlackChannel != nil {
+		ch := strings.TrimSpace(*s.SlackChannel)
+		if ch == "" || strings.ContainsAny(ch, " \t\n") {
+			return entity.TeamSettings{}, fmt.Errorf("%w: slack_channel must be a channel ID or #name", ErrInvalidSettings)
+		}
+		s.SlackChannel = &ch
+	}
+	if s.StackReviewers != nil && *s.StackReviewers != entity.StackReviewersSame && *s.StackReviewers != entity.StackReviewersDiversify {
+		return entity.TeamSettings{}, fmt.Errorf("%w: stack_reviewers must be same or diversify", ErrInvalidSettings)
+	}
+	if s.AssignmentStrategy != nil {
+		if _, ok := uc.strategies[*s.AssignmentStrategy]; !ok {
+			return entity.TeamSettings{}, fmt.Errorf("%w: assignment_strategy must be one of %s",
+				ErrInvalidSettings, strings.Join(uc.strategyNames(), ", "))
+		}
+	}
+
+	exists, err := uc.teamRepo.Exists(ctx, s.TeamName)
+	if err != nil {
+		return entity.TeamSettings{}, err
+	}
+	if !exists {
+		return entity.TeamSettings{}, ErrNotFound
+	}
+
+	if err := uc.authorize(ctx, s.TeamName, entity.ScopeSettings, ""); err != nil {
+		return entity.TeamSettings{}, err
+	}
+
+	if err := uc.teamSettings.Upsert(ctx, &s, clear); err != nil {
+		return entity.TeamSettings{}, err
+	}
+
+	return s, nil
+}
+
+// checkAuthorQuota compares the author's open PRs against the team's limit.
+// In warn mode the breach is reported as a Warning; in enforce mode as ErrQuotaExceeded.
+func (uc *PRUseCase) checkAuthorQuota(ctx context.Context, author entity.User) ([]Warning, error) {
+	limit, mode := uc.quota.MaxOpenPRsPerAuthor, uc.quota.Mode
+
+	s, err := uc.teamSettings.Get(ctx, author.TeamName)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if s.MaxOpenPRsPerAuthor != nil {
+		limit = *s.MaxOpenPRsPerAuthor
+	}
+	if s.QuotaMode != nil {
+		mode = *s.QuotaMode
+	}
+
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	open, err := uc.prRepo.CountOpenByAuthor(ctx, author.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if open < limit {
+		return nil, nil
+	}
+
+	if mode == entity.QuotaModeEnforce {
+		return nil, ErrQuotaExceeded
+	}
+
+	return []Warning{{
+		Code:    "QUOTA_EXCEEDED",
+		Message: fmt.Sprintf("author already has %d open PRs (limit %d); consider getting them reviewed first", open, limit),
+	}}, nil
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var ErrInvalidChange = errors.New("invalid reviewer change")
+
+type noConflicts struct{}
+
+func (noConflicts) Create(context.Context, *entity.ReviewerConflict) error { return nil }
+
+func (noConflicts) List(context.Context, entity.ReviewerConflictFilter) ([]entity.ReviewerConflict, error) {
+	return []entity.ReviewerConflict{}, nil
+}
+
+// ReconcileReviewer handles userID being added to or removed from prID's
+// reviewers directly in provider's VCS. Changes the assignment already agrees
+// with, such as the echoes of mirroring, are ignored, as are accounts that
+// are not service users. Anything else is a conflict resolved under the
+// reconciliation policy: taking the change over (an added reviewer must be
+// eligible, else the addition is reverted) or reverting it through the
+// reviewer mirror. A PR the mirror doesn't reach can't be reverted, and the
+// conflict is recorded as unresolved instead. The PR stays locked while it is
+// reconciled, so concurrent deliveries for it take turns. Conflicts are
+// recorded and returned; nil means none.
+func (uc *PRUseCase) ReconcileReviewer(ctx context.Context, provider, prID, userID, change string) (*entity.ReviewerConflict, error) {
+	if change != entity.ReviewerChangeAdded && change != entity.ReviewerChangeRemoved {
+		return nil, fmt.Errorf("%w: unknown change %q", ErrInvalidChange, change)
+	}
+
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		if errors.Is(lookupErr(err), ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var conflict *entity.ReviewerConflict
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		conflict, err = uc.reconcileReviewer(ctx, provider, prID, userID, change)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conflict, nil
+}
+
+func (uc *PRUseCase) reconcileReviewer(ctx context.Context, provider, prID, userID, change string) (*entity.ReviewerConflict, error) {
+	pr, err := uc.lockEditablePR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := contains(pr.AssignedReviewers, userID)
+	if assigned == (change == entity.ReviewerChangeAdded) {
+		return nil, nil
+	}
+
+	conflict := entity.ReviewerConflict{
+		PullRequestID: pr.PullRequestID,
+		Provider:      provider,
+		ReviewerID:    userID,
+		Change:        change,
+		Policy:        uc.reconcilePolicy,
+	}
+
+	switch {
+	case change == entity.ReviewerChangeAdded && uc.reconcilePolicy != entity.ReconcilePreferInternal:
+		if err := uc.applyExternalAdd(ctx, &pr, userID); err != nil {
+			if !errors.Is(err, ErrNotEligible) {
+				return nil, err
+			}
+			conflict.Error = err.Error()
+			conflict.Resolution = uc.revertExternal(ctx, pr, userID, false)
+		} else {
+			conflict.Resolution = entity.ConflictApplied
+		}
+	case change == entity.ReviewerChangeAdded:
+		conflict.Resolution = uc.revertExternal(ctx, pr, userID, false)
+	case uc.reconcilePolicy == entity.ReconcilePreferExternal:
+		pr.AssignedReviewers = without(pr.AssignedReviewers, userID)
+		pr.Approvals = without(pr.Approvals, userID)
+		if err := uc.prRepo.Update(ctx, &pr); err != nil {
+			return nil, err
+		}
+		uc.publish(ctx, entity.PREventReviewerUnassigned, pr, "", userID)
+		conflict.Resolution = entity.ConflictApplied
+	default:
+		conflict.Resolution = uc.revertExternal(ctx, pr, userID, true)
+	}
+
+	if err := uc.conflicts.Create(ctx, &conflict); err != nil {
+		return nil, fmt.Errorf("record conflict: %w", err)
+	}
+
+	uc.audit(ctx, entity.AuditEntry{
+		Action:        "pr.reviewer_conflict",
+		PullRequestID: pr.PullRequestID,
+		Source:        provider,
+	}, map[string]any{
+		"reviewer":   userID,
+		"change":     change,
+		"policy":     conflict.Policy,
+		"resolution": conflict.Resolution,
+	})
+
+	return &conflict, nil
+}
+
+// revertExternal undoes an external change in the VCS once the transaction
+// commits, assigning userID back or unassigning them, and returns the
+// resolution to record: ConflictUnresolved when pr isn't mirrored.
+func (uc *PRUseCase) revertExternal(ctx context.Context, pr entity.PullRequest, userID string, assign bool) string {
+	if !uc.mirror.Mirrors(pr) {
+		return entity.ConflictUnresolved
+	}
+
+	pr.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+	afterCommit(ctx, func(ctx context.Context) {
+		if assign {
+			uc.mirror.ReviewerAssigned(ctx, pr, userID)
+		} else {
+			uc.mirror.ReviewerUnassigned(ctx, pr, userID)
+		}
+	})
+
+	return entity.ConflictReverted
+}
+
+// applyExternalAdd assigns userID to pr as AddReviewer does; the caller
+// audits the change as a conflict.
+func (uc *PRUseCase) applyExternalAdd(ctx context.Context, pr *entity.PullRequest, userID string) error {
+	author, err := uc.eligibleReviewer(ctx, *pr, userID)
+	if err != nil {
+		return err
+	}
+
+	pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+	if err := uc.prRepo.Update(ctx, pr); err != nil {
+		return err
+	}
+
+	notified := *pr
+	afterCommit(ctx, func(ctx context.Context) { uc.notifyAssigned(ctx, notified, author.TeamName, userID) })
+	uc.publish(ctx, entity.PREventReviewerAssigned, *pr, author.TeamName, userID)
+
+	return nil
+}
+
+func (uc *PRUseCase) ListReviewerConflicts(ctx context.Context, f entity.ReviewerConflictFilter) ([]entity.ReviewerConflict, error) {
+	return uc.conflicts.List(ctx, f)
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+)
+
+// ReminderPolicy nudges reviewers who have not approved an open PR. Cadence
+// is how often, per PR priority; a missing or zero entry sends none.
+type ReminderPolicy struct {
+	Cadence map[string]time.Duration
+}
+
+type ReminderReport struct {
+	Reminded int `json:"reminded"`
+}
+
+// SendReminders reminds each pending reviewer whose last reminder (or
+// assignment) is at least a cadence old, skipping snoozed reviews. The first
+// reminder sent past a PR's review SLA also alerts the author's team.
+func (uc *PRUseCase) SendReminders(ctx context.Context, now time.Time, p ReminderPolicy) (ReminderReport, error) {
+	var report ReminderReport
+	now = now.UTC()
+	breached := map[string]bool{}
+
+	pending, err := uc.prRepo.ListPendingReviews(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, r := range pending {
+		cadence := p.Cadence[r.Priority]
+		if cadence <= 0 || (r.SnoozedUntil != nil && r.SnoozedUntil.After(now)) {
+			continue
+		}
+
+		last := r.AssignedAt
+		if r.RemindedAt != nil {
+			last = *r.RemindedAt
+		}
+		if now.Sub(last) < cadence {
+			continue
+		}
+
+		_ = uc.notifier.Notify(ctx, notifier.Message{
+			Kind:          "review_reminder",
+			UserID:        r.ReviewerID,
+			PullRequestID: r.PullRequestID,
+			Text: fmt.Sprintf("Reminder: %q by %s (%s priority) is waiting for your review",
+				r.PullRequestName, uc.displayName(ctx, r.AuthorID), r.Priority),
+			Actions: _reviewActions,
+		})
+
+		if due := r.AssignedAt.Add(uc.health.SLA(r.Priority)); now.After(due) && !last.After(due) && !breached[r.PullRequestID] {
+			breached[r.PullRequestID] = true
+			uc.notifyBreached(ctx, r, due)
+		}
+
+		if err := uc.prRepo.MarkReminded(ctx, r.PullRequestID, r.ReviewerID, now); err != nil {
+			return report, err
+		}
+		report.Reminded++
+	}
+
+	return report, nil
+}
+
+// notifyBreached alerts the author's team that r's PR is past its review SLA.
+func (uc *PRUseCase) notifyBreached(ctx context.Context, r entity.PendingReview, due time.Time) {
+	author, err := uc.userRepo.GetByID(ctx, r.AuthorID)
+	if err != nil {
+		return
+	}
+
+	_ = uc.notifier.Notify(ctx, notifier.Message{
+		Kind:          "review_sla_breached",
+		TeamName:      author.TeamName,
+		PullRequestID: r.PullRequestID,
+		Text: fmt.Sprintf("%q by %s (%s priority) missed its review SLA at %s",
+			r.PullRequestName, uc.displayName(ctx, r.AuthorID), r.Priority, due.Format(time.RFC3339)),
+	})
+}
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+)
+
+var ErrInvalidReview = errors.New("invalid review")
+
+// noReviews is used until a ReviewRepo is configured.
+type noReviews struct{}
+
+func (noReviews) Create(context.Context, *entity.Review) error {
+	return nil
+}
+
+func (noReviews) ListByPR(context.Context, string) ([]entity.Review, error) {
+	return []entity.Review{}, nil
+}
+
+func (noReviews) LatestByPR(context.Context, string) ([]entity.Review, error) {
+	return []entity.Review{}, nil
+}
+
+func (noReviews) LatestByReviewer(context.Context, string) ([]entity.Review, error) {
+	return []entity.Review{}, nil
+}
+
+// ApprovePR records the reviewer's approval; approving twice is a no-op. On
+// ErrPreconditionFailed the current PR is returned alongside the error.
+func (uc *PRUseCase) ApprovePR(ctx context.Context, prID, reviewerID string) (entity.PullRequest, error) {
+	pr, _, err := uc.SubmitReview(ctx, prID, reviewerID, entity.ReviewApproved, "")
+	return pr, err
+}
+
+// SubmitReview records an assigned reviewer's decision on an open PR.
+// Approving adds them to the PR's approvals and requesting changes withdraws
+// an earlier approval; a comment leaves approvals as they are. Approving an
+// already approved PR is a no-op and returns a nil review. On
+// ErrPreconditionFailed the current PR is returned alongside the error.
+func (uc *PRUseCase) SubmitReview(ctx context.Context, prID, reviewerID, decision, body string) (entity.PullRequest, *entity.Review, error) {
+	if !slices.Contains(entity.ReviewDecisions, decision) {
+		return entity.PullRequest{}, nil, fmt.Errorf("%w: decision must be one of %s",
+			ErrInvalidReview, strings.Join(entity.ReviewDecisions, ", "))
+	}
+
+	var (
+		pr     entity.PullRequest
+		review *entity.Review
+	)
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = uc.openAssignedPR(ctx, prID, reviewerID)
+		if err != nil {
+			return err
+		}
+
+		approved := contains(pr.Approvals, reviewerID)
+		if decision == entity.ReviewApproved && approved {
+			return nil
+		}
+
+		if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+			return err
+		}
+
+		switch {
+		case decision == entity.ReviewApproved:
+			pr.Approvals = append(pr.Approvals, reviewerID)
+		case decision == entity.ReviewChangesRequested && approved:
+			pr.Approvals = without(pr.Approvals, reviewerID)
+		}
+		if decision != entity.ReviewCommented {
+			if err := uc.prRepo.Update(ctx, &pr); err != nil {
+				return err
+			}
+		}
+
+		review = &entity.Review{PullRequestID: prID, ReviewerID: reviewerID, Decision: decision, Body: body}
+		return uc.reviews.Create(ctx, review)
+	})
+	if errors.Is(err, ErrPreconditionFailed) {
+		return pr, nil, err
+	}
+	if err != nil {
+		return entity.PullRequest{}, nil, err
+	}
+
+	if review != nil && decision == entity.ReviewApproved {
+		uc.publish(ctx, entity.PREventApproved, pr, "", reviewerID)
+	}
+
+	return pr, review, nil
+}
+
+// ListReviews returns every decision submitted on the PR, oldest first.
+func (uc *PRUseCase) ListReviews(ctx context.Context, prID string) ([]entity.Review, error) {
+	if _, err := uc.prRepo.GetByID(ctx, prID); err != nil {
+		return nil, lookupErr(err)
+	}
+
+	return uc.reviews.ListByPR(ctx, prID)
+}
+
+// CurrentReviews returns each reviewer's latest decision on the PR, ordered
+// by reviewer. Only reviewers still assigned are included, so decisions of
+// those since unassigned don't show; reviewers who have not decided yet are
+// missing.
+func (uc *PRUseCase) CurrentReviews(ctx context.Context, prID string) ([]entity.Review, error) {
+	pr, err := uc.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, lookupErr(err)
+	}
+
+	reviews, err := uc.reviews.LatestByPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(reviews, func(r entity.Review) bool {
+		return !contains(pr.AssignedReviewers, r.ReviewerID)
+	}), nil
+}
+
+// ReviewDecisions returns the reviewer's latest decision on each PR they
+// reviewed, keyed by PR ID.
+func (uc *PRUseCase) ReviewDecisions(ctx context.Context, reviewerID string) (map[string]entity.Review, error) {
+	reviews, err := uc.reviews.LatestByReviewer(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]entity.Review, len(reviews))
+	for _, r := range reviews {
+		out[r.PullRequestID] = r
+	}
+
+	return out, nil
+}
+
+// checkApprovals fails with ErrApprovalsRequired while fewer of pr's assigned
+// reviewers approved than the author's team requires, including while fewer
+// reviewers are assigned than that.
+func (uc *PRUseCase) checkApprovals(ctx context.Context, pr entity.PullRequest) error {
+	author, err := uc.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return lookupErr(err)
+	}
+
+	s, err := uc.teamSettings.Get(ctx, author.TeamName)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	approved := 0
+	for _, r := range pr.AssignedReviewers {
+		if contains(pr.Approvals, r) {
+			approved++
+		}
+	}
+
+	if approved < s.ApprovalsRequired(uc.requiredApprovals, len(pr.AssignedReviewers)) {
+		return ErrApprovalsRequired
+	}
+
+	return nil
+}
+
+// DeclineReview hands the reviewer's slot to another teammate.
+func (uc *PRUseCase) DeclineReview(ctx context.Context, prID, reviewerID string) (entity.PullRequest, string, error) {
+	return uc.ReassignReviewer(ctx, prID, reviewerID)
+}
+
+// SnoozeReview postpones the reviewer's reminders for the PR by d.
+func (uc *PRUseCase) SnoozeReview(ctx context.Context, prID, reviewerID string, d time.Duration) (time.Time, error) {
+	if _, err := uc.openAssignedPR(ctx, prID, reviewerID); err != nil {
+		return time.Time{}, err
+	}
+
+	until := time.Now().UTC().Add(d)
+	if err := uc.prRepo.Snooze(ctx, prID, reviewerID, until); err != nil {
+		return time.Time{}, err
+	}
+
+	return until, nil
+}
+
+// LinkIdentity maps the user to their account in an external system.
+func (uc *PRUseCase) LinkIdentity(ctx context.Context, id entity.UserIdentity) (entity.UserIdentity, error) {
+	if _, err := uc.userRepo.GetByID(ctx, id.UserID); err != nil {
+		return entity.UserIdentity{}, lookupErr(err)
+	}
+
+	if err := uc.identities.Link(ctx, &id); err != nil {
+		return entity.UserIdentity{}, err
+	}
+
+	return id, nil
+}
+
+// ResolveUserID maps an external account to a service user. Unlinked
+// accounts resolve to externalID itself, for setups where the IDs match.
+func (uc *PRUseCase) ResolveUserID(ctx context.Context, provider, externalID string) (string, error) {
+	userID, err := uc.identities.UserIDFor(ctx, provider, externalID)
+	if errors.Is(err, ErrNotFound) {
+		return externalID, nil
+	}
+
+	return userID, err
+}
+
+func (uc *PRUseCase) openAssignedPR(ctx context.Context, prID, reviewerID string) (entity.PullRequest, error) {
+	pr, err := uc.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return entity.PullRequest{}, lookupErr(err)
+	}
+
+	switch pr.Status {
+	case entity.PRStatusMerged:
+		return entity.PullRequest{}, ErrPRMerged
+	case entity.PRStatusClosed:
+		return entity.PullRequest{}, ErrPRClosed
+	}
+
+	if !contains(pr.AssignedReviewers, reviewerID) {
+		return entity.PullRequest{}, ErrNotAssigned
+	}
+
+	return pr, nil
+}
+
+// _reviewActions are the quick replies offered to a newly assigned reviewer.
+var _reviewActions = []notifier.Action{
+	{Kind: notifier.ActionApprove, Label: "Approve"},
+	{Kind: notifier.ActionDecline, Label: "Decline"},
+	{Kind: notifier.ActionSnooze, Label: "Snooze"},
+}
+
+// notifyAssigned tells each reviewer about their new review, offering quick replies.
+func (uc *PRUseCase) notifyAssigned(ctx context.Context, pr entity.PullRequest, teamName string, reviewers ...string) {
+	author := uc.displayName(ctx, pr.AuthorID)
+
+	for _, r := range reviewers {
+		_ = uc.notifier.Notify(ctx, notifier.Message{
+			Kind:          "review_assigned",
+			UserID:        r,
+			TeamName:      teamName,
+			PullRequestID: pr.PullRequestID,
+			Text:          fmt.Sprintf("You were assigned to review %q by %s", pr.PullRequestName, author),
+			Actions:       _reviewActions,
+		})
+	}
+}
+
+// notifyUnderstaffed tells the author's team when assignment left pr short of
+// reviewers, so someone can claim it.
+func (uc *PRUseCase) notifyUnderstaffed(ctx context.Context, pr entity.PullRequest, teamName string) {
+	if len(pr.AssignedReviewers) >= _reviewersPerPR {
+		return
+	}
+
+	_ = uc.notifier.Notify(ctx, notifier.Message{
+		Kind:          "pr_needs_reviewers",
+		TeamName:      teamName,
+		PullRequestID: pr.PullRequestID,
+		Text: fmt.Sprintf("%q by %s has %d of %d reviewers; claim it to help",
+			pr.PullRequestName, uc.displayName(ctx, pr.AuthorID), len(pr.AssignedReviewers), _reviewersPerPR),
+	})
+}
+
+// notifyReassigned tells the new reviewer they took over from oldReviewerID.
+func (uc *PRUseCase) notifyReassigned(ctx context.Context, pr entity.PullRequest, teamName, oldReviewerID, newReviewerID string) {
+	_ = uc.notifier.Notify(ctx, notifier.Message{
+		Kind:          "review_reassigned",
+		UserID:        newReviewerID,
+		TeamName:      teamName,
+		PullRequestID: pr.PullRequestID,
+		Text: fmt.Sprintf("You took over the review of %q by %s from %s",
+			pr.PullRequestName, uc.displayName(ctx, pr.AuthorID), uc.displayName(ctx, oldReviewerID)),
+		Actions: _reviewActions,
+	})
+}
+
+// notifyMerged tells the author their PR was merged.
+func (uc *PRUseCase) notifyMerged(ctx context.Context, pr entity.PullRequest) {
+	_ = uc.notifier.Notify(ctx, notifier.Message{
+		Kind:          "pr_merged",
+		UserID:        pr.AuthorID,
+		PullRequestID: pr.PullRequestID,
+		Text:          fmt.Sprintf("%q was merged", pr.PullRequestName),
+	})
+}
+
+// noIdentities is used until an IdentityRepo is configured.
+type noIdentities struct{}
+
+func (noIdentities) Link(context.Context, *entity.UserIdentity) error {
+	return fmt.Errorf("identities are not configured")
+}
+
+func (noIdentities) UserIDFor(context.Context, string, string) (string, error) {
+	return "", ErrNotFound
+}
+
+func (noIdentities) ExternalIDFor(context.Context, string, string) (string, error) {
+	return "", ErrNotFound
+}
+
+func (noIdentities) RevokeAll(context.Context, string) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var ErrInvalidExclusion = errors.New("invalid review exclusion")
+
+type noReviewExclusions struct{}
+
+func (noReviewExclusions) Create(context.Context, *entity.ReviewExclusion) error {
+	return nil
+}
+
+func (noReviewExclusions) List(context.Context) ([]entity.ReviewExclusion, error) {
+	return []entity.ReviewExclusion{}, nil
+}
+
+func (noReviewExclusions) Delete(context.Context, int64) error {
+	return ErrNotFound
+}
+
+// SaveReviewExclusion adds an exclusion. It must name reviewers and narrow
+// the PRs it covers by authors, labels or both; users must exist. The actor
+// must be allowed to manage the settings of each reviewer's team.
+func (uc *PRUseCase) SaveReviewExclusion(ctx context.Context, e entity.ReviewExclusion) (entity.ReviewExclusion, error) {
+	e.Reviewers = normalizeTags(e.Reviewers)
+	e.Authors = normalizeTags(e.Authors)
+	e.Labels = normalizeTags(e.Labels)
+	e.Reason = strings.TrimSpace(e.Reason)
+
+	if len(e.Reviewers) == 0 {
+		return entity.ReviewExclusion{}, fmt.Errorf("%w: at least one reviewer required", ErrInvalidExclusion)
+	}
+	if len(e.Authors) == 0 && len(e.Labels) == 0 {
+		return entity.ReviewExclusion{}, fmt.Errorf("%w: authors or labels required", ErrInvalidExclusion)
+	}
+
+	var teams []string
+	for i, id := range append(append([]string{}, e.Reviewers...), e.Authors...) {
+		u, err := uc.userRepo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return entity.ReviewExclusion{}, fmt.Errorf("%w: user %s not found", ErrInvalidExclusion, id)
+			}
+			return entity.ReviewExclusion{}, err
+		}
+		if i < len(e.Reviewers) && !contains(teams, u.TeamName) {
+			teams = append(teams, u.TeamName)
+		}
+	}
+
+	for _, team := range teams {
+		if err := uc.authorizeLead(ctx, team, entity.ScopeSettings); err != nil {
+			return entity.ReviewExclusion{}, err
+		}
+	}
+
+	if err := uc.exclusions.Create(ctx, &e); err != nil {
+		return entity.ReviewExclusion{}, err
+	}
+
+	return e, nil
+}
+
+func (uc *PRUseCase) ListReviewExclusions(ctx context.Context) ([]entity.ReviewExclusion, error) {
+	return uc.exclusions.List(ctx)
+}
+
+func (uc *PRUseCase) DeleteReviewExclusion(ctx context.Context, id int64) error {
+	return lookupErr(uc.exclusions.Delete(ctx, id))
+}
+
+// barredReviewers returns the users exclusions keep off a PR by authorID
+// with labels.
+func (uc *PRUseCase) barredReviewers(ctx context.Context, authorID string, labels []string) ([]string, error) {
+	exclusions, err := uc.exclusions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var barred []string
+	for _, e := range exclusions {
+		if !e.Covers(authorID, labels) {
+			continue
+		}
+		for _, id := range e.Reviewers {
+			if !contains(barred, id) {
+				barred = append(barred, id)
+			}
+		}
+	}
+
+	return barred, nil
+}
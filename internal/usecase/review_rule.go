@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/glob"
+)
+
+var ErrInvalidRule = errors.New("invalid review rule")
+
+type noReviewRules struct{}
+
+func (noReviewRules) Upsert(context.Context, *entity.ReviewRule) error {
+	return nil
+}
+
+func (noReviewRules) ListByRepository(context.Context, string) ([]entity.ReviewRule, error) {
+	return []entity.ReviewRule{}, nil
+}
+
+func (noReviewRules) Delete(context.Context, int64) error {
+	return ErrNotFound
+}
+
+// SaveReviewRule adds a rule, or replaces the reviewers of the repository's
+// rule with the same pattern. Reviewers must be existing users, and the actor
+// must be allowed to manage the settings of each of their teams.
+func (uc *PRUseCase) SaveReviewRule(ctx context.Context, rule entity.ReviewRule) (entity.ReviewRule, error) {
+	rule.Repository = strings.TrimSpace(rule.Repository)
+	rule.Pattern = strings.TrimSpace(rule.Pattern)
+	rule.Reviewers = normalizeTags(rule.Reviewers)
+
+	if rule.Repository == "" {
+		return entity.ReviewRule{}, fmt.Errorf("%w: repository required", ErrInvalidRule)
+	}
+	if _, err := glob.Compile(rule.Pattern); err != nil {
+		return entity.ReviewRule{}, fmt.Errorf("%w: pattern %q: %v", ErrInvalidRule, rule.Pattern, err)
+	}
+	if len(rule.Reviewers) == 0 {
+		return entity.ReviewRule{}, fmt.Errorf("%w: at least one reviewer required", ErrInvalidRule)
+	}
+
+	var teams []string
+	for _, id := range rule.Reviewers {
+		u, err := uc.userRepo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return entity.ReviewRule{}, fmt.Errorf("%w: user %s not found", ErrInvalidRule, id)
+			}
+			return entity.ReviewRule{}, err
+		}
+		if !contains(teams, u.TeamName) {
+			teams = append(teams, u.TeamName)
+		}
+	}
+
+	for _, team := range teams {
+		if err := uc.authorizeLead(ctx, team, entity.ScopeSettings); err != nil {
+			return entity.ReviewRule{}, err
+		}
+	}
+
+	if err := uc.reviewRules.Upsert(ctx, &rule); err != nil {
+		return entity.ReviewRule{}, err
+	}
+
+	return rule, nil
+}
+
+func (uc *PRUseCase) ListReviewRules(ctx context.Context, repository string) ([]entity.ReviewRule, error) {
+	return uc.reviewRules.ListByRepository(ctx, repository)
+}
+
+func (uc *PRUseCase) DeleteReviewRule(ctx context.Context, id int64) error {
+	return lookupErr(uc.reviewRules.Delete(ctx, id))
+}
+
+// ruleOwners returns the reviewers the repository's rules name for files,
+// in file order, keeping only active, undeleted users other than the author
+// who aren't absent. For each file the last matching rule wins.
+func (uc *PRUseCase) ruleOwners(ctx context.Context, repository string, files []string, author entity.User) ([]string, error) {
+	if repository == "" || len(files) == 0 {
+		return nil, nil
+	}
+
+	rules, err := uc.reviewRules.ListByRepository(ctx, repository)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	patterns := make([]*glob.Pattern, len(rules))
+	for i, r := range rules {
+		// Rules are validated on save; a pattern that no longer compiles is skipped.
+		patterns[i], _ = glob.Compile(r.Pattern)
+	}
+
+	var owners []string
+	for _, f := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if patterns[i] == nil || !patterns[i].Match(f) {
+				continue
+			}
+			for _, id := range rules[i].Reviewers {
+				if !contains(owners, id) {
+					owners = append(owners, id)
+				}
+			}
+			break
+		}
+	}
+
+	eligible := owners[:0]
+	for _, id := range owners {
+		if id == author.UserID {
+			continue
+		}
+		u, err := uc.userRepo.GetByID(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !u.IsActive || u.DeletedAt != nil {
+			continue
+		}
+		absent, err := uc.isAbsent(ctx, id, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if !absent {
+			eligible = append(eligible, id)
+		}
+	}
+
+	return eligible, nil
+}
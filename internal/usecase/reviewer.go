@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// AddReviewer assigns userID to an open PR on behalf of the actor on ctx, who
+// must be userID or allowed to reassign reviews in the author's team. The
+// reviewer must pass the same rules as a claimant (see eligibleReviewer), but
+// the usual reviewer count does not cap manual additions. On
+// ErrPreconditionFailed the current PR is returned alongside the error.
+func (uc *PRUseCase) AddReviewer(ctx context.Context, prID, userID string) (entity.PullRequest, error) {
+	actorID, _ := actorFrom(ctx)
+
+	pr, err := uc.editablePR(ctx, prID)
+	if err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+		return pr, err
+	}
+
+	author, err := uc.eligibleReviewer(ctx, pr, userID)
+	if err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if err := uc.authorize(ctx, author.TeamName, entity.ScopeReassign, userID); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if contains(pr.AssignedReviewers, userID) {
+		return entity.PullRequest{}, ErrAlreadyAssigned
+	}
+
+	pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+	if err := uc.prRepo.Update(ctx, &pr); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	uc.notifyAssigned(ctx, pr, author.TeamName, userID)
+	uc.publish(ctx, entity.PREventReviewerAssigned, pr, author.TeamName, userID)
+	uc.audit(ctx, entity.AuditEntry{
+		ActorID:       actorID,
+		Action:        "pr.reviewer_add",
+		PullRequestID: pr.PullRequestID,
+		Source:        "api",
+	}, map[string]any{"reviewer": userID})
+
+	return pr, nil
+}
+
+// RemoveReviewer unassigns userID from an open PR on behalf of the actor on
+// ctx, along with any approval they gave; the actor is authorized as in
+// AddReviewer. No replacement is picked. On ErrPreconditionFailed the current
+// PR is returned alongside the error.
+func (uc *PRUseCase) RemoveReviewer(ctx context.Context, prID, userID string) (entity.PullRequest, error) {
+	actorID, _ := actorFrom(ctx)
+
+	pr, err := uc.editablePR(ctx, prID)
+	if err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	if err := checkPrecondition(ctx, pr.UpdatedAt); err != nil {
+		return pr, err
+	}
+
+	if !contains(pr.AssignedReviewers, userID) {
+		return entity.PullRequest{}, ErrNotAssigned
+	}
+
+	author, err := uc.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return entity.PullRequest{}, lookupErr(err)
+	}
+
+	if err := uc.authorize(ctx, author.TeamName, entity.ScopeReassign, userID); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	pr.AssignedReviewers = without(pr.AssignedReviewers, userID)
+	pr.Approvals = without(pr.Approvals, userID)
+	if err := uc.prRepo.Update(ctx, &pr); err != nil {
+		return entity.PullRequest{}, err
+	}
+
+	uc.publish(ctx, entity.PREventReviewerUnassigned, pr, author.TeamName, userID)
+	uc.audit(ctx, entity.AuditEntry{
+		ActorID:       actorID,
+		Action:        "pr.reviewer_remove",
+		PullRequestID: pr.PullRequestID,
+		Source:        "api",
+	}, map[string]any{"reviewer": userID})
+
+	return pr, nil
+}
+
+// editablePR loads a PR whose reviewers may still change.
+func (uc *PRUseCase) editablePR(ctx context.Context, prID string) (entity.PullRequest, error) {
+	return editable(uc.prRepo.GetByID(ctx, prID))
+}
+
+// lockEditablePR is editablePR that locks the PR for the transaction on ctx.
+func (uc *PRUseCase) lockEditablePR(ctx context.Context, prID string) (entity.PullRequest, error) {
+	return editable(uc.prRepo.GetForUpdate(ctx, prID))
+}
+
+func editable(pr entity.PullRequest, err error) (entity.PullRequest, error) {
+	if err != nil {
+		return entity.PullRequest{}, lookupErr(err)
+	}
+
+	switch pr.Status {
+	case entity.PRStatusMerged:
+		return entity.PullRequest{}, ErrPRMerged
+	case entity.PRStatusClosed:
+		return entity.PullRequest{}, ErrPRClosed
+	case entity.PRStatusDraft:
+		return entity.PullRequest{}, ErrPRDraft
+	}
+
+	return pr, nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// _maxStackID bounds the length of a PR's stack_id.
+const _maxStackID = 100
+
+// stackOrder reorders the candidates for a stacked PR by the team's
+// stack_reviewers setting: with same, those already reviewing another of the
+// author's PRs in the stack rank first, so one reviewer follows the whole
+// change; with diversify they rank last, spreading the stack over more eyes.
+// With same, a stack reviewer the shortlist left out is added to it when
+// they are still an active member of the team and not in exclude. It runs
+// after the cooldown, which it overrides. It returns the mode applied, empty
+// when the stack is ignored, and the candidates it moved.
+func (uc *PRUseCase) stackOrder(ctx context.Context, pr *entity.PullRequest, teamName string, candidates []entity.User, exclude []string) ([]entity.User, string, []string, error) {
+	if pr.StackID == "" {
+		return candidates, "", nil, nil
+	}
+
+	s, err := uc.teamSettings.Get(ctx, teamName)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, "", nil, err
+	}
+	mode := s.StackReviewersOr("")
+	if mode == "" {
+		return candidates, "", nil, nil
+	}
+
+	reviewing, err := uc.prRepo.StackReviewers(ctx, pr.AuthorID, pr.StackID, pr.PullRequestID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if mode == entity.StackReviewersSame {
+		candidates, err = uc.withStackReviewers(ctx, teamName, candidates, reviewing, exclude)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	var stack []string
+	for _, c := range candidates {
+		if slices.Contains(reviewing, c.UserID) {
+			stack = append(stack, c.UserID)
+		}
+	}
+	if len(stack) == 0 {
+		return candidates, mode, nil, nil
+	}
+
+	rank := boolRank
+	if mode == entity.StackReviewersSame {
+		rank = func(b bool) int { return boolRank(!b) }
+	}
+
+	ranked := slices.Clone(candidates)
+	slices.SortStableFunc(ranked, func(a, b entity.User) int {
+		return rank(slices.Contains(stack, a.UserID)) - rank(slices.Contains(stack, b.UserID))
+	})
+
+	return ranked, mode, stack, nil
+}
+
+// withStackReviewers adds to candidates the stack reviewers in reviewing that
+// could review for teamName but are missing from them.
+func (uc *PRUseCase) withStackReviewers(ctx context.Context, teamName string, candidates []entity.User, reviewing, exclude []string) ([]entity.User, error) {
+	for _, id := range reviewing {
+		if contains(exclude, id) || slices.ContainsFunc(candidates, func(c entity.User) bool { return c.UserID == id }) {
+			continue
+		}
+
+		u, err := uc.userRepo.GetByID(ctx, id)
+		if errors.Is(lookupErr(err), ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if u.IsActive && u.DeletedAt == nil && u.TeamName == teamName {
+			candidates = append(candidates, u)
+		}
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// _maxStatsHistoryDays bounds the range StatsHistory returns in one call.
+const _maxStatsHistoryDays = 366
+
+// Reviewer leaderboard sizes.
+const (
+	_defaultLeaderboardLimit = 20
+	_maxLeaderboardLimit     = 100
+)
+
+var ErrInvalidStatsRange = errors.New("invalid stats range")
+
+// noStats is used until a StatsRepo is configured.
+type noStats struct{}
+
+func (noStats) Stats(context.Context) (entity.Stats, error) {
+	return entity.Stats{}, errors.New("stats are not configured")
+}
+
+func (noStats) UserStats(context.Context, string) (entity.UserReviewStats, error) {
+	return entity.UserReviewStats{}, errors.New("stats are not configured")
+}
+
+func (noStats) UsersStats(context.Context, []string) (map[string]entity.UserReviewStats, error) {
+	return nil, errors.New("stats are not configured")
+}
+
+func (noStats) Series(context.Context, entity.AnalyticsRange, string) ([]entity.TimeBucket, error) {
+	return nil, errors.New("stats are not configured")
+}
+
+func (noStats) TeamSLA(context.Context, entity.AnalyticsRange) ([]entity.TeamSLA, error) {
+	return nil, errors.New("stats are not configured")
+}
+
+func (noStats) Leaderboard(context.Context, entity.ReviewerRankFilter) ([]entity.ReviewerRank, error) {
+	return nil, errors.New("stats are not configured")
+}
+
+// noStatsHistory is used until a StatsHistoryRepo is configured.
+type noStatsHistory struct{}
+
+func (noStatsHistory) Snapshot(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func (noStatsHistory) List(context.Context, string, time.Time, time.Time) ([]entity.TeamStatsSnapshot, error) {
+	return []entity.TeamStatsSnapshot{}, nil
+}
+
+func (noStatsHistory) AsOf(context.Context, string, time.Time) ([]entity.TeamStatsSnapshot, error) {
+	return []entity.TeamStatsSnapshot{}, nil
+}
+
+// The stats methods below report stale when their result was served from
+// the cache set up by WithStatsShedding.
+
+// GetStats returns service-wide counters from the stats read model.
+func (uc *PRUseCase) GetStats(ctx context.Context) (entity.Stats, bool, error) {
+	return cachedStats(uc.statsCache, func() (entity.Stats, error) {
+		return uc.stats.Stats(ctx)
+	}, "stats")
+}
+
+// StatsSeries counts the PRs created, merged and closed in each day or week
+// (bucket) from the start of from's bucket up to to, with empty buckets
+// included so the series can be charted as is.
+func (uc *PRUseCase) StatsSeries(ctx context.Context, from, to time.Time, bucket string) ([]entity.TimeBucket, bool, error) {
+	if bucket != entity.BucketDay && bucket != entity.BucketWeek {
+		return nil, false, fmt.Errorf("%w: bucket must be day or week", ErrInvalidStatsRange)
+	}
+	if !from.Before(to) {
+		return nil, false, fmt.Errorf("%w: from must be before to", ErrInvalidStatsRange)
+	}
+	if to.Sub(from) > _maxStatsHistoryDays*24*time.Hour {
+		return nil, false, fmt.Errorf("%w: range must not exceed %d days", ErrInvalidStatsRange, _maxStatsHistoryDays)
+	}
+
+	return cachedStats(uc.statsCache, func() ([]entity.TimeBucket, error) {
+		return uc.stats.Series(ctx, entity.AnalyticsRange{From: from, To: to}, bucket)
+	}, "series ", from, to, bucket)
+}
+
+// TeamSLA returns each team's median and p90 time to merge and to first
+// approval between from and to.
+func (uc *PRUseCase) TeamSLA(ctx context.Context, from, to time.Time) ([]entity.TeamSLA, bool, error) {
+	if !from.Before(to) {
+		return nil, false, fmt.Errorf("%w: from must be before to", ErrInvalidStatsRange)
+	}
+	if to.Sub(from) > _maxStatsHistoryDays*24*time.Hour {
+		return nil, false, fmt.Errorf("%w: range must not exceed %d days", ErrInvalidStatsRange, _maxStatsHistoryDays)
+	}
+
+	return cachedStats(uc.statsCache, func() ([]entity.TeamSLA, error) {
+		return uc.stats.TeamSLA(ctx, entity.AnalyticsRange{From: from, To: to})
+	}, "sla ", from, to)
+}
+
+// ReviewerLeaderboard ranks reviewers by completed reviews or open
+// assignments (f.Sort, completed by default), up to f.Limit of them.
+func (uc *PRUseCase) ReviewerLeaderboard(ctx context.Context, f entity.ReviewerRankFilter) ([]entity.ReviewerRank, bool, error) {
+	if f.Sort == "" {
+		f.Sort = entity.LeaderboardByCompleted
+	}
+	if f.Sort != entity.LeaderboardByCompleted && f.Sort != entity.LeaderboardByOpen {
+		return nil, false, fmt.Errorf("%w: sort must be completed or open", ErrInvalidStatsRange)
+	}
+	if f.Limit == 0 {
+		f.Limit = _defaultLeaderboardLimit
+	}
+	if f.Limit < 1 || f.Limit > _maxLeaderboardLimit {
+		return nil, false, fmt.Errorf("%w: limit must be within 1..%d", ErrInvalidStatsRange, _maxLeaderboardLimit)
+	}
+	if !f.From.Before(f.To) {
+		return nil, false, fmt.Errorf("%w: from must be before to", ErrInvalidStatsRange)
+	}
+	if f.To.Sub(f.From) > _maxStatsHistoryDays*24*time.Hour {
+		return nil, false, fmt.Errorf("%w: range must not exceed %d days", ErrInvalidStatsRange, _maxStatsHistoryDays)
+	}
+
+	return cachedStats(uc.statsCache, func() ([]entity.ReviewerRank, error) {
+		return uc.stats.Leaderboard(ctx, f)
+	}, "leaderboard ", f)
+}
+
+// UserStats returns userID's review statistics.
+func (uc *PRUseCase) UserStats(ctx context.Context, userID string) (entity.UserReviewStats, bool, error) {
+	return cachedStats(uc.statsCache, func() (entity.UserReviewStats, error) {
+		if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+			return entity.UserReviewStats{}, lookupErr(err)
+		}
+
+		return uc.stats.UserStats(ctx, userID)
+	}, "user ", userID)
+}
+
+// ForgetUserStats drops the statistics remembered for userID, so a deleted
+// user's aren't served while the database is degraded.
+func (uc *PRUseCase) ForgetUserStats(userID string) {
+	if uc.statsCache != nil {
+		uc.statsCache.forget(fmt.Sprint("user ", userID))
+	}
+}
+
+// UsersStats returns the review statistics of each of userIDs, which are not
+// checked to exist.
+func (uc *PRUseCase) UsersStats(ctx context.Context, userIDs []string) (map[string]entity.UserReviewStats, error) {
+	return uc.stats.UsersStats(ctx, userIDs)
+}
+
+// SnapshotStats records every team's stats for now's UTC day; running it
+// again the same day replaces that day's snapshot. It returns how many teams
+// were recorded.
+func (uc *PRUseCase) SnapshotStats(ctx context.Context, now time.Time) (int, error) {
+	return uc.statsHistory.Snapshot(ctx, now.UTC())
+}
+
+// StatsHistory returns the snapshots taken between from and to inclusive;
+// an empty teamName covers every team.
+func (uc *PRUseCase) StatsHistory(ctx context.Context, teamName string, from, to time.Time) ([]entity.TeamStatsSnapshot, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidStatsRange)
+	}
+	if to.Sub(from) > _maxStatsHistoryDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: range must not exceed %d days", ErrInvalidStatsRange, _maxStatsHistoryDays)
+	}
+
+	return uc.statsHistory.List(ctx, teamName, from, to)
+}
+
+// StatsAsOf returns each team's latest snapshot taken on or before day, so
+// reports keep their numbers after the PRs behind them are gone.
+func (uc *PRUseCase) StatsAsOf(ctx context.Context, teamName string, day time.Time) ([]entity.TeamStatsSnapshot, error) {
+	return uc.statsHistory.AsOf(ctx, teamName, day)
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// _statsCacheSize bounds how many distinct stats queries are remembered.
+const _statsCacheSize = 256
+
+var _staleStats = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "pr_service_stats_stale_total",
+	Help: "Stats responses served from cache, by reason (degraded, error).",
+}, []string{"reason"})
+
+// statsCache keeps the last result of each stats query so it can be served
+// instead of querying a degraded database.
+type statsCache struct {
+	degraded func() bool
+	maxAge   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]statsEntry
+}
+
+type statsEntry struct {
+	value any
+	at    time.Time
+}
+
+func newStatsCache(degraded func() bool, maxAge time.Duration) *statsCache {
+	return &statsCache{degraded: degraded, maxAge: maxAge, entries: make(map[string]statsEntry)}
+}
+
+func (c *statsCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Since(e.at) > c.maxAge {
+		return nil, false
+	}
+
+	return e.value, true
+}
+
+func (c *statsCache) put(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= _statsCacheSize {
+		var oldest string
+		for k, e := range c.entries {
+			if oldest == "" || e.at.Before(c.entries[oldest].at) {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+
+	c.entries[key] = statsEntry{value: value, at: time.Now()}
+}
+
+func (c *statsCache) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// cachedStats runs load and remembers its result under key's arguments.
+// While the database is degraded, or when load fails with anything but
+// ErrNotFound, a result remembered at most maxAge ago is returned instead,
+// with stale set. Without a cache it just runs load.
+func cachedStats[T any](c *statsCache, load func() (T, error), key ...any) (result T, stale bool, err error) {
+	if c == nil {
+		result, err = load()
+		return result, false, err
+	}
+
+	k := fmt.Sprint(key...)
+
+	if c.degraded() {
+		if v, ok := c.get(k); ok {
+			_staleStats.WithLabelValues("degraded").Inc()
+			return v.(T), true, nil
+		}
+	}
+
+	result, err = load()
+	if err == nil {
+		c.put(k, result)
+		return result, false, nil
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		if v, ok := c.get(k); ok {
+			_staleStats.WithLabelValues("error").Inc()
+			return v.(T), true, nil
+		}
+	}
+
+	return result, false, err
+}
@@ -0,0 +1,310 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var (
+	ErrInvalidProfile  = errors.New("invalid profile")
+	ErrInvalidTeamName = errors.New("invalid team name")
+	ErrTeamHasOpenPRs  = errors.New("TEAM_HAS_OPEN_PRS")
+)
+
+// How DeleteTeam treats open PRs that reference the team's members.
+const (
+	TeamDeleteBlock    = "block"
+	TeamDeleteReassign = "reassign"
+	TeamDeleteCancel   = "cancel"
+)
+
+// CreateTeam creates a team with its members. Members without a user_id get a
+// server-generated one; members without a role become entity.RoleMember.
+// Members that already exist keep their team, role, seniority and activity,
+// and deleted ones stay deleted; only their name, title and avatar change.
+func (uc *PRUseCase) CreateTeam(ctx context.Context, t entity.Team) (entity.Team, error) {
+	for i := range t.Members {
+		m := &t.Members[i]
+		if m.UserID == "" {
+			m.UserID = uc.ids.New()
+		}
+		if m.Role == "" {
+			m.Role = entity.RoleMember
+		}
+		if err := entity.ValidateProfile(m.Role, m.Seniority); err != nil {
+			return entity.Team{}, fmt.Errorf("%w: member %s: %w", ErrInvalidProfile, m.UserID, err)
+		}
+	}
+
+	created := t
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := uc.teamRepo.GetByName(ctx, t.TeamName); err == nil {
+			return ErrTeamExists
+		}
+
+		err := uc.teamRepo.Create(ctx, t)
+		if errors.Is(err, ErrAlreadyExists) {
+			return ErrTeamExists
+		}
+		if err != nil {
+			return err
+		}
+
+		// Re-read to pick up the timestamps stamped by the database.
+		if team, err := uc.teamRepo.GetByName(ctx, t.TeamName); err == nil {
+			created = team
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.Team{}, err
+	}
+
+	for _, m := range created.Members {
+		uc.publishUser(ctx, entity.UserEventUpdated, entity.User{UserID: m.UserID, TeamName: created.TeamName, IsActive: m.IsActive})
+	}
+
+	return created, nil
+}
+
+// DeleteTeam deactivates the team's members, takes them out of it and
+// removes the team with its settings. With TeamDeleteBlock it returns
+// ErrTeamHasOpenPRs, listing the PRs, while members author or review open
+// PRs; TeamDeleteReassign hands their unapproved reviews to other members of
+// the PR author's team and TeamDeleteCancel only drops them. PRs the members
+// authored stay open either way. Only a lead of the team, or a delegate with
+// the settings scope, may delete it.
+func (uc *PRUseCase) DeleteTeam(ctx context.Context, teamName, mode string) (entity.TeamDeletion, error) {
+	actorID, _ := actorFrom(ctx)
+
+	var deletion entity.TeamDeletion
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		members, err := uc.userRepo.ListByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		exists, err := uc.teamRepo.Exists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if !exists && len(members) == 0 {
+			return ErrNotFound
+		}
+
+		if err := uc.authorizeLead(ctx, teamName, entity.ScopeSettings); err != nil {
+			return err
+		}
+		ctx = withoutActor(ctx)
+
+		deletion = entity.TeamDeletion{TeamName: teamName, Deactivated: []string{}, Reassigned: map[string]string{}, Unassigned: []string{}}
+
+		if mode == TeamDeleteBlock {
+			open, err := uc.openPRsOf(ctx, teamName, members)
+			if err != nil {
+				return err
+			}
+			if len(open) > 0 {
+				deletion.OpenPullRequests = open
+				return ErrTeamHasOpenPRs
+			}
+		}
+
+		// Take everyone out first so the reassignments below can't pick them.
+		for i := range members {
+			members[i].IsActive = false
+			members[i].TeamName = ""
+			if err := uc.userRepo.Update(ctx, &members[i]); err != nil {
+				return err
+			}
+			uc.publishUser(ctx, entity.UserEventUpdated, members[i])
+			deletion.Deactivated = append(deletion.Deactivated, members[i].UserID)
+		}
+
+		for _, u := range members {
+			if mode == TeamDeleteReassign {
+				reassigned, unassigned, err := uc.releaseReviews(ctx, u)
+				if err != nil {
+					return err
+				}
+				for prID, to := range reassigned {
+					deletion.Reassigned[prID] = to
+				}
+				deletion.Unassigned = append(deletion.Unassigned, unassigned...)
+				continue
+			}
+
+			unassigned, err := uc.cancelReviews(ctx, u, teamName)
+			if err != nil {
+				return err
+			}
+			deletion.Unassigned = append(deletion.Unassigned, unassigned...)
+		}
+
+		if err := uc.teamRepo.Delete(ctx, teamName); err != nil {
+			return err
+		}
+
+		uc.audit(ctx, entity.AuditEntry{
+			ActorID: actorID,
+			Action:  "team.delete",
+			Source:  "api",
+		}, map[string]any{
+			"team_name":   teamName,
+			"mode":        mode,
+			"deactivated": deletion.Deactivated,
+			"reassigned":  deletion.Reassigned,
+			"unassigned":  deletion.Unassigned,
+		})
+
+		return nil
+	})
+	if errors.Is(err, ErrTeamHasOpenPRs) {
+		return deletion, err
+	}
+	if err != nil {
+		return entity.TeamDeletion{}, err
+	}
+
+	return deletion, nil
+}
+
+// RenameTeam renames the team, moving its members, settings, webhooks and
+// rotations along with it in one transaction. ErrTeamExists is returned when
+// newName is taken. Only a lead of the team, or a delegate with the settings
+// scope, may rename it.
+func (uc *PRUseCase) RenameTeam(ctx context.Context, oldName, newName string) (entity.TeamRename, error) {
+	actorID, _ := actorFrom(ctx)
+
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return entity.TeamRename{}, fmt.Errorf("%w: new_team_name must not be empty", ErrInvalidTeamName)
+	}
+	if newName == oldName {
+		return entity.TeamRename{}, fmt.Errorf("%w: new_team_name must differ from team_name", ErrInvalidTeamName)
+	}
+
+	rename := entity.TeamRename{OldName: oldName, NewName: newName, Members: []string{}}
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		members, err := uc.userRepo.ListByTeam(ctx, oldName)
+		if err != nil {
+			return err
+		}
+
+		exists, err := uc.teamRepo.Exists(ctx, oldName)
+		if err != nil {
+			return err
+		}
+		if !exists && len(members) == 0 {
+			return ErrNotFound
+		}
+
+		if err := uc.authorizeLead(ctx, oldName, entity.ScopeSettings); err != nil {
+			return err
+		}
+
+		taken, err := uc.teamRepo.Exists(ctx, newName)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			occupants, err := uc.userRepo.ListByTeam(ctx, newName)
+			if err != nil {
+				return err
+			}
+			taken = len(occupants) > 0
+		}
+		if taken {
+			return ErrTeamExists
+		}
+
+		if err := uc.teamRepo.Rename(ctx, oldName, newName); err != nil {
+			return err
+		}
+
+		for _, u := range members {
+			rename.Members = append(rename.Members, u.UserID)
+		}
+
+		uc.audit(ctx, entity.AuditEntry{
+			ActorID: actorID,
+			Action:  "team.rename",
+			Source:  "api",
+		}, map[string]any{
+			"old_team_name": oldName,
+			"new_team_name": newName,
+			"members":       rename.Members,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return entity.TeamRename{}, err
+	}
+
+	return rename, nil
+}
+
+// openPRsOf lists open or draft PRs authored by the team or awaiting a review
+// from one of members.
+func (uc *PRUseCase) openPRsOf(ctx context.Context, teamName string, members []entity.User) ([]string, error) {
+	authored, err := uc.prRepo.ListTeamActivity(ctx, teamName, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var open []string
+	for _, pr := range authored {
+		if pr.Status != entity.PRStatusMerged {
+			open = append(open, pr.PullRequestID)
+		}
+	}
+
+	for _, u := range members {
+		prs, err := uc.prRepo.ListByReviewer(ctx, u.UserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			if pr.Status == entity.PRStatusOpen && !contains(pr.Approvals, u.UserID) && !contains(open, pr.PullRequestID) {
+				open = append(open, pr.PullRequestID)
+			}
+		}
+	}
+
+	return open, nil
+}
+
+// cancelReviews drops u from the open PRs they were reviewing and haven't
+// approved, without a replacement.
+func (uc *PRUseCase) cancelReviews(ctx context.Context, u entity.User, teamName string) ([]string, error) {
+	prs, err := uc.prRepo.ListByReviewer(ctx, u.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var unassigned []string
+	for _, pr := range prs {
+		if pr.Status != entity.PRStatusOpen || contains(pr.Approvals, u.UserID) {
+			continue
+		}
+
+		pr.AssignedReviewers = without(pr.AssignedReviewers, u.UserID)
+		if err := uc.prRepo.Update(ctx, &pr); err != nil {
+			return nil, err
+		}
+		uc.publish(ctx, entity.PREventReviewerUnassigned, pr, teamName, u.UserID)
+		unassigned = append(unassigned, pr.PullRequestID)
+	}
+
+	return unassigned, nil
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import "context"
+
+// noTx runs fn without a transaction; used until a TxManager is configured.
+type noTx struct{}
+
+func (noTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type afterCommitKey struct{}
+
+// hookedTx runs the hooks registered with afterCommit once the outermost
+// transaction commits, and drops them when it rolls back.
+type hookedTx struct {
+	TxManager
+}
+
+func (t hookedTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(afterCommitKey{}).(*[]func(context.Context)); ok {
+		return t.TxManager.WithinTx(ctx, fn)
+	}
+
+	var hooks []func(context.Context)
+	if err := t.TxManager.WithinTx(context.WithValue(ctx, afterCommitKey{}, &hooks), fn); err != nil {
+		return err
+	}
+
+	// The hooks get ctx from before the transaction, so their own queries
+	// don't go to the finished one.
+	for _, h := range hooks {
+		h(ctx)
+	}
+
+	return nil
+}
+
+// afterCommit defers fn until the transaction in ctx commits, or calls it now
+// outside one. Side effects others can observe (VCS calls, events) go through
+// it so a rolled-back change never leaks out.
+func afterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(afterCommitKey{}).(*[]func(context.Context)); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+
+	fn(ctx)
+}
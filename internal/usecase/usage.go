@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// UsageUseCase counts API requests per client. Requests are tallied in memory
+// and folded into hourly buckets in the repo by Flush, so serving a request
+// never waits on Postgres.
+type UsageUseCase struct {
+	repo UsageRepo
+
+	mu      sync.Mutex
+	pending map[usageKey]*entity.UsageBucket
+}
+
+type usageKey struct {
+	clientID, method, route string
+	bucket                  time.Time
+}
+
+func NewUsageUseCase(repo UsageRepo) *UsageUseCase {
+	return &UsageUseCase{
+		repo:    repo,
+		pending: make(map[usageKey]*entity.UsageBucket),
+	}
+}
+
+// Record tallies r.
+func (uc *UsageUseCase) Record(r entity.APIRequest) {
+	key := usageKey{clientID: r.ClientID, method: r.Method, route: r.Route, bucket: r.At.UTC().Truncate(time.Hour)}
+	ms := float64(r.Latency) / float64(time.Millisecond)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	b, ok := uc.pending[key]
+	if !ok {
+		b = &entity.UsageBucket{ClientID: key.clientID, Method: key.method, Route: key.route, Bucket: key.bucket}
+		uc.pending[key] = b
+	}
+
+	b.Requests++
+	if r.Status >= 400 {
+		b.Errors++
+	}
+	b.LatencyMs += ms
+	b.MaxLatencyMs = max(b.MaxLatencyMs, ms)
+}
+
+// Flush writes the tallied counters to the repo. On failure they are kept and
+// retried by the next Flush.
+func (uc *UsageUseCase) Flush(ctx context.Context) error {
+	uc.mu.Lock()
+	pending := uc.pending
+	uc.pending = make(map[usageKey]*entity.UsageBucket)
+	uc.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	buckets := make([]entity.UsageBucket, 0, len(pending))
+	for _, b := range pending {
+		buckets = append(buckets, *b)
+	}
+
+	if err := uc.repo.Add(ctx, buckets); err != nil {
+		uc.restore(pending)
+		return err
+	}
+
+	return nil
+}
+
+func (uc *UsageUseCase) restore(pending map[usageKey]*entity.UsageBucket) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for key, old := range pending {
+		b, ok := uc.pending[key]
+		if !ok {
+			uc.pending[key] = old
+			continue
+		}
+		b.Requests += old.Requests
+		b.Errors += old.Errors
+		b.LatencyMs += old.LatencyMs
+		b.MaxLatencyMs = max(b.MaxLatencyMs, old.MaxLatencyMs)
+	}
+}
+
+// Report returns per-client traffic since the start of since's hour, busiest
+// client first. An empty clientID reports every client. Counters this process
+// hasn't flushed yet are included; other processes' are not.
+func (uc *UsageUseCase) Report(ctx context.Context, since time.Time, clientID string) ([]entity.ClientUsage, error) {
+	if err := uc.Flush(ctx); err != nil {
+		return nil, err
+	}
+
+	buckets, err := uc.repo.List(ctx, since, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := []entity.ClientUsage{}
+	totals := []entity.UsageBucket{}
+	index := map[string]int{}
+	for _, b := range buckets {
+		i, ok := index[b.ClientID]
+		if !ok {
+			i = len(clients)
+			index[b.ClientID] = i
+			clients = append(clients, entity.ClientUsage{ClientID: b.ClientID, Endpoints: []entity.EndpointUsage{}})
+			totals = append(totals, entity.UsageBucket{})
+		}
+
+		clients[i].Endpoints = append(clients[i].Endpoints, entity.EndpointUsage{Method: b.Method, Route: b.Route, UsageSummary: summarize(b)})
+		totals[i].Requests += b.Requests
+		totals[i].Errors += b.Errors
+		totals[i].LatencyMs += b.LatencyMs
+		totals[i].MaxLatencyMs = max(totals[i].MaxLatencyMs, b.MaxLatencyMs)
+	}
+
+	for i := range clients {
+		c := &clients[i]
+		c.UsageSummary = summarize(totals[i])
+		sort.SliceStable(c.Endpoints, func(a, b int) bool { return c.Endpoints[a].Requests > c.Endpoints[b].Requests })
+	}
+	sort.SliceStable(clients, func(a, b int) bool { return clients[a].Requests > clients[b].Requests })
+
+	return clients, nil
+}
+
+func summarize(b entity.UsageBucket) entity.UsageSummary {
+	s := entity.UsageSummary{Requests: b.Requests, Errors: b.Errors, MaxLatencyMs: b.MaxLatencyMs}
+	if b.Requests > 0 {
+		s.ErrorRate = float64(b.Errors) / float64(b.Requests)
+		s.AvgLatencyMs = b.LatencyMs / float64(b.Requests)
+	}
+	return s
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// SetUserActive toggles the user's is_active flag. On ErrPreconditionFailed the
+// current user is returned alongside the error.
+func (uc *PRUseCase) SetUserActive(ctx context.Context, userID string, active bool) (entity.User, error) {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.User{}, lookupErr(err)
+	}
+
+	if err := uc.authorize(ctx, u.TeamName, entity.ScopeCapacity, u.UserID); err != nil {
+		return entity.User{}, err
+	}
+
+	if err := checkPrecondition(ctx, u.UpdatedAt); err != nil {
+		return u, err
+	}
+
+	u.IsActive = active
+	if err := uc.userRepo.Update(ctx, &u); err != nil {
+		return entity.User{}, err
+	}
+
+	uc.publishUser(ctx, entity.UserEventUpdated, u)
+
+	return u, nil
+}
+
+// ProfileUpdate changes the fields that are set and keeps the nil ones.
+type ProfileUpdate struct {
+	Role        *string
+	Title       *string
+	Seniority   *string
+	DisplayName *string
+	AvatarURL   *string
+	// MaxOpenReviews of 0 removes the user's limit.
+	MaxOpenReviews *int
+}
+
+// SetUserProfile updates the user's role, title, seniority, review limit and
+// display metadata. Users may change their own title, display name and
+// avatar; the role, seniority and review limit are lead-only, and no
+// delegation covers the role. On ErrPreconditionFailed the current user is
+// returned alongside the error.
+func (uc *PRUseCase) SetUserProfile(ctx context.Context, userID string, p ProfileUpdate) (entity.User, error) {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.User{}, lookupErr(err)
+	}
+
+	if err := uc.authorize(ctx, u.TeamName, entity.ScopeCapacity, u.UserID); err != nil {
+		return entity.User{}, err
+	}
+	if p.Seniority != nil || p.MaxOpenReviews != nil {
+		if err := uc.authorizeLead(ctx, u.TeamName, entity.ScopeCapacity); err != nil {
+			return entity.User{}, err
+		}
+	}
+	// Roles decide who leads the team, so a delegate can't grant them.
+	if p.Role != nil {
+		if err := uc.authorizeLead(ctx, u.TeamName, ""); err != nil {
+			return entity.User{}, err
+		}
+	}
+
+	if err := checkPrecondition(ctx, u.UpdatedAt); err != nil {
+		return u, err
+	}
+
+	if p.Role != nil {
+		u.Role = *p.Role
+	}
+	if p.Title != nil {
+		u.Title = *p.Title
+	}
+	if p.Seniority != nil {
+		u.Seniority = *p.Seniority
+	}
+	if p.DisplayName != nil {
+		u.DisplayName = *p.DisplayName
+	}
+	if p.AvatarURL != nil {
+		u.AvatarURL = *p.AvatarURL
+	}
+	if p.MaxOpenReviews != nil {
+		if *p.MaxOpenReviews < 0 {
+			return entity.User{}, fmt.Errorf("%w: max_open_reviews must not be negative", ErrInvalidProfile)
+		}
+		u.MaxOpenReviews = *p.MaxOpenReviews
+	}
+
+	if err := entity.ValidateProfile(u.Role, u.Seniority); err != nil {
+		return entity.User{}, fmt.Errorf("%w: %w", ErrInvalidProfile, err)
+	}
+
+	if err := uc.userRepo.Update(ctx, &u); err != nil {
+		return entity.User{}, err
+	}
+
+	uc.publishUser(ctx, entity.UserEventUpdated, u)
+
+	return u, nil
+}
+
+// SyncDisplay copies the display name and avatar reported by a VCS onto the
+// user. Empty values keep what is stored; unknown users are ignored.
+func (uc *PRUseCase) SyncDisplay(ctx context.Context, userID, displayName, avatarURL string) error {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if displayName != "" && displayName != u.DisplayName {
+		u.DisplayName, changed = displayName, true
+	}
+	if avatarURL != "" && avatarURL != u.AvatarURL {
+		u.AvatarURL, changed = avatarURL, true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := uc.userRepo.Update(ctx, &u); err != nil {
+		return err
+	}
+
+	uc.publishUser(ctx, entity.UserEventUpdated, u)
+
+	return nil
+}
+
+// displayName returns how userID is presented in notifications.
+func (uc *PRUseCase) displayName(ctx context.Context, userID string) string {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return userID
+	}
+
+	return u.Name()
+}
+
+// RemoveTeamMember takes userID out of teamName and hands the open reviews
+// they haven't approved to other members of the PR author's team. It returns
+// ErrNotTeamMember when the user belongs to another team.
+func (uc *PRUseCase) RemoveTeamMember(ctx context.Context, teamName, userID string) (entity.TeamChange, error) {
+	return uc.changeTeam(ctx, userID, teamName, "")
+}
+
+// TransferTeam moves userID to toTeam and reassigns their open reviews as
+// RemoveTeamMember does. Transferring to the current team changes nothing.
+func (uc *PRUseCase) TransferTeam(ctx context.Context, userID, toTeam string) (entity.TeamChange, error) {
+	return uc.changeTeam(ctx, userID, "", toTeam)
+}
+
+// changeTeam moves userID out of fromTeam (any team when empty) into toTeam
+// (none when empty). The actor must be allowed to manage capacity in both
+// teams; users may leave their own team but not join another on their own.
+func (uc *PRUseCase) changeTeam(ctx context.Context, userID, fromTeam, toTeam string) (entity.TeamChange, error) {
+	actorID, _ := actorFrom(ctx)
+
+	var change entity.TeamChange
+
+	err := uc.tx.WithinTx(ctx, func(ctx context.Context) error {
+		u, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return lookupErr(err)
+		}
+
+		if fromTeam != "" && u.TeamName != fromTeam {
+			return ErrNotTeamMember
+		}
+
+		change = entity.TeamChange{User: u, FromTeam: u.TeamName, ToTeam: toTeam, Reassigned: map[string]string{}, Unassigned: []string{}}
+		if toTeam != "" && toTeam == u.TeamName {
+			return nil
+		}
+
+		if toTeam != "" {
+			if err := uc.requireTeam(ctx, toTeam); err != nil {
+				return err
+			}
+		}
+
+		if u.TeamName != "" {
+			if err := uc.authorize(ctx, u.TeamName, entity.ScopeCapacity, u.UserID); err != nil {
+				return err
+			}
+		}
+		if toTeam != "" {
+			if err := uc.authorize(ctx, toTeam, entity.ScopeCapacity, ""); err != nil {
+				return err
+			}
+		}
+		ctx = withoutActor(ctx)
+
+		// Move first so the reassignments below can't pick the user again.
+		u.TeamName = toTeam
+		if err := uc.userRepo.Update(ctx, &u); err != nil {
+			return err
+		}
+		change.User = u
+		uc.publishUser(ctx, entity.UserEventUpdated, u)
+
+		if change.Reassigned, change.Unassigned, err = uc.releaseReviews(ctx, u); err != nil {
+			return err
+		}
+
+		action := "user.transfer_team"
+		if toTeam == "" {
+			action = "team.remove_member"
+		}
+		uc.audit(ctx, entity.AuditEntry{
+			ActorID: actorID,
+			Action:  action,
+			Source:  "api",
+		}, map[string]any{
+			"user_id":    userID,
+			"from_team":  change.FromTeam,
+			"to_team":    toTeam,
+			"reassigned": change.Reassigned,
+			"unassigned": change.Unassigned,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return entity.TeamChange{}, err
+	}
+
+	return change, nil
+}
+
+// GetUser -.
+func (uc *PRUseCase) GetUser(ctx context.Context, userID string) (entity.User, error) {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.User{}, lookupErr(err)
+	}
+
+	return u, nil
+}
+
+// ResolveCaller maps a verified token subject to the service user it was
+// linked to through an oidc identity, which only an admin or the user's own
+// authenticated request can create. Neither the subject nor the token's email
+// is trusted on its own. It returns ErrNotFound for an unlinked subject.
+func (uc *PRUseCase) ResolveCaller(ctx context.Context, subject string) (string, error) {
+	userID, err := uc.identities.UserIDFor(ctx, entity.IdentityProviderOIDC, subject)
+	if err != nil {
+		return "", lookupErr(err)
+	}
+
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return "", lookupErr(err)
+	}
+
+	return userID, nil
+}
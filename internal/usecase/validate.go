@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+// ErrInvalidEntity is returned when a write would store an entity breaking
+// its Validate rules.
+var ErrInvalidEntity = entity.ErrInvalid
+
+// The validated repos check entities with their Validate method before each
+// write, so no use case can persist an invalid one. NewPRUseCase wraps the
+// repositories it is given.
+type (
+	validatedPRs   struct{ PRRepo }
+	validatedUsers struct{ UserRepo }
+	validatedTeams struct{ TeamRepo }
+)
+
+func (r validatedPRs) Create(ctx context.Context, pr entity.PullRequest) error {
+	if err := pr.Validate(); err != nil {
+		return fmt.Errorf("invalid pull request: %w", err)
+	}
+	return r.PRRepo.Create(ctx, pr)
+}
+
+func (r validatedPRs) Update(ctx context.Context, pr *entity.PullRequest) error {
+	if err := pr.Validate(); err != nil {
+		return fmt.Errorf("invalid pull request: %w", err)
+	}
+	return r.PRRepo.Update(ctx, pr)
+}
+
+func (r validatedUsers) Create(ctx context.Context, u entity.User) error {
+	if err := u.Validate(); err != nil {
+		return fmt.Errorf("invalid user: %w", err)
+	}
+	return r.UserRepo.Create(ctx, u)
+}
+
+func (r validatedUsers) Update(ctx context.Context, u *entity.User) error {
+	if err := u.Validate(); err != nil {
+		return fmt.Errorf("invalid user: %w", err)
+	}
+	return r.UserRepo.Update(ctx, u)
+}
+
+func (r validatedTeams) Create(ctx context.Context, t entity.Team) error {
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("invalid team: %w", err)
+	}
+	return r.TeamRepo.Create(ctx, t)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+var (
+	ErrDuplicateDelivery = errors.New("DUPLICATE_DELIVERY")
+	ErrUnknownProvider   = errors.New("UNKNOWN_PROVIDER")
+)
+
+// WebhookUseCase persists raw inbound webhook payloads before handing them to
+// the provider's processor, so failed deliveries can be replayed later.
+type WebhookUseCase struct {
+	repo WebhookDeliveryRepo
+
+	mu         sync.RWMutex
+	processors map[string]WebhookProcessor
+}
+
+type ReplayResult struct {
+	ID    int64  `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+func NewWebhookUseCase(repo WebhookDeliveryRepo) *WebhookUseCase {
+	return &WebhookUseCase{
+		repo:       repo,
+		processors: make(map[string]WebhookProcessor),
+	}
+}
+
+func (uc *WebhookUseCase) RegisterProcessor(provider string, p WebhookProcessor) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.processors[provider] = p
+}
+
+// Ingest stores the payload and processes it. A repeated delivery ID for the
+// same provider returns ErrDuplicateDelivery without processing it again.
+func (uc *WebhookUseCase) Ingest(ctx context.Context, provider, deliveryID, event string, payload []byte) error {
+	id, err := uc.repo.Create(ctx, entity.WebhookDelivery{
+		Provider:   provider,
+		DeliveryID: deliveryID,
+		Event:      event,
+		Payload:    payload,
+	})
+	if err != nil {
+		if errors.Is(err, ErrAlreadyExists) {
+			return ErrDuplicateDelivery
+		}
+		return err
+	}
+
+	return uc.process(ctx, id, provider, event, payload)
+}
+
+// Replay re-runs the processor for each stored delivery and reports per-ID
+// results, including the errors of deliveries that couldn't be loaded.
+func (uc *WebhookUseCase) Replay(ctx context.Context, ids []int64) ([]ReplayResult, error) {
+	results := make([]ReplayResult, 0, len(ids))
+
+	for _, id := range ids {
+		d, err := uc.repo.GetByID(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			results = append(results, ReplayResult{ID: id, Error: ErrNotFound.Error()})
+			continue
+		}
+		if err != nil {
+			results = append(results, ReplayResult{ID: id, Error: err.Error()})
+			continue
+		}
+
+		if err := uc.process(ctx, d.ID, d.Provider, d.Event, d.Payload); err != nil {
+			results = append(results, ReplayResult{ID: id, Error: err.Error()})
+			continue
+		}
+
+		results = append(results, ReplayResult{ID: id, OK: true})
+	}
+
+	return results, nil
+}
+
+func (uc *WebhookUseCase) ListDeliveries(ctx context.Context, f entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, error) {
+	return uc.repo.List(ctx, f)
+}
+
+func (uc *WebhookUseCase) process(ctx context.Context, id int64, provider, event string, payload []byte) error {
+	uc.mu.RLock()
+	p, ok := uc.processors[provider]
+	uc.mu.RUnlock()
+
+	var processErr error
+	if !ok {
+		processErr = ErrUnknownProvider
+	} else {
+		processErr = p.Process(ctx, event, payload)
+	}
+
+	if err := uc.repo.MarkAttempt(ctx, id, processErr); err != nil {
+		return fmt.Errorf("mark attempt: %w", err)
+	}
+
+	return processErr
+}
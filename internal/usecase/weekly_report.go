@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+	"github.com/evrone/go-clean-template/pkg/notifier"
+	"github.com/evrone/go-clean-template/pkg/pagination"
+)
+
+// _weeklyReportWindow is the span a weekly report covers.
+const _weeklyReportWindow = 7 * 24 * time.Hour
+
+// SendWeeklyReports posts each team's digest of the week before now to the
+// team. Teams without members or without any activity that week are skipped.
+// It returns how many teams got a report.
+func (uc *PRUseCase) SendWeeklyReports(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	page := pagination.Page{Limit: pagination.MaxLimit}
+
+	for {
+		teams, err := uc.teamRepo.ListBrief(ctx, page)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, t := range teams[:min(len(teams), page.Limit)] {
+			if t.MemberCount == 0 {
+				continue
+			}
+
+			d, err := uc.TeamDigest(ctx, t.TeamName, now, _weeklyReportWindow)
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return sent, err
+			}
+
+			if digestEmpty(d) {
+				continue
+			}
+
+			_ = uc.notifier.Notify(ctx, notifier.Message{
+				Kind:     "weekly_report",
+				TeamName: t.TeamName,
+				Text:     d.Markdown,
+			})
+			sent++
+		}
+
+		if len(teams) <= page.Limit {
+			return sent, nil
+		}
+		page.After = &pagination.Cursor{ID: teams[page.Limit-1].TeamName}
+	}
+}
+
+// digestEmpty reports whether d has nothing to tell.
+func digestEmpty(d entity.TeamDigest) bool {
+	return len(d.NewPRs) == 0 && len(d.Merged) == 0 && len(d.Overdue) == 0 && len(d.Overloaded) == 0
+}
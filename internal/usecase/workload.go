@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/evrone/go-clean-template/internal/entity"
+)
+
+type noWorkload struct{}
+
+func (noWorkload) Refresh(context.Context) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (noWorkload) ListByTeam(context.Context, string) ([]entity.ReviewerLoad, time.Time, error) {
+	return []entity.ReviewerLoad{}, time.Time{}, nil
+}
+
+// TeamCapacity reports the open review load of the team's active members.
+func (uc *PRUseCase) TeamCapacity(ctx context.Context, teamName string) (entity.TeamCapacity, error) {
+	exists, err := uc.teamRepo.Exists(ctx, teamName)
+	if err != nil {
+		return entity.TeamCapacity{}, err
+	}
+	if !exists {
+		return entity.TeamCapacity{}, ErrNotFound
+	}
+
+	members, refreshedAt, err := uc.workload.ListByTeam(ctx, teamName)
+	if err != nil {
+		return entity.TeamCapacity{}, err
+	}
+
+	return entity.TeamCapacity{TeamName: teamName, Members: members, RefreshedAt: refreshedAt}, nil
+}
+
+// ReviewerLoad reports the user's live open review count against their limit.
+func (uc *PRUseCase) ReviewerLoad(ctx context.Context, userID string) (entity.ReviewerLoad, error) {
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return entity.ReviewerLoad{}, lookupErr(err)
+	}
+
+	counts, err := uc.prRepo.CountOpenByReviewer(ctx, []string{userID})
+	if err != nil {
+		return entity.ReviewerLoad{}, err
+	}
+
+	return entity.ReviewerLoad{UserID: userID, OpenReviews: counts[userID], MaxOpenReviews: u.MaxOpenReviews}, nil
+}
+
+// saturatedReviewers returns the members of teamName who reached their
+// max_open_reviews, by live count, for assignment to skip.
+func (uc *PRUseCase) saturatedReviewers(ctx context.Context, teamName string) ([]string, error) {
+	members, err := uc.userRepo.ListByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	capped := map[string]int{}
+	ids := []string{}
+	for _, u := range members {
+		if u.IsActive && u.MaxOpenReviews > 0 {
+			capped[u.UserID] = u.MaxOpenReviews
+			ids = append(ids, u.UserID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	counts, err := uc.prRepo.CountOpenByReviewer(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var saturated []string
+	for _, id := range ids {
+		if (entity.ReviewerLoad{OpenReviews: counts[id], MaxOpenReviews: capped[id]}).AtCapacity() {
+			saturated = append(saturated, id)
+		}
+	}
+
+	return saturated, nil
+}
+
+// RefreshWorkload recomputes the per-user review counts used for assignment.
+func (uc *PRUseCase) RefreshWorkload(ctx context.Context) (time.Time, error) {
+	return uc.workload.Refresh(ctx)
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS uq_pull_requests_repo_number;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS external_number;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository;
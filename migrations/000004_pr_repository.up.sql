@@ -0,0 +1,7 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS repository TEXT;
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS external_number INTEGER;
+
+-- Two repositories can both have PR #1024; uniqueness is per repository.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pull_requests_repo_number
+    ON pull_requests(repository, external_number)
+    WHERE repository IS NOT NULL AND external_number IS NOT NULL;
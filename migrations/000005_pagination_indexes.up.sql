@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_id ON pull_requests(created_at DESC, pull_request_id DESC);
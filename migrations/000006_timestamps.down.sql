@@ -0,0 +1,8 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE pull_requests ALTER COLUMN created_at DROP NOT NULL;
+
+ALTER TABLE teams DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE teams DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE users ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE pull_requests SET created_at = now() WHERE created_at IS NULL;
+ALTER TABLE pull_requests ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE pull_requests SET updated_at = COALESCE(merged_at, created_at);
+ALTER TABLE pull_requests ALTER COLUMN updated_at SET NOT NULL;
+ALTER TABLE pull_requests ALTER COLUMN updated_at SET DEFAULT now();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pull_requests_created;
+ALTER TABLE users DROP COLUMN IF EXISTS flagged_inactive_at;
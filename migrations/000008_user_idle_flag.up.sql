@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS flagged_inactive_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created ON pull_requests(created_at);
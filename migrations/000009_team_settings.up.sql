@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    max_open_prs_per_author INTEGER,
+    quota_mode TEXT,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
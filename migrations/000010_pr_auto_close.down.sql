@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pull_requests_close_warned;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS close_warned_at;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS closed_at;
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS close_warned_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_close_warned ON pull_requests(close_warned_at) WHERE close_warned_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS review_snoozes;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS approvals;
+DROP TABLE IF EXISTS user_identities;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, external_id),
+    UNIQUE (user_id, provider)
+);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS approvals JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE IF NOT EXISTS review_snoozes (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    snoozed_until TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (pull_request_id, user_id)
+);
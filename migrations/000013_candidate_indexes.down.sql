@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pull_requests_open_reviewers;
+DROP INDEX IF EXISTS idx_users_team_active;
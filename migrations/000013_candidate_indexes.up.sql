@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_users_team_active ON users(team_name, user_id) WHERE is_active;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_open_reviewers ON pull_requests USING GIN (assigned_reviewers) WHERE status = 'OPEN';
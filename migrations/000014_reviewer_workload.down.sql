@@ -0,0 +1 @@
+DROP MATERIALIZED VIEW IF EXISTS reviewer_workload;
@@ -0,0 +1,10 @@
+CREATE MATERIALIZED VIEW IF NOT EXISTS reviewer_workload AS
+SELECT u.user_id,
+       count(p.pull_request_id)::int AS open_reviews,
+       now() AS refreshed_at
+FROM users u
+LEFT JOIN pull_requests p ON p.status = 'OPEN' AND p.assigned_reviewers ? u.user_id
+GROUP BY u.user_id;
+
+-- Required by REFRESH MATERIALIZED VIEW CONCURRENTLY.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviewer_workload_user ON reviewer_workload(user_id);
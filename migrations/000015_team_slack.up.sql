@@ -0,0 +1 @@
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS slack_enabled BOOLEAN;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS seniority,
+    DROP COLUMN IF EXISTS title,
+    DROP COLUMN IF EXISTS role;
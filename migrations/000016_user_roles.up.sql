@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role      TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'lead')),
+    ADD COLUMN IF NOT EXISTS title     TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS seniority TEXT NOT NULL DEFAULT '' CHECK (seniority IN ('', 'junior', 'middle', 'senior'));
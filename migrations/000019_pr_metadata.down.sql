@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS size,
+    DROP COLUMN IF EXISTS linked_issues,
+    DROP COLUMN IF EXISTS labels;
@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS labels        JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS linked_issues JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS size          JSONB;
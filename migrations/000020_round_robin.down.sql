@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS team_rotation;
+ALTER TABLE team_settings DROP COLUMN IF EXISTS assignment_strategy;
@@ -0,0 +1,7 @@
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS assignment_strategy TEXT;
+
+CREATE TABLE IF NOT EXISTS team_rotation (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    last_user_id TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS oncall_rotations (
+    team_name TEXT PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    members TEXT[] NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS oncall_overrides (
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    week_start DATE NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    PRIMARY KEY (team_name, week_start)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS review_reminders;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,10 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'normal'
+        CHECK (priority IN ('low', 'normal', 'high', 'urgent'));
+
+CREATE TABLE IF NOT EXISTS review_reminders (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    reminded_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (pull_request_id, user_id)
+);
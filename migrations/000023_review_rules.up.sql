@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS review_rules (
+    id BIGSERIAL PRIMARY KEY,
+    repository TEXT NOT NULL,
+    pattern TEXT NOT NULL,
+    reviewers TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (repository, pattern)
+);
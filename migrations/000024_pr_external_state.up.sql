@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS external_state TEXT NOT NULL DEFAULT 'clean'
+        CHECK (external_state IN ('clean', 'conflicts', 'outdated'));
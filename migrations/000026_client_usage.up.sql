@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS client_usage (
+    client_id TEXT NOT NULL,
+    method TEXT NOT NULL,
+    route TEXT NOT NULL,
+    bucket TIMESTAMPTZ NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    latency_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_latency_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    PRIMARY KEY (client_id, method, route, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_usage_bucket ON client_usage (bucket);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS review_exclusions (
+    id BIGSERIAL PRIMARY KEY,
+    reviewers TEXT[] NOT NULL,
+    authors TEXT[] NOT NULL DEFAULT '{}',
+    labels TEXT[] NOT NULL DEFAULT '{}',
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (cardinality(authors) > 0 OR cardinality(labels) > 0)
+);
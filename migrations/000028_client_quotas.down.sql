@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS client_quota_usage;
+DROP TABLE IF EXISTS client_quotas;
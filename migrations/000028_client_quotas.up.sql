@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS client_quotas (
+    client_id TEXT PRIMARY KEY,
+    daily BIGINT NOT NULL DEFAULT 0 CHECK (daily >= 0),
+    monthly BIGINT NOT NULL DEFAULT 0 CHECK (monthly >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS client_quota_usage (
+    client_id TEXT NOT NULL,
+    period TEXT NOT NULL CHECK (period IN ('day', 'month')),
+    period_start TIMESTAMPTZ NOT NULL,
+    used BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (client_id, period, period_start)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE team_settings
+    DROP COLUMN IF EXISTS required_approvals,
+    DROP COLUMN IF EXISTS require_all_approvals;
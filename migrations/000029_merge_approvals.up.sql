@@ -0,0 +1,3 @@
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS required_approvals INT CHECK (required_approvals >= 0),
+    ADD COLUMN IF NOT EXISTS require_all_approvals BOOLEAN;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    provider TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    user_id TEXT,
+    team_name TEXT,
+    pull_request_id TEXT,
+    status TEXT NOT NULL CHECK (status IN ('sent', 'skipped', 'failed')),
+    detail TEXT NOT NULL DEFAULT '',
+    duration_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_user ON notification_deliveries (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_pr ON notification_deliveries (pull_request_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS team_delegations (
+    id BIGSERIAL PRIMARY KEY,
+    team_name TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    delegator_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    delegate_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL CHECK (ends_at > starts_at),
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_delegations_team ON team_delegations(team_name, ends_at);
+CREATE INDEX IF NOT EXISTS idx_team_delegations_delegate ON team_delegations(delegate_id, team_name) WHERE revoked_at IS NULL;
@@ -0,0 +1,24 @@
+-- Let a team rename propagate from teams to the tables keyed by it.
+ALTER TABLE team_settings DROP CONSTRAINT IF EXISTS team_settings_team_name_fkey;
+ALTER TABLE team_settings ADD CONSTRAINT team_settings_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE webhooks DROP CONSTRAINT IF EXISTS webhooks_team_name_fkey;
+ALTER TABLE webhooks ADD CONSTRAINT webhooks_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE team_rotation DROP CONSTRAINT IF EXISTS team_rotation_team_name_fkey;
+ALTER TABLE team_rotation ADD CONSTRAINT team_rotation_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE oncall_rotations DROP CONSTRAINT IF EXISTS oncall_rotations_team_name_fkey;
+ALTER TABLE oncall_rotations ADD CONSTRAINT oncall_rotations_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE oncall_overrides DROP CONSTRAINT IF EXISTS oncall_overrides_team_name_fkey;
+ALTER TABLE oncall_overrides ADD CONSTRAINT oncall_overrides_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE team_delegations DROP CONSTRAINT IF EXISTS team_delegations_team_name_fkey;
+ALTER TABLE team_delegations ADD CONSTRAINT team_delegations_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE ON UPDATE CASCADE;
@@ -0,0 +1,17 @@
+-- Daily per-team stats; rows outlive the PRs and teams they summarize.
+CREATE TABLE IF NOT EXISTS stats_history (
+    team_name TEXT NOT NULL,
+    snapshot_date DATE NOT NULL,
+    total_prs INTEGER NOT NULL,
+    open_prs INTEGER NOT NULL,
+    merged_prs INTEGER NOT NULL,
+    closed_prs INTEGER NOT NULL,
+    members INTEGER NOT NULL,
+    active_members INTEGER NOT NULL,
+    average_reviewers DOUBLE PRECISION NOT NULL,
+    average_review_turnaround_hours DOUBLE PRECISION NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (team_name, snapshot_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stats_history_date ON stats_history(snapshot_date);
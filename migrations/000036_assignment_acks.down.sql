@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS assignment_acks;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS require_ack;
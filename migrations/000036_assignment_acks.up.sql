@@ -0,0 +1,21 @@
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS require_ack BOOLEAN NOT NULL DEFAULT false;
+
+-- One row per reviewer.assigned delivery to a webhook that must acknowledge it.
+CREATE TABLE IF NOT EXISTS assignment_acks (
+    delivery_id TEXT PRIMARY KEY,
+    webhook_id BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    team_name TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    last_sent_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    acked_at TIMESTAMPTZ,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_acks_due ON assignment_acks(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_assignment_acks_status ON assignment_acks(status, created_at);
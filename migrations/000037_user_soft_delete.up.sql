@@ -0,0 +1,2 @@
+-- Deleted users keep their row so the PRs and reviews that reference them still add up.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
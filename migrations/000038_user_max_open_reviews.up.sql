@@ -0,0 +1,2 @@
+-- 0 means the user takes any number of reviews.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS max_open_reviews INTEGER NOT NULL DEFAULT 0 CHECK (max_open_reviews >= 0);
@@ -0,0 +1,14 @@
+-- External reviewer changes that disagreed with the service's assignment.
+CREATE TABLE IF NOT EXISTS reviewer_conflicts (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL,
+    change TEXT NOT NULL,
+    policy TEXT NOT NULL,
+    resolution TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_conflicts_pr ON reviewer_conflicts(pull_request_id, created_at);
@@ -0,0 +1,15 @@
+-- When each reviewer was first assigned to a PR, for per-user review stats.
+CREATE TABLE IF NOT EXISTS reviewer_assignments (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (pull_request_id, reviewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_assignments_reviewer ON reviewer_assignments(reviewer_id);
+
+-- Earlier assignments only have the PR's creation time to go by.
+INSERT INTO reviewer_assignments (pull_request_id, reviewer_id, assigned_at)
+SELECT p.pull_request_id, r.reviewer_id, p.created_at
+FROM pull_requests p, jsonb_array_elements_text(p.assigned_reviewers) AS r(reviewer_id)
+ON CONFLICT DO NOTHING;
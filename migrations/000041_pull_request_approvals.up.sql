@@ -0,0 +1,20 @@
+-- When each reviewer first approved a PR, for review turnaround stats.
+CREATE TABLE IF NOT EXISTS pull_request_approvals (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id TEXT NOT NULL,
+    approved_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (pull_request_id, reviewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_approvals_approved_at ON pull_request_approvals(approved_at);
+
+-- Current approvals take their first approving review's time, or the PR's
+-- last update when there is none.
+INSERT INTO pull_request_approvals (pull_request_id, reviewer_id, approved_at)
+SELECT p.pull_request_id, a.reviewer_id,
+       COALESCE((SELECT MIN(r.created_at) FROM reviews r
+                 WHERE r.pull_request_id = p.pull_request_id
+                   AND r.reviewer_id = a.reviewer_id
+                   AND r.decision = 'approved'), p.updated_at)
+FROM pull_requests p, jsonb_array_elements_text(p.approvals) AS a(reviewer_id)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pull_requests_status_created_id;
+DROP INDEX IF EXISTS idx_pull_requests_updated_id;
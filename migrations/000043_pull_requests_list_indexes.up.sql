@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_pull_requests_updated_id ON pull_requests(updated_at DESC, pull_request_id DESC);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created_id ON pull_requests(status, created_at DESC, pull_request_id DESC);
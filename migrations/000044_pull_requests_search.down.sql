@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pull_requests_name_trgm;
+DROP INDEX IF EXISTS idx_pull_requests_name_tsv;
@@ -0,0 +1,6 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- GET /pullRequest/search matches whole words through the tsvector index and
+-- substrings of three or more characters through the trigram one.
+CREATE INDEX IF NOT EXISTS idx_pull_requests_name_tsv ON pull_requests USING GIN (to_tsvector('simple', pull_request_name));
+CREATE INDEX IF NOT EXISTS idx_pull_requests_name_trgm ON pull_requests USING GIN (pull_request_name gin_trgm_ops);
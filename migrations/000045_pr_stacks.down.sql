@@ -0,0 +1,4 @@
+ALTER TABLE team_settings DROP COLUMN IF EXISTS stack_reviewers;
+
+DROP INDEX IF EXISTS idx_pull_requests_stack;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS stack_id;
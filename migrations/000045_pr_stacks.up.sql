@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS stack_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_stack ON pull_requests (stack_id) WHERE stack_id IS NOT NULL;
+
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS stack_reviewers TEXT;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_ordering_key;
+ALTER TABLE tasks DROP COLUMN IF EXISTS ordering_key;
@@ -0,0 +1,3 @@
+-- Tasks sharing an ordering key run one at a time, oldest first.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS ordering_key TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_tasks_ordering_key ON tasks(ordering_key, id) WHERE ordering_key <> '' AND status <> 'dead';
@@ -0,0 +1,2 @@
+-- Lets the users total_estimate subtract soft-deleted rows without a scan.
+CREATE INDEX IF NOT EXISTS idx_users_deleted ON users(user_id) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_reviewer_assignments_reviewer ON reviewer_assignments(reviewer_id);
+DROP INDEX IF EXISTS idx_reviewer_assignments_reviewer_at;
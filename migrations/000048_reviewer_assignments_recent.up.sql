@@ -0,0 +1,3 @@
+-- The assignment cooldown reads each candidate's assignments since a horizon.
+CREATE INDEX IF NOT EXISTS idx_reviewer_assignments_reviewer_at ON reviewer_assignments(reviewer_id, assigned_at);
+DROP INDEX IF EXISTS idx_reviewer_assignments_reviewer;
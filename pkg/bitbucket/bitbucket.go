@@ -0,0 +1,72 @@
+// Package bitbucket holds Bitbucket Cloud webhook payload types and signature checks.
+package bitbucket
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Webhook headers set by Bitbucket Cloud on every delivery.
+const (
+	HeaderEvent     = "X-Event-Key"
+	HeaderDelivery  = "X-Request-UUID"
+	HeaderSignature = "X-Hub-Signature"
+)
+
+// Pull request events handled by the service.
+const (
+	EventPullRequestCreated   = "pullrequest:created"
+	EventPullRequestFulfilled = "pullrequest:fulfilled"
+)
+
+// PullRequestEvent is the subset of pullrequest:* payloads the service uses.
+type PullRequestEvent struct {
+	PullRequest PullRequest `json:"pullrequest"`
+	Repository  Repository  `json:"repository"`
+}
+
+// PullRequest -.
+type PullRequest struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Author User   `json:"author"`
+	Draft  bool   `json:"draft"`
+}
+
+// Repository -.
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+// User -.
+type User struct {
+	AccountID   string `json:"account_id"`
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+	Links       struct {
+		Avatar struct {
+			Href string `json:"href"`
+		} `json:"avatar"`
+	} `json:"links"`
+}
+
+// VerifySignature reports whether header ("sha256=<hex>") is the HMAC-SHA256
+// of body keyed with the webhook secret.
+func VerifySignature(secret string, body []byte, header string) bool {
+	hexSum, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+
+	got, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,68 @@
+// Package buildinfo reports what binary is running. Commit and BuildTime are
+// set at link time:
+//
+//	go build -ldflags "-X github.com/evrone/go-clean-template/pkg/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/evrone/go-clean-template/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them the VCS stamp Go embeds in module builds is used, if any.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+var (
+	Commit    string
+	BuildTime string
+)
+
+// started approximates the process start time.
+var started = time.Now()
+
+// Info -.
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// Get returns the linked-in build info, falling back to the VCS stamp.
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+
+	return info
+}
+
+// StartedAt -.
+func StartedAt() time.Time {
+	return started
+}
+
+// Uptime -.
+func Uptime() time.Duration {
+	return time.Since(started)
+}
@@ -0,0 +1,310 @@
+// Package capture records verbose request and decision details for selected
+// teams or pull requests for a limited time, to debug individual complaints
+// without global debug logging.
+package capture
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	_defaultCapacity = 1000
+	_defaultDuration = 15 * time.Minute
+	_maxBodyBytes    = 8 << 10
+	_redacted        = "[redacted]"
+)
+
+// Entry kinds.
+const (
+	KindHTTP       = "http"
+	KindAssignment = "assignment"
+)
+
+// Session enables capture for a team, a pull request, or both, until ExpiresAt.
+type Session struct {
+	ID            int64     `json:"id"`
+	TeamName      string    `json:"team_name,omitempty"`
+	PullRequestID string    `json:"pull_request_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Active        bool      `json:"active"`
+}
+
+// Entry is a captured request or assignment decision.
+type Entry struct {
+	ID            int64       `json:"id"`
+	SessionID     int64       `json:"session_id"`
+	Kind          string      `json:"kind"`
+	TeamName      string      `json:"team_name,omitempty"`
+	PullRequestID string      `json:"pull_request_id,omitempty"`
+	Method        string      `json:"method,omitempty"`
+	Path          string      `json:"path,omitempty"`
+	Status        int         `json:"status,omitempty"`
+	LatencyMs     float64     `json:"latency_ms,omitempty"`
+	Request       string      `json:"request,omitempty"`
+	Response      string      `json:"response,omitempty"`
+	Decision      interface{} `json:"decision,omitempty"`
+	CapturedAt    time.Time   `json:"captured_at"`
+}
+
+// Recorder keeps capture sessions and the most recent entries in memory.
+type Recorder struct {
+	mu          sync.RWMutex
+	capacity    int
+	maxDuration time.Duration
+	nextID      int64
+	sessions    []Session
+	entries     []Entry
+	now         func() time.Time
+}
+
+// NewRecorder -. Sessions last at most maxDuration.
+func NewRecorder(capacity int, maxDuration time.Duration) *Recorder {
+	if capacity <= 0 {
+		capacity = _defaultCapacity
+	}
+
+	return &Recorder{capacity: capacity, maxDuration: maxDuration, now: time.Now}
+}
+
+// Start opens a session for teamName and/or prID lasting d, 15 minutes when
+// d is not positive, capped at the recorder's maximum.
+func (r *Recorder) Start(teamName, prID string, d time.Duration) Session {
+	if d <= 0 {
+		d = _defaultDuration
+	}
+
+	if r.maxDuration > 0 && d > r.maxDuration {
+		d = r.maxDuration
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now().UTC()
+
+	r.nextID++
+	s := Session{
+		ID:            r.nextID,
+		TeamName:      teamName,
+		PullRequestID: prID,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(d),
+		Active:        true,
+	}
+	r.sessions = append(r.sessions, s)
+
+	return s
+}
+
+// Stop expires a session early; it reports whether the session exists.
+func (r *Recorder) Stop(id int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.sessions {
+		if r.sessions[i].ID == id {
+			if now := r.now().UTC(); r.sessions[i].ExpiresAt.After(now) {
+				r.sessions[i].ExpiresAt = now
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sessions returns all sessions, newest first.
+func (r *Recorder) Sessions() []Session {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	out := make([]Session, 0, len(r.sessions))
+
+	for i := len(r.sessions) - 1; i >= 0; i-- {
+		s := r.sessions[i]
+		s.Active = s.ExpiresAt.After(now)
+		out = append(out, s)
+	}
+
+	return out
+}
+
+// Active reports whether any session is capturing; with teams set it only
+// counts sessions scoped to a team.
+func (r *Recorder) Active(teams bool) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+
+	for _, s := range r.sessions {
+		if s.ExpiresAt.After(now) && (!teams || s.TeamName != "") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match returns the active session covering teamName or prID. A session
+// scoped to both requires both to match.
+func (r *Recorder) Match(teamName, prID string) (int64, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+
+	for i := len(r.sessions) - 1; i >= 0; i-- {
+		s := r.sessions[i]
+		if !s.ExpiresAt.After(now) {
+			continue
+		}
+
+		if s.TeamName != "" && s.TeamName != teamName {
+			continue
+		}
+
+		if s.PullRequestID != "" && s.PullRequestID != prID {
+			continue
+		}
+
+		return s.ID, true
+	}
+
+	return 0, false
+}
+
+// Prune drops entries captured more than retention ago and sessions that
+// expired more than retention ago.
+func (r *Recorder) Prune(retention time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := r.now().Add(-retention)
+
+	sessions := r.sessions[:0]
+	for _, s := range r.sessions {
+		if s.ExpiresAt.After(cutoff) {
+			sessions = append(sessions, s)
+		}
+	}
+	clear(r.sessions[len(sessions):])
+	r.sessions = sessions
+
+	entries := r.entries[:0]
+	for _, e := range r.entries {
+		if e.CapturedAt.After(cutoff) {
+			entries = append(entries, e)
+		}
+	}
+	clear(r.entries[len(entries):])
+	r.entries = entries
+}
+
+// Record stores an entry, evicting the oldest one when the buffer is full.
+func (r *Recorder) Record(e Entry) Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	e.ID = r.nextID
+
+	if e.CapturedAt.IsZero() {
+		e.CapturedAt = r.now().UTC()
+	}
+
+	if len(r.entries) == r.capacity {
+		r.entries = r.entries[1:]
+	}
+
+	r.entries = append(r.entries, e)
+
+	return e
+}
+
+// CaptureDecision records an assignment decision when a session covers it.
+func (r *Recorder) CaptureDecision(teamName, prID string, decision interface{}) {
+	id, ok := r.Match(teamName, prID)
+	if !ok {
+		return
+	}
+
+	r.Record(Entry{
+		SessionID:     id,
+		Kind:          KindAssignment,
+		TeamName:      teamName,
+		PullRequestID: prID,
+		Decision:      decision,
+	})
+}
+
+// List returns entries, newest first, optionally limited to one session.
+func (r *Recorder) List(sessionID int64) []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Entry, 0, len(r.entries))
+
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if sessionID != 0 && r.entries[i].SessionID != sessionID {
+			continue
+		}
+
+		out = append(out, r.entries[i])
+	}
+
+	return out
+}
+
+// Sanitize redacts credential and contact fields from a JSON body and caps
+// its size. Bodies that are not JSON are only truncated.
+func Sanitize(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err == nil {
+		if b, err := json.Marshal(redact(v)); err == nil {
+			body = b
+		}
+	}
+
+	if len(body) > _maxBodyBytes {
+		return string(body[:_maxBodyBytes]) + "...[truncated]"
+	}
+
+	return string(body)
+}
+
+func redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if isSensitiveKey(k) {
+				t[k] = _redacted
+			} else {
+				t[k] = redact(val)
+			}
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+	}
+
+	return v
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"token", "secret", "password", "signature", "email"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+// Package chaos injects configurable latency and errors for resilience testing.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrInjected is returned by Inject when a fault is triggered.
+var ErrInjected = errors.New("chaos: injected fault")
+
+// Rule -.
+type Rule struct {
+	LatencyMS int     `json:"latency_ms"`
+	ErrorRate float64 `json:"error_rate"`
+}
+
+// Injector holds fault rules per target (e.g. "repo", "notifier").
+type Injector struct {
+	targets []string
+
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// New returns an injector for the given targets, the names its callers
+// inject under.
+func New(targets ...string) *Injector {
+	return &Injector{targets: targets, rules: make(map[string]Rule)}
+}
+
+// Targets returns the names rules may be set for.
+func (i *Injector) Targets() []string {
+	return slices.Clone(i.targets)
+}
+
+// Known reports whether target is one of the injector's targets.
+func (i *Injector) Known(target string) bool {
+	return slices.Contains(i.targets, target)
+}
+
+// Set replaces the rule for target; a zero rule removes it.
+func (i *Injector) Set(target string, r Rule) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if r == (Rule{}) {
+		delete(i.rules, target)
+
+		return
+	}
+
+	i.rules[target] = r
+}
+
+// Rules returns a copy of the active rules.
+func (i *Injector) Rules() map[string]Rule {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	out := make(map[string]Rule, len(i.rules))
+	for k, v := range i.rules {
+		out[k] = v
+	}
+
+	return out
+}
+
+// Reset removes all rules.
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.rules = make(map[string]Rule)
+}
+
+// Inject applies the rule for target: it waits for the configured latency and
+// then fails with ErrInjected with the configured probability.
+func (i *Injector) Inject(ctx context.Context, target string) error {
+	i.mu.RLock()
+	r, ok := i.rules[target]
+	i.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	if r.LatencyMS > 0 {
+		timer := time.NewTimer(time.Duration(r.LatencyMS) * time.Millisecond)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if r.ErrorRate > 0 && rand.Float64() < r.ErrorRate { //nolint:gosec // fault sampling doesn't need crypto rand
+		return ErrInjected
+	}
+
+	return nil
+}
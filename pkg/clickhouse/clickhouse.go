@@ -0,0 +1,123 @@
+// Package clickhouse is a minimal client for the ClickHouse HTTP interface.
+package clickhouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client -.
+type Client struct {
+	baseURL  string
+	database string
+	user     string
+	password string
+	http     *http.Client
+}
+
+// New -. baseURL points at the HTTP port, e.g. http://clickhouse:8123.
+func New(baseURL, database, user, password string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		database: database,
+		user:     user,
+		password: password,
+		http:     httpClient,
+	}
+}
+
+// Exec runs a statement that returns no rows (DDL, ALTER, ...).
+func (c *Client) Exec(ctx context.Context, query string) error {
+	_, err := c.do(ctx, query, nil, nil)
+	return err
+}
+
+// InsertJSONEachRow inserts rows, each marshalled as one JSON object.
+func (c *Client) InsertJSONEachRow(ctx context.Context, table string, rows []any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+
+	enc := json.NewEncoder(&body)
+	for _, r := range rows {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.do(ctx, "INSERT INTO "+table+" FORMAT JSONEachRow", nil, &body)
+	return err
+}
+
+// Select runs query with server-side parameters ({name:Type} placeholders)
+// and decodes the result rows into dest, a pointer to a slice of structs.
+func (c *Client) Select(ctx context.Context, query string, params map[string]string, dest any) error {
+	out, err := c.do(ctx, query+" FORMAT JSON", params, nil)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return fmt.Errorf("clickhouse - decode result: %w", err)
+	}
+
+	return json.Unmarshal(result.Data, dest)
+}
+
+func (c *Client) do(ctx context.Context, query string, params map[string]string, body io.Reader) ([]byte, error) {
+	q := url.Values{}
+	q.Set("database", c.database)
+	// 64-bit integers come back as JSON numbers rather than strings.
+	q.Set("output_format_json_quote_64bit_integers", "0")
+
+	for k, v := range params {
+		q.Set("param_"+k, v)
+	}
+
+	if body == nil {
+		body = strings.NewReader(query)
+	} else {
+		q.Set("query", query)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/?"+q.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse - request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse - read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("clickhouse - %s: %s", resp.Status, strings.TrimSpace(string(out)))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,119 @@
+// Package eventbus implements an in-process publish/subscribe bus with typed
+// topics. Publishing never blocks: every subscriber has its own buffer, and an
+// event is dropped for a subscriber whose buffer is full.
+package eventbus
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	_published = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_eventbus_published_total",
+		Help: "Events published on the in-process bus.",
+	}, []string{"topic"})
+	_dropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_eventbus_dropped_total",
+		Help: "Events a subscriber missed because its buffer was full.",
+	}, []string{"topic", "subscriber"})
+)
+
+// Topic fans events of type T out to its subscribers. It is safe for
+// concurrent use.
+type Topic[T any] struct {
+	name string
+
+	mu     sync.RWMutex
+	subs   map[*Subscription[T]]struct{}
+	closed bool
+}
+
+// NewTopic -. name labels the topic's metrics.
+func NewTopic[T any](name string) *Topic[T] {
+	return &Topic[T]{name: name, subs: make(map[*Subscription[T]]struct{})}
+}
+
+// Subscription receives a topic's events on C until it is cancelled or the
+// topic is closed, which closes C.
+type Subscription[T any] struct {
+	C <-chan T
+
+	ch      chan T
+	topic   *Topic[T]
+	dropped prometheus.Counter
+}
+
+// Subscribe registers a subscriber buffering up to buffer events; name labels
+// its metrics. Subscribing to a closed topic returns an already closed
+// subscription.
+func (t *Topic[T]) Subscribe(name string, buffer int) *Subscription[T] {
+	ch := make(chan T, buffer)
+	s := &Subscription[T]{C: ch, ch: ch, topic: t, dropped: _dropped.WithLabelValues(t.name, name)}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		close(ch)
+		return s
+	}
+
+	t.subs[s] = struct{}{}
+
+	return s
+}
+
+// Publish hands e to every subscriber with room for it.
+func (t *Topic[T]) Publish(e T) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.closed {
+		return
+	}
+
+	_published.WithLabelValues(t.name).Inc()
+
+	for s := range t.subs {
+		select {
+		case s.ch <- e:
+		default:
+			s.dropped.Inc()
+		}
+	}
+}
+
+// Close stops the topic and closes every subscription. Later publishes are
+// ignored.
+func (t *Topic[T]) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return
+	}
+
+	t.closed = true
+	for s := range t.subs {
+		close(s.ch)
+	}
+	t.subs = nil
+}
+
+// Cancel unsubscribes s and closes C. Events still buffered can be drained.
+func (s *Subscription[T]) Cancel() {
+	t := s.topic
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.subs[s]; !ok {
+		return
+	}
+
+	delete(t.subs, s)
+	close(s.ch)
+}
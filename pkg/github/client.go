@@ -0,0 +1,89 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const _defaultAPIURL = "https://api.github.com"
+
+// Client is a minimal GitHub REST API client authenticated with a token.
+type Client struct {
+	token   string
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient -. An empty apiURL selects api.github.com.
+func NewClient(token, apiURL string, httpClient *http.Client) *Client {
+	if apiURL == "" {
+		apiURL = _defaultAPIURL
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		token:   token,
+		baseURL: strings.TrimRight(apiURL, "/"),
+		http:    httpClient,
+	}
+}
+
+// RequestReviewers asks logins to review pull request number of repo
+// ("owner/name").
+func (c *Client) RequestReviewers(ctx context.Context, repo string, number int, logins []string) error {
+	return c.call(ctx, http.MethodPost, requestedReviewersPath(repo, number), map[string]any{"reviewers": logins})
+}
+
+// RemoveReviewers withdraws the review requests of logins on pull request
+// number of repo.
+func (c *Client) RemoveReviewers(ctx context.Context, repo string, number int, logins []string) error {
+	return c.call(ctx, http.MethodDelete, requestedReviewersPath(repo, number), map[string]any{"reviewers": logins})
+}
+
+func requestedReviewersPath(repo string, number int) string {
+	return fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", repo, number)
+}
+
+func (c *Client) call(ctx context.Context, method, path string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("github - %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var result struct {
+		Message string `json:"message"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(raw, &result) != nil || result.Message == "" {
+		result.Message = strings.TrimSpace(string(raw))
+	}
+
+	return fmt.Errorf("github - %s %s: %d %s", method, path, resp.StatusCode, result.Message)
+}
@@ -0,0 +1,86 @@
+// Package github holds GitHub webhook payload types, signature checks and a
+// minimal REST client.
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Webhook headers set by GitHub on every delivery.
+const (
+	HeaderEvent     = "X-GitHub-Event"
+	HeaderDelivery  = "X-GitHub-Delivery"
+	HeaderSignature = "X-Hub-Signature-256"
+)
+
+// EventPullRequest is the X-GitHub-Event value for pull request activity.
+const EventPullRequest = "pull_request"
+
+// Pull request actions handled by the service.
+const (
+	ActionOpened         = "opened"
+	ActionReopened       = "reopened"
+	ActionClosed         = "closed"
+	ActionReadyForReview = "ready_for_review"
+	// Reviewer changes carry the reviewer in RequestedReviewer; requests for
+	// a whole team carry none.
+	ActionReviewRequested      = "review_requested"
+	ActionReviewRequestRemoved = "review_request_removed"
+)
+
+// PullRequestEvent is the subset of the pull_request payload the service uses.
+type PullRequestEvent struct {
+	Action            string      `json:"action"`
+	Number            int         `json:"number"`
+	PullRequest       PullRequest `json:"pull_request"`
+	Repository        Repository  `json:"repository"`
+	RequestedReviewer *User       `json:"requested_reviewer"`
+	Sender            User        `json:"sender"`
+}
+
+// PullRequest -.
+type PullRequest struct {
+	Title  string  `json:"title"`
+	Merged bool    `json:"merged"`
+	Draft  bool    `json:"draft"`
+	User   User    `json:"user"`
+	Labels []Label `json:"labels"`
+}
+
+// Label -.
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Repository -.
+type Repository struct {
+	FullName string `json:"full_name"`
+}
+
+// User -.
+type User struct {
+	Login     string `json:"login"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+// VerifySignature reports whether header ("sha256=<hex>") is the HMAC-SHA256
+// of body keyed with secret.
+func VerifySignature(secret string, body []byte, header string) bool {
+	hexSum, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+
+	got, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,89 @@
+// Package gitlab is a minimal GitLab REST API client.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const _defaultAPIURL = "https://gitlab.com/api/v4"
+
+// HeaderToken carries the personal, project or group access token.
+const HeaderToken = "PRIVATE-TOKEN"
+
+// Client -.
+type Client struct {
+	token   string
+	baseURL string
+	http    *http.Client
+}
+
+// New -. An empty apiURL selects gitlab.com.
+func New(token, apiURL string, httpClient *http.Client) *Client {
+	if apiURL == "" {
+		apiURL = _defaultAPIURL
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		token:   token,
+		baseURL: strings.TrimRight(apiURL, "/"),
+		http:    httpClient,
+	}
+}
+
+// SetReviewers replaces the reviewers of merge request iid in project (its
+// path, "group/name") with the users reviewerIDs.
+func (c *Client) SetReviewers(ctx context.Context, project string, iid int, reviewerIDs []int64) error {
+	if reviewerIDs == nil {
+		reviewerIDs = []int64{}
+	}
+
+	path := fmt.Sprintf("/projects/%s/merge_requests/%d", url.PathEscape(project), iid)
+
+	return c.call(ctx, http.MethodPut, path, map[string]any{"reviewer_ids": reviewerIDs})
+}
+
+func (c *Client) call(ctx context.Context, method, path string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderToken, c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("gitlab - %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var result struct {
+		Message any `json:"message"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if json.Unmarshal(raw, &result) != nil || result.Message == nil {
+		return fmt.Errorf("gitlab - %s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	return fmt.Errorf("gitlab - %s %s: %d %v", method, path, resp.StatusCode, result.Message)
+}
@@ -0,0 +1,55 @@
+package gitlab
+
+import "crypto/subtle"
+
+// Webhook headers set by GitLab on every delivery.
+const (
+	HeaderEvent    = "X-Gitlab-Event"
+	HeaderDelivery = "X-Gitlab-Event-UUID"
+	HeaderSecret   = "X-Gitlab-Token"
+)
+
+// EventMergeRequest is the X-Gitlab-Event value for merge request activity.
+const EventMergeRequest = "Merge Request Hook"
+
+// MergeRequestEvent is the subset of the merge request payload the service
+// uses. Changes.Reviewers is only set when the reviewer list changed.
+type MergeRequestEvent struct {
+	User             User             `json:"user"`
+	Project          Project          `json:"project"`
+	ObjectAttributes ObjectAttributes `json:"object_attributes"`
+	Changes          Changes          `json:"changes"`
+}
+
+// Project -.
+type Project struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+// ObjectAttributes -.
+type ObjectAttributes struct {
+	IID    int    `json:"iid"`
+	Action string `json:"action"`
+}
+
+// Changes -.
+type Changes struct {
+	Reviewers *UsersChange `json:"reviewers"`
+}
+
+// UsersChange is a user list before and after an update.
+type UsersChange struct {
+	Previous []User `json:"previous"`
+	Current  []User `json:"current"`
+}
+
+// User -.
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// VerifySecret reports whether header is the webhook's secret token.
+func VerifySecret(secret, header string) bool {
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(header)) == 1
+}
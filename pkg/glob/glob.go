@@ -0,0 +1,78 @@
+// Package glob matches file paths against CODEOWNERS-style patterns.
+//
+// A pattern containing no slash other than a trailing one matches at any
+// depth; otherwise it is anchored at the repository root. A trailing slash
+// matches everything below a directory. "*" and "?" stay within one path
+// segment and "**" crosses segments.
+package glob
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrEmpty = errors.New("glob: empty pattern")
+
+// Pattern is a compiled glob.
+type Pattern struct {
+	re *regexp.Regexp
+}
+
+// Compile parses pattern.
+func Compile(pattern string) (*Pattern, error) {
+	p := strings.TrimSpace(pattern)
+	if p == "" || p == "/" {
+		return nil, ErrEmpty
+	}
+
+	dir := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+
+	var b strings.Builder
+	if strings.Contains(p, "/") {
+		b.WriteString("^")
+		p = strings.TrimPrefix(p, "/")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '*' && i+1 < len(p) && p[i+1] == '*':
+			i++
+			if i+1 < len(p) && p[i+1] == '/' {
+				// "**/" also matches no directory at all.
+				i++
+				b.WriteString("(?:.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if dir {
+		b.WriteString("/.*$")
+	} else {
+		// A pattern naming a directory also covers its contents.
+		b.WriteString("(?:/.*)?$")
+	}
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pattern{re: re}, nil
+}
+
+// Match reports whether path, relative to the repository root, matches.
+func (p *Pattern) Match(path string) bool {
+	return p.re.MatchString(strings.TrimPrefix(path, "/"))
+}
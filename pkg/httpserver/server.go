@@ -89,6 +89,13 @@ func (s *Server) Start() {
 	s.logger.Info("http server - Server - Started")
 }
 
+// Primary reports whether this process should run process-wide singletons
+// such as background jobs: always without prefork, and only in the parent
+// process with it, since every prefork child runs the same startup code.
+func (s *Server) Primary() bool {
+	return !s.prefork || !fiber.IsChild()
+}
+
 // Notify -.
 func (s *Server) Notify() <-chan error {
 	return s.notify
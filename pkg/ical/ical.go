@@ -0,0 +1,99 @@
+// Package ical renders minimal RFC 5545 calendars.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+const (
+	_timeFormat = "20060102T150405Z"
+	_lineLimit  = 75
+)
+
+// Event -.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+}
+
+// Calendar -.
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Encode renders the calendar with CRLF line endings and folded long lines.
+func (c Calendar) Encode() []byte {
+	var b bytes.Buffer
+
+	line(&b, "BEGIN:VCALENDAR")
+	line(&b, "VERSION:2.0")
+	line(&b, "PRODID:"+escape(c.ProdID))
+	line(&b, "CALSCALE:GREGORIAN")
+	line(&b, "METHOD:PUBLISH")
+
+	if c.Name != "" {
+		line(&b, "X-WR-CALNAME:"+escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		line(&b, "BEGIN:VEVENT")
+		line(&b, "UID:"+escape(e.UID))
+		line(&b, "DTSTAMP:"+e.Stamp.UTC().Format(_timeFormat))
+		line(&b, "DTSTART:"+e.Start.UTC().Format(_timeFormat))
+		line(&b, "DTEND:"+e.End.UTC().Format(_timeFormat))
+		line(&b, "SUMMARY:"+escape(e.Summary))
+
+		if e.Description != "" {
+			line(&b, "DESCRIPTION:"+escape(e.Description))
+		}
+
+		if e.URL != "" {
+			line(&b, "URL:"+e.URL)
+		}
+
+		line(&b, "END:VEVENT")
+	}
+
+	line(&b, "END:VCALENDAR")
+
+	return b.Bytes()
+}
+
+var _escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string {
+	return _escaper.Replace(s)
+}
+
+// line writes s folded at 75 octets without splitting UTF-8 sequences.
+func line(b *bytes.Buffer, s string) {
+	limit := _lineLimit
+
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8Start(s[cut]) {
+			cut--
+		}
+
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = _lineLimit - 1
+	}
+
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+func utf8Start(c byte) bool {
+	return c&0xC0 != 0x80
+}
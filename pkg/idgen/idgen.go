@@ -0,0 +1,74 @@
+// Package idgen generates sortable unique identifiers.
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	KindUUIDv7 = "uuidv7"
+	KindULID   = "ulid"
+)
+
+// Generator -.
+type Generator interface {
+	New() string
+}
+
+// New returns the generator for kind ("uuidv7" or "ulid").
+func New(kind string) (Generator, error) {
+	switch strings.ToLower(kind) {
+	case KindUUIDv7, "":
+		return UUIDv7{}, nil
+	case KindULID:
+		return ULID{}, nil
+	default:
+		return nil, fmt.Errorf("idgen - New: unknown kind %q", kind)
+	}
+}
+
+// UUIDv7 -.
+type UUIDv7 struct{}
+
+// New -.
+func (UUIDv7) New() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// ULID generates 26-char Crockford base32 ULIDs (48-bit ms timestamp + 80 random bits).
+type ULID struct{}
+
+const _crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// New -.
+func (ULID) New() string {
+	var b [16]byte
+
+	ms := uint64(time.Now().UnixMilli()) //nolint:gosec // unix millis are positive
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+
+	if _, err := rand.Read(b[6:]); err != nil {
+		panic(fmt.Sprintf("idgen - ULID: crypto/rand: %v", err))
+	}
+
+	// 128 bits -> 26 base32 chars; the first char only carries 3 bits.
+	var out [26]byte
+
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+
+	for i := 25; i >= 0; i-- {
+		out[i] = _crockford[lo&0x1f]
+		lo = (lo >> 5) | (hi << 59)
+		hi >>= 5
+	}
+
+	return string(out[:])
+}
@@ -1,13 +1,18 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/rs/zerolog"
 )
 
+// ErrInvalidLevel is returned by Levels.Set for an unknown level name.
+var ErrInvalidLevel = errors.New("invalid log level")
+
 // Interface -.
 type Interface interface {
 	Debug(message interface{}, args ...interface{})
@@ -20,83 +25,172 @@ type Interface interface {
 // Logger -.
 type Logger struct {
 	logger *zerolog.Logger
+	levels *Levels
+	module string
 }
 
 var _ Interface = (*Logger)(nil)
 
+// Levels holds the global level and per-module overrides shared by a Logger
+// and all of its module loggers; it may be changed at runtime.
+type Levels struct {
+	mu      sync.RWMutex
+	global  zerolog.Level
+	modules map[string]zerolog.Level
+}
+
 // New -.
 func New(level string) *Logger {
-	var l zerolog.Level
-
-	switch strings.ToLower(level) {
-	case "error":
-		l = zerolog.ErrorLevel
-	case "warn":
-		l = zerolog.WarnLevel
-	case "info":
-		l = zerolog.InfoLevel
-	case "debug":
-		l = zerolog.DebugLevel
-	default:
+	l, ok := parseLevel(level)
+	if !ok {
 		l = zerolog.InfoLevel
 	}
 
-	zerolog.SetGlobalLevel(l)
+	// Filtering is done by Levels so that it can change at runtime.
+	zerolog.SetGlobalLevel(zerolog.DebugLevel)
 
 	skipFrameCount := 3
 	logger := zerolog.New(os.Stdout).With().Timestamp().CallerWithSkipFrameCount(zerolog.CallerSkipFrameCount + skipFrameCount).Logger()
 
 	return &Logger{
 		logger: &logger,
+		levels: &Levels{global: l, modules: map[string]zerolog.Level{}},
+	}
+}
+
+// Module returns a logger tagged with the module name whose level can be
+// overridden separately via Levels.
+func (l *Logger) Module(name string) *Logger {
+	logger := l.logger.With().Str("module", name).Logger()
+
+	return &Logger{
+		logger: &logger,
+		levels: l.levels,
+		module: name,
 	}
 }
 
+// Levels returns the level registry shared by l and its module loggers.
+func (l *Logger) Levels() *Levels {
+	return l.levels
+}
+
 // Debug -.
 func (l *Logger) Debug(message interface{}, args ...interface{}) {
-	l.msg("debug", message, args...)
+	l.msg(zerolog.DebugLevel, message, args...)
 }
 
 // Info -.
 func (l *Logger) Info(message string, args ...interface{}) {
-	l.log(message, args...)
+	l.log(zerolog.InfoLevel, message, args...)
 }
 
 // Warn -.
 func (l *Logger) Warn(message string, args ...interface{}) {
-	l.log(message, args...)
+	l.log(zerolog.WarnLevel, message, args...)
 }
 
 // Error -.
 func (l *Logger) Error(message interface{}, args ...interface{}) {
-	if l.logger.GetLevel() == zerolog.DebugLevel {
+	if l.levels.level(l.module) == zerolog.DebugLevel {
 		l.Debug(message, args...)
 	}
 
-	l.msg("error", message, args...)
+	l.msg(zerolog.ErrorLevel, message, args...)
 }
 
 // Fatal -.
 func (l *Logger) Fatal(message interface{}, args ...interface{}) {
-	l.msg("fatal", message, args...)
+	l.msg(zerolog.FatalLevel, message, args...)
 
 	os.Exit(1)
 }
 
-func (l *Logger) log(message string, args ...interface{}) {
+func (l *Logger) log(level zerolog.Level, message string, args ...interface{}) {
+	if level < l.levels.level(l.module) {
+		return
+	}
+
 	if len(args) == 0 {
-		l.logger.Info().Msg(message)
+		l.logger.WithLevel(level).Msg(message)
 	} else {
-		l.logger.Info().Msgf(message, args...)
+		l.logger.WithLevel(level).Msgf(message, args...)
 	}
 }
 
-func (l *Logger) msg(level string, message interface{}, args ...interface{}) {
+func (l *Logger) msg(level zerolog.Level, message interface{}, args ...interface{}) {
 	switch msg := message.(type) {
 	case error:
-		l.log(msg.Error(), args...)
+		l.log(level, msg.Error(), args...)
 	case string:
-		l.log(msg, args...)
+		l.log(level, msg, args...)
+	default:
+		l.log(level, fmt.Sprintf("%s message %v has unknown type %v", level, message, msg), args...)
+	}
+}
+
+// Set changes the level of module, or the global level when module is empty.
+func (lv *Levels) Set(module, level string) error {
+	l, ok := parseLevel(level)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidLevel, level)
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	if module == "" {
+		lv.global = l
+	} else {
+		lv.modules[module] = l
+	}
+
+	return nil
+}
+
+// Reset drops the override of module so that it follows the global level again.
+func (lv *Levels) Reset(module string) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	delete(lv.modules, module)
+}
+
+// Snapshot returns the global level and the module overrides by name.
+func (lv *Levels) Snapshot() (string, map[string]string) {
+	lv.mu.RLock()
+	defer lv.mu.RUnlock()
+
+	modules := make(map[string]string, len(lv.modules))
+	for name, l := range lv.modules {
+		modules[name] = l.String()
+	}
+
+	return lv.global.String(), modules
+}
+
+func (lv *Levels) level(module string) zerolog.Level {
+	lv.mu.RLock()
+	defer lv.mu.RUnlock()
+
+	if l, ok := lv.modules[module]; ok && module != "" {
+		return l
+	}
+
+	return lv.global
+}
+
+func parseLevel(level string) (zerolog.Level, bool) {
+	switch strings.ToLower(level) {
+	case "error":
+		return zerolog.ErrorLevel, true
+	case "warn":
+		return zerolog.WarnLevel, true
+	case "info":
+		return zerolog.InfoLevel, true
+	case "debug":
+		return zerolog.DebugLevel, true
 	default:
-		l.log(fmt.Sprintf("%s message %v has unknown type %v", level, message, msg), args...)
+		return zerolog.NoLevel, false
 	}
 }
@@ -0,0 +1,85 @@
+// Package mail is a minimal SMTP client for plain-text messages.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evrone/go-clean-template/pkg/sandbox"
+)
+
+// sendFunc has the signature of smtp.SendMail.
+type sendFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+// Client -.
+type Client struct {
+	addr string
+	auth smtp.Auth
+	from string
+	send sendFunc
+}
+
+// New -. PLAIN auth is used when username is set; net/smtp only sends it
+// over TLS (STARTTLS) or to localhost.
+func New(host string, port int, username, password, from string) *Client {
+	c := &Client{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: from,
+		send: smtp.SendMail,
+	}
+
+	if username != "" {
+		c.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return c
+}
+
+// NewSandboxed returns a client that records messages in rec instead of
+// delivering them.
+func NewSandboxed(from string, rec *sandbox.Recorder) *Client {
+	return &Client{
+		from: from,
+		send: func(_ string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+			rec.Record(sandbox.Message{
+				Provider: "email",
+				Method:   "SMTP",
+				Target:   strings.Join(to, ","),
+				Body:     string(msg),
+			})
+
+			return nil
+		},
+	}
+}
+
+// Send delivers a plain-text message. smtp.SendMail does not take a context,
+// so ctx is only checked before dialing.
+func (c *Client) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var msg strings.Builder
+
+	fmt.Fprintf(&msg, "From: %s\r\n", c.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	if err := c.send(c.addr, c.auth, c.from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("mail - send to %s: %w", to, err)
+	}
+
+	return nil
+}
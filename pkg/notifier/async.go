@@ -0,0 +1,99 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// _asyncSendTimeout bounds a single queued delivery.
+const _asyncSendTimeout = 30 * time.Second
+
+var _asyncDroppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "pr_service_notifications_dropped_total",
+	Help: "Notifications dropped because the async send queue was full or stopped.",
+}, []string{"kind"})
+
+// Async queues messages for next and delivers them from background workers,
+// so a slow provider never adds latency to the caller. Messages are dropped
+// when the queue is full.
+type Async struct {
+	next    Notifier
+	queue   chan Message
+	workers int
+	l       logger.Interface
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewAsync -.
+func NewAsync(next Notifier, queueSize, workers int, l logger.Interface) *Async {
+	return &Async{
+		next:    next,
+		queue:   make(chan Message, queueSize),
+		workers: max(1, workers),
+		l:       l,
+	}
+}
+
+// Start launches the workers.
+func (a *Async) Start() {
+	for range a.workers {
+		a.wg.Add(1)
+
+		go func() {
+			defer a.wg.Done()
+
+			for m := range a.queue {
+				a.deliver(m)
+			}
+		}()
+	}
+}
+
+// Stop delivers what is already queued and waits for the workers to exit.
+// Messages passed to Notify after Stop are dropped.
+func (a *Async) Stop() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+
+	a.wg.Wait()
+}
+
+// Notify -. The caller's context is not carried over: delivery outlives the request.
+func (a *Async) Notify(_ context.Context, m Message) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		_asyncDroppedTotal.WithLabelValues(m.Kind).Inc()
+		return nil
+	}
+
+	select {
+	case a.queue <- m:
+	default:
+		_asyncDroppedTotal.WithLabelValues(m.Kind).Inc()
+	}
+
+	return nil
+}
+
+func (a *Async) deliver(m Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), _asyncSendTimeout)
+	defer cancel()
+
+	if err := a.next.Notify(ctx, m); err != nil {
+		a.l.Warn("notifier - async - %s to %s: %v", m.Kind, m.UserID, err)
+	}
+}
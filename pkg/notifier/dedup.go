@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var _suppressedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "pr_service_notifications_suppressed_total",
+	Help: "Notifications dropped as duplicates within the dedup window.",
+}, []string{"kind"})
+
+// Dedup drops messages about a PR that reach the same recipient within
+// window of the previous one, e.g. a reassign immediately followed by a merge.
+// Messages without a PullRequestID are always delivered.
+type Dedup struct {
+	next   Notifier
+	window time.Duration
+	now    func() time.Time
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+	// sent holds the keys in the order they were stored, so the oldest
+	// expire first.
+	sent []sentKey
+}
+
+type sentKey struct {
+	key string
+	at  time.Time
+}
+
+// NewDedup -.
+func NewDedup(next Notifier, window time.Duration) *Dedup {
+	return &Dedup{
+		next:     next,
+		window:   window,
+		now:      time.Now,
+		lastSent: make(map[string]time.Time),
+	}
+}
+
+// Notify -.
+func (d *Dedup) Notify(ctx context.Context, m Message) error {
+	if m.PullRequestID == "" {
+		return d.next.Notify(ctx, m)
+	}
+
+	recipient := m.UserID
+	if recipient == "" {
+		recipient = "team:" + m.TeamName
+	}
+
+	key := m.PullRequestID + "|" + recipient
+	now := d.now()
+
+	d.mu.Lock()
+	last, seen := d.lastSent[key]
+	if seen && now.Sub(last) < d.window {
+		d.mu.Unlock()
+		_suppressedTotal.WithLabelValues(m.Kind).Inc()
+
+		return nil
+	}
+
+	d.lastSent[key] = now
+	d.sent = append(d.sent, sentKey{key: key, at: now})
+	d.prune(now)
+	d.mu.Unlock()
+
+	return d.next.Notify(ctx, m)
+}
+
+// prune forgets keys older than the window, stopping at the first one that
+// is still in it; callers hold d.mu.
+func (d *Dedup) prune(now time.Time) {
+	for len(d.sent) > 0 && now.Sub(d.sent[0].at) >= d.window {
+		e := d.sent[0]
+		d.sent = d.sent[1:]
+		// A key sent again since has a newer entry further on.
+		if d.lastSent[e.key].Equal(e.at) {
+			delete(d.lastSent, e.key)
+		}
+	}
+}
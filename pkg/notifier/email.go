@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	"github.com/evrone/go-clean-template/pkg/mail"
+)
+
+// emailTemplate renders the subject and body of one message kind from a Message.
+type emailTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func newEmailTemplate(subject, body string) emailTemplate {
+	return emailTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// _emailTemplates is keyed by Message.Kind; other kinds use _defaultEmailTemplate.
+var _emailTemplates = map[string]emailTemplate{
+	"review_assigned": newEmailTemplate(
+		"Review requested: {{.PullRequestID}}",
+		`Hello,
+
+{{.Text}}.
+
+Pull request: {{.PullRequestID}}
+{{- if .TeamName}}
+Team: {{.TeamName}}{{end}}
+`),
+	"review_reassigned": newEmailTemplate(
+		"Review reassigned to you: {{.PullRequestID}}",
+		`Hello,
+
+{{.Text}}.
+
+Pull request: {{.PullRequestID}}
+{{- if .TeamName}}
+Team: {{.TeamName}}{{end}}
+`),
+	"pr_merged": newEmailTemplate(
+		"Merged: {{.PullRequestID}}",
+		`Hello,
+
+{{.Text}}.
+`),
+}
+
+var _defaultEmailTemplate = newEmailTemplate(
+	"PR service: {{.Kind}}",
+	`Hello,
+
+{{.Text}}
+`)
+
+// Email sends personal messages over SMTP; team-addressed messages are skipped.
+type Email struct {
+	client    *mail.Client
+	addresses ChatResolver
+}
+
+// NewEmail -. addresses resolves a service user to their email address.
+func NewEmail(client *mail.Client, addresses ChatResolver) *Email {
+	return &Email{client: client, addresses: addresses}
+}
+
+// Notify -. Users without a linked address are silently skipped.
+func (e *Email) Notify(ctx context.Context, m Message) error {
+	if m.UserID == "" {
+		Skip(ctx, "team-addressed message")
+		return nil
+	}
+
+	to, err := e.addresses(ctx, m.UserID)
+	if err != nil || to == "" {
+		Skip(ctx, "no linked email address")
+		return nil
+	}
+
+	subject, body, err := renderEmail(m)
+	if err != nil {
+		return err
+	}
+
+	return e.client.Send(ctx, to, subject, body)
+}
+
+func renderEmail(m Message) (subject, body string, err error) {
+	t, ok := _emailTemplates[m.Kind]
+	if !ok {
+		t = _defaultEmailTemplate
+	}
+
+	var s, b bytes.Buffer
+
+	if err := t.subject.Execute(&s, m); err != nil {
+		return "", "", fmt.Errorf("notifier - email subject %s: %w", m.Kind, err)
+	}
+
+	if err := t.body.Execute(&b, m); err != nil {
+		return "", "", fmt.Errorf("notifier - email body %s: %w", m.Kind, err)
+	}
+
+	return s.String(), b.String(), nil
+}
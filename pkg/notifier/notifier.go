@@ -0,0 +1,83 @@
+// Package notifier delivers short human-readable messages about PR events.
+package notifier
+
+import (
+	"context"
+	"strings"
+
+	"github.com/evrone/go-clean-template/pkg/logger"
+)
+
+// Message -. UserID is the recipient; an empty UserID addresses the whole team.
+type Message struct {
+	Kind          string
+	UserID        string
+	TeamName      string
+	PullRequestID string
+	Text          string
+	// Actions are quick replies the recipient may take on PullRequestID,
+	// rendered as buttons by providers that support them.
+	Actions []Action
+}
+
+// Action -. Kind is echoed back by the provider when the action is chosen.
+type Action struct {
+	Kind  string
+	Label string
+}
+
+// Review actions offered on assignment notifications.
+const (
+	ActionApprove = "approve"
+	ActionDecline = "decline"
+	ActionSnooze  = "snooze"
+)
+
+// Notifier -.
+type Notifier interface {
+	Notify(ctx context.Context, m Message) error
+}
+
+// Nop drops every message.
+type Nop struct{}
+
+// Notify -.
+func (Nop) Notify(context.Context, Message) error { return nil }
+
+// Log writes messages to the service log; useful before any provider is configured.
+type Log struct {
+	l logger.Interface
+}
+
+// NewLog -.
+func NewLog(l logger.Interface) *Log {
+	return &Log{l: l}
+}
+
+// Notify -.
+func (n *Log) Notify(_ context.Context, m Message) error {
+	to := m.UserID
+	if to == "" {
+		to = "team:" + m.TeamName
+	}
+
+	n.l.Info("notifier - %s -> %s: %s", m.Kind, to, strings.TrimSpace(m.Text))
+
+	return nil
+}
+
+// Multi fans a message out to every notifier, returning the first error.
+type Multi []Notifier
+
+// Notify -.
+func (m Multi) Notify(ctx context.Context, msg Message) error {
+	var first error
+
+	for _, n := range m {
+		if err := n.Notify(ctx, msg); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
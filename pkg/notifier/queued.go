@@ -0,0 +1,36 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/evrone/go-clean-template/pkg/taskqueue"
+)
+
+// Queued hands messages for next to a durable task queue instead of sending
+// them, so deliveries survive restarts and failed ones are retried. Unlike
+// Async, a message is only lost once its attempts run out.
+type Queued struct {
+	q    *taskqueue.Queue
+	kind string
+}
+
+// NewQueued registers the task kind delivering to next; call it before the
+// queue starts.
+func NewQueued(q *taskqueue.Queue, kind string, next Notifier) *Queued {
+	q.Register(kind, func(ctx context.Context, payload json.RawMessage) error {
+		var m Message
+		if err := json.Unmarshal(payload, &m); err != nil {
+			return fmt.Errorf("decode message: %w", err)
+		}
+		return next.Notify(ctx, m)
+	})
+
+	return &Queued{q: q, kind: kind}
+}
+
+// Notify -. The task is written in the transaction in ctx, if any.
+func (n *Queued) Notify(ctx context.Context, m Message) error {
+	return n.q.Enqueue(ctx, n.kind, m)
+}
@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/evrone/go-clean-template/pkg/slack"
+)
+
+// TeamFilter reports whether a provider should deliver to members of teamName.
+type TeamFilter func(ctx context.Context, teamName string) bool
+
+// TeamChannel returns the channel teamName's team-addressed messages go to;
+// empty means the team has none.
+type TeamChannel func(ctx context.Context, teamName string) string
+
+// Slack sends personal messages as bot DMs and team-addressed messages to the
+// team's channel; teams the filter rejects are skipped.
+type Slack struct {
+	client   *slack.Client
+	users    ChatResolver
+	enabled  TeamFilter
+	channels TeamChannel
+}
+
+// NewSlack -. users resolves a service user to their Slack member ID.
+func NewSlack(client *slack.Client, users ChatResolver, enabled TeamFilter, channels TeamChannel) *Slack {
+	return &Slack{client: client, users: users, enabled: enabled, channels: channels}
+}
+
+// Notify -. Users without a linked Slack account and teams without a channel
+// are silently skipped.
+func (s *Slack) Notify(ctx context.Context, m Message) error {
+	if !s.enabled(ctx, m.TeamName) {
+		Skip(ctx, "Slack disabled for team "+m.TeamName)
+		return nil
+	}
+
+	if m.UserID == "" {
+		channel := s.channels(ctx, m.TeamName)
+		if channel == "" {
+			Skip(ctx, "no Slack channel for team "+m.TeamName)
+			return nil
+		}
+		return s.client.PostMessage(ctx, channel, m.Text)
+	}
+
+	memberID, err := s.users(ctx, m.UserID)
+	if err != nil || memberID == "" {
+		Skip(ctx, "no linked Slack account")
+		return nil
+	}
+
+	return s.client.PostMessage(ctx, memberID, m.Text)
+}
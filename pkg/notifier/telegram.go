@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+
+	"github.com/evrone/go-clean-template/pkg/telegram"
+)
+
+// ChatResolver returns the provider-side chat or account of a user, or an
+// error when the user has not linked one.
+type ChatResolver func(ctx context.Context, userID string) (string, error)
+
+// Telegram sends personal messages through a bot; team-addressed messages are skipped.
+type Telegram struct {
+	client *telegram.Client
+	chats  ChatResolver
+}
+
+// NewTelegram -.
+func NewTelegram(client *telegram.Client, chats ChatResolver) *Telegram {
+	return &Telegram{client: client, chats: chats}
+}
+
+// Notify -. Users without a linked chat are silently skipped.
+func (t *Telegram) Notify(ctx context.Context, m Message) error {
+	if m.UserID == "" {
+		Skip(ctx, "team-addressed message")
+		return nil
+	}
+
+	chatID, err := t.chats(ctx, m.UserID)
+	if err != nil || chatID == "" {
+		Skip(ctx, "no linked Telegram chat")
+		return nil
+	}
+
+	buttons := make([]telegram.Button, 0, len(m.Actions))
+	for _, a := range m.Actions {
+		buttons = append(buttons, telegram.Button{
+			Text:         a.Label,
+			CallbackData: CallbackData(a.Kind, m.PullRequestID),
+		})
+	}
+
+	return t.client.SendMessage(ctx, chatID, m.Text, buttons)
+}
+
+// CallbackData encodes an action for a button; ParseCallbackData reverses it.
+func CallbackData(kind, prID string) string {
+	return kind + ":" + prID
+}
+
+// ParseCallbackData -.
+func ParseCallbackData(data string) (kind, prID string, ok bool) {
+	kind, prID, ok = strings.Cut(data, ":")
+
+	return kind, prID, ok && kind != "" && prID != ""
+}
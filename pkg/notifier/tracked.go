@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"time"
+)
+
+// Delivery outcomes reported by Tracked.
+const (
+	StatusSent    = "sent"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+)
+
+// Delivery is the outcome of handing one message to one provider. Detail
+// holds the skip reason or the provider's error.
+type Delivery struct {
+	Provider string
+	Message  Message
+	Status   string
+	Detail   string
+	Duration time.Duration
+	At       time.Time
+}
+
+// DeliveryLog stores delivery outcomes; it must not block for long.
+type DeliveryLog func(d Delivery)
+
+type attemptKey struct{}
+
+type attempt struct {
+	skipped string
+}
+
+// Skip tells the Tracked wrapping the current provider, if any, that the
+// message was deliberately not sent and why.
+func Skip(ctx context.Context, reason string) {
+	if a, ok := ctx.Value(attemptKey{}).(*attempt); ok {
+		a.skipped = reason
+	}
+}
+
+// Tracked reports every message next handles to log. Skips of team-addressed
+// messages are not reported: most providers skip all of them.
+type Tracked struct {
+	provider string
+	next     Notifier
+	log      DeliveryLog
+}
+
+// NewTracked -. provider names next in the reported deliveries.
+func NewTracked(provider string, next Notifier, log DeliveryLog) *Tracked {
+	return &Tracked{provider: provider, next: next, log: log}
+}
+
+// Notify -.
+func (t *Tracked) Notify(ctx context.Context, m Message) error {
+	a := &attempt{}
+	start := time.Now()
+
+	err := t.next.Notify(context.WithValue(ctx, attemptKey{}, a), m)
+
+	d := Delivery{Provider: t.provider, Message: m, Status: StatusSent, Duration: time.Since(start), At: start}
+	switch {
+	case err != nil:
+		d.Status, d.Detail = StatusFailed, err.Error()
+	case a.skipped != "":
+		if m.UserID == "" {
+			return nil
+		}
+		d.Status, d.Detail = StatusSkipped, a.skipped
+	}
+
+	t.log(d)
+
+	return err
+}
@@ -0,0 +1,338 @@
+// Package oidc verifies JWTs issued by an OpenID Connect provider against the
+// provider's published signing keys.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// _leeway tolerates clock skew between the provider and this service.
+	_leeway = time.Minute
+	// _minRefetch limits key refetches triggered by unknown key IDs.
+	_minRefetch = time.Minute
+)
+
+// ErrInvalidToken is returned for malformed, unsigned, expired or foreign tokens.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the verified claims of a token.
+type Claims struct {
+	Issuer        string
+	Subject       string
+	Audience      []string
+	Email         string
+	EmailVerified bool
+	ExpiresAt     time.Time
+}
+
+// Verifier checks token signatures, issuer, audience and lifetime. Signing
+// keys are fetched from jwksURL, or the issuer's discovery document when it
+// is empty, and cached for refresh.
+type Verifier struct {
+	issuer   string
+	audience string
+	jwksURL  string
+	refresh  time.Duration
+	client   *http.Client
+	now      func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewVerifier -. Tokens are only accepted for audience; with an empty one
+// every token is rejected.
+func NewVerifier(issuer, audience, jwksURL string, refresh time.Duration, client *http.Client) *Verifier {
+	return &Verifier{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		audience: audience,
+		jwksURL:  jwksURL,
+		refresh:  refresh,
+		client:   client,
+		now:      time.Now,
+	}
+}
+
+// Verify parses raw and returns its claims if the token is valid.
+func (v *Verifier) Verify(ctx context.Context, raw string) (Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return Claims{}, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Claims{}, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return Claims{}, err
+	}
+
+	var payload struct {
+		Iss           string          `json:"iss"`
+		Sub           string          `json:"sub"`
+		Aud           json.RawMessage `json:"aud"`
+		Exp           float64         `json:"exp"`
+		Nbf           float64         `json:"nbf"`
+		Email         string          `json:"email"`
+		EmailVerified bool            `json:"email_verified"`
+	}
+	if err := decodeSegment(parts[1], &payload); err != nil {
+		return Claims{}, err
+	}
+
+	claims := Claims{
+		Issuer:        payload.Iss,
+		Subject:       payload.Sub,
+		Audience:      audiences(payload.Aud),
+		Email:         payload.Email,
+		EmailVerified: payload.EmailVerified,
+		ExpiresAt:     time.Unix(int64(payload.Exp), 0).UTC(),
+	}
+
+	now := v.now()
+
+	switch {
+	case strings.TrimSuffix(claims.Issuer, "/") != v.issuer:
+		return Claims{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	case v.audience == "" || !slices.Contains(claims.Audience, v.audience):
+		return Claims{}, fmt.Errorf("%w: not issued for this audience", ErrInvalidToken)
+	case claims.Subject == "":
+		return Claims{}, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	case payload.Exp == 0 || now.Add(-_leeway).After(claims.ExpiresAt):
+		return Claims{}, fmt.Errorf("%w: expired", ErrInvalidToken)
+	case payload.Nbf != 0 && now.Add(_leeway).Before(time.Unix(int64(payload.Nbf), 0)):
+		return Claims{}, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// key returns the signing key kid, refetching the key set when it is stale
+// or doesn't know kid.
+func (v *Verifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	age := v.now().Sub(v.fetchedAt)
+	if _, ok := v.keys[kid]; v.keys == nil || age > v.refresh || (!ok && age > _minRefetch) {
+		keys, err := v.fetchKeys(ctx)
+		if err != nil && v.keys == nil {
+			return nil, err
+		}
+
+		if err == nil {
+			v.keys, v.fetchedAt = keys, v.now()
+		}
+	}
+
+	key, ok := v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, kid)
+	}
+
+	return key, nil
+}
+
+func (v *Verifier) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	jwksURL := v.jwksURL
+	if jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, v.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, err
+		}
+
+		jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := v.getJSON(ctx, jwksURL, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		if pub, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+
+	return keys, nil
+}
+
+func (v *Verifier) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("oidc - getJSON - http.NewRequest: %w", err)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("oidc - getJSON - client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc - getJSON - %s: unexpected status %d", url, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("oidc - getJSON - decode: %w", err)
+	}
+
+	return nil
+}
+
+// jwk is a JSON Web Key of type RSA or EC.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed string, sig []byte) error {
+	var h crypto.Hash
+
+	switch alg {
+	case "RS256", "ES256":
+		h = crypto.SHA256
+	case "RS384", "ES384":
+		h = crypto.SHA384
+	case "RS512":
+		h = crypto.SHA512
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+	}
+
+	hasher := h.New()
+	hasher.Write([]byte(signed))
+	digest := hasher.Sum(nil)
+
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if alg[:2] == "RS" && rsa.VerifyPKCS1v15(pub, h, digest, sig) == nil {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if alg[:2] == "ES" && len(sig) == 2*size {
+			r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+			if ecdsa.Verify(pub, digest, r, s) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+}
+
+func decodeSegment(seg string, out any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// audiences reads the aud claim, which is either a string or a list.
+func audiences(raw json.RawMessage) []string {
+	var one string
+	if json.Unmarshal(raw, &one) == nil {
+		return []string{one}
+	}
+
+	var many []string
+	_ = json.Unmarshal(raw, &many)
+
+	return many
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CounterKey is the context key a *QueryCounter is found under, e.g. set
+// with fiber's Locals so every query of a request is counted.
+type CounterKey struct{}
+
+// QueryCounter tallies the queries run with contexts it is attached to,
+// including transaction statements, and the time they took.
+type QueryCounter struct {
+	queries atomic.Int64
+	nanos   atomic.Int64
+}
+
+// Queries -.
+func (c *QueryCounter) Queries() int64 {
+	return c.queries.Load()
+}
+
+// Duration is the total time spent in the counted queries.
+func (c *QueryCounter) Duration() time.Duration {
+	return time.Duration(c.nanos.Load())
+}
+
+type queryStartKey struct{}
+
+// counterTracer times every query for the pool's Latency and feeds the
+// QueryCounter of each query's context, if any.
+type counterTracer struct {
+	latency *Latency
+}
+
+func (counterTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, time.Now())
+}
+
+func (t counterTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	d := time.Since(start)
+
+	t.latency.observe(d)
+
+	if c, ok := ctx.Value(CounterKey{}).(*QueryCounter); ok {
+		c.queries.Add(1)
+		c.nanos.Add(int64(d))
+	}
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ErrPoolExhausted is returned when no connection frees up within the acquire timeout.
+var ErrPoolExhausted = errors.New("postgres: connection pool exhausted")
+
+var (
+	_acquireSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "pr_service_pg_pool_acquire_seconds",
+		Help:    "Time spent waiting for a connection from the pool.",
+		Buckets: []float64{.0005, .001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5},
+	})
+	_acquireTimeouts = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "pr_service_pg_pool_acquire_timeouts_total",
+		Help: "Connection acquisitions that gave up because the pool stayed saturated.",
+	})
+)
+
+// DB is the query surface shared by *pgxpool.Pool and *Guarded.
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Ping(ctx context.Context) error
+}
+
+// Guarded bounds how long a query waits for a pooled connection, so requests
+// fail fast with ErrPoolExhausted instead of hanging while the pool is saturated.
+type Guarded struct {
+	pool    *pgxpool.Pool
+	timeout time.Duration
+}
+
+// NewGuarded -. A zero timeout waits as long as the caller's context allows.
+func NewGuarded(pool *pgxpool.Pool, timeout time.Duration) *Guarded {
+	return &Guarded{pool: pool, timeout: timeout}
+}
+
+func (g *Guarded) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	actx := ctx
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		actx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	conn, err := g.pool.Acquire(actx)
+	_acquireSeconds.Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(actx.Err(), context.DeadlineExceeded) {
+			_acquireTimeouts.Inc()
+			return nil, fmt.Errorf("%w: no connection within %s", ErrPoolExhausted, g.timeout)
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// Exec -.
+func (g *Guarded) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	conn, err := g.acquire(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer conn.Release()
+
+	return conn.Exec(ctx, sql, args...)
+}
+
+// Query -. The connection returns to the pool when the rows are closed or exhausted.
+func (g *Guarded) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	conn, err := g.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	return &releasingRows{Rows: rows, conn: conn}, nil
+}
+
+// QueryRow -. The connection returns to the pool after Scan.
+func (g *Guarded) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	conn, err := g.acquire(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+
+	return &releasingRow{row: conn.QueryRow(ctx, sql, args...), conn: conn}
+}
+
+// Begin -. The connection returns to the pool on Commit or Rollback.
+func (g *Guarded) Begin(ctx context.Context) (pgx.Tx, error) {
+	conn, err := g.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+
+	return &releasingTx{Tx: tx, conn: conn}, nil
+}
+
+// Ping -.
+func (g *Guarded) Ping(ctx context.Context) error {
+	conn, err := g.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	return conn.Ping(ctx)
+}
+
+type releasingRows struct {
+	pgx.Rows
+	conn *pgxpool.Conn
+	once sync.Once
+}
+
+func (r *releasingRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+
+	r.release()
+
+	return false
+}
+
+func (r *releasingRows) Close() {
+	r.Rows.Close()
+	r.release()
+}
+
+func (r *releasingRows) release() {
+	r.once.Do(r.conn.Release)
+}
+
+type releasingRow struct {
+	row  pgx.Row
+	conn *pgxpool.Conn
+}
+
+func (r *releasingRow) Scan(dest ...any) error {
+	defer r.conn.Release()
+
+	return r.row.Scan(dest...)
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
+
+type releasingTx struct {
+	pgx.Tx
+	conn *pgxpool.Conn
+	once sync.Once
+}
+
+func (t *releasingTx) Commit(ctx context.Context) error {
+	defer t.release()
+
+	return t.Tx.Commit(ctx)
+}
+
+func (t *releasingTx) Rollback(ctx context.Context) error {
+	defer t.release()
+
+	return t.Tx.Rollback(ctx)
+}
+
+func (t *releasingTx) release() {
+	t.once.Do(t.conn.Release)
+}
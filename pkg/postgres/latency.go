@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"sync"
+	"time"
+)
+
+// _latencyWindow is how long one window of query timings lasts. Average
+// covers the current window and the one before it.
+const _latencyWindow = 10 * time.Second
+
+// Latency averages the round trip of recent queries across the pool, so
+// callers can shed optional reads while the database is slow. With no
+// queries in the last two windows the average is 0: a quiet database counts
+// as healthy until queries show otherwise.
+type Latency struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	cur, prev   latencyWindow
+}
+
+type latencyWindow struct {
+	total time.Duration
+	n     int64
+}
+
+func (l *Latency) observe(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rotate(time.Now())
+	l.cur.total += d
+	l.cur.n++
+}
+
+// Average is the mean query duration over the last one to two windows.
+func (l *Latency) Average() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rotate(time.Now())
+
+	n := l.cur.n + l.prev.n
+	if n == 0 {
+		return 0
+	}
+
+	return (l.cur.total + l.prev.total) / time.Duration(n)
+}
+
+// rotate starts a new window once the current one is over, dropping both
+// when a whole window passed without a rotation.
+func (l *Latency) rotate(now time.Time) {
+	elapsed := now.Sub(l.windowStart)
+	switch {
+	case elapsed < _latencyWindow:
+		return
+	case elapsed < 2*_latencyWindow:
+		l.prev = l.cur
+	default:
+		l.prev = latencyWindow{}
+	}
+
+	l.cur = latencyWindow{}
+	l.windowStart = now
+}
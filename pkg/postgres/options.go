@@ -25,3 +25,10 @@ func ConnTimeout(timeout time.Duration) Option {
 		c.connTimeout = timeout
 	}
 }
+
+// AcquireTimeout bounds how long DB() waits for a free connection.
+func AcquireTimeout(timeout time.Duration) Option {
+	return func(c *Postgres) {
+		c.acquireTimeout = timeout
+	}
+}
@@ -19,9 +19,11 @@ const (
 
 // Postgres -.
 type Postgres struct {
-	maxPoolSize  int
-	connAttempts int
-	connTimeout  time.Duration
+	maxPoolSize    int
+	connAttempts   int
+	connTimeout    time.Duration
+	acquireTimeout time.Duration
+	latency        *Latency
 
 	Builder squirrel.StatementBuilderType
 	Pool    *pgxpool.Pool
@@ -33,6 +35,7 @@ func New(url string, opts ...Option) (*Postgres, error) {
 		maxPoolSize:  _defaultMaxPoolSize,
 		connAttempts: _defaultConnAttempts,
 		connTimeout:  _defaultConnTimeout,
+		latency:      &Latency{},
 	}
 
 	// Custom options
@@ -48,6 +51,7 @@ func New(url string, opts ...Option) (*Postgres, error) {
 	}
 
 	poolConfig.MaxConns = int32(pg.maxPoolSize) //nolint:gosec // skip integer overflow conversion int -> int32
+	poolConfig.ConnConfig.Tracer = counterTracer{latency: pg.latency}
 
 	for pg.connAttempts > 0 {
 		pg.Pool, err = pgxpool.NewWithConfig(context.Background(), poolConfig)
@@ -69,6 +73,16 @@ func New(url string, opts ...Option) (*Postgres, error) {
 	return pg, nil
 }
 
+// DB returns the pool guarded by the configured acquire timeout.
+func (p *Postgres) DB() *Guarded {
+	return NewGuarded(p.Pool, p.acquireTimeout)
+}
+
+// Latency tracks the pool's recent query round trips.
+func (p *Postgres) Latency() *Latency {
+	return p.latency
+}
+
 // Close -.
 func (p *Postgres) Close() {
 	if p.Pool != nil {
@@ -0,0 +1,170 @@
+// Package sandbox captures outbound integration traffic instead of delivering it.
+package sandbox
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const _defaultCapacity = 500
+
+// Message -.
+type Message struct {
+	ID         int64             `json:"id"`
+	Provider   string            `json:"provider"`
+	Method     string            `json:"method,omitempty"`
+	Target     string            `json:"target"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`
+	CapturedAt time.Time         `json:"captured_at"`
+}
+
+// Recorder keeps the most recent captured messages in memory.
+type Recorder struct {
+	mu       sync.RWMutex
+	capacity int
+	nextID   int64
+	messages []Message
+}
+
+// NewRecorder -.
+func NewRecorder(capacity int) *Recorder {
+	if capacity <= 0 {
+		capacity = _defaultCapacity
+	}
+
+	return &Recorder{capacity: capacity}
+}
+
+// Record stores a message, evicting the oldest one when the buffer is full.
+func (r *Recorder) Record(m Message) Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	m.ID = r.nextID
+
+	if m.CapturedAt.IsZero() {
+		m.CapturedAt = time.Now().UTC()
+	}
+
+	if len(r.messages) == r.capacity {
+		r.messages = r.messages[1:]
+	}
+
+	r.messages = append(r.messages, m)
+
+	return m
+}
+
+// List returns captured messages, newest first, optionally filtered by provider.
+func (r *Recorder) List(provider string) []Message {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Message, 0, len(r.messages))
+
+	for i := len(r.messages) - 1; i >= 0; i-- {
+		if provider != "" && r.messages[i].Provider != provider {
+			continue
+		}
+
+		out = append(out, r.messages[i])
+	}
+
+	return out
+}
+
+// Reset drops all captured messages.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = nil
+}
+
+// Transport is an http.RoundTripper that records requests and answers them locally.
+type Transport struct {
+	recorder *Recorder
+}
+
+// NewClient returns an *http.Client whose requests never leave the process.
+func NewClient(r *Recorder) *http.Client {
+	return &http.Client{Transport: &Transport{recorder: r}}
+}
+
+// RoundTrip -.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		_ = req.Body.Close()
+		body = b
+	}
+
+	headers := make(map[string]string, len(req.Header))
+
+	for k := range req.Header {
+		if isSensitiveHeader(k) {
+			headers[k] = "[redacted]"
+
+			continue
+		}
+
+		headers[k] = req.Header.Get(k)
+	}
+
+	t.recorder.Record(Message{
+		Provider: ProviderFromHost(req.URL.Hostname()),
+		Method:   req.Method,
+		Target:   req.URL.String(),
+		Headers:  headers,
+		Body:     string(body),
+	})
+
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        "200 OK",
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewBufferString(`{"ok":true}`)),
+		ContentLength: int64(len(`{"ok":true}`)),
+		Request:       req,
+	}, nil
+}
+
+// ProviderFromHost guesses the integration name from the destination host.
+func ProviderFromHost(host string) string {
+	switch {
+	case strings.HasSuffix(host, "slack.com"):
+		return "slack"
+	case strings.HasSuffix(host, "github.com"):
+		return "github"
+	case strings.HasSuffix(host, "gitlab.com"):
+		return "gitlab"
+	case strings.HasSuffix(host, "telegram.org"):
+		return "telegram"
+	default:
+		return "http"
+	}
+}
+
+func isSensitiveHeader(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Cookie", "X-Hub-Signature-256", "X-Signature", "Private-Token":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,14 @@
+package scheduler
+
+import "time"
+
+// Option -.
+type Option func(*Scheduler)
+
+// Jitter delays every run by up to d (and at most half the wait before it)
+// at random, so instances started together don't hit the database at once.
+func Jitter(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.jitter = d
+	}
+}
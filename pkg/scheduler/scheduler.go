@@ -0,0 +1,301 @@
+// Package scheduler runs named background jobs on a schedule. Each job runs
+// in its own goroutine, one run at a time; a failing or panicking run is
+// logged and counted without affecting other jobs or later runs.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Run results, as reported in metrics and JobStatus.
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+	ResultPanic = "panic"
+)
+
+// ErrUnknownJob is returned by Trigger for names that were never added.
+var ErrUnknownJob = errors.New("scheduler: unknown job")
+
+var (
+	_runs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "pr_service_job_runs_total",
+		Help: "Background job runs by result (ok, error, panic).",
+	}, []string{"job", "result"})
+	_duration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pr_service_job_duration_seconds",
+		Help:    "Time background job runs took.",
+		Buckets: []float64{.01, .05, .1, .5, 1, 5, 15, 60, 300},
+	}, []string{"job"})
+	_lastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pr_service_job_last_success_timestamp_seconds",
+		Help: "Unix time of each background job's last successful run.",
+	}, []string{"job"})
+)
+
+// Func is one run of a job. ctx is cancelled when the scheduler stops.
+type Func func(ctx context.Context) error
+
+// Schedule tells when a job runs next after now.
+type Schedule interface {
+	Next(now time.Time) time.Time
+}
+
+// Every runs a job at a fixed interval, the first time one interval after start.
+type Every time.Duration
+
+func (e Every) Next(now time.Time) time.Time {
+	return now.Add(time.Duration(e))
+}
+
+// DailyAt runs a job once a day at the hour (0-23, UTC).
+type DailyAt int
+
+func (d DailyAt) Next(now time.Time) time.Time {
+	now = now.UTC()
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), int(d), 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return next
+}
+
+// WeeklyAt runs a job once a week on Day at Hour (0-23, UTC).
+type WeeklyAt struct {
+	Day  time.Weekday
+	Hour int
+}
+
+func (w WeeklyAt) Next(now time.Time) time.Time {
+	now = now.UTC()
+
+	days := (int(w.Day) - int(now.Weekday()) + 7) % 7
+	next := time.Date(now.Year(), now.Month(), now.Day()+days, w.Hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+
+	return next
+}
+
+// JobStatus describes a job and its last run. Running is set while a run is
+// in progress; Last* are empty before the first run.
+type JobStatus struct {
+	Name           string     `json:"name"`
+	Running        bool       `json:"running"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	Runs           int        `json:"runs"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastDurationMs float64    `json:"last_duration_ms"`
+	LastResult     string     `json:"last_result,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	fn       Func
+	// trigger requests a run outside the schedule; one pending request at most.
+	trigger chan struct{}
+
+	mu     sync.Mutex
+	status JobStatus
+}
+
+// Scheduler -.
+type Scheduler struct {
+	l      logger.Interface
+	jitter time.Duration
+
+	mu      sync.Mutex
+	jobs    []*job
+	started bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New -.
+func New(l logger.Interface, opts ...Option) *Scheduler {
+	s := &Scheduler{l: l}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Add registers fn under name. Jobs added after Start are ignored.
+func (s *Scheduler) Add(name string, schedule Schedule, fn Func) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		s.l.Warn("scheduler - job %s added after start, skipped", name)
+		return
+	}
+
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		schedule: schedule,
+		fn:       fn,
+		trigger:  make(chan struct{}, 1),
+		status:   JobStatus{Name: name},
+	})
+}
+
+// Jobs reports every registered job in the order they were added.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		j.mu.Lock()
+		statuses = append(statuses, j.status)
+		j.mu.Unlock()
+	}
+
+	return statuses
+}
+
+// Trigger asks for an immediate run of the named job. The run happens on the
+// job's own goroutine after any run in progress, so runs never overlap;
+// triggering again before it starts asks for the same run. Triggers before
+// Start are picked up once the scheduler starts.
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs {
+		if j.name == name {
+			select {
+			case j.trigger <- struct{}{}:
+			default:
+			}
+			return nil
+		}
+	}
+
+	return ErrUnknownJob
+}
+
+// Start launches every registered job.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop cancels running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	defer s.wg.Done()
+
+	for {
+		now := time.Now()
+		wait := s.withJitter(j.schedule.Next(now).Sub(now))
+		timer := time.NewTimer(wait)
+
+		next := now.Add(wait).UTC()
+		j.mu.Lock()
+		j.status.NextRunAt = &next
+		j.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.run(ctx, j)
+		case <-j.trigger:
+			timer.Stop()
+			s.run(ctx, j)
+		}
+	}
+}
+
+// withJitter adds a random delay of up to the configured jitter to wait.
+func (s *Scheduler) withJitter(wait time.Duration) time.Duration {
+	limit := min(s.jitter, wait/2)
+	if limit <= 0 {
+		return wait
+	}
+
+	return wait + rand.N(limit)
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	start := time.Now()
+	result, errText := ResultOK, ""
+
+	startedAt := start.UTC()
+	j.mu.Lock()
+	j.status.Running = true
+	j.status.LastStartedAt = &startedAt
+	j.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			result, errText = ResultPanic, fmt.Sprint(r)
+			s.l.Error(fmt.Errorf("scheduler - job %s panicked: %v", j.name, r))
+		}
+
+		took := time.Since(start)
+
+		j.mu.Lock()
+		j.status.Running = false
+		j.status.Runs++
+		j.status.LastDurationMs = float64(took) / float64(time.Millisecond)
+		j.status.LastResult = result
+		j.status.LastError = errText
+		j.mu.Unlock()
+
+		_runs.WithLabelValues(j.name, result).Inc()
+		_duration.WithLabelValues(j.name).Observe(took.Seconds())
+		if result == ResultOK {
+			_lastSuccess.WithLabelValues(j.name).SetToCurrentTime()
+		}
+	}()
+
+	if err := j.fn(ctx); err != nil {
+		result, errText = ResultError, err.Error()
+		s.l.Error(fmt.Errorf("scheduler - job %s: %w", j.name, err))
+	}
+}
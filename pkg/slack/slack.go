@@ -0,0 +1,82 @@
+// Package slack is a minimal Slack Web API client.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const _defaultAPIURL = "https://slack.com/api"
+
+// Client -.
+type Client struct {
+	token   string
+	baseURL string
+	http    *http.Client
+}
+
+// New -. An empty apiURL selects the public Web API.
+func New(token, apiURL string, httpClient *http.Client) *Client {
+	if apiURL == "" {
+		apiURL = _defaultAPIURL
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		token:   token,
+		baseURL: strings.TrimRight(apiURL, "/"),
+		http:    httpClient,
+	}
+}
+
+// PostMessage posts text to channel; a Slack user ID as channel sends a DM
+// from the bot.
+func (c *Client) PostMessage(ctx context.Context, channel, text string) error {
+	return c.call(ctx, "chat.postMessage", map[string]any{
+		"channel": channel,
+		"text":    text,
+	})
+}
+
+func (c *Client) call(ctx context.Context, method string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/"+method, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack - %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("slack - %s: decode response: %w", method, err)
+	}
+
+	if !result.OK {
+		return fmt.Errorf("slack - %s: %s", method, result.Error)
+	}
+
+	return nil
+}
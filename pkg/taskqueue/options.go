@@ -0,0 +1,52 @@
+package taskqueue
+
+import "time"
+
+type options struct {
+	workers        int
+	pollInterval   time.Duration
+	visibility     time.Duration
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// Option -.
+type Option func(*options)
+
+// Workers sets how many tasks run at once in this process.
+func Workers(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// PollInterval sets how long an idle worker waits before looking again.
+func PollInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = d
+	}
+}
+
+// VisibilityTimeout sets how long a claimed task is hidden from other
+// workers; it also bounds each run.
+func VisibilityTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.visibility = d
+	}
+}
+
+// MaxAttempts sets how many times new tasks run before they are dead.
+func MaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// Backoff sets the delay before the first retry, doubling up to maxDelay.
+func Backoff(initial, maxDelay time.Duration) Option {
+	return func(o *options) {
+		o.initialBackoff = initial
+		o.maxBackoff = maxDelay
+	}
+}
@@ -0,0 +1,287 @@
+// Package taskqueue runs deferred work from a durable store, so it survives
+// restarts and is shared by every instance. Workers poll the store for due
+// tasks; a claimed task is hidden from other workers for the visibility
+// timeout, and becomes due again if its worker dies without finishing it.
+// Failures are retried with exponential backoff until the task's attempts
+// run out, after which it is kept as dead for an operator to retry. Tasks
+// sharing an ordering key run one at a time, in the order they were enqueued.
+package taskqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Task states. Tasks that succeed are deleted.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDead    = "dead"
+)
+
+// ErrNotFound is returned by Retry when no dead task has the ID, and by
+// Complete and Fail when the task was claimed again since.
+var ErrNotFound = errors.New("taskqueue: task not found")
+
+var _tasks = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "pr_service_tasks_total",
+	Help: "Task queue runs by result (ok, retry, dead).",
+}, []string{"kind", "result"})
+
+// Task is a unit of deferred work. RunAt is when it is next due; while it
+// runs, that is when its visibility timeout expires. A task with a Key waits
+// for the earlier tasks with the same Key that are not dead.
+type Task struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Key         string          `json:"key,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// Store persists tasks. Claim must be safe for concurrent callers across
+// processes: it marks the oldest due task of one of kinds whose key is free
+// running, counts the attempt, sets RunAt visibility from now and returns it,
+// or nil when none is due. Complete and Fail only apply to the claim that
+// made attempt; they return ErrNotFound once the task was claimed again.
+type Store interface {
+	Enqueue(ctx context.Context, t *Task) error
+	Claim(ctx context.Context, kinds []string, visibility time.Duration) (*Task, error)
+	Complete(ctx context.Context, id int64, attempt int) error
+	// Fail records errText and makes the task due again at retryAt, or dead.
+	Fail(ctx context.Context, id int64, attempt int, errText string, retryAt time.Time, dead bool) error
+	List(ctx context.Context, status, kind string, limit int) ([]Task, error)
+	// Retry makes a dead task pending again with its attempts reset.
+	Retry(ctx context.Context, id int64) error
+}
+
+// Handler runs one task with its payload. Tasks may run more than once and
+// out of enqueue order, so handlers should be idempotent.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Queue -.
+type Queue struct {
+	store    Store
+	opts     options
+	l        logger.Interface
+	handlers map[string]Handler
+
+	mu      sync.Mutex
+	started bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// New -.
+func New(store Store, l logger.Interface, opts ...Option) *Queue {
+	q := &Queue{
+		store: store,
+		opts: options{
+			workers:        4,
+			pollInterval:   time.Second,
+			visibility:     time.Minute,
+			maxAttempts:    8,
+			initialBackoff: 5 * time.Second,
+			maxBackoff:     10 * time.Minute,
+		},
+		l:        l,
+		handlers: make(map[string]Handler),
+	}
+
+	for _, opt := range opts {
+		opt(&q.opts)
+	}
+
+	return q
+}
+
+// Register sets the handler for kind. Only registered kinds are claimed;
+// handlers registered after Start are ignored.
+func (q *Queue) Register(kind string, h Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.started {
+		q.l.Warn("taskqueue - handler for %s registered after start, skipped", kind)
+		return
+	}
+
+	q.handlers[kind] = h
+}
+
+// Enqueue stores a task of kind with payload encoded as JSON, due now. It
+// joins the transaction in ctx, if the store supports one, so the task is
+// only queued when the work that asked for it commits.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any) error {
+	return q.EnqueueOrdered(ctx, kind, "", payload)
+}
+
+// EnqueueOrdered is Enqueue for tasks that must not overtake each other: a
+// task only runs once the earlier ones with the same key have finished or
+// died, retries included. An empty key does not order the task.
+func (q *Queue) EnqueueOrdered(ctx context.Context, kind, key string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("taskqueue - encode %s: %w", kind, err)
+	}
+
+	return q.store.Enqueue(ctx, &Task{Kind: kind, Key: key, Payload: raw, MaxAttempts: q.opts.maxAttempts})
+}
+
+// List returns tasks in status (all when empty) and of kind (all when empty).
+func (q *Queue) List(ctx context.Context, status, kind string, limit int) ([]Task, error) {
+	return q.store.List(ctx, status, kind, limit)
+}
+
+// Retry requeues a dead task.
+func (q *Queue) Retry(ctx context.Context, id int64) error {
+	return q.store.Retry(ctx, id)
+}
+
+// Start launches the workers.
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.started {
+		return
+	}
+	q.started = true
+
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+
+	for range max(1, q.opts.workers) {
+		q.wg.Add(1)
+		go q.work(ctx, kinds)
+	}
+}
+
+// Stop lets the tasks in progress finish and waits for the workers to exit.
+// Their handlers' ctx is cancelled, so a task cut short is retried later.
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	cancel := q.cancel
+	q.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	q.wg.Wait()
+}
+
+func (q *Queue) work(ctx context.Context, kinds []string) {
+	defer q.wg.Done()
+
+	for ctx.Err() == nil {
+		t, err := q.store.Claim(ctx, kinds, q.opts.visibility)
+		if err != nil && ctx.Err() == nil {
+			q.l.Error(fmt.Errorf("taskqueue - claim: %w", err))
+		}
+		if t != nil {
+			q.run(ctx, t)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(q.opts.pollInterval):
+		}
+	}
+}
+
+func (q *Queue) run(ctx context.Context, t *Task) {
+	// A task over its attempts here was claimed again after its visibility
+	// timeout, most likely because running it keeps killing the process.
+	if t.Attempts > t.MaxAttempts {
+		q.fail(t, errors.New("visibility timeout expired on the last attempt"))
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, q.opts.visibility)
+	defer cancel()
+
+	err := q.call(runCtx, t)
+	if err == nil {
+		// Not the run ctx: the outcome is recorded even when stopping.
+		doneCtx, doneCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer doneCancel()
+
+		switch err := q.store.Complete(doneCtx, t.ID, t.Attempts); {
+		case errors.Is(err, ErrNotFound):
+			q.l.Warn("taskqueue - %s %d finished after its claim expired", t.Kind, t.ID)
+		case err != nil:
+			q.l.Error(fmt.Errorf("taskqueue - complete %s %d: %w", t.Kind, t.ID, err))
+		}
+		_tasks.WithLabelValues(t.Kind, "ok").Inc()
+
+		return
+	}
+
+	q.fail(t, err)
+}
+
+// call runs t's handler, turning a panic into an error.
+func (q *Queue) call(ctx context.Context, t *Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return q.handlers[t.Kind](ctx, t.Payload)
+}
+
+func (q *Queue) fail(t *Task, cause error) {
+	dead := t.Attempts >= t.MaxAttempts
+
+	backoff := q.opts.initialBackoff
+	for i := 1; i < t.Attempts && backoff < q.opts.maxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, q.opts.maxBackoff)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	switch err := q.store.Fail(ctx, t.ID, t.Attempts, cause.Error(), time.Now().Add(backoff), dead); {
+	case errors.Is(err, ErrNotFound):
+		// Another worker owns the task now and records its own outcome.
+		q.l.Warn("taskqueue - %s %d failed after its claim expired: %v", t.Kind, t.ID, cause)
+		return
+	case err != nil:
+		q.l.Error(fmt.Errorf("taskqueue - fail %s %d: %w", t.Kind, t.ID, err))
+	}
+
+	if dead {
+		_tasks.WithLabelValues(t.Kind, "dead").Inc()
+		q.l.Error(fmt.Errorf("taskqueue - %s %d dead after %d attempt(s): %w", t.Kind, t.ID, t.Attempts, cause))
+		return
+	}
+
+	_tasks.WithLabelValues(t.Kind, "retry").Inc()
+	q.l.Warn("taskqueue - %s %d attempt %d failed, retrying in %s: %v", t.Kind, t.ID, t.Attempts, backoff, cause)
+}
@@ -0,0 +1,136 @@
+// Package telegram is a minimal Telegram Bot API client.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const _defaultAPIURL = "https://api.telegram.org"
+
+// Button is an inline keyboard button that sends CallbackData back to the bot.
+type Button struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// Client -.
+type Client struct {
+	token   string
+	baseURL string
+	http    *http.Client
+}
+
+// New -. An empty apiURL selects the public Bot API.
+func New(token, apiURL string, httpClient *http.Client) *Client {
+	if apiURL == "" {
+		apiURL = _defaultAPIURL
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		token:   token,
+		baseURL: strings.TrimRight(apiURL, "/"),
+		http:    httpClient,
+	}
+}
+
+// SendMessage posts text to chatID; buttons are laid out in a single row.
+func (c *Client) SendMessage(ctx context.Context, chatID, text string, buttons []Button) error {
+	req := map[string]any{
+		"chat_id": chatID,
+		"text":    text,
+	}
+
+	if len(buttons) > 0 {
+		req["reply_markup"] = map[string]any{"inline_keyboard": [][]Button{buttons}}
+	}
+
+	return c.call(ctx, "sendMessage", req)
+}
+
+// AnswerCallbackQuery acknowledges a button press, showing text to the user.
+func (c *Client) AnswerCallbackQuery(ctx context.Context, callbackID, text string) error {
+	return c.call(ctx, "answerCallbackQuery", map[string]any{
+		"callback_query_id": callbackID,
+		"text":              text,
+	})
+}
+
+func (c *Client) call(ctx context.Context, method string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram - %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram - %s: decode response: %w", method, err)
+	}
+
+	if !result.OK {
+		return fmt.Errorf("telegram - %s: %s", method, result.Description)
+	}
+
+	return nil
+}
+
+// Update is the subset of an incoming bot update the service handles.
+type Update struct {
+	UpdateID      int64          `json:"update_id"`
+	Message       *Message       `json:"message,omitempty"`
+	CallbackQuery *CallbackQuery `json:"callback_query,omitempty"`
+}
+
+// CallbackQuery -.
+type CallbackQuery struct {
+	ID      string   `json:"id"`
+	From    User     `json:"from"`
+	Data    string   `json:"data"`
+	Message *Message `json:"message,omitempty"`
+}
+
+// User -.
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+// Message -. From and Text are set on messages sent to the bot.
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+// Chat -.
+type Chat struct {
+	ID int64 `json:"id"`
+}
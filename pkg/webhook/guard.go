@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned by Deliver, and fails Send's deliveries,
+// when the URL resolves to a loopback, link-local, private or otherwise
+// non-public address.
+var ErrForbiddenAddress = errors.New("webhook: destination address is not public")
+
+// _sharedAddressSpace is 100.64.0.0/10 (RFC 6598), used for carrier-grade NAT.
+var _sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// publicOnly returns a copy of c that refuses to connect to non-public
+// addresses. The check runs on each resolved address as it is dialled, so
+// neither DNS rebinding nor redirects get around it, and proxies are not
+// used. A client with a custom transport, such as the sandbox recorder, never
+// dials and is returned as is.
+func publicOnly(c *http.Client) *http.Client {
+	var t *http.Transport
+	switch base := c.Transport.(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = base.Clone()
+	default:
+		return c
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublic(ip) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+	t.DialContext = dialer.DialContext
+	t.Proxy = nil
+
+	guarded := *c
+	guarded.Transport = t
+
+	return &guarded
+}
+
+// IsPublic reports whether deliveries may connect to ip.
+func IsPublic(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() && !_sharedAddressSpace.Contains(ip)
+}
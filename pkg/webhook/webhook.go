@@ -0,0 +1,247 @@
+// Package webhook delivers signed JSON payloads to outgoing webhook URLs in
+// the background, retrying failures with exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/evrone/go-clean-template/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Headers set on every delivery.
+const (
+	HeaderEvent     = "X-PR-Service-Event"
+	HeaderDelivery  = "X-PR-Service-Delivery"
+	HeaderSignature = "X-PR-Service-Signature"
+)
+
+var _deliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "pr_service_outgoing_webhooks_total",
+	Help: "Outgoing webhook deliveries by final result (delivered, failed, dropped).",
+}, []string{"event", "result"})
+
+// Sign returns the signature header value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is Sign(secret, body).
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+type delivery struct {
+	url, secret, event, id string
+	payload                []byte
+	// attempts made so far, and the wait before the next retry.
+	attempts int
+	backoff  time.Duration
+}
+
+// Options -.
+type Options struct {
+	QueueSize      int
+	Workers        int
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Sender queues deliveries and sends them from background workers. Deliveries
+// are dropped when the queue is full.
+type Sender struct {
+	// http only connects to public addresses, see publicOnly.
+	http *http.Client
+	opts Options
+	l    logger.Interface
+
+	queue  chan delivery
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewSender -.
+func NewSender(httpClient *http.Client, opts Options, l logger.Interface) *Sender {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Sender{
+		http:   publicOnly(httpClient),
+		opts:   opts,
+		l:      l,
+		queue:  make(chan delivery, opts.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start launches the workers.
+func (s *Sender) Start() {
+	for range max(1, s.opts.Workers) {
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+
+			for d := range s.queue {
+				s.deliver(d)
+			}
+		}()
+	}
+}
+
+// Stop abandons pending retries and waits for the workers to exit;
+// deliveries still queued are dropped.
+func (s *Sender) Stop() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Send queues payload for url without blocking.
+func (s *Sender) Send(url, secret, event, deliveryID string, payload []byte) {
+	s.enqueue(delivery{url: url, secret: secret, event: event, id: deliveryID, payload: payload, backoff: s.opts.InitialBackoff})
+}
+
+func (s *Sender) enqueue(d delivery) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		_deliveriesTotal.WithLabelValues(d.event, "dropped").Inc()
+		return
+	}
+
+	select {
+	case s.queue <- d:
+	default:
+		_deliveriesTotal.WithLabelValues(d.event, "dropped").Inc()
+	}
+}
+
+// deliver makes one attempt. A retry is queued again once its backoff has
+// passed, so the worker moves on to other deliveries meanwhile.
+func (s *Sender) deliver(d delivery) {
+	if s.ctx.Err() != nil {
+		_deliveriesTotal.WithLabelValues(d.event, "dropped").Inc()
+		return
+	}
+
+	d.attempts++
+
+	retry, err := s.post(d)
+	if err == nil {
+		_deliveriesTotal.WithLabelValues(d.event, "delivered").Inc()
+		return
+	}
+
+	if !retry || d.attempts >= s.opts.MaxAttempts {
+		_deliveriesTotal.WithLabelValues(d.event, "failed").Inc()
+		s.l.Warn("webhook - %s %s to %s failed after %d attempt(s): %v", d.event, d.id, d.url, d.attempts, err)
+
+		return
+	}
+
+	wait := d.backoff
+	d.backoff = min(d.backoff*2, s.opts.MaxBackoff)
+	time.AfterFunc(wait, func() { s.enqueue(d) })
+}
+
+// Result is an endpoint's response to a delivery made by Deliver. The body
+// is not kept, so a delivery can't be used to read another service.
+type Result struct {
+	StatusCode int
+	Duration   time.Duration
+}
+
+// Deliver makes a single signed delivery right away and reports the response,
+// whatever its status. Unlike Send it neither queues nor retries, and it only
+// connects to public addresses (see ErrForbiddenAddress); the error is only
+// set when no response was received.
+func (s *Sender) Deliver(ctx context.Context, url, secret, event, deliveryID string, payload []byte) (Result, error) {
+	req, err := newRequest(ctx, delivery{url: url, secret: secret, event: event, id: deliveryID, payload: payload})
+	if err != nil {
+		return Result{}, err
+	}
+
+	start := time.Now()
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return Result{Duration: time.Since(start)}, err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return Result{StatusCode: resp.StatusCode, Duration: time.Since(start)}, nil
+}
+
+func newRequest(ctx context.Context, d delivery) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(d.payload))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.event)
+	req.Header.Set(HeaderDelivery, d.id)
+	req.Header.Set(HeaderSignature, Sign(d.secret, d.payload))
+
+	return req, nil
+}
+
+// post makes one attempt. Network errors, 408, 429 and 5xx are retried;
+// other non-2xx responses and non-public addresses are not.
+func (s *Sender) post(d delivery) (retry bool, err error) {
+	req, err := newRequest(s.ctx, d)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return !errors.Is(err, ErrForbiddenAddress), err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return true, fmt.Errorf("status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("status %d", resp.StatusCode)
+	}
+}